@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func folderSegmentHasArtist(segment string) bool {
+	return strings.Contains(segment, "{artist}") || strings.Contains(segment, "{album_artist}") || strings.Contains(segment, "{albumartist}")
+}
+
+func artistFolderForTrack(req DownloadRequest, filename string) string {
+	segments := strings.Split(strings.Trim(loadBatchDownloadSettings().FolderTemplate, "/"), "/")
+	albumDir := albumFolderForTrack(req, filename)
+
+	switch last := segments[len(segments)-1]; {
+	case len(segments) >= 2 && folderSegmentHasArtist(segments[len(segments)-2]):
+		return filepath.Dir(albumDir)
+	case folderSegmentHasArtist(last) && !strings.Contains(last, "{album}"):
+		return albumDir
+	}
+	return ""
+}
+
+func saveAlbumExtras(req DownloadRequest, filename string) {
+	if backend.GetDownloadArtistImageSetting() {
+		if artistDir := artistFolderForTrack(req, filename); artistDir != "" {
+			artistName := req.AlbumArtist
+			if artistName == "" {
+				artistName = req.ArtistName
+			}
+			if err := backend.NewCoverClient().SaveArtistImage(artistDir, backend.GetFirstArtist(artistName), req.ArtistImageURL); err != nil {
+				fmt.Printf("Warning: failed to save artist image: %v\n", err)
+			}
+		}
+	}
+
+	if backend.GetDownloadBookletSetting() && req.AlbumName != "" {
+		isrc := req.ISRC
+		if isrc == "" && req.SpotifyID != "" {
+			isrc = backend.ResolveTrackISRC(req.SpotifyID)
+		}
+		if err := backend.DownloadQobuzBooklets(isrc, albumFolderForTrack(req, filename)); err != nil {
+			fmt.Printf("Warning: failed to download booklet: %v\n", err)
+		}
+	}
+}
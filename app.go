@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,7 +24,19 @@ import (
 )
 
 type App struct {
-	ctx context.Context
+	ctx             context.Context
+	downloadsCtx    context.Context
+	cancelDownloads context.CancelFunc
+	cancelWatch     context.CancelFunc
+	cancelAPI       context.CancelFunc
+	cancelScan      context.CancelFunc
+	watchMu         sync.Mutex
+	scanMu          sync.Mutex
+	apiJobs         chan DownloadRequest
+	apiDone         chan struct{}
+	apiToken        string
+	headless        bool
+	startedAt       time.Time
 }
 
 type CurrentIPInfo struct {
@@ -36,7 +49,19 @@ type CurrentIPInfo struct {
 const checkOperationTimeout = 10 * time.Second
 
 func NewApp() *App {
-	return &App{}
+	downloadsCtx, cancelDownloads := context.WithCancel(context.Background())
+	return &App{downloadsCtx: downloadsCtx, cancelDownloads: cancelDownloads}
+}
+
+// downloadContext derives a context from ctx that is also cancelled when the
+// app shuts down, so downloads waiting on a pause or the network can't outlive it.
+func (a *App) downloadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(a.downloadsCtx, cancel)
+	return merged, func() {
+		stop()
+		cancel()
+	}
 }
 
 type timedResult[T any] struct {
@@ -160,7 +185,7 @@ func fetchCurrentIPInfo() (CurrentIPInfo, error) {
 		Reason      string `json:"reason"`
 	}
 
-	client := &http.Client{Timeout: 8 * time.Second}
+	client := backend.NewHTTPClient(8 * time.Second)
 	tryFetch := func(source, reqURL string, parse func(body []byte) (CurrentIPInfo, error)) (CurrentIPInfo, error) {
 		req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 		if err != nil {
@@ -266,6 +291,18 @@ func (a *App) getFirstArtist(artistString string) string {
 
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.startServices(ctx)
+	a.startClipboardWatcher(ctx)
+
+	if backend.GetAPIServerEnabledSetting() {
+		if err := a.startAPI(ctx, fmt.Sprintf("127.0.0.1:%d", backend.GetAPIServerPortSetting())); err != nil {
+			fmt.Printf("Failed to start API server: %v\n", err)
+		}
+	}
+}
+
+func (a *App) startServices(ctx context.Context) {
+	a.startedAt = time.Now()
 
 	if err := backend.InitHistoryDB("SpotiFLAC"); err != nil {
 		fmt.Printf("Failed to init history DB: %v\n", err)
@@ -276,17 +313,85 @@ func (a *App) startup(ctx context.Context) {
 	if err := backend.InitProviderPriorityDB(); err != nil {
 		fmt.Printf("Failed to init provider priority DB: %v\n", err)
 	}
+	if err := backend.InitWatchlistDB(); err != nil {
+		fmt.Printf("Failed to init watchlist DB: %v\n", err)
+	}
+	if err := backend.InitDataUsageDB(); err != nil {
+		fmt.Printf("Failed to init data usage DB: %v\n", err)
+	}
 	go func() {
 		if err := backend.PrimeTidalAPIList(); err != nil {
 			fmt.Printf("Failed to prime Tidal API list: %v\n", err)
 		}
 	}()
+
+	backend.StartSuspendMonitor(ctx, func(gap time.Duration) {
+		a.emitEvent("system-resumed", gap.Seconds())
+	})
+	backend.SetNetworkStatusHandler(func(online bool) {
+		a.emitEvent("network-status", map[string]bool{"online": online})
+	})
+
+	go backend.SweepOrphanedPartialDownloads(tempCleanupDirs())
+	go backend.SweepOrphanedOutputClaims(tempCleanupDirs())
+
+	backend.StartTempCleanupScheduler(ctx, tempCleanupDirs, func(result backend.TempCleanupResult) {
+		a.emitEvent("temp-cleanup", result)
+	})
+
+	backend.StartDownloadScheduler(ctx, func(status backend.DownloadScheduleStatus) {
+		a.emitEvent("download-schedule", status)
+	})
+	backend.StartDataUsageMonitor(ctx, func(usage backend.DataUsage) {
+		a.emitEvent("data-budget", usage)
+	})
+
+	backend.StartDiscordPresence(ctx)
+
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	a.cancelWatch = cancelWatch
+	a.startWatchScheduler(watchCtx)
+}
+
+func (a *App) startAPI(ctx context.Context, addr string) error {
+	apiCtx, cancelAPI := context.WithCancel(ctx)
+	if err := a.startAPIServer(apiCtx, addr); err != nil {
+		cancelAPI()
+		return err
+	}
+	a.cancelAPI = cancelAPI
+	return nil
+}
+
+func (a *App) emitEvent(name string, data ...interface{}) {
+	if a.ctx == nil || a.headless {
+		return
+	}
+	runtime.EventsEmit(a.ctx, name, data...)
 }
 
 func (a *App) shutdown(ctx context.Context) {
+	if !a.headless {
+		if _, err := backend.SaveQueueSession(); err != nil {
+			fmt.Printf("Warning: failed to save queue session: %v\n", err)
+		}
+	}
+	a.cancelDownloads()
+	if a.cancelWatch != nil {
+		a.cancelWatch()
+	}
+	if a.cancelAPI != nil {
+		a.cancelAPI()
+		<-a.apiDone
+	}
 	backend.CloseHistoryDB()
 	backend.CloseISRCCacheDB()
 	backend.CloseProviderPriorityDB()
+	backend.CloseWatchlistDB()
+	backend.CloseRenameLogDB()
+	backend.CloseDataUsageDB()
+	backend.WaitForHooks(30 * time.Second)
+	backend.FlushSubsonicScan()
 }
 
 type SpotifyMetadataRequest struct {
@@ -298,42 +403,48 @@ type SpotifyMetadataRequest struct {
 }
 
 type DownloadRequest struct {
-	Service              string `json:"service"`
-	Query                string `json:"query,omitempty"`
-	TrackName            string `json:"track_name,omitempty"`
-	ArtistName           string `json:"artist_name,omitempty"`
-	AlbumName            string `json:"album_name,omitempty"`
-	AlbumArtist          string `json:"album_artist,omitempty"`
-	ReleaseDate          string `json:"release_date,omitempty"`
-	CoverURL             string `json:"cover_url,omitempty"`
-	TidalAPIURL          string `json:"tidal_api_url,omitempty"`
-	OutputDir            string `json:"output_dir,omitempty"`
-	AudioFormat          string `json:"audio_format,omitempty"`
-	FilenameFormat       string `json:"filename_format,omitempty"`
-	TrackNumber          bool   `json:"track_number,omitempty"`
-	Position             int    `json:"position,omitempty"`
-	UseAlbumTrackNumber  bool   `json:"use_album_track_number,omitempty"`
-	SpotifyID            string `json:"spotify_id,omitempty"`
-	EmbedLyrics          bool   `json:"embed_lyrics,omitempty"`
-	EmbedMaxQualityCover bool   `json:"embed_max_quality_cover,omitempty"`
-	ServiceURL           string `json:"service_url,omitempty"`
-	Duration             int    `json:"duration,omitempty"`
-	ItemID               string `json:"item_id,omitempty"`
-	SpotifyTrackNumber   int    `json:"spotify_track_number,omitempty"`
-	SpotifyDiscNumber    int    `json:"spotify_disc_number,omitempty"`
-	SpotifyTotalTracks   int    `json:"spotify_total_tracks,omitempty"`
-	SpotifyTotalDiscs    int    `json:"spotify_total_discs,omitempty"`
-	ISRC                 string `json:"isrc,omitempty"`
-	Copyright            string `json:"copyright,omitempty"`
-	Publisher            string `json:"publisher,omitempty"`
-	Composer             string `json:"composer,omitempty"`
-	PlaylistName         string `json:"playlist_name,omitempty"`
-	PlaylistOwner        string `json:"playlist_owner,omitempty"`
-	AllowFallback        bool   `json:"allow_fallback"`
-	UseFirstArtistOnly   bool   `json:"use_first_artist_only,omitempty"`
-	UseSingleGenre       bool   `json:"use_single_genre,omitempty"`
-	EmbedGenre           bool   `json:"embed_genre,omitempty"`
-	Separator            string `json:"separator,omitempty"`
+	Service              string            `json:"service"`
+	Query                string            `json:"query,omitempty"`
+	TrackName            string            `json:"track_name,omitempty"`
+	ArtistName           string            `json:"artist_name,omitempty"`
+	AlbumName            string            `json:"album_name,omitempty"`
+	AlbumArtist          string            `json:"album_artist,omitempty"`
+	ReleaseDate          string            `json:"release_date,omitempty"`
+	CoverURL             string            `json:"cover_url,omitempty"`
+	TidalAPIURL          string            `json:"tidal_api_url,omitempty"`
+	OutputDir            string            `json:"output_dir,omitempty"`
+	AudioFormat          string            `json:"audio_format,omitempty"`
+	FilenameFormat       string            `json:"filename_format,omitempty"`
+	TrackNumber          bool              `json:"track_number,omitempty"`
+	Position             int               `json:"position,omitempty"`
+	UseAlbumTrackNumber  bool              `json:"use_album_track_number,omitempty"`
+	SpotifyID            string            `json:"spotify_id,omitempty"`
+	EmbedLyrics          bool              `json:"embed_lyrics,omitempty"`
+	EmbedMaxQualityCover bool              `json:"embed_max_quality_cover,omitempty"`
+	ServiceURL           string            `json:"service_url,omitempty"`
+	Duration             int               `json:"duration,omitempty"`
+	ItemID               string            `json:"item_id,omitempty"`
+	SpotifyTrackNumber   int               `json:"spotify_track_number,omitempty"`
+	SpotifyDiscNumber    int               `json:"spotify_disc_number,omitempty"`
+	SpotifyTotalTracks   int               `json:"spotify_total_tracks,omitempty"`
+	SpotifyTotalDiscs    int               `json:"spotify_total_discs,omitempty"`
+	ISRC                 string            `json:"isrc,omitempty"`
+	Copyright            string            `json:"copyright,omitempty"`
+	Publisher            string            `json:"publisher,omitempty"`
+	Composer             string            `json:"composer,omitempty"`
+	PlaylistName         string            `json:"playlist_name,omitempty"`
+	PlaylistOwner        string            `json:"playlist_owner,omitempty"`
+	AllowFallback        bool              `json:"allow_fallback"`
+	UseFirstArtistOnly   bool              `json:"use_first_artist_only,omitempty"`
+	UseSingleGenre       bool              `json:"use_single_genre,omitempty"`
+	EmbedGenre           bool              `json:"embed_genre,omitempty"`
+	Separator            string            `json:"separator,omitempty"`
+	ExtraTags            map[string]string `json:"extra_tags,omitempty"`
+	SourcePlaylist       string            `json:"source_playlist,omitempty"`
+	PlaylistPosition     int               `json:"playlist_position,omitempty"`
+	ArtistImageURL       string            `json:"artist_image_url,omitempty"`
+	ConvertTo            string            `json:"convert_to,omitempty"`
+	AllowedServices      []string          `json:"allowed_services,omitempty"`
 }
 
 type DownloadResponse struct {
@@ -342,7 +453,10 @@ type DownloadResponse struct {
 	File          string `json:"file,omitempty"`
 	Error         string `json:"error,omitempty"`
 	AlreadyExists bool   `json:"already_exists,omitempty"`
+	Skipped       bool   `json:"skipped,omitempty"`
 	ItemID        string `json:"item_id,omitempty"`
+	Service       string `json:"service,omitempty"`
+	Quality       string `json:"quality,omitempty"`
 }
 
 func cleanupInvalidDownloadArtifacts(paths ...string) {
@@ -373,6 +487,12 @@ func (a *App) GetStreamingURLs(spotifyTrackID string, region string) (string, er
 		return "", err
 	}
 
+	if platforms, err := client.GetPlatformLinksFromSpotify(spotifyTrackID, region); err == nil {
+		urls.Platforms = platforms
+	} else {
+		fmt.Printf("[GetStreamingURLs] Platform links unavailable: %v\n", err)
+	}
+
 	jsonData, err := json.Marshal(urls)
 	if err != nil {
 		return "", fmt.Errorf("failed to encode response: %v", err)
@@ -381,6 +501,24 @@ func (a *App) GetStreamingURLs(spotifyTrackID string, region string) (string, er
 	return string(jsonData), nil
 }
 
+func (a *App) GetPlatformLinks(spotifyTrackID string, region string) (string, error) {
+	if spotifyTrackID == "" {
+		return "", fmt.Errorf("spotify track ID is required")
+	}
+
+	platforms, err := backend.NewSongLinkClient().GetPlatformLinksFromSpotify(spotifyTrackID, region)
+	if err != nil {
+		return "", err
+	}
+
+	jsonData, err := json.Marshal(platforms)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode response: %v", err)
+	}
+
+	return string(jsonData), nil
+}
+
 func (a *App) GetSpotifyMetadata(req SpotifyMetadataRequest) (string, error) {
 	if req.URL == "" {
 		return "", fmt.Errorf("URL parameter is required")
@@ -473,16 +611,43 @@ func (a *App) SearchSpotifyByType(req SpotifySearchByTypeRequest) ([]backend.Sea
 }
 
 func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
+	return a.downloadTrack(context.Background(), req)
+}
+
+func (a *App) downloadTrack(ctx context.Context, req DownloadRequest) (response DownloadResponse, err error) {
+	ctx, cancel := a.downloadContext(ctx)
+	defer cancel()
 
-	if req.Service == "qobuz" && req.SpotifyID == "" {
+	defer func() {
+		recordDownloadOutcome(req, response, err)
+		discardFinishedQueueSession()
+		if err == nil && response.Success && !response.AlreadyExists && !response.Skipped {
+			backend.RequestSubsonicScan()
+		}
+	}()
+
+	if req.Service == "" {
+		req.Service = "tidal"
+	}
+	if !isServiceAllowed(req.Service, req.AllowedServices) {
 		return DownloadResponse{
 			Success: false,
-			Error:   "Spotify ID is required for Qobuz",
-		}, fmt.Errorf("spotify ID is required for Qobuz")
+			Error:   fmt.Sprintf("Service %s is not allowed for this download", req.Service),
+		}, fmt.Errorf("service %s is not allowed for this download", req.Service)
 	}
 
-	if req.Service == "" {
-		req.Service = "tidal"
+	serviceDownloader, ok := lookupServiceDownloader(req.Service)
+	if !ok {
+		return DownloadResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Unknown service: %s", req.Service),
+		}, fmt.Errorf("unknown service: %s", req.Service)
+	}
+	if err := serviceDownloader.Probe(req); err != nil {
+		return DownloadResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, err
 	}
 
 	if req.OutputDir == "" {
@@ -501,12 +666,12 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		req.AudioFormat = "LOSSLESS"
 	}
 
-	var err error
 	var filename string
 
 	if req.FilenameFormat == "" {
 		req.FilenameFormat = "title-artist"
 	}
+	applyMultiDiscLayout(&req)
 	shouldResolveISRC := strings.Contains(req.FilenameFormat, "{isrc}") || backend.GetExistingFileCheckModeSetting() == "isrc"
 	if req.ISRC == "" && shouldResolveISRC && req.SpotifyID != "" {
 		req.ISRC = backend.ResolveTrackISRC(req.SpotifyID)
@@ -523,9 +688,23 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 
 		backend.AddToQueue(itemID, req.TrackName, req.ArtistName, req.AlbumName, req.SpotifyID)
 	}
+	backend.SetDownloadItemContext(itemID, req.SpotifyID, req.OutputDir, req.SourcePlaylist, req.PlaylistPosition)
+
+	if backend.IsDownloadsPaused() {
+		fmt.Printf("Downloads paused, waiting to start %s - %s\n", req.ArtistName, req.TrackName)
+		if err := backend.WaitWhilePaused(ctx); err != nil {
+			backend.FailDownloadItem(itemID, "Download cancelled")
+			return DownloadResponse{
+				Success: false,
+				Error:   "Download cancelled",
+				ItemID:  itemID,
+			}, err
+		}
+	}
 
 	backend.SetDownloading(true)
 	backend.StartDownloadItem(itemID)
+	backend.SetDownloadItemCover(itemID, req.CoverURL)
 	defer backend.SetDownloading(false)
 
 	spotifyURL := ""
@@ -598,9 +777,14 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 
 	if req.TrackName != "" && req.ArtistName != "" {
 		expectedFilename := backend.BuildExpectedFilename(req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.FilenameFormat, req.PlaylistName, req.PlaylistOwner, req.TrackNumber, req.Position, req.SpotifyDiscNumber, req.UseAlbumTrackNumber, req.ISRC)
-		expectedPath := filepath.Join(req.OutputDir, expectedFilename)
+		expectedPath := backend.FitPathLength(filepath.Join(req.OutputDir, expectedFilename), backend.GetMaxPathLengthSetting())
 
 		if !backend.GetRedownloadWithSuffixSetting() {
+			if target := resolveConvertTarget(req.ConvertTo); target != "" {
+				if fileInfo, err := os.Stat(convertedTrackPath(expectedPath, target)); err == nil && fileInfo.Size() > 100*1024 {
+					expectedPath = convertedTrackPath(expectedPath, target)
+				}
+			}
 			if fileInfo, err := os.Stat(expectedPath); err == nil && fileInfo.Size() > 100*1024 {
 
 				backend.SkipDownloadItem(itemID, expectedPath)
@@ -615,31 +799,45 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		}
 	}
 
+	if song := backend.FindOwnedSubsonicTrack(req.TrackName, req.ArtistName, req.Duration); song != nil {
+		fmt.Printf("Skipping %s - %s: already on Subsonic server (%s)\n", req.ArtistName, req.TrackName, song.Path)
+		backend.SkipDownloadItem(itemID, "")
+		return DownloadResponse{
+			Success: true,
+			Message: "Already on Subsonic server",
+			Skipped: true,
+			ItemID:  itemID,
+		}, nil
+	}
+
 	lyricsChan := make(chan string, 1)
 	isrcChan := make(chan string, 1)
 
-	if req.SpotifyID != "" {
-		if req.EmbedLyrics {
-			go func() {
-				client := backend.NewLyricsClient()
-				resp, _, err := client.FetchLyricsAllSources(req.SpotifyID, req.TrackName, req.ArtistName, req.AlbumName, req.Duration)
-				if err == nil && resp != nil && len(resp.Lines) > 0 {
-					lrc := client.ConvertToLRC(resp, req.TrackName, req.ArtistName)
-					lyricsChan <- lrc
-				} else {
-					lyricsChan <- ""
-				}
-			}()
-		} else {
-			close(lyricsChan)
-		}
+	if !req.EmbedLyrics {
+		req.EmbedLyrics = backend.GetEmbedLyricsSetting()
+	}
+	if req.EmbedLyrics && req.TrackName != "" {
+		go func() {
+			client := backend.NewLyricsClient()
+			resp, _, err := client.FetchLyricsAllSources(req.SpotifyID, req.TrackName, req.ArtistName, req.AlbumName, req.Duration)
+			if err == nil && resp != nil && len(resp.Lines) > 0 {
+				lrc := client.ConvertToLRC(resp, req.TrackName, req.ArtistName)
+				lyricsChan <- lrc
+			} else {
+				lyricsChan <- ""
+			}
+		}()
+	} else {
+		close(lyricsChan)
+	}
 
-		if req.Service == "qobuz" {
+	if req.SpotifyID != "" {
+		if serviceNeedsISRC(serviceDownloader) && strings.TrimSpace(req.ISRC) == "" {
 			go func() {
 				client := backend.NewSongLinkClient()
 				isrc, err := client.GetISRCDirect(req.SpotifyID)
 				if err != nil {
-					fmt.Printf("Warning: failed to resolve ISRC for Qobuz: %v\n", err)
+					fmt.Printf("Warning: failed to resolve ISRC for %s: %v\n", serviceDownloader.Name(), err)
 				}
 				isrcChan <- isrc
 			}()
@@ -647,58 +845,41 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 			close(isrcChan)
 		}
 	} else {
-		close(lyricsChan)
 		close(isrcChan)
 	}
 
-	switch req.Service {
-	case "amazon":
-
-		downloader := backend.NewAmazonDownloader()
-		if req.ServiceURL != "" {
-			filename, err = downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.PlaylistName, req.PlaylistOwner, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.CoverURL, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, metadataSeparator, req.ISRC, spotifyURL, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
-		} else {
-			filename, err = downloader.DownloadBySpotifyID(req.SpotifyID, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.PlaylistName, req.PlaylistOwner, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.CoverURL, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, metadataSeparator, req.ISRC, spotifyURL, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
-		}
+	var resolvedISRC string
+	var isrcOnce sync.Once
+	downloadCtx := serviceDownloadContext{
+		MetadataSeparator: metadataSeparator,
+		SpotifyURL:        spotifyURL,
+		ISRC: func() string {
+			isrcOnce.Do(func() {
+				resolvedISRC = <-isrcChan
+			})
+			return resolvedISRC
+		},
+	}
 
-	case "tidal":
-		if req.TidalAPIURL == "" || req.TidalAPIURL == "auto" {
-			downloader := backend.NewTidalDownloader("")
-			if req.ServiceURL != "" {
-				filename, err = downloader.DownloadByURLWithFallback(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, metadataSeparator, req.ISRC, spotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
-			} else {
-				filename, err = downloader.Download(req.SpotifyID, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, metadataSeparator, req.ISRC, spotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
-			}
-		} else {
-			downloader := backend.NewTidalDownloader(req.TidalAPIURL)
-			if req.ServiceURL != "" {
-				filename, err = downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, metadataSeparator, req.ISRC, spotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
-			} else {
-				filename, err = downloader.Download(req.SpotifyID, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, metadataSeparator, req.ISRC, spotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
-			}
+	if backend.IsNetworkOffline() {
+		fmt.Printf("Waiting for the network before downloading %s - %s\n", req.ArtistName, req.TrackName)
+		if err := backend.WaitForNetwork(ctx); err != nil {
+			backend.FailDownloadItem(itemID, "Download cancelled")
+			return DownloadResponse{
+				Success: false,
+				Error:   "Download cancelled",
+				ItemID:  itemID,
+			}, err
 		}
+	}
 
-	case "qobuz":
-
-		isrc := strings.TrimSpace(req.ISRC)
-		if isrc == "" {
-			fmt.Println("Waiting for ISRC (Qobuz dependency)...")
-			isrc = <-isrcChan
-		}
-		downloader := backend.NewQobuzDownloader()
-		quality := req.AudioFormat
-		if quality == "" {
-			quality = "6"
+	filename, err = serviceDownloader.Download(req, downloadCtx)
+	if err != nil && backend.ReportNetworkError(err) {
+		fmt.Printf("Download of %s - %s failed while offline, retrying when the network returns\n", req.ArtistName, req.TrackName)
+		if waitErr := backend.WaitForNetwork(ctx); waitErr == nil {
+			filename, err = serviceDownloader.Download(req, downloadCtx)
 		}
-		filename, err = downloader.DownloadTrackWithISRC(isrc, req.OutputDir, quality, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, metadataSeparator, spotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
-
-	default:
-		return DownloadResponse{
-			Success: false,
-			Error:   fmt.Sprintf("Unknown service: %s", req.Service),
-		}, fmt.Errorf("unknown service: %s", req.Service)
 	}
-
 	if err != nil {
 		backend.FailDownloadItem(itemID, fmt.Sprintf("Download failed: %v", err))
 
@@ -725,8 +906,10 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		filename = strings.TrimPrefix(filename, "EXISTS:")
 	}
 
+	validationWarning := ""
 	if !alreadyExists {
-		validated, validationErr := backend.ValidateDownloadedTrackDuration(filename, req.Duration)
+		validated, warning, validationErr := backend.ValidateDownloadedTrack(filename, req.Duration, req.ISRC, req.SpotifyID)
+		validationWarning = warning
 		if validationErr != nil {
 			cleanupInvalidDownloadArtifacts(filename)
 			errorMessage := validationErr.Error()
@@ -742,7 +925,23 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		}
 	}
 
-	if !alreadyExists && req.SpotifyID != "" && req.EmbedLyrics && (strings.HasSuffix(filename, ".flac") || strings.HasSuffix(filename, ".mp3") || strings.HasSuffix(filename, ".m4a")) {
+	if rule := backend.GetMinQualitySetting(); !alreadyExists && rule.Enabled() {
+		if shortfall := backend.CheckMinQuality(filename, serviceIsLossy(serviceDownloader), rule); shortfall != "" {
+			if rule.Skip {
+				cleanupInvalidDownloadArtifacts(filename)
+				errorMessage := fmt.Sprintf("%s: %s", backend.PendingBetterSourcePrefix, shortfall)
+				backend.FailDownloadItem(itemID, errorMessage)
+				return DownloadResponse{
+					Success: false,
+					Error:   errorMessage,
+					ItemID:  itemID,
+				}, errors.New(errorMessage)
+			}
+			validationWarning = shortfall
+		}
+	}
+
+	if !alreadyExists && req.EmbedLyrics && req.TrackName != "" && (strings.HasSuffix(filename, ".flac") || strings.HasSuffix(filename, ".mp3") || strings.HasSuffix(filename, ".m4a")) {
 		fmt.Printf("\nWaiting for lyrics fetch to complete...\n")
 		lyrics := <-lyricsChan
 		if lyrics != "" {
@@ -768,7 +967,51 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		}
 	}
 
+	playlistTagName := strings.TrimSpace(req.SourcePlaylist)
+	if playlistTagName == "" {
+		playlistTagName = strings.TrimSpace(req.PlaylistName)
+	}
+	playlistPosition := req.PlaylistPosition
+	if playlistPosition == 0 && playlistTagName != "" {
+		playlistPosition = req.Position
+	}
+	if playlistTagName != "" && backend.GetEmbedPlaylistTagsSetting() {
+		extraTags := make(map[string]string, len(req.ExtraTags)+2)
+		extraTags["PLAYLIST"] = playlistTagName
+		if playlistPosition > 0 {
+			extraTags["PLAYLISTPOSITION"] = strconv.Itoa(playlistPosition)
+		}
+		for key, value := range req.ExtraTags {
+			extraTags[key] = value
+		}
+		req.ExtraTags = extraTags
+	}
+
+	if !alreadyExists && len(req.ExtraTags) > 0 {
+		if err := backend.EmbedExtraTags(filename, req.ExtraTags); err != nil {
+			fmt.Printf("Warning: failed to embed extra tags: %v\n", err)
+		} else {
+			fmt.Printf("Embedded %d extra tag(s) into: %s\n", len(req.ExtraTags), filename)
+		}
+	}
+
+	if maxBitDepth, maxSampleRate := backend.GetMaxQualitySetting(); !alreadyExists && maxBitDepth > 0 && strings.EqualFold(filepath.Ext(filename), ".flac") {
+		if _, err := backend.DownconvertFLAC(filename, maxBitDepth, maxSampleRate); err != nil {
+			fmt.Printf("Warning: failed to downconvert %s: %v\n", filepath.Base(filename), err)
+		}
+	}
+
+	if target := resolveConvertTarget(req.ConvertTo); !alreadyExists && target != "" {
+		filename = convertDownloadedTrack(filename, target)
+	}
+
 	message := "Download completed successfully"
+	if serviceIsLossy(serviceDownloader) {
+		message = "Download completed from a LOSSY source"
+	}
+	if validationWarning != "" {
+		message = fmt.Sprintf("Download completed with warning: %s", validationWarning)
+	}
 	if alreadyExists {
 		message = "File already exists"
 		backend.SkipDownloadItem(itemID, filename)
@@ -782,6 +1025,19 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 			}
 		}
 
+		if req.CoverURL != "" && req.AlbumName != "" {
+			if coverErr := backend.NewCoverClient().SaveAlbumCoverFiles(albumFolderForTrack(req, filename), req.CoverURL); coverErr != nil {
+				fmt.Printf("Warning: failed to save album cover: %v\n", coverErr)
+			}
+		}
+		saveAlbumExtras(req, filename)
+
+		if mode := backend.GetChecksumModeSetting(); mode != "" {
+			if err := backend.RecordChecksum(filename, mode); err != nil {
+				fmt.Printf("Warning: failed to record checksum: %v\n", err)
+			}
+		}
+
 		if fileInfo, statErr := os.Stat(filename); statErr == nil {
 			finalSize := float64(fileInfo.Size()) / (1024 * 1024)
 			backend.CompleteDownloadItem(itemID, filename, finalSize)
@@ -791,8 +1047,9 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		}
 
 		historySource := req.Service
+		lossySource := serviceIsLossy(serviceDownloader)
 
-		go func(fPath, track, artist, album, sID, cover, format, source string) {
+		go func(fPath, track, artist, album, sID, cover, format, source, playlist string, playlistPosition int) {
 			time.Sleep(2 * time.Second)
 
 			quality := "Unknown"
@@ -810,6 +1067,9 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 			} else {
 				fmt.Printf("[History] Failed to get metadata for %s: %v\n", fPath, err)
 			}
+			if lossySource {
+				quality = "LOSSY " + quality
+			}
 
 			item := backend.HistoryItem{
 				SpotifyID:   sID,
@@ -821,6 +1081,12 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 				Quality:     quality,
 				Path:        fPath,
 				Source:      source,
+				Playlist:    playlist,
+				PlaylistPos: playlistPosition,
+			}
+
+			if fullMeta, err := backend.ExtractFullMetadataFromFile(fPath); err == nil {
+				item.Genre = fullMeta.Genre
 			}
 
 			item.Format = strings.ToUpper(strings.TrimSpace(format))
@@ -837,7 +1103,7 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 			}
 
 			backend.AddHistoryItem(item, "SpotiFLAC")
-		}(filename, req.TrackName, req.ArtistName, req.AlbumName, req.SpotifyID, req.CoverURL, req.AudioFormat, historySource)
+		}(filename, req.TrackName, req.ArtistName, req.AlbumName, req.SpotifyID, req.CoverURL, req.AudioFormat, historySource, playlistTagName, playlistPosition)
 	}
 
 	return DownloadResponse{
@@ -846,9 +1112,28 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		File:          filename,
 		AlreadyExists: alreadyExists,
 		ItemID:        itemID,
+		Service:       req.Service,
+		Quality:       deliveredQuality(filename, serviceIsLossy(serviceDownloader), req.AudioFormat),
 	}, nil
 }
 
+// deliveredQuality describes the audio that actually landed on disk, falling
+// back to the requested tier when the file can't be probed.
+func deliveredQuality(path string, lossy bool, requested string) string {
+	meta, err := backend.GetTrackMetadata(path)
+	if err != nil || meta.SampleRate == 0 {
+		return requested
+	}
+	rate := strconv.FormatFloat(float64(meta.SampleRate)/1000, 'f', -1, 64)
+	if lossy || meta.BitsPerSample == 0 {
+		if meta.Bitrate > 0 {
+			return fmt.Sprintf("%dkbps/%skHz", meta.Bitrate/1000, rate)
+		}
+		return rate + "kHz"
+	}
+	return fmt.Sprintf("%d-bit/%skHz", meta.BitsPerSample, rate)
+}
+
 func (a *App) OpenFolder(path string) error {
 	if path == "" {
 		return fmt.Errorf("path is required")
@@ -902,14 +1187,34 @@ func (a *App) ClearAllDownloads() {
 
 func (a *App) AddToDownloadQueue(spotifyID, trackName, artistName, albumName string) string {
 	itemID := fmt.Sprintf("%s-%d", spotifyID, time.Now().UnixNano())
-	backend.AddToQueue(itemID, trackName, artistName, albumName, "")
+	backend.AddToQueue(itemID, trackName, artistName, albumName, spotifyID)
 	return itemID
 }
 
+func (a *App) SetDownloadItemPlaylist(itemID, playlistName string, position int) {
+	backend.SetDownloadItemContext(itemID, "", "", playlistName, position)
+}
+
 func (a *App) MarkDownloadItemFailed(itemID, errorMsg string) {
 	backend.FailDownloadItem(itemID, errorMsg)
 }
 
+func (a *App) SetDownloadPriority(itemID, priority string) error {
+	level, err := backend.ParseDownloadPriority(priority)
+	if err != nil {
+		return err
+	}
+	return backend.SetDownloadItemPriority(itemID, level)
+}
+
+func (a *App) MoveDownloadToFront(itemID string) error {
+	return backend.MoveDownloadItemToFront(itemID)
+}
+
+func (a *App) MoveAlbumToFront(albumName string) (int, error) {
+	return backend.MoveAlbumToFront(albumName)
+}
+
 func (a *App) CancelAllQueuedItems() {
 	backend.CancelAllQueuedItems()
 }
@@ -1052,7 +1357,7 @@ func (a *App) CheckCustomTidalAPI(apiURL string) bool {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/146.0.0.0 Safari/537.36")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{Timeout: 12 * time.Second}
+	client := backend.NewHTTPClient(12 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Printf("[CheckCustomTidalAPI] Probe request failed for %s: %v\n", apiURL, err)
@@ -1151,11 +1456,24 @@ func buildQobuzStatusCheckURL(apiBase string) string {
 }
 
 func buildAmazonStatusCheckURLs(apiURL string) []string {
-	baseURL := strings.TrimRight(strings.TrimSpace(apiURL), "/")
-	if baseURL == "" {
-		baseURL = backend.GetAmazonMusicAPIBaseURL()
+	if baseURL := strings.TrimRight(strings.TrimSpace(apiURL), "/"); baseURL != "" {
+		return []string{fmt.Sprintf("%s/status", baseURL)}
+	}
+
+	mirrors := backend.GetAmazonAPIAttemptList()
+	urls := make([]string, 0, len(mirrors))
+	for _, baseURL := range mirrors {
+		urls = append(urls, fmt.Sprintf("%s/status", baseURL))
+	}
+	return urls
+}
+
+func (a *App) GetAmazonMirrorHealth() (string, error) {
+	jsonData, err := json.Marshal(backend.GetAmazonMirrorHealth())
+	if err != nil {
+		return "", fmt.Errorf("failed to encode response: %v", err)
 	}
-	return []string{fmt.Sprintf("%s/status", baseURL)}
+	return string(jsonData), nil
 }
 
 func buildLRCLIBStatusCheckURLs(apiURL string) []string {
@@ -1214,7 +1532,7 @@ func checkGroupedAPIStatus(apiType string, checkURLs []string) bool {
 }
 
 func checkSingleAPIStatus(apiType string, checkURL string) bool {
-	client := &http.Client{Timeout: 4 * time.Second}
+	client := backend.NewHTTPClient(4 * time.Second)
 	if (apiType == "qobuz" || apiType == "qbz") && strings.EqualFold(strings.TrimSpace(checkURL), strings.TrimSpace(backend.GetQobuzMusicDLDownloadAPIURL())) {
 		return backend.CheckQobuzMusicDLStatus(client)
 	}
@@ -1259,6 +1577,14 @@ func (a *App) GetDownloadHistory() ([]backend.HistoryItem, error) {
 	return backend.GetHistoryItems("SpotiFLAC")
 }
 
+func (a *App) GetDownloadStatistics(limit int) (*backend.DownloadStatistics, error) {
+	return backend.GetDownloadStatistics("SpotiFLAC", limit)
+}
+
+func (a *App) GetDataUsage() (backend.DataUsage, error) {
+	return backend.GetDataUsage()
+}
+
 func (a *App) ClearDownloadHistory() error {
 	return backend.ClearHistory("SpotiFLAC")
 }
@@ -1579,6 +1905,26 @@ func (a *App) CheckTrackAvailability(spotifyTrackID string) (string, error) {
 	})
 }
 
+func (a *App) CheckAvailability(spotifyTrackID string, isrc string) (string, error) {
+	if spotifyTrackID == "" && isrc == "" {
+		return "", fmt.Errorf("spotify track ID or ISRC is required")
+	}
+
+	return runWithTimeout(checkOperationTimeout, func() (string, error) {
+		matrix, err := backend.CheckAvailability(spotifyTrackID, isrc)
+		if err != nil {
+			return "", err
+		}
+
+		jsonData, err := json.Marshal(matrix)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode response: %v", err)
+		}
+
+		return string(jsonData), nil
+	})
+}
+
 func (a *App) IsFFmpegInstalled() (bool, error) {
 	return backend.IsFFmpegInstalled()
 }
@@ -1651,22 +1997,49 @@ func (a *App) InstallFFmpegWithBrew() InstallFFmpegWithBrewResponse {
 }
 
 type ConvertAudioRequest struct {
-	InputFiles   []string `json:"input_files"`
-	OutputFormat string   `json:"output_format"`
-	Bitrate      string   `json:"bitrate"`
-	Codec        string   `json:"codec"`
+	InputFiles     []string `json:"input_files"`
+	OutputFormat   string   `json:"output_format"`
+	Bitrate        string   `json:"bitrate"`
+	Codec          string   `json:"codec"`
+	DeleteOriginal bool     `json:"delete_original,omitempty"`
+	LoudnessTarget float64  `json:"loudness_target,omitempty"`
 }
 
 func (a *App) ConvertAudio(req ConvertAudioRequest) ([]backend.ConvertAudioResult, error) {
 	backendReq := backend.ConvertAudioRequest{
-		InputFiles:   req.InputFiles,
-		OutputFormat: req.OutputFormat,
-		Bitrate:      req.Bitrate,
-		Codec:        req.Codec,
+		InputFiles:     req.InputFiles,
+		OutputFormat:   req.OutputFormat,
+		Bitrate:        req.Bitrate,
+		Codec:          req.Codec,
+		DeleteOriginal: req.DeleteOriginal,
+		LoudnessTarget: req.LoudnessTarget,
 	}
 	return backend.ConvertAudio(backendReq)
 }
 
+func (a *App) GetAudioFileInfo(filePath string) (*backend.AudioFileInfo, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	return backend.GetAudioFileInfo(filePath)
+}
+
+func (a *App) GetAudioFilesInfo(filePaths []string) []*backend.AudioFileInfo {
+	results := make([]*backend.AudioFileInfo, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		info, err := backend.GetAudioFileInfo(filePath)
+		if err != nil {
+			info = &backend.AudioFileInfo{
+				Path:       filePath,
+				Filename:   filepath.Base(filePath),
+				ProbeError: err.Error(),
+			}
+		}
+		results = append(results, info)
+	}
+	return results
+}
+
 type ResampleAudioRequest struct {
 	InputFiles []string `json:"input_files"`
 	SampleRate string   `json:"sample_rate"`
@@ -1727,6 +2100,137 @@ func (a *App) RenameFilesByMetadata(files []string, format string) []backend.Ren
 	return backend.RenameFiles(files, format)
 }
 
+func (a *App) OrganizeLibrary(rootDir, folderTemplate string, dryRun bool) (*backend.OrganizeResult, error) {
+	if rootDir == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+	return backend.OrganizeLibrary(rootDir, folderTemplate, dryRun)
+}
+
+func (a *App) startLibraryScan() (context.Context, context.CancelFunc) {
+	a.scanMu.Lock()
+	defer a.scanMu.Unlock()
+
+	if a.cancelScan != nil {
+		a.cancelScan()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelScan = cancel
+	return ctx, cancel
+}
+
+func (a *App) CancelLibraryScan() {
+	a.scanMu.Lock()
+	defer a.scanMu.Unlock()
+
+	if a.cancelScan != nil {
+		a.cancelScan()
+		a.cancelScan = nil
+	}
+}
+
+func (a *App) VerifyLibrary(rootDir string, opts backend.LibraryVerifyOptions) (*backend.LibraryVerifyResult, error) {
+	if rootDir == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+
+	ctx, cancel := a.startLibraryScan()
+	defer cancel()
+
+	return backend.VerifyLibrary(ctx, rootDir, opts, func(progress backend.LibraryScanProgress) {
+		a.emitEvent("library-scan-progress", progress)
+	})
+}
+
+func (a *App) FindHiResUpgrades(rootDir string) (*backend.HiResUpgradeScan, error) {
+	if rootDir == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+
+	ctx, cancel := a.startLibraryScan()
+	defer cancel()
+
+	return backend.FindHiResUpgrades(ctx, rootDir, 0, func(progress backend.LibraryScanProgress) {
+		a.emitEvent("library-scan-progress", progress)
+	})
+}
+
+func (a *App) UpgradeToHiRes(paths []string, archiveDir string) ([]backend.HiResUpgradeResult, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files provided")
+	}
+	return backend.UpgradeFilesToHiRes(paths, archiveDir), nil
+}
+
+func (a *App) UndoLastRename() ([]backend.RenameResult, error) {
+	return backend.UndoLastRename()
+}
+
+func (a *App) WriteFileMetadata(filePath string, metadata backend.AudioMetadata) error {
+	if filePath == "" {
+		return fmt.Errorf("file path is required")
+	}
+	return backend.WriteAudioMetadata(filePath, metadata)
+}
+
+func (a *App) WriteFilesMetadata(requests []backend.MetadataWriteRequest) []backend.MetadataWriteResult {
+	return backend.WriteAudioMetadataBatch(requests)
+}
+
+func (a *App) WriteFilesMetadataFields(files []string, fields map[string]string) []backend.MetadataWriteResult {
+	return backend.WriteAudioMetadataFields(files, fields)
+}
+
+func (a *App) AuditAlbumFolder(folderPath string) (*backend.AlbumAuditResult, error) {
+	if folderPath == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+	return backend.AuditAlbumFolder(folderPath)
+}
+
+func (a *App) NormalizeAlbumFolder(folderPath string, values map[string]string) (*backend.AlbumNormalizeResult, error) {
+	if folderPath == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+	return backend.NormalizeAlbumFolder(folderPath, values)
+}
+
+func (a *App) PackageAlbumFolder(folderPath, format string) (*backend.AlbumPackageResult, error) {
+	if folderPath == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+	return backend.PackageAlbumFolder(folderPath, format, "")
+}
+
+func (a *App) CheckAlbumGapless(folderPath string, repair bool) (*backend.GaplessReport, error) {
+	if folderPath == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+	return backend.CheckAlbumFolderGapless(folderPath, repair)
+}
+
+func (a *App) CreateAlbumCueSheet(folderPath string) (string, error) {
+	if folderPath == "" {
+		return "", fmt.Errorf("folder path is required")
+	}
+	return backend.WriteAlbumFolderCueSheet(folderPath)
+}
+
+func tempCleanupDirs() []string {
+	return []string{loadBatchDownloadSettings().DownloadPath}
+}
+
+func (a *App) VerifyChecksums(folderPath string) (backend.ChecksumReport, error) {
+	if folderPath == "" {
+		return backend.ChecksumReport{}, fmt.Errorf("folder path is required")
+	}
+	return backend.VerifyChecksums(folderPath, nil)
+}
+
+func (a *App) CleanupTempFiles() backend.TempCleanupResult {
+	return backend.CleanupStaleTempFiles(tempCleanupDirs(), backend.GetTempCleanupMaxAgeSetting())
+}
+
 func (a *App) ReadTextFile(filePath string) (string, error) {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -1969,7 +2473,7 @@ func (a *App) CheckFilesExistence(outputDir string, rootDir string, tracks []Che
 				targetDir = filepath.Join(outputDir, t.RelativePath)
 			}
 
-			expectedPath := filepath.Join(targetDir, expectedFilename)
+			expectedPath := backend.FitPathLength(filepath.Join(targetDir, expectedFilename), backend.GetMaxPathLengthSetting())
 			if redownloadWithSuffix {
 				expectedPath, _ = backend.ResolveOutputPathForDownload(expectedPath, true)
 				resultsChan <- result{index: idx, result: res}
@@ -2058,7 +2562,14 @@ func (a *App) SaveSettings(settings map[string]interface{}) error {
 		return err
 	}
 
-	return os.WriteFile(configPath, data, 0644)
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return err
+	}
+	a.GetDownloadScheduleStatus()
+	if usage, changed := backend.ApplyDataBudget(); changed {
+		a.emitEvent("data-budget", usage)
+	}
+	return nil
 }
 
 func (a *App) SaveFonts(fonts []map[string]interface{}) error {
@@ -2131,6 +2642,19 @@ func (a *App) LoadFonts() ([]map[string]interface{}, error) {
 	return fonts, nil
 }
 
+func (a *App) CreatePlaylistManifest(name string, outputDir string, format string, entries []backend.PlaylistManifestEntry) (string, error) {
+	format = backend.NormalizeManifestFormat(format)
+	if format == "" {
+		return "", fmt.Errorf("manifest format must be csv or json")
+	}
+
+	path := backend.BuildPlaylistManifestPath(outputDir, name, format)
+	if err := backend.WritePlaylistManifest(path, backend.PlaylistManifest{Name: name, Entries: entries}); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 func (a *App) CheckFFmpegInstalled() (bool, error) {
 	return backend.IsFFmpegInstalled()
 }
@@ -0,0 +1,279 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+)
+
+const (
+	albumAuditFieldAlbum       = "ALBUM"
+	albumAuditFieldAlbumArtist = "ALBUMARTIST"
+	albumAuditFieldDate        = "DATE"
+	albumAuditFieldDiscNumber  = "DISCNUMBER"
+)
+
+var albumAuditFields = []string{
+	albumAuditFieldAlbum,
+	albumAuditFieldAlbumArtist,
+	albumAuditFieldDate,
+	albumAuditFieldDiscNumber,
+}
+
+type AlbumTagValue struct {
+	Value string   `json:"value"`
+	Count int      `json:"count"`
+	Files []string `json:"files"`
+}
+
+type AlbumTagInconsistency struct {
+	Field     string          `json:"field"`
+	Values    []AlbumTagValue `json:"values"`
+	Suggested string          `json:"suggested"`
+}
+
+type AlbumAuditResult struct {
+	Folder          string                  `json:"folder"`
+	Files           []string                `json:"files"`
+	Consistent      bool                    `json:"consistent"`
+	Inconsistencies []AlbumTagInconsistency `json:"inconsistencies,omitempty"`
+	Errors          []string                `json:"errors,omitempty"`
+}
+
+type AlbumNormalizeResult struct {
+	Folder  string            `json:"folder"`
+	Values  map[string]string `json:"values"`
+	Updated []string          `json:"updated"`
+	Errors  []string          `json:"errors,omitempty"`
+}
+
+func listAlbumFolderAudioFiles(folder string) ([]string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read folder: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".flac", ".mp3", ".m4a":
+			files = append(files, filepath.Join(folder, entry.Name()))
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func albumAuditFieldValue(metadata *AudioMetadata, field string) string {
+	switch field {
+	case albumAuditFieldAlbum:
+		return strings.TrimSpace(metadata.Album)
+	case albumAuditFieldAlbumArtist:
+		return strings.TrimSpace(metadata.AlbumArtist)
+	case albumAuditFieldDate:
+		return strings.TrimSpace(metadata.Year)
+	case albumAuditFieldDiscNumber:
+		if metadata.DiscNumber > 0 {
+			return strconv.Itoa(metadata.DiscNumber)
+		}
+	}
+	return ""
+}
+
+func isGenuineMultiDisc(metadataByFile map[string]*AudioMetadata) bool {
+	seenTracks := make(map[int]int)
+	for _, metadata := range metadataByFile {
+		if metadata.TrackNumber <= 0 {
+			continue
+		}
+		if disc, ok := seenTracks[metadata.TrackNumber]; ok && disc != metadata.DiscNumber {
+			return true
+		}
+		seenTracks[metadata.TrackNumber] = metadata.DiscNumber
+	}
+	return false
+}
+
+func AuditAlbumFolder(folder string) (*AlbumAuditResult, error) {
+	files, err := listAlbumFolderAudioFiles(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AlbumAuditResult{
+		Folder:     folder,
+		Files:      files,
+		Consistent: true,
+	}
+
+	metadataByFile := make(map[string]*AudioMetadata, len(files))
+	for _, file := range files {
+		metadata, err := ReadAudioMetadata(file)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", filepath.Base(file), err))
+			continue
+		}
+		metadataByFile[file] = metadata
+	}
+
+	multiDisc := isGenuineMultiDisc(metadataByFile)
+
+	for _, field := range albumAuditFields {
+		if field == albumAuditFieldDiscNumber && multiDisc {
+			continue
+		}
+
+		valueIndex := make(map[string]int)
+		var values []AlbumTagValue
+		for _, file := range files {
+			metadata, ok := metadataByFile[file]
+			if !ok {
+				continue
+			}
+
+			value := albumAuditFieldValue(metadata, field)
+			idx, ok := valueIndex[value]
+			if !ok {
+				idx = len(values)
+				valueIndex[value] = idx
+				values = append(values, AlbumTagValue{Value: value})
+			}
+			values[idx].Count++
+			values[idx].Files = append(values[idx].Files, file)
+		}
+
+		if len(values) <= 1 {
+			continue
+		}
+
+		sort.SliceStable(values, func(i, j int) bool {
+			return values[i].Count > values[j].Count
+		})
+
+		suggested := ""
+		for _, value := range values {
+			if value.Value != "" {
+				suggested = value.Value
+				break
+			}
+		}
+
+		result.Consistent = false
+		result.Inconsistencies = append(result.Inconsistencies, AlbumTagInconsistency{
+			Field:     field,
+			Values:    values,
+			Suggested: suggested,
+		})
+	}
+
+	return result, nil
+}
+
+func NormalizeAlbumFolder(folder string, overrides map[string]string) (*AlbumNormalizeResult, error) {
+	audit, err := AuditAlbumFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, inconsistency := range audit.Inconsistencies {
+		if inconsistency.Suggested != "" {
+			values[inconsistency.Field] = inconsistency.Suggested
+		}
+	}
+	for key, value := range overrides {
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		for _, field := range albumAuditFields {
+			if key == field {
+				values[key] = value
+			}
+		}
+	}
+
+	result := &AlbumNormalizeResult{
+		Folder: folder,
+		Values: values,
+	}
+	if len(values) == 0 {
+		return result, nil
+	}
+
+	for _, file := range audit.Files {
+		if err := writeAlbumTags(file, values); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", filepath.Base(file), err))
+			continue
+		}
+		result.Updated = append(result.Updated, file)
+	}
+
+	return result, nil
+}
+
+func writeAlbumTags(filePath string, values map[string]string) error {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		return embedExtraTagsToFLAC(filePath, values)
+	case ".mp3":
+		return writeMP3AlbumTags(filePath, values)
+	case ".m4a":
+		ffmpegKeys := map[string]string{
+			albumAuditFieldAlbum:       "ALBUM",
+			albumAuditFieldAlbumArtist: "ALBUM_ARTIST",
+			albumAuditFieldDate:        "DATE",
+			albumAuditFieldDiscNumber:  "DISC",
+		}
+		tags := make(map[string]string, len(values))
+		for key, value := range values {
+			if ffmpegKey, ok := ffmpegKeys[key]; ok {
+				tags[ffmpegKey] = value
+			}
+		}
+		return embedExtraTagsToM4A(filePath, tags)
+	default:
+		return fmt.Errorf("unsupported file format: %s", filepath.Ext(filePath))
+	}
+}
+
+func writeMP3AlbumTags(filePath string, values map[string]string) error {
+	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+	if err != nil {
+		return fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	for key, value := range values {
+		switch key {
+		case albumAuditFieldAlbum:
+			tag.SetAlbum(value)
+		case albumAuditFieldAlbumArtist:
+			addMP3TextFrame(tag, "TPE2", value)
+		case albumAuditFieldDate:
+			if tag.Version() == 4 {
+				tag.SetYear(value)
+			} else {
+				tag.SetYear(extractYear(value))
+			}
+		case albumAuditFieldDiscNumber:
+			addMP3TextFrame(tag, tag.CommonID("Part of a set"), value)
+		}
+	}
+
+	if err := tag.Save(); err != nil {
+		return fmt.Errorf("failed to save MP3 tags: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	deezerArtistSearchURL = "https://api.deezer.com/search/artist"
+	artistImageFilename   = "artist.jpg"
+	qobuzBookletFormatID  = 21
+)
+
+var (
+	albumExtrasMu      sync.Mutex
+	albumExtrasClaimed = make(map[string]bool)
+)
+
+func claimAlbumExtra(kind, dir string) bool {
+	albumExtrasMu.Lock()
+	defer albumExtrasMu.Unlock()
+
+	key := kind + "|" + filepath.Clean(dir)
+	if albumExtrasClaimed[key] {
+		return false
+	}
+	albumExtrasClaimed[key] = true
+	return true
+}
+
+func FindDeezerArtistImage(artistName string) (string, error) {
+	artistName = strings.TrimSpace(artistName)
+	if artistName == "" {
+		return "", fmt.Errorf("artist name is required")
+	}
+
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, deezerArtistSearchURL+"?q="+url.QueryEscape(artistName), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := NewHTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Deezer artist search failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Deezer artist search returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []struct {
+			Name       string `json:"name"`
+			PictureXL  string `json:"picture_xl"`
+			PictureBig string `json:"picture_big"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse Deezer artist search: %w", err)
+	}
+
+	for _, artist := range result.Data {
+		if !strings.EqualFold(normalizeSearchText(artist.Name), normalizeSearchText(artistName)) {
+			continue
+		}
+		if artist.PictureXL != "" {
+			return artist.PictureXL, nil
+		}
+		if artist.PictureBig != "" {
+			return artist.PictureBig, nil
+		}
+	}
+
+	return "", fmt.Errorf("no Deezer artist image found for %s", artistName)
+}
+
+func (c *CoverClient) SaveArtistImage(artistDir, artistName, imageURL string) error {
+	if artistDir == "" || !claimAlbumExtra("artist", artistDir) {
+		return nil
+	}
+
+	target := filepath.Join(artistDir, artistImageFilename)
+	if fileExists(target) {
+		return nil
+	}
+
+	if imageURL == "" {
+		found, err := FindDeezerArtistImage(artistName)
+		if err != nil {
+			return err
+		}
+		imageURL = found
+	}
+
+	if err := c.DownloadCoverToPath(imageURL, target, false); err != nil {
+		os.Remove(target)
+		return err
+	}
+	fmt.Printf("Saved artist image: %s\n", target)
+	return nil
+}
+
+type qobuzAlbumGoodies struct {
+	Goodies []struct {
+		FileFormatID int    `json:"file_format_id"`
+		Name         string `json:"name"`
+		URL          string `json:"url"`
+		OriginalURL  string `json:"original_url"`
+	} `json:"goodies"`
+}
+
+func DownloadQobuzBooklets(isrc, albumDir string) error {
+	if isrc == "" || albumDir == "" || !claimAlbumExtra("booklet", albumDir) {
+		return nil
+	}
+
+	track, err := NewQobuzDownloader().searchByISRC(isrc)
+	if err != nil {
+		return err
+	}
+	if track.Album.ID == "" {
+		return nil
+	}
+
+	var album qobuzAlbumGoodies
+	if err := doQobuzSignedJSONRequest("album/get", url.Values{"album_id": {track.Album.ID}}, &album); err != nil {
+		return fmt.Errorf("failed to fetch Qobuz album: %w", err)
+	}
+
+	client := NewHTTPClient(2 * time.Minute)
+	saved := 0
+	for _, goodie := range album.Goodies {
+		bookletURL := goodie.OriginalURL
+		if bookletURL == "" {
+			bookletURL = goodie.URL
+		}
+		if goodie.FileFormatID != qobuzBookletFormatID && !strings.HasSuffix(strings.ToLower(bookletURL), ".pdf") {
+			continue
+		}
+		if bookletURL == "" {
+			continue
+		}
+
+		saved++
+		name := "Booklet.pdf"
+		if saved > 1 {
+			name = fmt.Sprintf("Booklet %d.pdf", saved)
+		}
+		target := filepath.Join(albumDir, name)
+		if fileExists(target) {
+			continue
+		}
+
+		if err := downloadBooklet(client, bookletURL, target); err != nil {
+			return err
+		}
+		fmt.Printf("Saved booklet: %s\n", target)
+	}
+
+	return nil
+}
+
+func downloadBooklet(client *http.Client, bookletURL, target string) error {
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, bookletURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download booklet: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download booklet: HTTP %d", resp.StatusCode)
+	}
+
+	tmpPath := target + ".part"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create booklet file: %w", err)
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write booklet: %w", err)
+	}
+	out.Close()
+
+	return os.Rename(tmpPath, target)
+}
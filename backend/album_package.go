@@ -0,0 +1,382 @@
+package backend
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-flac/go-flac"
+)
+
+const (
+	AlbumPackageZip = "zip"
+	AlbumPackageTar = "tar"
+
+	albumPackageInfoFile = "info.txt"
+)
+
+type AlbumPackageTrack struct {
+	File        string  `json:"file"`
+	Title       string  `json:"title"`
+	Artist      string  `json:"artist"`
+	TrackNumber int     `json:"track_number"`
+	DiscNumber  int     `json:"disc_number"`
+	ISRC        string  `json:"isrc,omitempty"`
+	Quality     string  `json:"quality,omitempty"`
+	Duration    float64 `json:"duration,omitempty"`
+	Size        int64   `json:"size"`
+}
+
+type AlbumPackageResult struct {
+	Folder  string              `json:"folder"`
+	Archive string              `json:"archive"`
+	Format  string              `json:"format"`
+	Files   int                 `json:"files"`
+	Size    int64               `json:"size"`
+	Tracks  []AlbumPackageTrack `json:"tracks"`
+	Errors  []string            `json:"errors,omitempty"`
+}
+
+type albumPackageEntry struct {
+	path string
+	name string
+	info fs.FileInfo
+}
+
+func NormalizeAlbumPackageFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", AlbumPackageZip:
+		return AlbumPackageZip, nil
+	case AlbumPackageTar:
+		return AlbumPackageTar, nil
+	}
+	return "", fmt.Errorf("unsupported package format %q (use zip or tar)", format)
+}
+
+func readAlbumPackageQuality(path string) (string, float64) {
+	if !strings.EqualFold(filepath.Ext(path), ".flac") {
+		if analysis, err := GetMetadataWithFFprobe(path); err == nil {
+			if analysis.Bitrate > 0 {
+				return fmt.Sprintf("%s %d kbps", strings.ToUpper(strings.TrimPrefix(filepath.Ext(path), ".")), analysis.Bitrate/1000), analysis.Duration
+			}
+			return "", analysis.Duration
+		}
+		return "", 0
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0
+	}
+	defer file.Close()
+
+	f, err := flac.ParseMetadata(file)
+	if err != nil {
+		return "", 0
+	}
+	streamInfo, err := f.GetStreamInfo()
+	if err != nil || streamInfo.SampleRate == 0 {
+		return "", 0
+	}
+	quality := fmt.Sprintf("FLAC %d-bit/%gkHz", streamInfo.BitDepth, float64(streamInfo.SampleRate)/1000)
+	return quality, float64(streamInfo.SampleCount) / float64(streamInfo.SampleRate)
+}
+
+func collectAlbumPackageEntries(folder string) ([]albumPackageEntry, error) {
+	var entries []albumPackageEntry
+	err := filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || isStagingArtifact(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(folder, path)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(rel, albumPackageInfoFile) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, albumPackageEntry{path: path, name: filepath.ToSlash(rel), info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read album folder: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries, nil
+}
+
+func formatPackageDuration(seconds float64) string {
+	total := int(seconds + 0.5)
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+func buildAlbumInfoText(folder string, tracks []AlbumPackageTrack, metadata *AudioMetadata) string {
+	album, albumArtist, year, upc := filepath.Base(folder), "", "", ""
+	if metadata != nil {
+		if metadata.Album != "" {
+			album = metadata.Album
+		}
+		albumArtist, year, upc = metadata.AlbumArtist, metadata.Year, metadata.UPC
+		if albumArtist == "" {
+			albumArtist = metadata.Artist
+		}
+	}
+
+	qualities := make(map[string]bool)
+	var totalDuration float64
+	var totalSize int64
+	for _, track := range tracks {
+		if track.Quality != "" {
+			qualities[track.Quality] = true
+		}
+		totalDuration += track.Duration
+		totalSize += track.Size
+	}
+	qualityList := make([]string, 0, len(qualities))
+	for quality := range qualities {
+		qualityList = append(qualityList, quality)
+	}
+	sort.Strings(qualityList)
+
+	heading := album
+	if albumArtist != "" {
+		heading = albumArtist + " - " + album
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", heading)
+	b.WriteString(strings.Repeat("=", 50) + "\n\n")
+	for _, field := range [][2]string{
+		{"Artist", albumArtist},
+		{"Album", album},
+		{"Year", year},
+		{"UPC", upc},
+		{"Quality", strings.Join(qualityList, ", ")},
+		{"Tracks", fmt.Sprintf("%d", len(tracks))},
+		{"Length", formatPackageDuration(totalDuration)},
+		{"Size", fmt.Sprintf("%.2f MB", float64(totalSize)/(1024*1024))},
+	} {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "%-9s %s\n", field[0]+":", field[1])
+		}
+	}
+
+	b.WriteString("\nTracklist\n")
+	b.WriteString(strings.Repeat("-", 50) + "\n")
+	multiDisc := false
+	for _, track := range tracks {
+		if track.DiscNumber > 1 {
+			multiDisc = true
+			break
+		}
+	}
+	for _, track := range tracks {
+		number := fmt.Sprintf("%02d", track.TrackNumber)
+		if multiDisc {
+			number = fmt.Sprintf("%d-%02d", max(track.DiscNumber, 1), track.TrackNumber)
+		}
+		title := track.Title
+		if track.Artist != "" && track.Artist != albumArtist {
+			title = track.Artist + " - " + title
+		}
+		fmt.Fprintf(&b, "%s. %s [%s]\n", number, title, formatPackageDuration(track.Duration))
+		details := []string{}
+		if track.Quality != "" {
+			details = append(details, track.Quality)
+		}
+		if track.ISRC != "" {
+			details = append(details, "ISRC "+track.ISRC)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&b, "    %s\n", strings.Join(details, " | "))
+		}
+	}
+
+	fmt.Fprintf(&b, "\nPackaged by SpotiFLAC %s on %s\n", AppVersion, time.Now().Format("2006-01-02"))
+	return b.String()
+}
+
+func isCompressedPackageEntry(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".flac", ".mp3", ".m4a", ".jpg", ".jpeg", ".png", ".webp", ".pdf":
+		return true
+	}
+	return false
+}
+
+func writeZipPackage(w io.Writer, root string, entries []albumPackageEntry, info string) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		header, err := zip.FileInfoHeader(entry.info)
+		if err != nil {
+			return err
+		}
+		header.Name = root + "/" + entry.name
+		header.Method = zip.Deflate
+		if isCompressedPackageEntry(entry.name) {
+			header.Method = zip.Store
+		}
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := copyPackageFile(dst, entry.path); err != nil {
+			return err
+		}
+	}
+
+	dst, err := zw.CreateHeader(&zip.FileHeader{Name: root + "/" + albumPackageInfoFile, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(dst, info); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func writeTarPackage(w io.Writer, root string, entries []albumPackageEntry, info string) error {
+	tw := tar.NewWriter(w)
+	for _, entry := range entries {
+		header, err := tar.FileInfoHeader(entry.info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = root + "/" + entry.name
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if err := copyPackageFile(tw, entry.path); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: root + "/" + albumPackageInfoFile, Mode: 0644, Size: int64(len(info)), ModTime: time.Now()}); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(tw, info); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func copyPackageFile(dst io.Writer, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func PackageAlbumFolder(folder, format, outputPath string) (*AlbumPackageResult, error) {
+	folder = filepath.Clean(folder)
+	format, err := NormalizeAlbumPackageFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(folder); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("album folder not found: %s", folder)
+	}
+
+	entries, err := collectAlbumPackageEntries(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AlbumPackageResult{Folder: folder, Format: format}
+	var albumMetadata *AudioMetadata
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.name)) {
+		case ".flac", ".mp3", ".m4a":
+		default:
+			continue
+		}
+
+		track := AlbumPackageTrack{File: entry.name, Title: strings.TrimSuffix(filepath.Base(entry.name), filepath.Ext(entry.name)), Size: entry.info.Size()}
+		if metadata, err := ReadAudioMetadata(entry.path); err == nil {
+			if metadata.Title != "" {
+				track.Title = metadata.Title
+			}
+			track.Artist = metadata.Artist
+			track.TrackNumber = metadata.TrackNumber
+			track.DiscNumber = metadata.DiscNumber
+			track.ISRC = metadata.ISRC
+			if albumMetadata == nil {
+				albumMetadata = metadata
+			}
+		} else {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", entry.name, err))
+		}
+		track.Quality, track.Duration = readAlbumPackageQuality(entry.path)
+		result.Tracks = append(result.Tracks, track)
+	}
+	if len(result.Tracks) == 0 {
+		return nil, fmt.Errorf("no audio files found in %s", folder)
+	}
+
+	sort.SliceStable(result.Tracks, func(i, j int) bool {
+		if result.Tracks[i].DiscNumber != result.Tracks[j].DiscNumber {
+			return result.Tracks[i].DiscNumber < result.Tracks[j].DiscNumber
+		}
+		return result.Tracks[i].TrackNumber < result.Tracks[j].TrackNumber
+	})
+
+	if strings.TrimSpace(outputPath) == "" {
+		outputPath = folder + "." + format
+	}
+	result.Archive = outputPath
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return nil, err
+	}
+	tmpPath := PartialDownloadPath(outputPath)
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Base(folder)
+	info := buildAlbumInfoText(folder, result.Tracks, albumMetadata)
+	if format == AlbumPackageTar {
+		err = writeTarPackage(out, root, entries, info)
+	} else {
+		err = writeZipPackage(out, root, entries, info)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, outputPath)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to write album package: %w", err)
+	}
+
+	result.Files = len(entries) + 1
+	if stat, err := os.Stat(outputPath); err == nil {
+		result.Size = stat.Size()
+	}
+	return result, nil
+}
@@ -22,6 +22,8 @@ type AmazonDownloader struct {
 	regions []string
 }
 
+const amazonMirrorAttempts = 2
+
 type AmazonStreamResponse struct {
 	StreamURL     string `json:"streamUrl"`
 	DecryptionKey string `json:"decryptionKey"`
@@ -99,9 +101,7 @@ func getAmazonMusicDebugKey() (string, error) {
 
 func NewAmazonDownloader() *AmazonDownloader {
 	return &AmazonDownloader{
-		client: &http.Client{
-			Timeout: 120 * time.Second,
-		},
+		client:  NewServiceHTTPClient("amazon", 120*time.Second),
 		regions: []string{"us", "eu"},
 	}
 }
@@ -122,82 +122,121 @@ func (a *AmazonDownloader) GetAmazonURLFromSpotify(spotifyTrackID string) (strin
 	return amazonURL, nil
 }
 
-func (a *AmazonDownloader) DownloadFromAfkarXYZ(amazonURL, outputDir, quality string) (string, error) {
-
-	asinRegex := regexp.MustCompile(`(B[0-9A-Z]{9})`)
-	asin := asinRegex.FindString(amazonURL)
-	if asin == "" {
-		return "", fmt.Errorf("failed to extract ASIN from URL: %s", amazonURL)
-	}
-
-	apiURL := fmt.Sprintf("%s/api/track/%s", amazonMusicAPIBaseURL, asin)
+func (a *AmazonDownloader) fetchStream(mirror, asin string) (*AmazonStreamResponse, error) {
+	apiURL := fmt.Sprintf("%s/api/track/%s", mirror, asin)
 	req, err := NewRequestWithDefaultHeaders(http.MethodGet, apiURL, nil)
 	if err != nil {
-		return "", err
+		return nil, &AmazonDownloadError{Mirror: mirror, Stage: "request", Err: err}
 	}
 
 	debugKey, err := getAmazonMusicDebugKey()
 	if err != nil {
-		return "", fmt.Errorf("failed to decrypt Amazon debug key: %w", err)
+		return nil, fmt.Errorf("failed to decrypt Amazon debug key: %w", err)
 	}
 	req.Header.Set("X-Debug-Key", debugKey)
 
-	fmt.Printf("Fetching from Amazon API (ASIN: %s)...\n", asin)
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, &AmazonDownloadError{Mirror: mirror, Stage: "stream lookup", Err: err}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("Amazon API returned status %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &AmazonDownloadError{Mirror: mirror, Stage: "stream lookup", StatusCode: resp.StatusCode}
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, &AmazonDownloadError{Mirror: mirror, Stage: "stream lookup", Err: err}
 	}
 
 	var apiResp AmazonStreamResponse
 	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, &AmazonDownloadError{Mirror: mirror, Stage: "stream lookup", StatusCode: http.StatusBadRequest, Err: fmt.Errorf("failed to decode response: %w", err)}
 	}
 
 	if apiResp.StreamURL == "" {
-		return "", fmt.Errorf("no stream URL found in response")
+		return nil, &AmazonDownloadError{Mirror: mirror, Stage: "stream lookup", StatusCode: http.StatusNotFound, Err: fmt.Errorf("no stream URL found in response")}
 	}
 
-	downloadURL := apiResp.StreamURL
-	fileName := fmt.Sprintf("%s.m4a", asin)
-	filePath := filepath.Join(outputDir, fileName)
+	return &apiResp, nil
+}
 
-	out, err := os.Create(filePath)
-	if err != nil {
-		return "", err
+func (a *AmazonDownloader) fetchStreamWithRetry(mirror, asin string) (*AmazonStreamResponse, error) {
+	var lastErr error
+	for attempt := 1; attempt <= amazonMirrorAttempts; attempt++ {
+		apiResp, err := a.fetchStream(mirror, asin)
+		if err == nil {
+			return apiResp, nil
+		}
+		lastErr = err
+		if !isRetryableAmazonError(err) || attempt == amazonMirrorAttempts {
+			break
+		}
+		fmt.Printf("Amazon mirror %s failed (%v), retrying...\n", mirror, err)
+		time.Sleep(time.Duration(attempt) * 1500 * time.Millisecond)
 	}
-	defer out.Close()
+	return nil, lastErr
+}
 
-	dlReq, err := NewRequestWithDefaultHeaders(http.MethodGet, downloadURL, nil)
-	if err != nil {
-		return "", err
+func (a *AmazonDownloader) DownloadFromAfkarXYZ(amazonURL, outputDir, quality string) (string, error) {
+
+	asinRegex := regexp.MustCompile(`(B[0-9A-Z]{9})`)
+	asin := asinRegex.FindString(amazonURL)
+	if asin == "" {
+		return "", fmt.Errorf("failed to extract ASIN from URL: %s", amazonURL)
 	}
 
-	dlResp, err := a.client.Do(dlReq)
-	if err != nil {
-		return "", err
+	mirrors := GetAmazonAPIAttemptList()
+	var failures []string
+	for refreshed := false; ; refreshed = true {
+		for _, mirror := range mirrors {
+			fmt.Printf("Fetching from Amazon API %s (ASIN: %s)...\n", mirror, asin)
+			apiResp, err := a.fetchStreamWithRetry(mirror, asin)
+			if err == nil {
+				var filePath string
+				filePath, err = a.downloadStream(apiResp, asin, outputDir)
+				if err == nil {
+					RecordAmazonMirrorResult(mirror, nil)
+					return filePath, nil
+				}
+				err = &AmazonDownloadError{Mirror: mirror, Stage: "download", Err: err}
+			}
+			RecordAmazonMirrorResult(mirror, err)
+			failures = append(failures, err.Error())
+		}
+
+		if refreshed || GetAmazonMirrorListURLSetting() == "" {
+			break
+		}
+		urls, err := RefreshAmazonAPIList(true)
+		if err != nil || len(urls) == 0 {
+			break
+		}
+		fmt.Println("All cached Amazon mirrors failed, refreshed mirror list and retrying...")
+		mirrors = GetAmazonAPIAttemptList()
+	}
+
+	fmt.Println("All Amazon mirrors failed:")
+	for _, item := range failures {
+		fmt.Printf("  ✗ %s\n", item)
 	}
-	defer dlResp.Body.Close()
+	return "", fmt.Errorf("all amazon mirrors failed: %s", strings.Join(failures, " | "))
+}
+
+func (a *AmazonDownloader) downloadStream(apiResp *AmazonStreamResponse, asin, outputDir string) (string, error) {
+	downloadURL := apiResp.StreamURL
+	fileName := fmt.Sprintf("%s.m4a", asin)
+	filePath := filepath.Join(outputDir, fileName)
 
 	fmt.Printf("Downloading track: %s\n", fileName)
-	pw := NewProgressWriter(out)
-	_, err = io.Copy(pw, dlResp.Body)
+	total, err := DownloadURLToFile(a.client, downloadURL, filePath)
 	if err != nil {
-		out.Close()
 		os.Remove(filePath)
 		return "", err
 	}
 
-	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
+	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(total)/(1024*1024))
 
 	if apiResp.DecryptionKey != "" {
 		fmt.Printf("Decrypting file...\n")
@@ -287,10 +326,8 @@ func (a *AmazonDownloader) DownloadFromService(amazonURL, outputDir, quality str
 
 func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, quality, filenameFormat, playlistName, playlistOwner string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, spotifyCoverURL string, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, embedMaxQualityCover bool, spotifyTotalDiscs int, spotifyCopyright, spotifyPublisher, spotifyComposer, metadataSeparator, isrcOverride, spotifyURL string, useFirstArtistOnly bool, useSingleGenre bool, embedGenre bool) (string, error) {
 
-	if outputDir != "." {
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("failed to create output directory: %w", err)
-		}
+	if err := EnsureOutputDir(outputDir); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	if spotifyTrackName != "" && spotifyArtistName != "" {
@@ -301,11 +338,13 @@ func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, quality, filename
 			filenameAlbumArtist = GetFirstArtist(spotifyAlbumArtist)
 		}
 		expectedFilename := BuildExpectedFilename(spotifyTrackName, filenameArtist, spotifyAlbumName, filenameAlbumArtist, spotifyReleaseDate, filenameFormat, playlistName, playlistOwner, includeTrackNumber, position, spotifyDiscNumber, false, isrcOverride)
-		expectedPath := filepath.Join(outputDir, expectedFilename)
+		expectedPath := FitPathLength(filepath.Join(outputDir, expectedFilename), GetMaxPathLengthSetting())
 
 		if !GetRedownloadWithSuffixSetting() {
-			if fileInfo, err := os.Stat(expectedPath); err == nil && fileInfo.Size() > 0 {
-				fmt.Printf("File already exists: %s (%.2f MB)\n", expectedPath, float64(fileInfo.Size())/(1024*1024))
+			_, alreadyExists, releaseOutput := ReserveOutputPathForDownload(expectedPath, false)
+			defer releaseOutput()
+			if alreadyExists {
+				fmt.Printf("File already exists: %s (%.2f MB)\n", expectedPath, float64(mustFileSize(expectedPath))/(1024*1024))
 				return "EXISTS:" + expectedPath, nil
 			}
 		}
@@ -382,63 +421,34 @@ func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, quality, filename
 	originalFileBase := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
 
 	if spotifyTrackName != "" && spotifyArtistName != "" {
-		safeArtist := sanitizeFilename(spotifyArtistName)
-		safeAlbumArtist := sanitizeFilename(spotifyAlbumArtist)
-
+		filenameArtist := spotifyArtistName
+		filenameAlbumArtist := spotifyAlbumArtist
 		if useFirstArtistOnly {
-			safeArtist = sanitizeFilename(GetFirstArtist(spotifyArtistName))
-			safeAlbumArtist = sanitizeFilename(GetFirstArtist(spotifyAlbumArtist))
+			filenameArtist = GetFirstArtist(spotifyArtistName)
+			filenameAlbumArtist = GetFirstArtist(spotifyAlbumArtist)
 		}
 
-		safeTitle := sanitizeFilename(spotifyTrackName)
-		safeAlbum := sanitizeFilename(spotifyAlbumName)
-
-		year := ""
-		if len(spotifyReleaseDate) >= 4 {
-			year = spotifyReleaseDate[:4]
+		templateData := FilenameTemplateData{
+			Title:       spotifyTrackName,
+			Artist:      filenameArtist,
+			Album:       spotifyAlbumName,
+			AlbumArtist: filenameAlbumArtist,
+			ReleaseDate: spotifyReleaseDate,
+			Playlist:    playlistName,
+			Creator:     playlistOwner,
+			ISRC:        isrc,
+			Track:       position,
+			Disc:        spotifyDiscNumber,
+			Quality:     quality,
+			Service:     "amazon",
 		}
-
-		var newFilename string
-
-		if strings.Contains(filenameFormat, "{") {
-			newFilename = filenameFormat
-			newFilename = strings.ReplaceAll(newFilename, "{title}", safeTitle)
-			newFilename = strings.ReplaceAll(newFilename, "{artist}", safeArtist)
-			newFilename = strings.ReplaceAll(newFilename, "{album}", safeAlbum)
-			newFilename = strings.ReplaceAll(newFilename, "{album_artist}", safeAlbumArtist)
-			newFilename = strings.ReplaceAll(newFilename, "{year}", year)
-			newFilename = strings.ReplaceAll(newFilename, "{date}", SanitizeFilename(spotifyReleaseDate))
-			newFilename = strings.ReplaceAll(newFilename, "{isrc}", SanitizeOptionalFilename(isrc))
-
-			if spotifyDiscNumber > 0 {
-				newFilename = strings.ReplaceAll(newFilename, "{disc}", fmt.Sprintf("%d", spotifyDiscNumber))
-			} else {
-				newFilename = strings.ReplaceAll(newFilename, "{disc}", "")
-			}
-
-			if position > 0 {
-				newFilename = strings.ReplaceAll(newFilename, "{track}", fmt.Sprintf("%02d", position))
-			} else {
-
-				newFilename = regexp.MustCompile(`\{track\}\.\s*`).ReplaceAllString(newFilename, "")
-				newFilename = regexp.MustCompile(`\{track\}\s*-\s*`).ReplaceAllString(newFilename, "")
-				newFilename = regexp.MustCompile(`\{track\}\s*`).ReplaceAllString(newFilename, "")
-			}
-		} else {
-
-			switch filenameFormat {
-			case "artist-title":
-				newFilename = fmt.Sprintf("%s - %s", safeArtist, safeTitle)
-			case "title":
-				newFilename = safeTitle
-			default:
-				newFilename = fmt.Sprintf("%s - %s", safeTitle, safeArtist)
-			}
-
-			if includeTrackNumber && position > 0 {
-				newFilename = fmt.Sprintf("%02d. %s", position, newFilename)
+		if strings.Contains(filenameFormat, "{bit_depth") || strings.Contains(filenameFormat, "{sample_rate") || strings.Contains(filenameFormat, "{quality") {
+			if info, err := GetTrackMetadata(filePath); err == nil {
+				templateData.BitDepth = int(info.BitsPerSample)
+				templateData.SampleRate = int(info.SampleRate)
 			}
 		}
+		newFilename := buildTemplatedFilename(filenameFormat, templateData, includeTrackNumber, position, "%02d. ")
 
 		ext := filepath.Ext(filePath)
 		if ext == "" {
@@ -447,7 +457,9 @@ func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, quality, filename
 		newFilename = newFilename + ext
 		newFilePath := filepath.Join(outputDir, newFilename)
 		if GetRedownloadWithSuffixSetting() {
-			newFilePath, _ = ResolveOutputPathForDownload(newFilePath, true)
+			var releaseOutput func()
+			newFilePath, _, releaseOutput = ReserveOutputPathForDownload(newFilePath, true)
+			defer releaseOutput()
 		}
 
 		if err := os.Rename(filePath, newFilePath); err != nil {
@@ -471,6 +483,7 @@ func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, quality, filename
 		} else {
 			defer os.Remove(coverPath)
 			fmt.Println("Spotify cover downloaded")
+			coverPath = PrepareCoverForEmbedding(coverPath)
 		}
 	}
 
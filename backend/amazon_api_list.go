@@ -0,0 +1,257 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const amazonAPIListCacheFile = "amazon-api-urls.json"
+
+type AmazonMirrorHealth struct {
+	URL           string `json:"url"`
+	Successes     int    `json:"successes"`
+	Failures      int    `json:"failures"`
+	Consecutive   int    `json:"consecutive_failures"`
+	LastError     string `json:"last_error,omitempty"`
+	LastSuccessAt int64  `json:"last_success_at_unix,omitempty"`
+	LastFailureAt int64  `json:"last_failure_at_unix,omitempty"`
+}
+
+type amazonAPIListCache struct {
+	URLs        []string                       `json:"urls"`
+	LastUsedURL string                         `json:"last_used_url,omitempty"`
+	UpdatedAt   int64                          `json:"updated_at_unix"`
+	Source      string                         `json:"source,omitempty"`
+	Health      map[string]*AmazonMirrorHealth `json:"health,omitempty"`
+}
+
+type AmazonDownloadError struct {
+	Mirror     string
+	Stage      string
+	StatusCode int
+	Err        error
+}
+
+func (e *AmazonDownloadError) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("amazon %s via %s: HTTP %d", e.Stage, e.Mirror, e.StatusCode)
+	}
+	return fmt.Sprintf("amazon %s via %s: %v", e.Stage, e.Mirror, e.Err)
+}
+
+func (e *AmazonDownloadError) Unwrap() error {
+	return e.Err
+}
+
+func (e *AmazonDownloadError) Retryable() bool {
+	if errors.Is(e.Err, ErrUpstreamProtected) {
+		return false
+	}
+	return e.StatusCode == 0 || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+var (
+	amazonAPIListMu    sync.Mutex
+	amazonAPIListState *amazonAPIListCache
+)
+
+func loadAmazonAPIListStateLocked() (*amazonAPIListCache, error) {
+	if amazonAPIListState != nil {
+		return amazonAPIListState, nil
+	}
+
+	appDir, err := EnsureAppDir()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &amazonAPIListCache{}
+	data, err := os.ReadFile(filepath.Join(appDir, amazonAPIListCacheFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read amazon api cache: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("failed to parse amazon api cache: %w", err)
+		}
+	}
+
+	state.URLs = normalizeTidalAPIURLs(state.URLs)
+	if state.Health == nil {
+		state.Health = make(map[string]*AmazonMirrorHealth)
+	}
+	amazonAPIListState = state
+	return state, nil
+}
+
+func saveAmazonAPIListStateLocked(state *amazonAPIListCache) error {
+	appDir, err := EnsureAppDir()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode amazon api cache: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(appDir, amazonAPIListCacheFile), payload, 0o644); err != nil {
+		return fmt.Errorf("failed to write amazon api cache: %w", err)
+	}
+
+	amazonAPIListState = state
+	return nil
+}
+
+func fetchAmazonAPIURLs(listURL string) ([]string, error) {
+	client := NewServiceHTTPClient("amazon", 12*time.Second)
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create amazon mirror list request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch amazon mirror list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		preview, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return nil, fmt.Errorf("amazon mirror list returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(preview)))
+	}
+
+	var urls []string
+	if err := json.NewDecoder(resp.Body).Decode(&urls); err != nil {
+		return nil, fmt.Errorf("failed to decode amazon mirror list: %w", err)
+	}
+
+	urls = normalizeTidalAPIURLs(urls)
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("amazon mirror list returned no valid urls")
+	}
+
+	return urls, nil
+}
+
+func RefreshAmazonAPIList(force bool) ([]string, error) {
+	amazonAPIListMu.Lock()
+	defer amazonAPIListMu.Unlock()
+
+	state, err := loadAmazonAPIListStateLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	listURL := GetAmazonMirrorListURLSetting()
+	if listURL == "" || (!force && len(state.URLs) > 0) {
+		return append([]string(nil), state.URLs...), nil
+	}
+
+	urls, fetchErr := fetchAmazonAPIURLs(listURL)
+	if fetchErr != nil {
+		return append([]string(nil), state.URLs...), fetchErr
+	}
+
+	state.URLs = urls
+	state.UpdatedAt = time.Now().Unix()
+	state.Source = listURL
+	if !containsString(state.URLs, state.LastUsedURL) {
+		state.LastUsedURL = ""
+	}
+
+	return append([]string(nil), state.URLs...), saveAmazonAPIListStateLocked(state)
+}
+
+func GetAmazonAPIAttemptList() []string {
+	amazonAPIListMu.Lock()
+	defer amazonAPIListMu.Unlock()
+
+	var cached []string
+	lastUsed := ""
+	if state, err := loadAmazonAPIListStateLocked(); err == nil {
+		cached = append(cached, state.URLs...)
+		lastUsed = state.LastUsedURL
+	}
+
+	var urls []string
+	if custom := GetCustomAmazonAPISetting(); custom != "" {
+		urls = append(urls, custom)
+	}
+	urls = append(urls, rotateTidalAPIURLs(append(cached, amazonMusicAPIBaseURL), lastUsed)...)
+	return normalizeTidalAPIURLs(urls)
+}
+
+func RecordAmazonMirrorResult(mirror string, err error) {
+	mirror = strings.TrimRight(strings.TrimSpace(mirror), "/")
+	if mirror == "" {
+		return
+	}
+
+	amazonAPIListMu.Lock()
+	defer amazonAPIListMu.Unlock()
+
+	state, loadErr := loadAmazonAPIListStateLocked()
+	if loadErr != nil {
+		return
+	}
+
+	health := state.Health[mirror]
+	if health == nil {
+		health = &AmazonMirrorHealth{URL: mirror}
+		state.Health[mirror] = health
+	}
+
+	now := time.Now().Unix()
+	if err == nil {
+		health.Successes++
+		health.Consecutive = 0
+		health.LastSuccessAt = now
+		state.LastUsedURL = mirror
+	} else {
+		health.Failures++
+		health.Consecutive++
+		health.LastError = err.Error()
+		health.LastFailureAt = now
+	}
+
+	if saveErr := saveAmazonAPIListStateLocked(state); saveErr != nil {
+		fmt.Printf("Warning: failed to persist Amazon mirror health: %v\n", saveErr)
+	}
+}
+
+func GetAmazonMirrorHealth() []AmazonMirrorHealth {
+	urls := GetAmazonAPIAttemptList()
+
+	amazonAPIListMu.Lock()
+	defer amazonAPIListMu.Unlock()
+
+	state, err := loadAmazonAPIListStateLocked()
+	result := make([]AmazonMirrorHealth, 0, len(urls))
+	for _, mirror := range urls {
+		health := AmazonMirrorHealth{URL: mirror}
+		if err == nil && state.Health[mirror] != nil {
+			health = *state.Health[mirror]
+		}
+		result = append(result, health)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Consecutive < result[j].Consecutive
+	})
+	return result
+}
+
+func isRetryableAmazonError(err error) bool {
+	var amazonErr *AmazonDownloadError
+	return errors.As(err, &amazonErr) && amazonErr.Retryable()
+}
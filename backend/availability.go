@@ -0,0 +1,267 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const availabilityCacheTTL = 30 * time.Minute
+
+type ServiceAvailability struct {
+	Service      string `json:"service"`
+	Available    bool   `json:"available"`
+	URL          string `json:"url,omitempty"`
+	BitDepth     int    `json:"bit_depth,omitempty"`
+	SampleRate   int    `json:"sample_rate,omitempty"`
+	QualityKnown bool   `json:"quality_known"`
+	Downloadable bool   `json:"downloadable"`
+	QualityLabel string `json:"quality_label,omitempty"`
+	NotFound     bool   `json:"not_found,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+type AvailabilityMatrix struct {
+	SpotifyID string                `json:"spotify_id,omitempty"`
+	ISRC      string                `json:"isrc,omitempty"`
+	Services  []ServiceAvailability `json:"services"`
+	CheckedAt time.Time             `json:"checked_at"`
+}
+
+var (
+	availabilityCacheMu sync.Mutex
+	availabilityCache   = make(map[string]*AvailabilityMatrix)
+)
+
+func (m *AvailabilityMatrix) Service(name string) *ServiceAvailability {
+	if m == nil {
+		return nil
+	}
+	for i := range m.Services {
+		if m.Services[i].Service == name {
+			return &m.Services[i]
+		}
+	}
+	return nil
+}
+
+func (m *AvailabilityMatrix) IsAvailable(name string) bool {
+	service := m.Service(name)
+	return service != nil && service.Available
+}
+
+func (m *AvailabilityMatrix) IsNotFound(name string) bool {
+	service := m.Service(name)
+	return service != nil && service.NotFound
+}
+
+func formatAvailabilityQuality(bitDepth, sampleRate int) string {
+	if bitDepth <= 0 || sampleRate <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d-bit/%skHz", bitDepth, formatSampleRateKHz(sampleRate))
+}
+
+func lossless16Availability(service, serviceURL string) ServiceAvailability {
+	availability := ServiceAvailability{Service: service, Downloadable: true}
+	if serviceURL == "" {
+		availability.Reason = "no link found"
+		return availability
+	}
+	availability.Available = true
+	availability.URL = serviceURL
+	availability.BitDepth = 16
+	availability.SampleRate = 44100
+	availability.QualityLabel = formatAvailabilityQuality(16, 44100) + "+"
+	return availability
+}
+
+func CheckAvailability(spotifyID, isrc string) (*AvailabilityMatrix, error) {
+	spotifyID = strings.TrimSpace(spotifyID)
+	isrc = strings.ToUpper(strings.TrimSpace(isrc))
+	if spotifyID == "" && isrc == "" {
+		return nil, fmt.Errorf("spotify ID or ISRC is required")
+	}
+
+	cacheKey := spotifyID + "|" + isrc
+	availabilityCacheMu.Lock()
+	if cached, ok := availabilityCache[cacheKey]; ok && time.Since(cached.CheckedAt) < availabilityCacheTTL {
+		availabilityCacheMu.Unlock()
+		return cached, nil
+	}
+	availabilityCacheMu.Unlock()
+
+	client := NewSongLinkClient()
+	var links *resolvedTrackLinks
+	var err error
+	if spotifyID != "" {
+		links, err = client.resolveSpotifyTrackLinks(spotifyID, "")
+	} else {
+		links, err = client.resolveISRCTrackLinks(isrc, "")
+	}
+	if links == nil {
+		links = &resolvedTrackLinks{}
+	}
+	if isrc == "" {
+		isrc = strings.TrimSpace(links.ISRC)
+	}
+
+	deezerURL := normalizeDeezerTrackURL(links.DeezerURL)
+	if isrc == "" && deezerURL != "" {
+		if resolvedISRC, deezerErr := getDeezerISRC(deezerURL); deezerErr == nil {
+			isrc = resolvedISRC
+		}
+	}
+
+	matrix := &AvailabilityMatrix{
+		SpotifyID: spotifyID,
+		ISRC:      isrc,
+		CheckedAt: time.Now(),
+	}
+
+	qobuz, qobuzErr := checkQobuzServiceAvailability(isrc)
+	matrix.Services = append(matrix.Services,
+		lossless16Availability("tidal", links.TidalURL),
+		qobuz,
+		lossless16Availability("amazon", normalizeAmazonMusicURL(links.AmazonURL)),
+	)
+
+	deezer := lossless16Availability("deezer", deezerURL)
+	deezer.Downloadable = false
+	matrix.Services = append(matrix.Services, deezer)
+
+	anyAvailable := false
+	for _, service := range matrix.Services {
+		anyAvailable = anyAvailable || service.Available
+	}
+	if !anyAvailable {
+		if err == nil {
+			err = qobuzErr
+		}
+		if err == nil {
+			err = fmt.Errorf("no platforms found")
+		}
+		return matrix, err
+	}
+
+	if qobuzErr != nil {
+		return matrix, nil
+	}
+
+	availabilityCacheMu.Lock()
+	availabilityCache[cacheKey] = matrix
+	availabilityCacheMu.Unlock()
+
+	return matrix, nil
+}
+
+func checkQobuzServiceAvailability(isrc string) (ServiceAvailability, error) {
+	availability := ServiceAvailability{Service: "qobuz", Downloadable: true}
+	if isrc == "" {
+		availability.Reason = "ISRC unknown"
+		return availability, nil
+	}
+
+	item, found, err := searchQobuzAvailability(isrc)
+	if err != nil {
+		availability.Reason = "lookup failed"
+		return availability, err
+	}
+	if !found {
+		availability.NotFound = true
+		availability.Reason = "not found by ISRC"
+		return availability, nil
+	}
+
+	availability.Available = true
+	availability.URL = qobuzAvailabilityURL(&item)
+	if item.MaximumBitDepth > 0 && item.MaximumSamplingRate > 0 {
+		availability.BitDepth = item.MaximumBitDepth
+		availability.SampleRate = int(item.MaximumSamplingRate * 1000)
+		availability.QualityKnown = true
+		availability.QualityLabel = formatAvailabilityQuality(availability.BitDepth, availability.SampleRate)
+	}
+	return availability, nil
+}
+
+const (
+	ServiceStrategyOrder       = "order"
+	ServiceStrategyBestQuality = "best-quality"
+)
+
+func NormalizeServiceStrategy(strategy string) string {
+	switch strings.ToLower(strings.TrimSpace(strategy)) {
+	case ServiceStrategyBestQuality, "best", "best_quality":
+		return ServiceStrategyBestQuality
+	default:
+		return ServiceStrategyOrder
+	}
+}
+
+func ParseQualityLevel(value string) (int, int) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.NewReplacer("-bit", "", "bit", "", "khz", "", " ", "").Replace(value)
+	if value == "" {
+		return 0, 0
+	}
+
+	bitPart, ratePart, _ := strings.Cut(value, "/")
+	bitDepth, err := strconv.Atoi(bitPart)
+	if err != nil || bitDepth <= 0 {
+		return 0, 0
+	}
+
+	sampleRate := 0
+	if ratePart != "" {
+		rate, err := strconv.ParseFloat(ratePart, 64)
+		if err != nil || rate <= 0 {
+			return 0, 0
+		}
+		if rate < 1000 {
+			rate *= 1000
+		}
+		sampleRate = int(rate)
+	}
+	return bitDepth, sampleRate
+}
+
+func qualityAtLeast(bitDepth, sampleRate, floorBitDepth, floorSampleRate int) bool {
+	return bitDepth >= floorBitDepth && sampleRate >= floorSampleRate
+}
+
+func (m *AvailabilityMatrix) RankServices(order []string, floorBitDepth, floorSampleRate int) []string {
+	type candidate struct {
+		service string
+		quality ServiceAvailability
+	}
+
+	var candidates []candidate
+	for _, service := range order {
+		availability := m.Service(service)
+		if availability == nil || !availability.Available || !availability.Downloadable {
+			continue
+		}
+		if availability.QualityKnown && !qualityAtLeast(availability.BitDepth, availability.SampleRate, floorBitDepth, floorSampleRate) {
+			fmt.Printf("[BestQuality] Skipping %s: %s is below the %d-bit/%dHz floor\n", service, availability.QualityLabel, floorBitDepth, floorSampleRate)
+			continue
+		}
+		candidates = append(candidates, candidate{service: service, quality: *availability})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].quality, candidates[j].quality
+		if a.BitDepth != b.BitDepth {
+			return a.BitDepth > b.BitDepth
+		}
+		return a.SampleRate > b.SampleRate
+	})
+
+	ranked := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, c.service)
+	}
+	return ranked
+}
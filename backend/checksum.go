@@ -0,0 +1,228 @@
+package backend
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	ChecksumModeSHA256 = "sha256"
+	ChecksumModeSFV    = "sfv"
+
+	sha256ChecksumFile = "checksums.sha256"
+	sfvChecksumFile    = "checksums.sfv"
+)
+
+var checksumFileMu sync.Mutex
+
+type ChecksumIssue struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+type ChecksumReport struct {
+	Checked  int             `json:"checked"`
+	OK       int             `json:"ok"`
+	Mismatch int             `json:"mismatch"`
+	Missing  int             `json:"missing"`
+	Issues   []ChecksumIssue `json:"issues,omitempty"`
+}
+
+func GetChecksumModeSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	mode, _ := settings["checksumMode"].(string)
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case ChecksumModeSHA256:
+		return ChecksumModeSHA256
+	case ChecksumModeSFV:
+		return ChecksumModeSFV
+	}
+	return ""
+}
+
+func checksumFileName(mode string) string {
+	if mode == ChecksumModeSFV {
+		return sfvChecksumFile
+	}
+	return sha256ChecksumFile
+}
+
+func hashFile(path, mode string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var h hash.Hash
+	if mode == ChecksumModeSFV {
+		h = crc32.NewIEEE()
+	} else {
+		h = sha256.New()
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if mode == ChecksumModeSFV {
+		sum = strings.ToUpper(sum)
+	}
+	return sum, nil
+}
+
+func parseChecksumLine(line, mode string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	if mode == ChecksumModeSFV {
+		idx := strings.LastIndex(line, " ")
+		if idx <= 0 {
+			return "", "", false
+		}
+		return strings.TrimSpace(line[:idx]), strings.ToUpper(line[idx+1:]), true
+	}
+
+	sum, name, ok := strings.Cut(line, " ")
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimPrefix(strings.TrimSpace(name), "*"), strings.ToLower(sum), true
+}
+
+func readChecksumFile(path, mode string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if name, sum, ok := parseChecksumLine(scanner.Text(), mode); ok {
+			entries[name] = sum
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func writeChecksumFile(path, mode string, entries map[string]string) error {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		if mode == ChecksumModeSFV {
+			fmt.Fprintf(&b, "%s %s\n", name, entries[name])
+		} else {
+			fmt.Fprintf(&b, "%s  %s\n", entries[name], name)
+		}
+	}
+
+	tmpPath := PartialDownloadPath(path)
+	if err := os.WriteFile(tmpPath, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func RecordChecksum(filePath, mode string) error {
+	sum, err := hashFile(filePath, mode)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", filepath.Base(filePath), err)
+	}
+
+	checksumFileMu.Lock()
+	defer checksumFileMu.Unlock()
+
+	checksumPath := filepath.Join(filepath.Dir(filePath), checksumFileName(mode))
+	entries, err := readChecksumFile(checksumPath, mode)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", checksumPath, err)
+	}
+	if entries == nil {
+		entries = make(map[string]string)
+	}
+	entries[filepath.Base(filePath)] = sum
+
+	if err := writeChecksumFile(checksumPath, mode, entries); err != nil {
+		return fmt.Errorf("failed to write %s: %w", checksumPath, err)
+	}
+	return nil
+}
+
+func VerifyChecksums(root string, onProgress func(path, status string)) (ChecksumReport, error) {
+	var report ChecksumReport
+	if _, err := os.Stat(root); err != nil {
+		return report, err
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		var mode string
+		switch d.Name() {
+		case sha256ChecksumFile:
+			mode = ChecksumModeSHA256
+		case sfvChecksumFile:
+			mode = ChecksumModeSFV
+		default:
+			return nil
+		}
+
+		entries, err := readChecksumFile(path, mode)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		dir := filepath.Dir(path)
+		for name, want := range entries {
+			target := filepath.Join(dir, name)
+			report.Checked++
+
+			status := "ok"
+			got, hashErr := hashFile(target, mode)
+			switch {
+			case os.IsNotExist(hashErr):
+				status = "missing"
+				report.Missing++
+			case hashErr != nil || got != want:
+				status = "mismatch"
+				report.Mismatch++
+			default:
+				report.OK++
+			}
+			if status != "ok" {
+				report.Issues = append(report.Issues, ChecksumIssue{Path: target, Status: status})
+			}
+			if onProgress != nil {
+				onProgress(target, status)
+			}
+		}
+		return nil
+	})
+	return report, err
+}
@@ -0,0 +1,99 @@
+package backend
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const cloudflareBodyPeekLimit = 32 * 1024
+
+var ErrUpstreamProtected = errors.New("upstream protected")
+
+var cloudflareChallengeMarkers = []string{
+	"just a moment...",
+	"cf-browser-verification",
+	"challenge-platform",
+	"cf_chl_opt",
+	"attention required! | cloudflare",
+}
+
+var cloudflareRateLimitMarkers = []string{
+	"error code: 1015",
+	"you are being rate limited",
+}
+
+type UpstreamProtectedError struct {
+	Host        string
+	StatusCode  int
+	RateLimited bool
+}
+
+func (e *UpstreamProtectedError) Error() string {
+	kind := "challenge"
+	if e.RateLimited {
+		kind = "rate limit"
+	}
+	return fmt.Sprintf("%s is behind a Cloudflare %s (HTTP %d); try another mirror later or route requests through a proxy (HTTPS_PROXY)", e.Host, kind, e.StatusCode)
+}
+
+func (e *UpstreamProtectedError) Unwrap() error {
+	return ErrUpstreamProtected
+}
+
+func isCloudflareResponse(resp *http.Response) bool {
+	if resp.Header.Get("Cf-Ray") != "" || resp.Header.Get("Cf-Mitigated") != "" {
+		return true
+	}
+	return strings.EqualFold(resp.Header.Get("Server"), "cloudflare")
+}
+
+func isCloudflareBlockStatus(status int) bool {
+	switch status {
+	case http.StatusForbidden, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+	return status >= 520 && status <= 527
+}
+
+func detectCloudflareBlock(resp *http.Response) error {
+	if resp == nil || !isCloudflareBlockStatus(resp.StatusCode) || !isCloudflareResponse(resp) {
+		return nil
+	}
+
+	peek, _ := io.ReadAll(io.LimitReader(resp.Body, cloudflareBodyPeekLimit))
+	lower := strings.ToLower(string(peek))
+
+	blockErr := &UpstreamProtectedError{
+		Host:       resp.Request.URL.Host,
+		StatusCode: resp.StatusCode,
+	}
+	switch {
+	case strings.EqualFold(resp.Header.Get("Cf-Mitigated"), "challenge"):
+	case resp.StatusCode >= 520:
+	case resp.StatusCode == http.StatusTooManyRequests || containsAny(lower, cloudflareRateLimitMarkers):
+		blockErr.RateLimited = true
+	case containsAny(lower, cloudflareChallengeMarkers):
+	default:
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+		return nil
+	}
+
+	resp.Body.Close()
+	return blockErr
+}
+
+func containsAny(value string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(value, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package backend
+
+const VariousArtistsName = "Various Artists"
+
+var variousArtistsAliases = map[string]bool{
+	"various artists": true,
+	"various":         true,
+	"va":              true,
+	"v a":             true,
+	"verschiedene":    true,
+	"artistes divers": true,
+	"varios artistas": true,
+}
+
+func IsVariousArtists(albumArtist string) bool {
+	return variousArtistsAliases[normalizeSearchText(albumArtist)]
+}
+
+func applyCompilationMetadata(metadata *Metadata) {
+	if IsVariousArtists(metadata.AlbumArtist) {
+		metadata.AlbumArtist = VariousArtistsName
+		metadata.Compilation = true
+	}
+}
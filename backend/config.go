@@ -1,10 +1,15 @@
 package backend
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 func GetDefaultMusicPath() string {
@@ -126,3 +131,671 @@ func GetLinkResolverAllowFallback() bool {
 
 	return allowFallback
 }
+
+func GetWatchEnabledSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["watchEnabled"].(bool)
+	return enabled
+}
+
+func GetWatchIntervalSetting() time.Duration {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return defaultWatchIntervalMins * time.Minute
+	}
+
+	minutes, ok := settings["watchIntervalMinutes"].(float64)
+	if !ok || minutes <= 0 {
+		return defaultWatchIntervalMins * time.Minute
+	}
+	if minutes < minimumWatchIntervalMins {
+		minutes = minimumWatchIntervalMins
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+func GetEmbedPlaylistTagsSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["embedPlaylistTags"].(bool)
+	return enabled
+}
+
+func GetPreferOriginalYearSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["preferOriginalYear"].(bool)
+	return enabled
+}
+
+func GetManifestFormatSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	format, _ := settings["manifestFormat"].(string)
+	return NormalizeManifestFormat(format)
+}
+
+const defaultAPIServerPort = 8473
+
+func GetAPIServerEnabledSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["apiServerEnabled"].(bool)
+	return enabled
+}
+
+func GetAPIServerPortSetting() int {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return defaultAPIServerPort
+	}
+
+	port, ok := settings["apiServerPort"].(float64)
+	if !ok || port <= 0 || port > 65535 {
+		return defaultAPIServerPort
+	}
+	return int(port)
+}
+
+func GetAPIServerTokenSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	token, _ := settings["apiServerToken"].(string)
+	return strings.TrimSpace(token)
+}
+
+const apiServerTokenFile = "api-token"
+
+func GetAPIServerTokenPath() (string, error) {
+	appDir, err := GetAppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, apiServerTokenFile), nil
+}
+
+// HasAPIServerToken reports whether a token is configured or was generated by
+// an earlier run, without creating one.
+func HasAPIServerToken() bool {
+	if GetAPIServerTokenSetting() != "" {
+		return true
+	}
+	tokenPath, err := GetAPIServerTokenPath()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(tokenPath)
+	return err == nil && strings.TrimSpace(string(data)) != ""
+}
+
+func EnsureAPIServerToken() (string, bool, error) {
+	if token := GetAPIServerTokenSetting(); token != "" {
+		return token, false, nil
+	}
+
+	if _, err := EnsureAppDir(); err != nil {
+		return "", false, err
+	}
+	tokenPath, err := GetAPIServerTokenPath()
+	if err != nil {
+		return "", false, err
+	}
+	if data, err := os.ReadFile(tokenPath); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, false, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", false, err
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", false, err
+	}
+	token := hex.EncodeToString(raw)
+	if err := os.WriteFile(tokenPath, []byte(token+"\n"), 0o600); err != nil {
+		return "", false, fmt.Errorf("failed to save API token: %w", err)
+	}
+	return token, true, nil
+}
+
+func GetAPIServerAllowedOriginsSetting() []string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return nil
+	}
+
+	var origins []string
+	switch value := settings["apiServerAllowedOrigins"].(type) {
+	case string:
+		origins = strings.Split(value, ",")
+	case []interface{}:
+		for _, entry := range value {
+			if text, ok := entry.(string); ok {
+				origins = append(origins, text)
+			}
+		}
+	}
+
+	allowed := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" && origin != "*" {
+			allowed = append(allowed, origin)
+		}
+	}
+	return allowed
+}
+
+const defaultTempCleanupMaxAgeHours = 24
+
+func GetTempCleanupMaxAgeSetting() time.Duration {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return defaultTempCleanupMaxAgeHours * time.Hour
+	}
+
+	hours, ok := settings["tempCleanupMaxAgeHours"].(float64)
+	if !ok {
+		return defaultTempCleanupMaxAgeHours * time.Hour
+	}
+	if hours <= 0 {
+		return 0
+	}
+
+	return time.Duration(hours * float64(time.Hour))
+}
+
+func GetDiscSubfoldersSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["discSubfolders"].(bool)
+	return enabled
+}
+
+func GetEmbedLyricsSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["embedLyrics"].(bool)
+	return enabled
+}
+
+func GetLyricsProvidersSetting() []string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return defaultLyricsProviders
+	}
+
+	var providers []string
+	switch value := settings["lyricsProviders"].(type) {
+	case string:
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				providers = append(providers, name)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if name, ok := item.(string); ok && strings.TrimSpace(name) != "" {
+				providers = append(providers, strings.TrimSpace(name))
+			}
+		}
+	}
+
+	if len(providers) == 0 {
+		return defaultLyricsProviders
+	}
+	return providers
+}
+
+func GetLRCFormatSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return LRCFormatStandard
+	}
+
+	format, _ := settings["lrcFormat"].(string)
+	return NormalizeLRCFormat(format)
+}
+
+func GetLyricsRomanizationSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return LyricsRomanizationOff
+	}
+
+	mode, _ := settings["lyricsRomanization"].(string)
+	return NormalizeLyricsRomanization(mode)
+}
+
+func GetRomanizeFilenamesSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["romanizeFilenames"].(bool)
+	return enabled
+}
+
+func GetTransliterateFilenamesSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["transliterateFilenames"].(bool)
+	return enabled
+}
+
+func GetCoverMaxSizeSetting() int {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return 0
+	}
+
+	size, ok := settings["coverMaxSize"].(float64)
+	if !ok || size <= 0 {
+		return 0
+	}
+	return int(size)
+}
+
+func GetCoverJPEGQualitySetting() int {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return 0
+	}
+
+	quality, ok := settings["coverJpegQuality"].(float64)
+	if !ok || quality <= 0 {
+		return 0
+	}
+	return min(int(quality), 100)
+}
+
+func GetCoverFolderOnlySetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["coverFolderOnly"].(bool)
+	return enabled
+}
+
+func GetAlbumCoverFileSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["albumCoverFile"].(bool)
+	return enabled
+}
+
+func GetAlbumFolderJPGSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["albumFolderJpg"].(bool)
+	return enabled
+}
+
+func GetDownloadArtistImageSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["downloadArtistImage"].(bool)
+	return enabled
+}
+
+func GetDownloadBookletSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["downloadBooklet"].(bool)
+	return enabled
+}
+
+func GetServiceStrategySetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ServiceStrategyOrder
+	}
+
+	strategy, _ := settings["serviceStrategy"].(string)
+	return NormalizeServiceStrategy(strategy)
+}
+
+func GetQualityFloorSetting() (int, int) {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return 16, 44100
+	}
+
+	floor, _ := settings["qualityFloor"].(string)
+	bitDepth, sampleRate := ParseQualityLevel(floor)
+	if bitDepth == 0 {
+		return 16, 44100
+	}
+	return bitDepth, sampleRate
+}
+
+func GetDurationToleranceSetting() int {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return 0
+	}
+
+	seconds, ok := settings["durationToleranceSeconds"].(float64)
+	if !ok || seconds <= 0 {
+		return 0
+	}
+	return int(seconds)
+}
+
+func GetMismatchActionSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return MismatchActionReject
+	}
+
+	action, _ := settings["mismatchAction"].(string)
+	if strings.EqualFold(strings.TrimSpace(action), MismatchActionFlag) {
+		return MismatchActionFlag
+	}
+	return MismatchActionReject
+}
+
+func GetCountryCodeSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	code, _ := settings["countryCode"].(string)
+	return strings.TrimSpace(code)
+}
+
+func GetCustomAmazonAPISetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	customAPI, _ := settings["customAmazonApi"].(string)
+	customAPI = strings.TrimRight(strings.TrimSpace(customAPI), "/")
+	if strings.HasPrefix(customAPI, "https://") {
+		return customAPI
+	}
+
+	return ""
+}
+
+func GetAmazonMirrorListURLSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	listURL, _ := settings["amazonMirrorListUrl"].(string)
+	return strings.TrimSpace(listURL)
+}
+
+func GetExperimentalDownloadersSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["experimentalDownloaders"].(bool)
+	return enabled
+}
+
+func GetYouTubeFallbackSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["youtubeFallback"].(bool)
+	return enabled
+}
+
+func GetYouTubeFallbackFormatSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return YouTubeFallbackFormatM4A
+	}
+
+	format, _ := settings["youtubeFallbackFormat"].(string)
+	return NormalizeYouTubeFallbackFormat(format)
+}
+
+func GetGaplessCheckSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["gaplessCheck"].(bool)
+	return enabled
+}
+
+func GetGaplessRepairSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["gaplessRepair"].(bool)
+	return enabled
+}
+
+func GetCreateCueSheetSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["createCueSheet"].(bool)
+	return enabled
+}
+
+func GetConvertToSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	format, _ := settings["convertTo"].(string)
+	return NormalizeConvertTarget(format)
+}
+
+func GetConvertBitrateSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return "320k"
+	}
+
+	bitrate, _ := settings["convertBitrate"].(string)
+	if strings.TrimSpace(bitrate) == "" {
+		return "320k"
+	}
+	return strings.TrimSpace(bitrate)
+}
+
+func GetConvertDeleteOriginalSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["convertDeleteOriginal"].(bool)
+	return enabled
+}
+
+func GetMaxQualitySetting() (int, int) {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return 0, 0
+	}
+
+	maxQuality, _ := settings["maxQuality"].(string)
+	return ParseQualityLevel(maxQuality)
+}
+
+func GetLoudnessTargetSetting() float64 {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return 0
+	}
+
+	target, _ := settings["loudnessTarget"].(float64)
+	if target >= 0 || target < -70 {
+		return 0
+	}
+	return target
+}
+
+func GetHookWebhookURLSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	url, _ := settings["hookWebhookUrl"].(string)
+	return strings.TrimSpace(url)
+}
+
+func GetHookCommandSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	command, _ := settings["hookCommand"].(string)
+	return strings.TrimSpace(command)
+}
+
+func GetHookEventsSetting() []string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return defaultHookEvents
+	}
+
+	var events []string
+	switch value := settings["hookEvents"].(type) {
+	case string:
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				events = append(events, name)
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if name, ok := item.(string); ok && strings.TrimSpace(name) != "" {
+				events = append(events, strings.TrimSpace(name))
+			}
+		}
+	}
+
+	if len(events) == 0 {
+		return defaultHookEvents
+	}
+	return events
+}
+
+func GetSubsonicSetting() SubsonicConfig {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return SubsonicConfig{}
+	}
+
+	config := SubsonicConfig{}
+	config.URL, _ = settings["subsonicUrl"].(string)
+	config.Username, _ = settings["subsonicUsername"].(string)
+	config.Password, _ = settings["subsonicPassword"].(string)
+	config.SkipOwned, _ = settings["subsonicSkipOwned"].(bool)
+	config.ScanOnDownload, _ = settings["subsonicScanOnDownload"].(bool)
+	config.URL = strings.TrimSpace(config.URL)
+	config.Username = strings.TrimSpace(config.Username)
+	return config
+}
+
+func GetDiscordPresenceSetting() (bool, string) {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false, ""
+	}
+
+	enabled, _ := settings["discordRichPresence"].(bool)
+	clientID, _ := settings["discordClientId"].(string)
+	return enabled, strings.TrimSpace(clientID)
+}
+
+func GetClipboardWatcherSetting() bool {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return false
+	}
+
+	enabled, _ := settings["clipboardWatcher"].(bool)
+	return enabled
+}
+
+func ParseServiceList(raw string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(raw), func(r rune) bool {
+		return r == ',' || r == '-' || r == ' ' || r == ';'
+	})
+	services := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field != "" && !slices.Contains(services, field) {
+			services = append(services, field)
+		}
+	}
+	return services
+}
+
+func GetAllowedServicesSetting() ([]string, bool) {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return nil, false
+	}
+
+	allowed, _ := settings["allowedServices"].(string)
+	strict, _ := settings["strictService"].(bool)
+	return ParseServiceList(allowed), strict
+}
@@ -0,0 +1,192 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	networkDropGrace          = 3 * time.Second
+	connectivityProbeInterval = 5 * time.Second
+	connectivityProbeTimeout  = 3 * time.Second
+)
+
+var connectivityProbeHosts = []string{
+	"api.spotify.com:443",
+	"1.1.1.1:443",
+	"8.8.8.8:53",
+}
+
+var (
+	connectivityMu       sync.Mutex
+	networkOffline       bool
+	networkOnlineCh      = make(chan struct{})
+	networkStatusHandler func(online bool)
+	probeInFlight        chan struct{}
+	lastProbeOnline      bool
+)
+
+func SetNetworkStatusHandler(handler func(online bool)) {
+	connectivityMu.Lock()
+	networkStatusHandler = handler
+	connectivityMu.Unlock()
+}
+
+func IsNetworkOffline() bool {
+	connectivityMu.Lock()
+	defer connectivityMu.Unlock()
+	return networkOffline
+}
+
+func IsNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"dial tcp", "no such host", "network is unreachable", "no route to host", "connection reset", "connection refused", "broken pipe", "i/o timeout", "tls handshake timeout", "unexpected eof"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func probeConnectivity() bool {
+	dialer := net.Dialer{Timeout: connectivityProbeTimeout}
+	for _, host := range connectivityProbeHosts {
+		conn, err := dialer.Dial("tcp", host)
+		if err == nil {
+			conn.Close()
+			return true
+		}
+	}
+	return false
+}
+
+func checkConnectivity() bool {
+	connectivityMu.Lock()
+	if networkOffline {
+		connectivityMu.Unlock()
+		return false
+	}
+	if pending := probeInFlight; pending != nil {
+		connectivityMu.Unlock()
+		<-pending
+		connectivityMu.Lock()
+		online := lastProbeOnline
+		connectivityMu.Unlock()
+		return online
+	}
+	done := make(chan struct{})
+	probeInFlight = done
+	connectivityMu.Unlock()
+
+	online := probeConnectivity()
+
+	connectivityMu.Lock()
+	lastProbeOnline = online
+	probeInFlight = nil
+	connectivityMu.Unlock()
+	close(done)
+	return online
+}
+
+// ReportNetworkError reports whether err was caused by the connection going
+// down. The first probe can race the drop itself, so an error that doesn't
+// prove the remote host answered is probed once more after a short grace.
+func ReportNetworkError(err error) bool {
+	if !IsNetworkError(err) {
+		return false
+	}
+	if checkConnectivity() {
+		if strings.Contains(strings.ToLower(err.Error()), "connection refused") {
+			return false
+		}
+		time.Sleep(networkDropGrace)
+		if checkConnectivity() {
+			return false
+		}
+	}
+
+	setNetworkOffline()
+	return true
+}
+
+func setNetworkOffline() {
+	connectivityMu.Lock()
+	if networkOffline {
+		connectivityMu.Unlock()
+		return
+	}
+	networkOffline = true
+	networkOnlineCh = make(chan struct{})
+	handler := networkStatusHandler
+	connectivityMu.Unlock()
+
+	fmt.Println("[Network] Connection lost, pausing downloads until the network returns")
+	if handler != nil {
+		handler(false)
+	}
+	go watchForReconnect()
+}
+
+func watchForReconnect() {
+	ticker := time.NewTicker(connectivityProbeInterval)
+	defer ticker.Stop()
+
+	offlineSince := time.Now()
+	for range ticker.C {
+		if !probeConnectivity() {
+			continue
+		}
+
+		connectivityMu.Lock()
+		networkOffline = false
+		close(networkOnlineCh)
+		handler := networkStatusHandler
+		connectivityMu.Unlock()
+
+		fmt.Printf("[Network] Connection restored after %s, resuming downloads\n", time.Since(offlineSince).Round(time.Second))
+		interruptTransfers()
+		if handler != nil {
+			handler(true)
+		}
+		return
+	}
+}
+
+func WaitForNetwork(ctx context.Context) error {
+	connectivityMu.Lock()
+	offline := networkOffline
+	onlineCh := networkOnlineCh
+	connectivityMu.Unlock()
+	if !offline {
+		return nil
+	}
+
+	select {
+	case <-onlineCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,99 @@
+package backend
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxConsoleLogLines     = 2000
+	maxConsoleLogLineBytes = 4096
+)
+
+var (
+	consoleLogMu      sync.Mutex
+	consoleLogLines   []string
+	consoleLogStarted bool
+)
+
+func appendConsoleLogLine(line string) {
+	consoleLogMu.Lock()
+	defer consoleLogMu.Unlock()
+
+	consoleLogLines = append(consoleLogLines, time.Now().Format("15:04:05")+" "+line)
+	if len(consoleLogLines) > maxConsoleLogLines {
+		consoleLogLines = consoleLogLines[len(consoleLogLines)-maxConsoleLogLines:]
+	}
+}
+
+func StartConsoleCapture() error {
+	consoleLogMu.Lock()
+	if consoleLogStarted {
+		consoleLogMu.Unlock()
+		return nil
+	}
+	consoleLogStarted = true
+	consoleLogMu.Unlock()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	original := os.Stdout
+	os.Stdout = writer
+
+	go func() {
+		// ReadLine never fails on long lines, so the pipe keeps draining and
+		// writers to os.Stdout can't block; oversized lines are truncated in the log.
+		buffered := bufio.NewReaderSize(reader, 64*1024)
+		var line []byte
+		truncated := false
+		for {
+			chunk, isPrefix, err := buffered.ReadLine()
+			if err != nil {
+				os.Stdout = original
+				_ = writer.Close()
+				_ = reader.Close()
+				return
+			}
+			_, _ = original.Write(chunk)
+			if !isPrefix {
+				_, _ = original.WriteString("\n")
+			}
+
+			if room := maxConsoleLogLineBytes - len(line); room > 0 {
+				if len(chunk) > room {
+					chunk, truncated = chunk[:room], true
+				}
+				line = append(line, chunk...)
+			} else if len(chunk) > 0 {
+				truncated = true
+			}
+			if isPrefix {
+				continue
+			}
+			if truncated {
+				appendConsoleLogLine(string(line) + " [truncated]")
+			} else {
+				appendConsoleLogLine(string(line))
+			}
+			line, truncated = line[:0], false
+		}
+	}()
+
+	return nil
+}
+
+func GetConsoleLog() string {
+	consoleLogMu.Lock()
+	defer consoleLogMu.Unlock()
+
+	if len(consoleLogLines) == 0 {
+		return ""
+	}
+	return strings.Join(consoleLogLines, "\n") + "\n"
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
@@ -65,65 +64,20 @@ type CoverClient struct {
 
 func NewCoverClient() *CoverClient {
 	return &CoverClient{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: NewHTTPClient(30 * time.Second),
 	}
 }
 
 func buildCoverFilename(trackName, artistName, albumName, albumArtist, releaseDate, filenameFormat string, includeTrackNumber bool, position, discNumber int) string {
-	safeTitle := sanitizeFilename(trackName)
-	safeArtist := sanitizeFilename(artistName)
-	safeAlbum := sanitizeFilename(albumName)
-	safeAlbumArtist := sanitizeFilename(albumArtist)
-
-	year := ""
-	if len(releaseDate) >= 4 {
-		year = releaseDate[:4]
-	}
-
-	var filename string
-
-	if strings.Contains(filenameFormat, "{") {
-		filename = filenameFormat
-		filename = strings.ReplaceAll(filename, "{title}", safeTitle)
-		filename = strings.ReplaceAll(filename, "{artist}", safeArtist)
-		filename = strings.ReplaceAll(filename, "{album}", safeAlbum)
-		filename = strings.ReplaceAll(filename, "{album_artist}", safeAlbumArtist)
-		filename = strings.ReplaceAll(filename, "{year}", year)
-		filename = strings.ReplaceAll(filename, "{date}", sanitizeFilename(releaseDate))
-
-		if discNumber > 0 {
-			filename = strings.ReplaceAll(filename, "{disc}", fmt.Sprintf("%d", discNumber))
-		} else {
-			filename = strings.ReplaceAll(filename, "{disc}", "")
-		}
-
-		if position > 0 {
-			filename = strings.ReplaceAll(filename, "{track}", fmt.Sprintf("%02d", position))
-		} else {
-
-			filename = regexp.MustCompile(`\{track\}\.\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*-\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*`).ReplaceAllString(filename, "")
-		}
-	} else {
-
-		switch filenameFormat {
-		case "artist-title":
-			filename = fmt.Sprintf("%s - %s", safeArtist, safeTitle)
-		case "title-artist":
-			filename = fmt.Sprintf("%s - %s", safeTitle, safeArtist)
-		case "title":
-			filename = safeTitle
-		default:
-			filename = fmt.Sprintf("%s - %s", safeTitle, safeArtist)
-		}
-
-		if includeTrackNumber && position > 0 {
-			filename = fmt.Sprintf("%02d - %s", position, filename)
-		}
-	}
-
-	return filename + ".jpg"
+	return buildTemplatedFilename(filenameFormat, FilenameTemplateData{
+		Title:       trackName,
+		Artist:      artistName,
+		Album:       albumName,
+		AlbumArtist: albumArtist,
+		ReleaseDate: releaseDate,
+		Track:       position,
+		Disc:        discNumber,
+	}, includeTrackNumber, position, "%02d - ") + ".jpg"
 }
 
 func convertSmallToMedium(imageURL string) string {
@@ -162,14 +116,17 @@ func (c *CoverClient) DownloadCoverToPath(coverURL, outputPath string, embedMaxQ
 		return fmt.Errorf("failed to download cover: HTTP %d", resp.StatusCode)
 	}
 
-	file, err := os.Create(outputPath)
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %v", err)
+		return fmt.Errorf("failed to read cover: %v", err)
 	}
-	defer file.Close()
 
-	_, err = io.Copy(file, resp.Body)
+	data, err = normalizeCoverData(data)
 	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write cover file: %v", err)
 	}
 
@@ -184,7 +141,7 @@ func (c *CoverClient) ApplyMacOSFLACFileIcon(filePath, coverURL string, iconSize
 		return fmt.Errorf("cover URL is required")
 	}
 
-	tmpFile, err := os.CreateTemp("", "spotiflac-file-icon-*.jpg")
+	tmpFile, err := os.CreateTemp(StagingDir(), "spotiflac-file-icon-*.jpg")
 	if err != nil {
 		return fmt.Errorf("failed to create temporary cover file: %w", err)
 	}
@@ -221,7 +178,7 @@ func ResizeImageForIcon(sourcePath string, iconSize int) (string, error) {
 	dst := image.NewRGBA(image.Rect(0, 0, iconSize, iconSize))
 	xdraw.CatmullRom.Scale(dst, dst.Bounds(), srcImage, srcImage.Bounds(), xdraw.Over, nil)
 
-	tmpFile, err := os.CreateTemp("", "spotiflac-resized-icon-*.png")
+	tmpFile, err := os.CreateTemp(StagingDir(), "spotiflac-resized-icon-*.png")
 	if err != nil {
 		return "", fmt.Errorf("failed to create resized icon temp file: %w", err)
 	}
@@ -268,13 +225,15 @@ func (c *CoverClient) DownloadCover(req CoverDownloadRequest) (*CoverDownloadRes
 	filename := buildCoverFilename(req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, filenameFormat, req.TrackNumber, req.Position, req.DiscNumber)
 	filePath := filepath.Join(outputDir, filename)
 
-	if fileInfo, err := os.Stat(filePath); err == nil && fileInfo.Size() > 0 {
-		return &CoverDownloadResponse{
-			Success:       true,
-			Message:       "Cover file already exists",
-			File:          filePath,
-			AlreadyExists: true,
-		}, nil
+	for _, existing := range []string{filePath, strings.TrimSuffix(filePath, ".jpg") + ".png"} {
+		if fileInfo, err := os.Stat(existing); err == nil && fileInfo.Size() > 0 {
+			return &CoverDownloadResponse{
+				Success:       true,
+				Message:       "Cover file already exists",
+				File:          existing,
+				AlreadyExists: true,
+			}, nil
+		}
 	}
 
 	downloadURL := c.getMaxResolutionURL(req.CoverURL)
@@ -295,17 +254,19 @@ func (c *CoverClient) DownloadCover(req CoverDownloadRequest) (*CoverDownloadRes
 		}, fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
-	file, err := os.Create(filePath)
+	data, err := io.ReadAll(resp.Body)
+	if err == nil {
+		data, err = normalizeCoverData(data)
+	}
 	if err != nil {
 		return &CoverDownloadResponse{
 			Success: false,
-			Error:   fmt.Sprintf("failed to create file: %v", err),
+			Error:   fmt.Sprintf("failed to read cover: %v", err),
 		}, err
 	}
-	defer file.Close()
 
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
+	filePath = strings.TrimSuffix(filePath, ".jpg") + coverFileExtension(data)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
 		return &CoverDownloadResponse{
 			Success: false,
 			Error:   fmt.Sprintf("failed to write cover file: %v", err),
@@ -0,0 +1,161 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	defaultCoverJPEGQuality = 90
+	folderCoverFilename     = "cover.jpg"
+)
+
+func ResizeCoverImage(coverPath string, maxSize, quality int) error {
+	data, err := os.ReadFile(coverPath)
+	if err != nil {
+		return fmt.Errorf("failed to read cover image: %w", err)
+	}
+
+	srcImage, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode cover image: %w", err)
+	}
+
+	bounds := srcImage.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	needsResize := maxSize > 0 && (width > maxSize || height > maxSize)
+	if !needsResize && (format == "png" || (format == "jpeg" && quality <= 0)) {
+		return nil
+	}
+
+	dstImage := srcImage
+	if needsResize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+		resized := image.NewRGBA(image.Rect(0, 0, width, height))
+		xdraw.CatmullRom.Scale(resized, resized.Bounds(), srcImage, bounds, xdraw.Over, nil)
+		dstImage = resized
+	}
+
+	encoded, err := encodeCoverImage(dstImage, format, quality)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(coverPath, encoded, 0644); err != nil {
+		return fmt.Errorf("failed to write cover image: %w", err)
+	}
+
+	return nil
+}
+
+func encodeCoverImage(img image.Image, format string, quality int) ([]byte, error) {
+	var encoded bytes.Buffer
+	if format == "png" {
+		if err := png.Encode(&encoded, img); err != nil {
+			return nil, fmt.Errorf("failed to encode cover image: %w", err)
+		}
+		return encoded.Bytes(), nil
+	}
+
+	if quality <= 0 {
+		quality = defaultCoverJPEGQuality
+	}
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode cover image: %w", err)
+	}
+	return encoded.Bytes(), nil
+}
+
+func CoverMIMEType(data []byte) string {
+	if mime := http.DetectContentType(data); strings.HasPrefix(mime, "image/") {
+		return mime
+	}
+	return "image/jpeg"
+}
+
+func coverFileExtension(data []byte) string {
+	if CoverMIMEType(data) == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+func normalizeCoverData(data []byte) ([]byte, error) {
+	switch CoverMIMEType(data) {
+	case "image/jpeg", "image/png":
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cover image: %w", err)
+	}
+	return encodeCoverImage(img, "jpeg", 0)
+}
+
+func PrepareCoverForEmbedding(coverPath string) string {
+	if coverPath == "" || GetCoverFolderOnlySetting() {
+		return ""
+	}
+
+	if err := ResizeCoverImage(coverPath, GetCoverMaxSizeSetting(), GetCoverJPEGQualitySetting()); err != nil {
+		fmt.Printf("Warning: Failed to resize cover for embedding: %v\n", err)
+	}
+	return coverPath
+}
+
+func (c *CoverClient) SaveAlbumCoverFiles(albumDir, coverURL string) error {
+	if albumDir == "" || coverURL == "" {
+		return nil
+	}
+
+	var targets []string
+	if GetAlbumCoverFileSetting() || GetCoverFolderOnlySetting() {
+		targets = append(targets, folderCoverFilename)
+	}
+	if GetAlbumFolderJPGSetting() {
+		targets = append(targets, "folder.jpg")
+	}
+
+	for _, name := range targets {
+		target := filepath.Join(albumDir, name)
+		if fileExists(target) {
+			continue
+		}
+
+		tmpFile, err := os.CreateTemp(albumDir, name+".*.part")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary cover file: %w", err)
+		}
+		tmpPath := tmpFile.Name()
+		tmpFile.Close()
+
+		if err := c.DownloadCoverToPath(coverURL, tmpPath, true); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+		if err := os.Rename(tmpPath, target); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to save %s: %w", name, err)
+		}
+		fmt.Printf("Saved album cover: %s\n", target)
+	}
+
+	return nil
+}
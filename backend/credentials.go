@@ -0,0 +1,388 @@
+package backend
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	credentialsVaultFile    = "credentials.vault"
+	credentialsKeyFile      = "credentials.key"
+	credentialsKeychainName = "SpotiFLAC"
+	credentialsKeychainUser = "credentials-vault"
+	credentialsKeySize      = 32
+
+	CredentialStorageKeychain = "keychain"
+	CredentialStorageFile     = "file"
+)
+
+var CredentialServices = []string{"tidal", "qobuz"}
+
+var errKeychainUnavailable = errors.New("os keychain unavailable")
+
+type ServiceCredential struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	UserID       string `json:"user_id,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+	ExpiresAt    int64  `json:"expires_at,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	ClientID     string `json:"client_id,omitempty"`
+	DisplayName  string `json:"display_name,omitempty"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+type CredentialStatus struct {
+	Service   string `json:"service"`
+	Present   bool   `json:"present"`
+	UserID    string `json:"user_id,omitempty"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+	UpdatedAt int64  `json:"updated_at,omitempty"`
+	Storage   string `json:"storage"`
+}
+
+var (
+	credentialsMu         sync.Mutex
+	credentialsCache      map[string]ServiceCredential
+	credentialsStorage    string
+	credentialsLoadWarned string
+)
+
+func isCredentialService(service string) bool {
+	for _, known := range CredentialServices {
+		if known == service {
+			return true
+		}
+	}
+	return false
+}
+
+func credentialsVaultPath() (string, error) {
+	dir, err := EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, credentialsVaultFile), nil
+}
+
+var (
+	credentialsKeychainGet = keychainGet
+	credentialsKeychainSet = keychainSet
+)
+
+func credentialsVaultExists() (bool, error) {
+	vaultPath, err := credentialsVaultPath()
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(vaultPath); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// loadCredentialsKeyFile reads the legacy key file. A new key is only minted
+// when no vault exists yet; created reports whether that happened.
+func loadCredentialsKeyFile(vaultExists bool) (key []byte, created bool, err error) {
+	dir, err := EnsureAppDir()
+	if err != nil {
+		return nil, false, err
+	}
+	keyPath := filepath.Join(dir, credentialsKeyFile)
+
+	data, err := os.ReadFile(keyPath)
+	if err == nil {
+		key, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if decodeErr != nil || len(key) != credentialsKeySize {
+			return nil, false, fmt.Errorf("credentials key file is corrupt")
+		}
+		return key, false, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, false, err
+	}
+	if vaultExists {
+		return nil, false, fmt.Errorf("credentials vault exists but its key is missing from both the keychain and %s", credentialsKeyFile)
+	}
+
+	key = make([]byte, credentialsKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, false, err
+	}
+	if err := os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(key)), 0o600); err != nil {
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+func migrateCredentialsKeyToKeychain(key []byte) bool {
+	encoded := base64.StdEncoding.EncodeToString(key)
+	if err := credentialsKeychainSet(credentialsKeychainName, credentialsKeychainUser, encoded); err != nil {
+		return false
+	}
+	if stored, err := credentialsKeychainGet(credentialsKeychainName, credentialsKeychainUser); err != nil || stored != encoded {
+		return false
+	}
+
+	dir, err := EnsureAppDir()
+	if err != nil {
+		return true
+	}
+	if err := os.Remove(filepath.Join(dir, credentialsKeyFile)); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Warning: failed to remove credentials key file after moving it to the keychain: %v\n", err)
+	}
+	return true
+}
+
+func loadCredentialsKey() ([]byte, string, error) {
+	encoded, lookupErr := credentialsKeychainGet(credentialsKeychainName, credentialsKeychainUser)
+	// A failed lookup (e.g. secret-tool without a D-Bus session) is treated like
+	// a missing keychain: the key file still works, but nothing is minted for or
+	// migrated into a keychain we couldn't read.
+	keychainAvailable := lookupErr == nil
+	if encoded != "" {
+		key, decodeErr := base64.StdEncoding.DecodeString(encoded)
+		if decodeErr != nil || len(key) != credentialsKeySize {
+			return nil, "", fmt.Errorf("credentials key in keychain is corrupt")
+		}
+		return key, CredentialStorageKeychain, nil
+	}
+
+	vaultExists, err := credentialsVaultExists()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check credentials vault: %w", err)
+	}
+	if keychainAvailable && !vaultExists {
+		key := make([]byte, credentialsKeySize)
+		if _, randErr := rand.Read(key); randErr != nil {
+			return nil, "", randErr
+		}
+		setErr := credentialsKeychainSet(credentialsKeychainName, credentialsKeychainUser, base64.StdEncoding.EncodeToString(key))
+		if setErr == nil {
+			return key, CredentialStorageKeychain, nil
+		}
+		fmt.Printf("Warning: failed to store credentials key in keychain, using key file: %v\n", setErr)
+	}
+
+	key, created, err := loadCredentialsKeyFile(vaultExists)
+	if err != nil {
+		if lookupErr != nil && !errors.Is(lookupErr, errKeychainUnavailable) {
+			return nil, "", fmt.Errorf("failed to load credentials key: %w (keychain lookup failed: %v)", err, lookupErr)
+		}
+		return nil, "", fmt.Errorf("failed to load credentials key: %w", err)
+	}
+	if keychainAvailable && !created && migrateCredentialsKeyToKeychain(key) {
+		return key, CredentialStorageKeychain, nil
+	}
+	return key, CredentialStorageFile, nil
+}
+
+func newCredentialsCipher() (cipher.AEAD, string, error) {
+	key, storage, err := loadCredentialsKey()
+	if err != nil {
+		return nil, "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, "", err
+	}
+	return aead, storage, nil
+}
+
+func loadCredentialsLocked() (map[string]ServiceCredential, error) {
+	if credentialsCache != nil {
+		return credentialsCache, nil
+	}
+
+	vaultPath, err := credentialsVaultPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(vaultPath)
+	if os.IsNotExist(err) {
+		credentialsCache = make(map[string]ServiceCredential)
+		return credentialsCache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	aead, storage, err := newCredentialsCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("credentials vault is corrupt")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials vault: %w", err)
+	}
+
+	entries := make(map[string]ServiceCredential)
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials vault: %w", err)
+	}
+	credentialsCache = entries
+	credentialsStorage = storage
+	return credentialsCache, nil
+}
+
+func saveCredentialsLocked(entries map[string]ServiceCredential) error {
+	vaultPath, err := credentialsVaultPath()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if err := os.Remove(vaultPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		credentialsCache = entries
+		return nil
+	}
+
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	aead, storage, err := newCredentialsCipher()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	tmpPath := PartialDownloadPath(vaultPath)
+	if err := os.WriteFile(tmpPath, aead.Seal(nonce, nonce, plain, nil), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, vaultPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	credentialsCache = entries
+	credentialsStorage = storage
+	return nil
+}
+
+// warnCredentialsLoadLocked logs a vault load failure once per distinct error,
+// since signed service requests look up credentials on every call.
+func warnCredentialsLoadLocked(err error) {
+	if msg := err.Error(); msg != credentialsLoadWarned {
+		credentialsLoadWarned = msg
+		fmt.Printf("Warning: failed to load credentials vault: %v\n", err)
+	}
+}
+
+func GetServiceCredential(service string) (ServiceCredential, bool) {
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+
+	entries, err := loadCredentialsLocked()
+	if err != nil {
+		warnCredentialsLoadLocked(err)
+		return ServiceCredential{}, false
+	}
+	cred, ok := entries[strings.ToLower(service)]
+	if !ok || strings.TrimSpace(cred.Token) == "" {
+		return ServiceCredential{}, false
+	}
+	return cred, true
+}
+
+func SetServiceCredential(service string, cred ServiceCredential) error {
+	service = strings.ToLower(strings.TrimSpace(service))
+	if !isCredentialService(service) {
+		return fmt.Errorf("unsupported credential service: %s", service)
+	}
+	return storeServiceCredential(service, cred)
+}
+
+func storeServiceCredential(service string, cred ServiceCredential) error {
+	cred.Token = strings.TrimSpace(cred.Token)
+	if cred.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+	cred.UpdatedAt = time.Now().Unix()
+
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+
+	entries, err := loadCredentialsLocked()
+	if err != nil {
+		return err
+	}
+	next := make(map[string]ServiceCredential, len(entries)+1)
+	for key, value := range entries {
+		next[key] = value
+	}
+	next[service] = cred
+	return saveCredentialsLocked(next)
+}
+
+func DeleteServiceCredential(service string) error {
+	service = strings.ToLower(strings.TrimSpace(service))
+
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+
+	entries, err := loadCredentialsLocked()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[service]; !ok {
+		return nil
+	}
+	next := make(map[string]ServiceCredential, len(entries))
+	for key, value := range entries {
+		if key != service {
+			next[key] = value
+		}
+	}
+	return saveCredentialsLocked(next)
+}
+
+func GetCredentialStatuses() []CredentialStatus {
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+
+	entries, err := loadCredentialsLocked()
+	if err != nil {
+		warnCredentialsLoadLocked(err)
+		entries = nil
+	}
+
+	statuses := make([]CredentialStatus, 0, len(CredentialServices))
+	for _, service := range CredentialServices {
+		status := CredentialStatus{Service: service, Storage: credentialsStorage}
+		if cred, ok := entries[service]; ok && cred.Token != "" {
+			status.Present = true
+			status.UserID = cred.UserID
+			status.ExpiresAt = cred.ExpiresAt
+			status.UpdatedAt = cred.UpdatedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
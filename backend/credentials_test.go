@@ -0,0 +1,116 @@
+package backend
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func stubCredentialsKeychain(t *testing.T, get func(service, account string) (string, error)) *int {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+
+	originalGet, originalSet := credentialsKeychainGet, credentialsKeychainSet
+	t.Cleanup(func() {
+		credentialsKeychainGet, credentialsKeychainSet = originalGet, originalSet
+	})
+
+	sets := 0
+	credentialsKeychainGet = get
+	credentialsKeychainSet = func(service, account, secret string) error {
+		sets++
+		return nil
+	}
+	return &sets
+}
+
+func writeTestCredentialsVault(t *testing.T) string {
+	t.Helper()
+	vaultPath, err := credentialsVaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(vaultPath, []byte("encrypted"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Dir(vaultPath)
+}
+
+func TestLoadCredentialsKeyFailedLookupKeepsExistingVault(t *testing.T) {
+	sets := stubCredentialsKeychain(t, func(service, account string) (string, error) {
+		return "", errors.New("secret-tool lookup failed: cannot autolaunch D-Bus")
+	})
+	appDir := writeTestCredentialsVault(t)
+
+	if _, _, err := loadCredentialsKey(); err == nil {
+		t.Fatal("expected an error when the keychain lookup fails")
+	}
+	if *sets != 0 {
+		t.Fatalf("keychain was written %d times, want 0", *sets)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, credentialsKeyFile)); !os.IsNotExist(err) {
+		t.Fatalf("a new key file was created: %v", err)
+	}
+}
+
+func TestLoadCredentialsKeyCorruptKeychainEntry(t *testing.T) {
+	sets := stubCredentialsKeychain(t, func(service, account string) (string, error) {
+		return "c2hvcnQ=", nil
+	})
+	writeTestCredentialsVault(t)
+
+	if _, _, err := loadCredentialsKey(); err == nil {
+		t.Fatal("expected an error for a keychain key of the wrong length")
+	}
+	if *sets != 0 {
+		t.Fatalf("keychain was written %d times, want 0", *sets)
+	}
+}
+
+func TestLoadCredentialsKeyMissingKeyWithExistingVault(t *testing.T) {
+	sets := stubCredentialsKeychain(t, func(service, account string) (string, error) {
+		return "", nil
+	})
+	appDir := writeTestCredentialsVault(t)
+
+	if _, _, err := loadCredentialsKey(); err == nil {
+		t.Fatal("expected an error instead of minting a key for an existing vault")
+	}
+	if *sets != 0 {
+		t.Fatalf("keychain was written %d times, want 0", *sets)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, credentialsKeyFile)); !os.IsNotExist(err) {
+		t.Fatalf("a new key file was created: %v", err)
+	}
+}
+
+func TestLoadCredentialsKeyFailedLookupFallsBackToKeyFile(t *testing.T) {
+	sets := stubCredentialsKeychain(t, func(service, account string) (string, error) {
+		return "", errors.New("secret-tool lookup failed: cannot autolaunch D-Bus")
+	})
+
+	key, storage, err := loadCredentialsKey()
+	if err != nil {
+		t.Fatalf("expected a key file fallback without a vault, got %v", err)
+	}
+	if storage != CredentialStorageFile {
+		t.Fatalf("storage = %q, want %q", storage, CredentialStorageFile)
+	}
+
+	appDir := writeTestCredentialsVault(t)
+	again, storage, err := loadCredentialsKey()
+	if err != nil {
+		t.Fatalf("expected the existing key file to be used, got %v", err)
+	}
+	if string(again) != string(key) || storage != CredentialStorageFile {
+		t.Fatal("existing key file was not reused")
+	}
+	if *sets != 0 {
+		t.Fatalf("keychain was written %d times, want 0", *sets)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, credentialsKeyFile)); err != nil {
+		t.Fatalf("key file was removed: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const cueFramesPerSecond = 75
+
+type CueTrack struct {
+	Path        string
+	Title       string
+	Performer   string
+	ISRC        string
+	TrackNumber int
+	DiscNumber  int
+	Duration    float64
+}
+
+func formatCueTime(seconds float64) string {
+	frames := int64(seconds*cueFramesPerSecond + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d", frames/(60*cueFramesPerSecond), (frames/cueFramesPerSecond)%60, frames%cueFramesPerSecond)
+}
+
+func cueQuote(value string) string {
+	return `"` + strings.ReplaceAll(strings.TrimSpace(value), `"`, "'") + `"`
+}
+
+func cueFileType(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".mp3") {
+		return "MP3"
+	}
+	return "WAVE"
+}
+
+func BuildAlbumCueSheet(files []string) (string, string, error) {
+	var tracks []CueTrack
+	album, albumArtist, year := "", "", ""
+
+	for _, file := range files {
+		metadata, err := ReadAudioMetadata(file)
+		if err != nil {
+			fmt.Printf("[CUE] Skipping %s: %v\n", filepath.Base(file), err)
+			continue
+		}
+		duration, err := GetAudioDuration(file)
+		if err != nil {
+			fmt.Printf("[CUE] Warning: failed to read duration of %s: %v\n", filepath.Base(file), err)
+		}
+
+		tracks = append(tracks, CueTrack{
+			Path:        file,
+			Title:       metadata.Title,
+			Performer:   metadata.Artist,
+			ISRC:        metadata.ISRC,
+			TrackNumber: metadata.TrackNumber,
+			DiscNumber:  metadata.DiscNumber,
+			Duration:    duration,
+		})
+		if album == "" {
+			album = metadata.Album
+		}
+		if albumArtist == "" {
+			albumArtist = metadata.AlbumArtist
+		}
+		if year == "" {
+			year = metadata.Year
+		}
+	}
+
+	if len(tracks) == 0 {
+		return "", "", fmt.Errorf("no readable tracks")
+	}
+
+	sort.SliceStable(tracks, func(i, j int) bool {
+		if tracks[i].DiscNumber != tracks[j].DiscNumber {
+			return tracks[i].DiscNumber < tracks[j].DiscNumber
+		}
+		if tracks[i].TrackNumber != tracks[j].TrackNumber {
+			return tracks[i].TrackNumber < tracks[j].TrackNumber
+		}
+		return tracks[i].Path < tracks[j].Path
+	})
+
+	if albumArtist == "" {
+		albumArtist = tracks[0].Performer
+	}
+
+	var sb strings.Builder
+	if year != "" {
+		sb.WriteString(fmt.Sprintf("REM DATE %s\n", extractYear(year)))
+	}
+	sb.WriteString("REM COMMENT \"SpotiFLAC\"\n")
+	if albumArtist != "" {
+		sb.WriteString(fmt.Sprintf("PERFORMER %s\n", cueQuote(albumArtist)))
+	}
+	if album != "" {
+		sb.WriteString(fmt.Sprintf("TITLE %s\n", cueQuote(album)))
+	}
+
+	var offset float64
+	for i, track := range tracks {
+		title := track.Title
+		if title == "" {
+			title = strings.TrimSuffix(filepath.Base(track.Path), filepath.Ext(track.Path))
+		}
+
+		sb.WriteString(fmt.Sprintf("FILE %s %s\n", cueQuote(filepath.Base(track.Path)), cueFileType(track.Path)))
+		sb.WriteString(fmt.Sprintf("  TRACK %02d AUDIO\n", i+1))
+		sb.WriteString(fmt.Sprintf("    TITLE %s\n", cueQuote(title)))
+		if track.Performer != "" {
+			sb.WriteString(fmt.Sprintf("    PERFORMER %s\n", cueQuote(track.Performer)))
+		}
+		if track.ISRC != "" {
+			sb.WriteString(fmt.Sprintf("    ISRC %s\n", strings.ToUpper(track.ISRC)))
+		}
+		if track.Duration > 0 {
+			sb.WriteString(fmt.Sprintf("    REM LENGTH %s\n", formatCueTime(track.Duration)))
+			sb.WriteString(fmt.Sprintf("    REM ALBUM_OFFSET %s\n", formatCueTime(offset)))
+			offset += track.Duration
+		}
+		sb.WriteString("    INDEX 01 00:00:00\n")
+	}
+
+	return sb.String(), album, nil
+}
+
+func WriteAlbumCueSheet(folder string, files []string) (string, error) {
+	content, album, err := BuildAlbumCueSheet(files)
+	if err != nil {
+		return "", err
+	}
+
+	name := SanitizeFilename(album)
+	if name == "" {
+		name = filepath.Base(folder)
+	}
+	cuePath := filepath.Join(folder, name+".cue")
+
+	tmpPath := cuePath + ".part"
+	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write cue sheet: %w", err)
+	}
+	if err := os.Rename(tmpPath, cuePath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write cue sheet: %w", err)
+	}
+	return cuePath, nil
+}
+
+func WriteAlbumFolderCueSheet(folder string) (string, error) {
+	files, err := listAlbumFolderAudioFiles(folder)
+	if err != nil {
+		return "", err
+	}
+	return WriteAlbumCueSheet(folder, files)
+}
@@ -0,0 +1,296 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	dataUsageDBFile       = "data_usage.db"
+	dataUsageBucket       = "DataUsage"
+	dataUsageFlushBytes   = 16 << 20
+	dataUsageMonitorEvery = 15 * time.Second
+)
+
+type DataUsage struct {
+	Day                string `json:"day"`
+	Month              string `json:"month"`
+	DayBytes           int64  `json:"day_bytes"`
+	MonthBytes         int64  `json:"month_bytes"`
+	MeteredDayBytes    int64  `json:"metered_day_bytes"`
+	MeteredMonthBytes  int64  `json:"metered_month_bytes"`
+	DailyBudgetBytes   int64  `json:"daily_budget_bytes"`
+	MonthlyBudgetBytes int64  `json:"monthly_budget_bytes"`
+	MeteredOnly        bool   `json:"metered_only"`
+	Metered            bool   `json:"metered"`
+	Exceeded           bool   `json:"exceeded"`
+	Reason             string `json:"reason,omitempty"`
+}
+
+type DataBudget struct {
+	DailyBytes   int64
+	MonthlyBytes int64
+	MeteredOnly  bool
+}
+
+var errDataUsageDBClosed = errors.New("data usage database is closed")
+
+var (
+	dataUsageDB       *bolt.DB
+	dataUsageDBMu     sync.Mutex
+	dataUsageDBClosed bool
+
+	pendingUsageBytes   atomic.Int64
+	pendingMeteredBytes atomic.Int64
+	onMeteredConnection atomic.Bool
+)
+
+func InitDataUsageDB() error {
+	dataUsageDBMu.Lock()
+	defer dataUsageDBMu.Unlock()
+	return initDataUsageDBLocked()
+}
+
+func initDataUsageDBLocked() error {
+	if dataUsageDBClosed {
+		return errDataUsageDBClosed
+	}
+	if dataUsageDB != nil {
+		return nil
+	}
+
+	appDir, err := EnsureAppDir()
+	if err != nil {
+		return err
+	}
+
+	dbPath := filepath.Join(appDir, dataUsageDBFile)
+	db, err := bolt.Open(dbPath, 0o600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(dataUsageBucket))
+		return err
+	}); err != nil {
+		db.Close()
+		return err
+	}
+
+	dataUsageDB = db
+	return nil
+}
+
+func CloseDataUsageDB() {
+	if err := FlushDataUsage(); err != nil {
+		fmt.Printf("Warning: failed to save data usage: %v\n", err)
+	}
+
+	dataUsageDBMu.Lock()
+	defer dataUsageDBMu.Unlock()
+
+	dataUsageDBClosed = true
+	if dataUsageDB != nil {
+		_ = dataUsageDB.Close()
+		dataUsageDB = nil
+	}
+}
+
+func withDataUsageDB(fn func(db *bolt.DB) error) error {
+	dataUsageDBMu.Lock()
+	defer dataUsageDBMu.Unlock()
+
+	if err := initDataUsageDBLocked(); err != nil {
+		return err
+	}
+	return fn(dataUsageDB)
+}
+
+func GetDataBudgetSetting() DataBudget {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return DataBudget{}
+	}
+
+	var budget DataBudget
+	if mb, ok := settings["dataBudgetDailyMB"].(float64); ok && mb > 0 {
+		budget.DailyBytes = int64(mb * 1024 * 1024)
+	}
+	if mb, ok := settings["dataBudgetMonthlyMB"].(float64); ok && mb > 0 {
+		budget.MonthlyBytes = int64(mb * 1024 * 1024)
+	}
+	budget.MeteredOnly, _ = settings["dataBudgetMeteredOnly"].(bool)
+	return budget
+}
+
+func usageKeys(now time.Time) (string, string) {
+	return now.Format("2006-01-02"), now.Format("2006-01")
+}
+
+func recordDataUsage(n int) {
+	if n <= 0 {
+		return
+	}
+	if onMeteredConnection.Load() {
+		pendingMeteredBytes.Add(int64(n))
+	}
+	if pendingUsageBytes.Add(int64(n)) >= dataUsageFlushBytes {
+		if err := FlushDataUsage(); err != nil {
+			fmt.Printf("Warning: failed to save data usage: %v\n", err)
+		}
+	}
+}
+
+type usageCountingBody struct {
+	io.ReadCloser
+}
+
+func (b *usageCountingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	recordDataUsage(n)
+	return n, err
+}
+
+func readUsageCounter(bucket *bolt.Bucket, key string) int64 {
+	value, _ := strconv.ParseInt(string(bucket.Get([]byte(key))), 10, 64)
+	return value
+}
+
+func addUsageCounter(bucket *bolt.Bucket, key string, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	total := readUsageCounter(bucket, key) + delta
+	return bucket.Put([]byte(key), []byte(strconv.FormatInt(total, 10)))
+}
+
+func FlushDataUsage() error {
+	total := pendingUsageBytes.Swap(0)
+	metered := pendingMeteredBytes.Swap(0)
+	if total == 0 && metered == 0 {
+		return nil
+	}
+
+	restore := func() {
+		pendingUsageBytes.Add(total)
+		pendingMeteredBytes.Add(metered)
+	}
+
+	day, month := usageKeys(time.Now())
+	err := withDataUsageDB(func(db *bolt.DB) error {
+		return db.Update(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(dataUsageBucket))
+			for key, delta := range map[string]int64{
+				"day:" + day:             total,
+				"month:" + month:         total,
+				"metered-day:" + day:     metered,
+				"metered-month:" + month: metered,
+			} {
+				if err := addUsageCounter(bucket, key, delta); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		restore()
+	}
+	return err
+}
+
+func GetDataUsage() (DataUsage, error) {
+	if err := FlushDataUsage(); err != nil {
+		return DataUsage{}, err
+	}
+
+	budget := GetDataBudgetSetting()
+	day, month := usageKeys(time.Now())
+	usage := DataUsage{
+		Day:                day,
+		Month:              month,
+		DailyBudgetBytes:   budget.DailyBytes,
+		MonthlyBudgetBytes: budget.MonthlyBytes,
+		MeteredOnly:        budget.MeteredOnly,
+		Metered:            onMeteredConnection.Load(),
+	}
+
+	err := withDataUsageDB(func(db *bolt.DB) error {
+		return db.View(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(dataUsageBucket))
+			usage.DayBytes = readUsageCounter(bucket, "day:"+day)
+			usage.MonthBytes = readUsageCounter(bucket, "month:"+month)
+			usage.MeteredDayBytes = readUsageCounter(bucket, "metered-day:"+day)
+			usage.MeteredMonthBytes = readUsageCounter(bucket, "metered-month:"+month)
+			return nil
+		})
+	})
+	if err != nil {
+		return DataUsage{}, err
+	}
+
+	dayBytes, monthBytes := usage.DayBytes, usage.MonthBytes
+	if budget.MeteredOnly {
+		dayBytes, monthBytes = usage.MeteredDayBytes, usage.MeteredMonthBytes
+		if !usage.Metered {
+			return usage, nil
+		}
+	}
+	switch {
+	case budget.DailyBytes > 0 && dayBytes >= budget.DailyBytes:
+		usage.Exceeded = true
+		usage.Reason = fmt.Sprintf("daily data budget of %.0f MB reached", float64(budget.DailyBytes)/(1024*1024))
+	case budget.MonthlyBytes > 0 && monthBytes >= budget.MonthlyBytes:
+		usage.Exceeded = true
+		usage.Reason = fmt.Sprintf("monthly data budget of %.0f MB reached", float64(budget.MonthlyBytes)/(1024*1024))
+	}
+	return usage, nil
+}
+
+func ApplyDataBudget() (DataUsage, bool) {
+	if GetDataBudgetSetting().MeteredOnly {
+		IsMeteredConnection()
+	}
+
+	usage, err := GetDataUsage()
+	if err != nil {
+		fmt.Printf("Warning: failed to read data usage: %v\n", err)
+		return usage, false
+	}
+
+	changed := setPauseHold(PauseReasonBudget, usage.Exceeded)
+	if changed {
+		if usage.Exceeded {
+			fmt.Printf("[Budget] Pausing queue: %s\n", usage.Reason)
+		} else {
+			fmt.Println("[Budget] Data budget available again, resuming queue")
+		}
+	}
+	return usage, changed
+}
+
+func StartDataUsageMonitor(ctx context.Context, onChange func(DataUsage)) {
+	go func() {
+		for {
+			if usage, changed := ApplyDataBudget(); changed && onChange != nil {
+				onChange(usage)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(dataUsageMonitorEvery):
+			}
+		}
+	}()
+}
@@ -0,0 +1,282 @@
+package backend
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+const (
+	discordPresenceInterval = 5 * time.Second
+	discordIOTimeout        = 5 * time.Second
+	discordMaxFrameSize     = 1 << 20
+
+	discordOpHandshake = 0
+	discordOpFrame     = 1
+	discordOpClose     = 2
+)
+
+type discordPresence struct {
+	conn     io.ReadWriteCloser
+	clientID string
+	lastSent string
+	warned   bool
+}
+
+func discordIPCPaths() []string {
+	var paths []string
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("discord-ipc-%d", i)
+		if runtime.GOOS == "windows" {
+			paths = append(paths, `\\.\pipe\`+name)
+			continue
+		}
+
+		var dirs []string
+		for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+			if dir := os.Getenv(env); dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		dirs = append(dirs, "/tmp")
+		for _, dir := range dirs {
+			paths = append(paths,
+				filepath.Join(dir, name),
+				filepath.Join(dir, "app", "com.discordapp.Discord", name),
+				filepath.Join(dir, "snap.discord", name),
+			)
+		}
+	}
+	return paths
+}
+
+func dialDiscordIPC() (io.ReadWriteCloser, error) {
+	for _, path := range discordIPCPaths() {
+		if runtime.GOOS == "windows" {
+			if f, err := os.OpenFile(path, os.O_RDWR, 0); err == nil {
+				return f, nil
+			}
+			continue
+		}
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, fmt.Errorf("discord is not running")
+}
+
+func (p *discordPresence) setDeadline() {
+	if conn, ok := p.conn.(interface{ SetDeadline(time.Time) error }); ok {
+		conn.SetDeadline(time.Now().Add(discordIOTimeout))
+	}
+}
+
+func (p *discordPresence) send(op uint32, payload interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	p.setDeadline()
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header[0:4], op)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(data)))
+	if _, err := p.conn.Write(append(header, data...)); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(p.conn, header); err != nil {
+		return nil, err
+	}
+	replyOp := binary.LittleEndian.Uint32(header[0:4])
+	length := binary.LittleEndian.Uint32(header[4:8])
+	if length > discordMaxFrameSize {
+		return nil, fmt.Errorf("discord frame too large: %d bytes", length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(p.conn, body); err != nil {
+		return nil, err
+	}
+
+	var reply map[string]interface{}
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return nil, err
+	}
+	if replyOp == discordOpClose {
+		return nil, fmt.Errorf("discord closed the connection: %v", reply["message"])
+	}
+	if evt, _ := reply["evt"].(string); evt == "ERROR" {
+		if data, ok := reply["data"].(map[string]interface{}); ok {
+			return nil, fmt.Errorf("discord error: %v", data["message"])
+		}
+		return nil, fmt.Errorf("discord returned an error")
+	}
+	return reply, nil
+}
+
+func (p *discordPresence) connect(clientID string) error {
+	conn, err := dialDiscordIPC()
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	p.clientID = clientID
+
+	if _, err := p.send(discordOpHandshake, map[string]interface{}{"v": 1, "client_id": clientID}); err != nil {
+		p.close()
+		return err
+	}
+	fmt.Println("[Discord] Rich Presence connected")
+	return nil
+}
+
+func (p *discordPresence) close() {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn = nil
+	p.lastSent = ""
+}
+
+func (p *discordPresence) setActivity(activity map[string]interface{}) error {
+	_, err := p.send(discordOpFrame, map[string]interface{}{
+		"cmd":   "SET_ACTIVITY",
+		"nonce": strconv.FormatInt(time.Now().UnixNano(), 10),
+		"args": map[string]interface{}{
+			"pid":      os.Getpid(),
+			"activity": activity,
+		},
+	})
+	return err
+}
+
+func discordText(text string) string {
+	runes := []rune(text)
+	if len(runes) > 128 {
+		runes = append(runes[:127], '…')
+	}
+	for len(runes) < 2 {
+		runes = append(runes, ' ')
+	}
+	return string(runes)
+}
+
+func currentDiscordActivity() map[string]interface{} {
+	queue := GetDownloadQueue()
+	if !queue.IsDownloading {
+		return nil
+	}
+
+	var current *DownloadItem
+	position := 0
+	for i := range queue.Queue {
+		if queue.Queue[i].Status == StatusDownloading {
+			current = &queue.Queue[i]
+			position = i + 1
+			break
+		}
+	}
+	if current == nil {
+		return nil
+	}
+
+	state := fmt.Sprintf("by %s", current.ArtistName)
+	if current.Progress > 0 {
+		state = fmt.Sprintf("%s · %.1f MB", state, current.Progress)
+	}
+	if len(queue.Queue) > 1 {
+		state = fmt.Sprintf("%s · %d/%d", state, position, len(queue.Queue))
+	}
+
+	activity := map[string]interface{}{
+		"details": discordText(current.TrackName),
+		"state":   discordText(state),
+	}
+	if current.StartTime > 0 {
+		activity["timestamps"] = map[string]interface{}{"start": current.StartTime}
+	}
+
+	assets := map[string]interface{}{}
+	if current.CoverURL != "" {
+		assets["large_image"] = current.CoverURL
+	}
+	if current.AlbumName != "" {
+		assets["large_text"] = discordText(current.AlbumName)
+	}
+	if len(assets) > 0 {
+		activity["assets"] = assets
+	}
+	return activity
+}
+
+func (p *discordPresence) update() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Warning: discord presence update panicked: %v\n", r)
+			p.close()
+		}
+	}()
+
+	enabled, clientID := GetDiscordPresenceSetting()
+	if !enabled || clientID == "" {
+		p.close()
+		return
+	}
+	if p.conn != nil && p.clientID != clientID {
+		p.close()
+	}
+
+	activity := currentDiscordActivity()
+	key, _ := json.Marshal(activity)
+	if string(key) == p.lastSent {
+		return
+	}
+	if p.conn == nil {
+		if activity == nil {
+			return
+		}
+		if err := p.connect(clientID); err != nil {
+			if !p.warned {
+				fmt.Printf("Warning: discord presence unavailable: %v\n", err)
+				p.warned = true
+			}
+			return
+		}
+		p.warned = false
+	}
+
+	if err := p.setActivity(activity); err != nil {
+		fmt.Printf("Warning: failed to update discord presence: %v\n", err)
+		p.close()
+		return
+	}
+	p.lastSent = string(key)
+}
+
+func StartDiscordPresence(ctx context.Context) {
+	go func() {
+		presence := &discordPresence{}
+		defer presence.close()
+
+		ticker := time.NewTicker(discordPresenceInterval)
+		defer ticker.Stop()
+
+		for {
+			presence.update()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
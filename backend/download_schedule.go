@@ -0,0 +1,163 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ScheduleModeOff               = "off"
+	ScheduleModeWindow            = "window"
+	ScheduleModeUnmetered         = "unmetered"
+	ScheduleModeWindowOrUnmetered = "window-or-unmetered"
+
+	downloadScheduleInterval = 30 * time.Second
+	defaultWindowStart       = "01:00"
+	defaultWindowEnd         = "07:00"
+)
+
+var errMeteredUnknown = errors.New("metered state unknown")
+
+var meteredWarnOnce sync.Once
+
+type DownloadSchedule struct {
+	Mode        string `json:"mode"`
+	WindowStart string `json:"window_start"`
+	WindowEnd   string `json:"window_end"`
+}
+
+type DownloadScheduleStatus struct {
+	DownloadSchedule
+	Allowed bool   `json:"allowed"`
+	Metered bool   `json:"metered"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func GetDownloadScheduleSetting() DownloadSchedule {
+	schedule := DownloadSchedule{Mode: ScheduleModeOff, WindowStart: defaultWindowStart, WindowEnd: defaultWindowEnd}
+
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return schedule
+	}
+
+	if mode, ok := settings["downloadScheduleMode"].(string); ok {
+		switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+		case ScheduleModeWindow, ScheduleModeUnmetered, ScheduleModeWindowOrUnmetered:
+			schedule.Mode = mode
+		}
+	}
+	if start, ok := settings["downloadWindowStart"].(string); ok {
+		if _, err := parseClockMinutes(start); err == nil {
+			schedule.WindowStart = strings.TrimSpace(start)
+		}
+	}
+	if end, ok := settings["downloadWindowEnd"].(string); ok {
+		if _, err := parseClockMinutes(end); err == nil {
+			schedule.WindowEnd = strings.TrimSpace(end)
+		}
+	}
+	return schedule
+}
+
+func parseClockMinutes(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (use HH:MM)", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func (s DownloadSchedule) InWindow(now time.Time) bool {
+	start, err := parseClockMinutes(s.WindowStart)
+	if err != nil {
+		return true
+	}
+	end, err := parseClockMinutes(s.WindowEnd)
+	if err != nil {
+		return true
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	switch {
+	case start == end:
+		return true
+	case start < end:
+		return current >= start && current < end
+	default:
+		return current >= start || current < end
+	}
+}
+
+func IsMeteredConnection() bool {
+	metered, err := detectMeteredConnection()
+	if err != nil {
+		meteredWarnOnce.Do(func() {
+			fmt.Printf("Warning: cannot detect metered connections on this system, treating network as unmetered: %v\n", err)
+		})
+		onMeteredConnection.Store(false)
+		return false
+	}
+	onMeteredConnection.Store(metered)
+	return metered
+}
+
+func EvaluateDownloadSchedule(now time.Time) DownloadScheduleStatus {
+	status := DownloadScheduleStatus{DownloadSchedule: GetDownloadScheduleSetting(), Allowed: true}
+
+	if status.Mode == ScheduleModeUnmetered || status.Mode == ScheduleModeWindowOrUnmetered {
+		status.Metered = IsMeteredConnection()
+	}
+
+	switch status.Mode {
+	case ScheduleModeWindow:
+		if !status.InWindow(now) {
+			status.Allowed = false
+			status.Reason = fmt.Sprintf("outside download window %s-%s", status.WindowStart, status.WindowEnd)
+		}
+	case ScheduleModeUnmetered:
+		if status.Metered {
+			status.Allowed = false
+			status.Reason = "on a metered connection"
+		}
+	case ScheduleModeWindowOrUnmetered:
+		if status.Metered && !status.InWindow(now) {
+			status.Allowed = false
+			status.Reason = fmt.Sprintf("on a metered connection outside download window %s-%s", status.WindowStart, status.WindowEnd)
+		}
+	}
+	return status
+}
+
+func ApplyDownloadSchedule(now time.Time) (DownloadScheduleStatus, bool) {
+	status := EvaluateDownloadSchedule(now)
+	changed := setPauseHold(PauseReasonSchedule, !status.Allowed)
+	if changed {
+		if status.Allowed {
+			fmt.Println("[Schedule] Download window open, resuming queue")
+		} else {
+			fmt.Printf("[Schedule] Pausing queue: %s\n", status.Reason)
+		}
+	}
+	return status, changed
+}
+
+func StartDownloadScheduler(ctx context.Context, onChange func(DownloadScheduleStatus)) {
+	go func() {
+		for {
+			if status, changed := ApplyDownloadSchedule(time.Now()); changed && onChange != nil {
+				onChange(status)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(downloadScheduleInterval):
+			}
+		}
+	}()
+}
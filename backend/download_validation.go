@@ -1,8 +1,11 @@
 package backend
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"sync"
 )
 
 const (
@@ -13,6 +16,72 @@ const (
 	durationDiffRatio         = 0.25
 )
 
+const (
+	MismatchActionReject = "reject"
+	MismatchActionFlag   = "flag"
+)
+
+var deliveredISRCs sync.Map
+
+func RecordDeliveredISRC(filePath, isrc string) {
+	isrc = strings.ToUpper(strings.TrimSpace(isrc))
+	if filePath == "" || isrc == "" {
+		return
+	}
+	deliveredISRCs.Store(filePath, isrc)
+}
+
+func takeDeliveredISRC(filePath string) string {
+	value, ok := deliveredISRCs.LoadAndDelete(filePath)
+	if !ok {
+		return ""
+	}
+	isrc, _ := value.(string)
+	return isrc
+}
+
+func ValidateDownloadedTrack(filePath string, expectedSeconds int, expectedISRC, spotifyID string) (bool, string, error) {
+	isrcWarning := deliveredISRCWarning(takeDeliveredISRC(filePath), expectedISRC, spotifyID)
+
+	validated, err := ValidateDownloadedTrackDuration(filePath, expectedSeconds)
+	if err != nil {
+		var mismatch *trackMismatchError
+		if GetMismatchActionSetting() != MismatchActionFlag || !errors.As(err, &mismatch) {
+			return validated, "", err
+		}
+		fmt.Printf("[DownloadValidation] Keeping flagged file %s: %s\n", filePath, mismatch.detail)
+		validated = true
+		isrcWarning = strings.TrimPrefix(isrcWarning+"; "+mismatch.detail, "; ")
+	}
+
+	return validated, isrcWarning, nil
+}
+
+type trackMismatchError struct {
+	detail string
+}
+
+func (e *trackMismatchError) Error() string {
+	return e.detail + ". file was removed"
+}
+
+func deliveredISRCWarning(delivered, expectedISRC, spotifyID string) string {
+	if delivered == "" {
+		return ""
+	}
+
+	expectedISRC = strings.ToUpper(strings.TrimSpace(expectedISRC))
+	if expectedISRC == "" && spotifyID != "" {
+		expectedISRC = strings.ToUpper(ResolveTrackISRC(spotifyID))
+	}
+	if expectedISRC == "" || delivered == expectedISRC {
+		return ""
+	}
+
+	fmt.Printf("[DownloadValidation] ISRC mismatch: delivered %s, expected %s\n", delivered, expectedISRC)
+	return fmt.Sprintf("ISRC mismatch: delivered %s, expected %s", delivered, expectedISRC)
+}
+
 func ValidateDownloadedTrackDuration(filePath string, expectedSeconds int) (bool, error) {
 	if filePath == "" || expectedSeconds <= 0 {
 		return false, nil
@@ -32,11 +101,15 @@ func ValidateDownloadedTrackDuration(filePath string, expectedSeconds int) (bool
 		return true, fmt.Errorf("detected preview/sample download: file is %ds, expected about %ds. file was removed", actualSeconds, expectedSeconds)
 	}
 
-	if expectedSeconds >= largeMismatchMinExpected {
+	diff := int(math.Abs(float64(actualSeconds - expectedSeconds)))
+	if tolerance := GetDurationToleranceSetting(); tolerance > 0 {
+		if diff > tolerance {
+			return true, &trackMismatchError{detail: fmt.Sprintf("downloaded file duration mismatch: file is %ds, expected about %ds", actualSeconds, expectedSeconds)}
+		}
+	} else if expectedSeconds >= largeMismatchMinExpected {
 		allowedDiff := int(math.Max(minAllowedDurationDiff, math.Round(float64(expectedSeconds)*durationDiffRatio)))
-		diff := int(math.Abs(float64(actualSeconds - expectedSeconds)))
 		if diff > allowedDiff {
-			return true, fmt.Errorf("downloaded file duration mismatch: file is %ds, expected about %ds. file was removed", actualSeconds, expectedSeconds)
+			return true, &trackMismatchError{detail: fmt.Sprintf("downloaded file duration mismatch: file is %ds, expected about %ds", actualSeconds, expectedSeconds)}
 		}
 	}
 
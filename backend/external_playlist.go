@@ -0,0 +1,480 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	youtubeMusicBrowseURL     = "https://music.youtube.com/youtubei/v1/browse?prettyPrint=false"
+	youtubeMusicClientVersion = "1.20250101.01.00"
+	externalPlaylistMaxPages  = 50
+)
+
+var appleMusicServerData = regexp.MustCompile(`(?s)<script[^>]*id="serialized-server-data"[^>]*>(.*?)</script>`)
+
+type ExternalPlaylistTrack struct {
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	URL        string `json:"url,omitempty"`
+	DurationMS int    `json:"duration_ms,omitempty"`
+}
+
+type externalPlaylist struct {
+	Source string
+	Name   string
+	Owner  string
+	Cover  string
+	Tracks []ExternalPlaylistTrack
+}
+
+func isAppleMusicPlaylistURL(parsed *url.URL) bool {
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	if host != "music.apple.com" && host != "embed.music.apple.com" {
+		return false
+	}
+	return strings.Contains(parsed.Path, "/playlist/")
+}
+
+func youtubeMusicPlaylistID(parsed *url.URL) string {
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	if host != "music.youtube.com" && host != "youtube.com" && host != "m.youtube.com" {
+		return ""
+	}
+	if strings.TrimRight(parsed.Path, "/") != "/playlist" {
+		return ""
+	}
+	return strings.TrimSpace(parsed.Query().Get("list"))
+}
+
+func IsExternalPlaylistURL(rawURL string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	return isAppleMusicPlaylistURL(parsed) || youtubeMusicPlaylistID(parsed) != ""
+}
+
+func fetchExternalPlaylist(ctx context.Context, rawURL string) (*externalPlaylist, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, err
+	}
+	if isAppleMusicPlaylistURL(parsed) {
+		return fetchAppleMusicPlaylist(ctx, parsed.String())
+	}
+	if id := youtubeMusicPlaylistID(parsed); id != "" {
+		return fetchYouTubeMusicPlaylist(ctx, id)
+	}
+	return nil, fmt.Errorf("unsupported playlist URL: %s", rawURL)
+}
+
+func walkJSONObjects(value interface{}, fn func(map[string]interface{})) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		fn(typed)
+		for _, child := range typed {
+			walkJSONObjects(child, fn)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			walkJSONObjects(child, fn)
+		}
+	}
+}
+
+func jsonString(value map[string]interface{}, keys ...string) string {
+	var current interface{} = value
+	for _, key := range keys {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = object[key]
+	}
+	text, _ := current.(string)
+	return strings.TrimSpace(text)
+}
+
+func fetchAppleMusicPlaylist(ctx context.Context, pageURL string) (*externalPlaylist, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", songLinkUserAgent)
+
+	resp, err := NewHTTPClient(30 * time.Second).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Apple Music playlist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Apple Music returned HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	match := appleMusicServerData.FindSubmatch(body)
+	if match == nil {
+		return nil, fmt.Errorf("Apple Music playlist data not found in page")
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(match[1], &data); err != nil {
+		return nil, fmt.Errorf("failed to decode Apple Music playlist data: %w", err)
+	}
+
+	playlist := &externalPlaylist{Source: "Apple Music"}
+	walkJSONObjects(data, func(object map[string]interface{}) {
+		items, _ := object["items"].([]interface{})
+		switch object["itemKind"] {
+		case "containerDetailHeaderLockup":
+			for _, raw := range items {
+				item, ok := raw.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if playlist.Name == "" {
+					playlist.Name = jsonString(item, "title")
+				}
+				if links, ok := item["subtitleLinks"].([]interface{}); ok && len(links) > 0 && playlist.Owner == "" {
+					if link, ok := links[0].(map[string]interface{}); ok {
+						playlist.Owner = jsonString(link, "title")
+					}
+				}
+				if cover := jsonString(item, "artwork", "dictionary", "url"); cover != "" && playlist.Cover == "" {
+					replacer := strings.NewReplacer("{w}", "600", "{h}", "600", "{c}", "bb", "{f}", "jpg")
+					playlist.Cover = replacer.Replace(cover)
+				}
+			}
+		case "trackLockup":
+			for _, raw := range items {
+				item, ok := raw.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				track := ExternalPlaylistTrack{
+					Title:  jsonString(item, "title"),
+					Artist: jsonString(item, "artistName"),
+					URL:    jsonString(item, "contentDescriptor", "url"),
+				}
+				if duration, ok := item["duration"].(float64); ok {
+					track.DurationMS = int(duration)
+				}
+				if track.Title != "" {
+					playlist.Tracks = append(playlist.Tracks, track)
+				}
+			}
+		}
+	})
+
+	if len(playlist.Tracks) == 0 {
+		return nil, fmt.Errorf("no tracks found in Apple Music playlist")
+	}
+	if playlist.Name == "" {
+		playlist.Name = "Apple Music Playlist"
+	}
+	return playlist, nil
+}
+
+func youtubeMusicText(value interface{}) string {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if simple, ok := object["simpleText"].(string); ok {
+		return strings.TrimSpace(simple)
+	}
+	runs, _ := object["runs"].([]interface{})
+	var b strings.Builder
+	for _, raw := range runs {
+		if run, ok := raw.(map[string]interface{}); ok {
+			text, _ := run["text"].(string)
+			b.WriteString(text)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func youtubeMusicFlexColumn(renderer map[string]interface{}, index int) string {
+	columns, _ := renderer["flexColumns"].([]interface{})
+	if index >= len(columns) {
+		return ""
+	}
+	column, _ := columns[index].(map[string]interface{})
+	inner, _ := column["musicResponsiveListItemFlexColumnRenderer"].(map[string]interface{})
+	return youtubeMusicText(inner["text"])
+}
+
+func youtubeMusicDuration(text string) int {
+	var total int
+	for _, part := range strings.Split(text, ":") {
+		var value int
+		if _, err := fmt.Sscanf(strings.TrimSpace(part), "%d", &value); err != nil {
+			return 0
+		}
+		total = total*60 + value
+	}
+	return total * 1000
+}
+
+func youtubeMusicBrowse(ctx context.Context, body map[string]interface{}) (interface{}, error) {
+	body["context"] = map[string]interface{}{
+		"client": map[string]interface{}{
+			"clientName":    "WEB_REMIX",
+			"clientVersion": youtubeMusicClientVersion,
+			"hl":            "en",
+		},
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, youtubeMusicBrowseURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", songLinkUserAgent)
+	req.Header.Set("Origin", "https://music.youtube.com")
+
+	resp, err := NewHTTPClient(30 * time.Second).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch YouTube Music playlist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("YouTube Music returned HTTP %d", resp.StatusCode)
+	}
+
+	var data interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode YouTube Music response: %w", err)
+	}
+	return data, nil
+}
+
+func fetchYouTubeMusicPlaylist(ctx context.Context, playlistID string) (*externalPlaylist, error) {
+	playlist := &externalPlaylist{Source: "YouTube Music"}
+	body := map[string]interface{}{"browseId": "VL" + playlistID}
+	seen := make(map[string]bool)
+
+	for page := 0; page < externalPlaylistMaxPages; page++ {
+		data, err := youtubeMusicBrowse(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+
+		continuation := ""
+		added := 0
+		walkJSONObjects(data, func(object map[string]interface{}) {
+			for _, key := range []string{"musicResponsiveHeaderRenderer", "musicDetailHeaderRenderer", "musicEditablePlaylistDetailHeaderRenderer"} {
+				if header, ok := object[key].(map[string]interface{}); ok && playlist.Name == "" {
+					playlist.Name = youtubeMusicText(header["title"])
+					if owner := youtubeMusicText(header["straplineTextOne"]); owner != "" {
+						playlist.Owner = owner
+					}
+				}
+			}
+
+			if token := jsonString(object, "continuationCommand", "token"); token != "" {
+				continuation = token
+			}
+			if token := jsonString(object, "nextContinuationData", "continuation"); token != "" {
+				continuation = token
+			}
+
+			renderer, ok := object["musicResponsiveListItemRenderer"].(map[string]interface{})
+			if !ok {
+				return
+			}
+			videoID := jsonString(renderer, "playlistItemData", "videoId")
+			if videoID == "" || seen[videoID] {
+				return
+			}
+			seen[videoID] = true
+
+			track := ExternalPlaylistTrack{
+				Title: youtubeMusicFlexColumn(renderer, 0),
+				URL:   "https://music.youtube.com/watch?v=" + videoID,
+			}
+			artist := youtubeMusicFlexColumn(renderer, 1)
+			if idx := strings.Index(artist, " • "); idx >= 0 {
+				artist = artist[:idx]
+			}
+			track.Artist = strings.TrimSpace(artist)
+
+			if fixed, ok := renderer["fixedColumns"].([]interface{}); ok && len(fixed) > 0 {
+				if column, ok := fixed[0].(map[string]interface{}); ok {
+					if inner, ok := column["musicResponsiveListItemFixedColumnRenderer"].(map[string]interface{}); ok {
+						track.DurationMS = youtubeMusicDuration(youtubeMusicText(inner["text"]))
+					}
+				}
+			}
+			if track.Title != "" {
+				playlist.Tracks = append(playlist.Tracks, track)
+				added++
+			}
+		})
+
+		if continuation == "" || added == 0 || ctx.Err() != nil {
+			break
+		}
+		body = map[string]interface{}{"continuation": continuation}
+	}
+
+	if len(playlist.Tracks) == 0 {
+		return nil, fmt.Errorf("no tracks found in YouTube Music playlist")
+	}
+	if playlist.Name == "" {
+		playlist.Name = "YouTube Music Playlist"
+	}
+	return playlist, nil
+}
+
+func (c *SpotifyMetadataClient) resolveExternalTrack(ctx context.Context, track ExternalPlaylistTrack) (AlbumTrackMetadata, bool) {
+	for _, query := range BuildSearchQueries(track.Title, track.Artist) {
+		results, err := c.SearchByType(ctx, query, "track", 5, 0)
+		if err != nil {
+			continue
+		}
+		var matches []SearchResult
+		var candidates []VersionCandidate
+		for _, result := range results {
+			if !SearchResultMatches(track.Title, track.Artist, result.Name, result.Artists) {
+				continue
+			}
+			matches = append(matches, result)
+			candidates = append(candidates, VersionCandidate{
+				Title:       result.Name,
+				Artist:      result.Artists,
+				Album:       result.AlbumName,
+				ReleaseDate: result.ReleaseDate,
+				DurationSec: (result.Duration + 500) / 1000,
+			})
+		}
+		target := VersionTarget{Title: track.Title, Artist: track.Artist, DurationSec: (track.DurationMS + 500) / 1000}
+		if best := PickBestVersion(target, candidates); best >= 0 {
+			result := matches[best]
+			return AlbumTrackMetadata{
+				SpotifyID:   result.ID,
+				Artists:     result.Artists,
+				Name:        result.Name,
+				AlbumName:   result.AlbumName,
+				DurationMS:  result.Duration,
+				Images:      result.Images,
+				ExternalURL: result.ExternalURL,
+				IsExplicit:  result.IsExplicit,
+			}, true
+		}
+	}
+
+	if track.URL == "" {
+		return AlbumTrackMetadata{}, false
+	}
+
+	links, err := NewSongLinkClient().fetchSongLinkLinksByURL(track.URL, "")
+	if err != nil {
+		return AlbumTrackMetadata{}, false
+	}
+	link, ok := links.LinksByPlatform["spotify"]
+	if !ok || link.URL == "" {
+		return AlbumTrackMetadata{}, false
+	}
+	spotifyID, err := extractSpotifyTrackID(link.URL)
+	if err != nil {
+		return AlbumTrackMetadata{}, false
+	}
+
+	raw, err := c.fetchTrack(ctx, spotifyID)
+	if err != nil {
+		return AlbumTrackMetadata{}, false
+	}
+	meta := c.formatTrackData(raw).Track
+	return AlbumTrackMetadata{
+		SpotifyID:   meta.SpotifyID,
+		Artists:     meta.Artists,
+		Name:        meta.Name,
+		AlbumName:   meta.AlbumName,
+		AlbumArtist: meta.AlbumArtist,
+		DurationMS:  meta.DurationMS,
+		Images:      meta.Images,
+		ReleaseDate: meta.ReleaseDate,
+		TrackNumber: meta.TrackNumber,
+		TotalTracks: meta.TotalTracks,
+		DiscNumber:  meta.DiscNumber,
+		TotalDiscs:  meta.TotalDiscs,
+		ExternalURL: meta.ExternalURL,
+		AlbumID:     meta.AlbumID,
+		AlbumURL:    meta.AlbumURL,
+		ArtistID:    meta.ArtistID,
+		ArtistURL:   meta.ArtistURL,
+		ArtistsData: meta.ArtistsData,
+		UPC:         meta.UPC,
+		IsExplicit:  meta.IsExplicit,
+	}, true
+}
+
+func (c *SpotifyMetadataClient) getExternalPlaylistData(ctx context.Context, rawURL string, callback MetadataCallback) (PlaylistResponsePayload, error) {
+	playlist, err := fetchExternalPlaylist(ctx, rawURL)
+	if err != nil {
+		return PlaylistResponsePayload{}, err
+	}
+	fmt.Printf("[%s] Resolving %d track(s) from %s\n", playlist.Source, len(playlist.Tracks), playlist.Name)
+
+	var info PlaylistInfoMetadata
+	info.Tracks.Total = len(playlist.Tracks)
+	info.Owner.DisplayName = playlist.Owner
+	info.Owner.Name = playlist.Name
+	info.Cover = playlist.Cover
+	info.Description = fmt.Sprintf("Imported from %s", playlist.Source)
+
+	if callback != nil {
+		callback(PlaylistResponsePayload{
+			PlaylistInfo: info,
+			TrackList:    []AlbumTrackMetadata{},
+		})
+	}
+
+	tracks := make([]AlbumTrackMetadata, 0, len(playlist.Tracks))
+	var unmatched int
+	for _, track := range playlist.Tracks {
+		if err := ctx.Err(); err != nil {
+			return PlaylistResponsePayload{}, err
+		}
+		resolved, ok := c.resolveExternalTrack(ctx, track)
+		if !ok {
+			unmatched++
+			fmt.Printf("[%s] No Spotify match for %s - %s\n", playlist.Source, track.Artist, track.Title)
+			continue
+		}
+		tracks = append(tracks, resolved)
+	}
+
+	if unmatched > 0 {
+		info.Description = fmt.Sprintf("Imported from %s (%d of %d tracks could not be matched)", playlist.Source, unmatched, len(playlist.Tracks))
+	}
+	info.Tracks.Total = len(tracks)
+
+	if callback != nil {
+		callback(tracks)
+	}
+
+	return PlaylistResponsePayload{
+		PlaylistInfo: info,
+		TrackList:    tracks,
+	}, nil
+}
@@ -0,0 +1,217 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	pathfilepath "path/filepath"
+	"sort"
+	"strings"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+)
+
+func normalizeExtraTagKey(key string) string {
+	key = strings.ToUpper(strings.TrimSpace(key))
+
+	var result strings.Builder
+	for _, r := range key {
+		if r < 0x20 || r > 0x7D || r == '=' {
+			continue
+		}
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
+
+func normalizeExtraTags(tags map[string]string) ([]string, map[string]string) {
+	normalized := make(map[string]string, len(tags))
+	for key, value := range tags {
+		key = normalizeExtraTagKey(key)
+		if key == "" {
+			continue
+		}
+		normalized[key] = strings.TrimSpace(value)
+	}
+
+	keys := make([]string, 0, len(normalized))
+	for key := range normalized {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, normalized
+}
+
+func applyVorbisExtraTags(cmt *flacvorbis.MetaDataBlockVorbisComment, tags map[string]string) {
+	keys, normalized := normalizeExtraTags(tags)
+	if len(keys) == 0 {
+		return
+	}
+
+	filtered := cmt.Comments[:0]
+	for _, comment := range cmt.Comments {
+		parts := strings.SplitN(comment, "=", 2)
+		if _, overridden := normalized[strings.ToUpper(parts[0])]; overridden {
+			continue
+		}
+		filtered = append(filtered, comment)
+	}
+	cmt.Comments = filtered
+
+	for _, key := range keys {
+		if value := normalized[key]; value != "" {
+			_ = cmt.Add(key, value)
+		}
+	}
+}
+
+func applyMP3ExtraTags(tag *id3v2.Tag, tags map[string]string) {
+	keys, normalized := normalizeExtraTags(tags)
+	for _, key := range keys {
+		value := normalized[key]
+
+		switch key {
+		case "COMMENT":
+			tag.DeleteFrames(tag.CommonID("Comments"))
+			if value != "" {
+				tag.AddCommentFrame(id3v2.CommentFrame{
+					Encoding: id3v2.EncodingUTF8,
+					Language: "eng",
+					Text:     value,
+				})
+			}
+			continue
+		case "GROUPING":
+			addMP3TextFrame(tag, "TIT1", value)
+			continue
+		}
+
+		existing := tag.GetFrames("TXXX")
+		tag.DeleteFrames("TXXX")
+		for _, frame := range existing {
+			userTextFrame, ok := frame.(id3v2.UserDefinedTextFrame)
+			if ok && strings.EqualFold(userTextFrame.Description, key) {
+				continue
+			}
+			tag.AddFrame("TXXX", frame)
+		}
+
+		if value != "" {
+			tag.AddUserDefinedTextFrame(id3v2.UserDefinedTextFrame{
+				Encoding:    id3v2.EncodingUTF8,
+				Description: key,
+				Value:       value,
+			})
+		}
+	}
+}
+
+func appendFFmpegExtraTagArgs(args []string, tags map[string]string) []string {
+	keys, normalized := normalizeExtraTags(tags)
+	for _, key := range keys {
+		args = append(args, "-metadata", strings.ToLower(key)+"="+normalized[key])
+	}
+	return args
+}
+
+func EmbedExtraTags(filePath string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	switch strings.ToLower(pathfilepath.Ext(filePath)) {
+	case ".flac":
+		return embedExtraTagsToFLAC(filePath, tags)
+	case ".mp3":
+		tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+		if err != nil {
+			return fmt.Errorf("failed to open MP3 file: %w", err)
+		}
+		defer tag.Close()
+
+		applyMP3ExtraTags(tag, tags)
+		if err := tag.Save(); err != nil {
+			return fmt.Errorf("failed to save MP3 tags: %w", err)
+		}
+		return nil
+	case ".m4a":
+		return embedExtraTagsToM4A(filePath, tags)
+	default:
+		return fmt.Errorf("unsupported file format: %s", pathfilepath.Ext(filePath))
+	}
+}
+
+func embedExtraTagsToFLAC(filePath string, tags map[string]string) error {
+	f, err := flac.ParseFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse FLAC file: %w", err)
+	}
+
+	cmtIdx := -1
+	cmt := flacvorbis.New()
+	for idx, block := range f.Meta {
+		if block.Type == flac.VorbisComment {
+			cmtIdx = idx
+			if existing, err := flacvorbis.ParseFromMetaDataBlock(*block); err == nil {
+				cmt = existing
+			}
+			break
+		}
+	}
+
+	applyVorbisExtraTags(cmt, tags)
+
+	cmtBlock := cmt.Marshal()
+	if cmtIdx < 0 {
+		f.Meta = append(f.Meta, &cmtBlock)
+	} else {
+		f.Meta[cmtIdx] = &cmtBlock
+	}
+
+	if err := f.Save(filePath); err != nil {
+		return fmt.Errorf("failed to save FLAC file: %w", err)
+	}
+
+	return nil
+}
+
+func embedExtraTagsToM4A(filePath string, tags map[string]string) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	args := []string{"-i", filePath, "-y", "-map", "0", "-codec", "copy", "-map_metadata", "0"}
+	args = appendFFmpegExtraTagArgs(args, tags)
+
+	tmpOutputFile := strings.TrimSuffix(filePath, pathfilepath.Ext(filePath)) + ".tmp" + pathfilepath.Ext(filePath)
+	defer func() {
+		if _, err := os.Stat(tmpOutputFile); err == nil {
+			os.Remove(tmpOutputFile)
+		}
+	}()
+
+	args = append(args, "-f", "ipod", tmpOutputFile)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed to embed tags: %s - %w", string(output), err)
+	}
+
+	if err := os.Rename(tmpOutputFile, filePath); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+
+	return nil
+}
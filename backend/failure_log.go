@@ -0,0 +1,204 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	failedDownloadsBucket = "FailedDownloads"
+	maxFailureAttempts    = 20
+)
+
+const (
+	FailureCodeNotFound    = "not_found"
+	FailureCodeRateLimited = "rate_limited"
+	FailureCodeProtected   = "upstream_protected"
+	FailureCodeAuth        = "auth"
+	FailureCodeTimeout     = "timeout"
+	FailureCodeNetwork     = "network"
+	FailureCodeValidation  = "validation"
+	FailureCodeQuality     = "below_quality"
+	FailureCodeCancelled   = "cancelled"
+	FailureCodeUnknown     = "unknown"
+)
+
+type DownloadAttempt struct {
+	Service   string `json:"service"`
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type FailedDownload struct {
+	Key              string            `json:"key"`
+	SpotifyID        string            `json:"spotify_id,omitempty"`
+	TrackName        string            `json:"track_name"`
+	ArtistName       string            `json:"artist_name"`
+	AlbumName        string            `json:"album_name,omitempty"`
+	ISRC             string            `json:"isrc,omitempty"`
+	Playlist         string            `json:"playlist,omitempty"`
+	PlaylistPosition int               `json:"playlist_position,omitempty"`
+	Attempts         []DownloadAttempt `json:"attempts"`
+	FirstFailedAt    int64             `json:"first_failed_at"`
+	LastFailedAt     int64             `json:"last_failed_at"`
+}
+
+var failureCodePatterns = []struct {
+	code     string
+	patterns []string
+}{
+	{FailureCodeQuality, []string{strings.ToLower(PendingBetterSourcePrefix)}},
+	{FailureCodeCancelled, []string{"context canceled", "cancelled", "canceled"}},
+	{FailureCodeProtected, []string{"cloudflare"}},
+	{FailureCodeRateLimited, []string{"429", "rate limit", "too many requests"}},
+	{FailureCodeAuth, []string{"401", "403", "unauthorized", "forbidden", "token", "credential"}},
+	{FailureCodeTimeout, []string{"timeout", "timed out", "deadline exceeded"}},
+	{FailureCodeValidation, []string{"duration", "validation", "mismatch", "corrupt"}},
+	{FailureCodeNotFound, []string{"404", "not found", "no match", "not available", "unavailable", "no results", "no link"}},
+	{FailureCodeNetwork, []string{"connection", "no such host", "eof", "network", "dial tcp", "tls"}},
+}
+
+func ClassifyDownloadError(errMsg string) string {
+	lower := strings.ToLower(errMsg)
+	for _, entry := range failureCodePatterns {
+		for _, pattern := range entry.patterns {
+			if strings.Contains(lower, pattern) {
+				return entry.code
+			}
+		}
+	}
+	return FailureCodeUnknown
+}
+
+func FailedDownloadKey(spotifyID, trackName, artistName string) string {
+	if spotifyID = strings.TrimSpace(spotifyID); spotifyID != "" {
+		return spotifyID
+	}
+	return strings.ToLower(strings.TrimSpace(trackName) + "|" + strings.TrimSpace(artistName))
+}
+
+func RecordDownloadFailure(failure FailedDownload, attempt DownloadAttempt, appName string) error {
+	if historyDB == nil {
+		if err := InitHistoryDB(appName); err != nil {
+			return err
+		}
+	}
+
+	if failure.Key == "" {
+		failure.Key = FailedDownloadKey(failure.SpotifyID, failure.TrackName, failure.ArtistName)
+	}
+	if attempt.Timestamp == 0 {
+		attempt.Timestamp = time.Now().Unix()
+	}
+	if attempt.Code == "" {
+		attempt.Code = ClassifyDownloadError(attempt.Error)
+	}
+
+	return historyDB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(failedDownloadsBucket))
+		if err != nil {
+			return err
+		}
+
+		record := failure
+		if v := b.Get([]byte(failure.Key)); v != nil {
+			var existing FailedDownload
+			if err := json.Unmarshal(v, &existing); err == nil {
+				record = existing
+				if failure.ISRC != "" {
+					record.ISRC = failure.ISRC
+				}
+				if failure.Playlist != "" {
+					record.Playlist = failure.Playlist
+					record.PlaylistPosition = failure.PlaylistPosition
+				}
+			}
+		}
+		if record.FirstFailedAt == 0 {
+			record.FirstFailedAt = attempt.Timestamp
+		}
+		record.LastFailedAt = attempt.Timestamp
+		record.Attempts = append(record.Attempts, attempt)
+		if len(record.Attempts) > maxFailureAttempts {
+			record.Attempts = record.Attempts[len(record.Attempts)-maxFailureAttempts:]
+		}
+
+		buf, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(record.Key), buf)
+	})
+}
+
+func ClearDownloadFailure(key string, appName string) error {
+	if historyDB == nil {
+		if err := InitHistoryDB(appName); err != nil {
+			return err
+		}
+	}
+	return historyDB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(failedDownloadsBucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+func GetFailedDownloads(appName string) ([]FailedDownload, error) {
+	if historyDB == nil {
+		if err := InitHistoryDB(appName); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []FailedDownload
+	err := historyDB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(failedDownloadsBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var item FailedDownload
+			if err := json.Unmarshal(v, &item); err == nil {
+				items = append(items, item)
+			}
+			return nil
+		})
+	})
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].LastFailedAt > items[j].LastFailedAt
+	})
+
+	return items, err
+}
+
+func ClearFailedDownloads(appName string) error {
+	if historyDB == nil {
+		if err := InitHistoryDB(appName); err != nil {
+			return err
+		}
+	}
+	return historyDB.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(failedDownloadsBucket)) == nil {
+			return nil
+		}
+		return tx.DeleteBucket([]byte(failedDownloadsBucket))
+	})
+}
+
+func (f FailedDownload) LastError() string {
+	if len(f.Attempts) == 0 {
+		return ""
+	}
+	last := f.Attempts[len(f.Attempts)-1]
+	return fmt.Sprintf("[%s] %s", last.Service, last.Error)
+}
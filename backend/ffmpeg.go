@@ -3,7 +3,8 @@ package backend
 import (
 	"archive/tar"
 	"archive/zip"
-
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -169,6 +171,21 @@ func runExecutableVersionCheck(path string) error {
 	return cmd.Run()
 }
 
+func GetFFmpegVersion() (string, error) {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command(ffmpegPath, "-version")
+	setHideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0]), nil
+}
+
 func removeMacOSQuarantineAttribute(path string) error {
 	cmd := exec.Command("xattr", "-d", "com.apple.quarantine", path)
 	setHideWindow(cmd)
@@ -467,14 +484,14 @@ func downloadWithFallback(urls []string, destDir string, progressCallback func(i
 
 func downloadAndExtract(url, destDir string, progressCallback func(int), progressStart, progressEnd int) error {
 
-	tmpFile, err := os.CreateTemp("", "ffmpeg-*")
+	tmpFile, err := os.CreateTemp(StagingDir(), "ffmpeg-*")
 	if err != nil {
 		return fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer os.Remove(tmpFile.Name())
 	defer tmpFile.Close()
 
-	client := &http.Client{}
+	client := NewHTTPClient(0)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -739,10 +756,12 @@ func extractTarXz(tarXzPath, destDir string) error {
 }
 
 type ConvertAudioRequest struct {
-	InputFiles   []string `json:"input_files"`
-	OutputFormat string   `json:"output_format"`
-	Bitrate      string   `json:"bitrate"`
-	Codec        string   `json:"codec"`
+	InputFiles     []string `json:"input_files"`
+	OutputFormat   string   `json:"output_format"`
+	Bitrate        string   `json:"bitrate"`
+	Codec          string   `json:"codec"`
+	DeleteOriginal bool     `json:"delete_original,omitempty"`
+	LoudnessTarget float64  `json:"loudness_target,omitempty"`
 }
 
 type ConvertAudioResult struct {
@@ -769,166 +788,266 @@ func ConvertAudio(req ConvertAudioRequest) ([]ConvertAudioResult, error) {
 
 	results := make([]ConvertAudioResult, len(req.InputFiles))
 	var wg sync.WaitGroup
-	var mu sync.Mutex
 
 	for i, inputFile := range req.InputFiles {
 		wg.Add(1)
 		go func(idx int, inputFile string) {
 			defer wg.Done()
+			outputDir := filepath.Join(filepath.Dir(inputFile), strings.ToUpper(req.OutputFormat))
+			results[idx] = convertAudioFile(ffmpegPath, inputFile, outputDir, req)
+		}(i, inputFile)
+	}
 
-			result := ConvertAudioResult{
-				InputFile: inputFile,
-			}
+	wg.Wait()
+	return results, nil
+}
 
-			inputExt := strings.ToLower(filepath.Ext(inputFile))
-			baseName := strings.TrimSuffix(filepath.Base(inputFile), inputExt)
-			inputDir := filepath.Dir(inputFile)
+func ConvertDownloadedFile(inputFile string, req ConvertAudioRequest) (string, error) {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get ffmpeg path: %w", err)
+	}
 
-			outputFormatUpper := strings.ToUpper(req.OutputFormat)
-			outputDir := filepath.Join(inputDir, outputFormatUpper)
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return "", fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
 
-			if err := os.MkdirAll(outputDir, 0755); err != nil {
-				result.Error = fmt.Sprintf("failed to create output directory: %v", err)
-				result.Success = false
-				mu.Lock()
-				results[idx] = result
-				mu.Unlock()
-				return
-			}
+	result := convertAudioFile(ffmpegPath, inputFile, filepath.Dir(inputFile), req)
+	if !result.Success {
+		return "", errors.New(result.Error)
+	}
+	return result.OutputFile, nil
+}
 
-			outputExt := "." + strings.ToLower(req.OutputFormat)
-			outputFile := filepath.Join(outputDir, baseName+outputExt)
-			outputFile = norm.NFC.String(outputFile)
-
-			if inputExt == outputExt {
-				result.Error = "Input and output formats are the same"
-				result.Success = false
-				mu.Lock()
-				results[idx] = result
-				mu.Unlock()
-				return
-			}
+func convertAudioFile(ffmpegPath, inputFile, outputDir string, req ConvertAudioRequest) ConvertAudioResult {
+	result := ConvertAudioResult{
+		InputFile: inputFile,
+	}
 
-			result.OutputFile = outputFile
+	inputExt := strings.ToLower(filepath.Ext(inputFile))
+	baseName := strings.TrimSuffix(filepath.Base(inputFile), inputExt)
 
-			var coverArtPath string
-			var lyrics string
-			var inputMetadata Metadata
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		result.Error = fmt.Sprintf("failed to create output directory: %v", err)
+		return result
+	}
 
-			inputMetadata, err = ExtractFullMetadataFromFile(inputFile)
-			if err != nil {
-				fmt.Printf("[FFmpeg] Warning: Failed to extract metadata from %s: %v\n", inputFile, err)
-			}
+	outputExt := ConvertOutputExtension(req.OutputFormat)
+	outputFile := filepath.Join(outputDir, baseName+outputExt)
+	outputFile = norm.NFC.String(outputFile)
 
-			inputFile = norm.NFC.String(inputFile)
-			coverArtPath, err = ExtractCoverArt(inputFile)
-			if err != nil {
-				fmt.Printf("[FFmpeg] Warning: Failed to extract cover art from %s: %v\n", inputFile, err)
-			}
-			lyrics, err = ExtractLyrics(inputFile)
-			if err != nil {
-				fmt.Printf("[FFmpeg] Warning: Failed to extract lyrics from %s: %v\n", inputFile, err)
-			} else if lyrics != "" {
-				fmt.Printf("[FFmpeg] Lyrics extracted from %s: %d characters\n", inputFile, len(lyrics))
-			} else {
-				fmt.Printf("[FFmpeg] No lyrics found in %s\n", inputFile)
-			}
+	if inputExt == outputExt {
+		result.Error = "Input and output formats are the same"
+		return result
+	}
 
-			inputMetadata.Lyrics = lyrics
+	result.OutputFile = outputFile
 
-			args := []string{
-				"-i", inputFile,
-				"-y",
-			}
+	inputMetadata, err := ExtractFullMetadataFromFile(inputFile)
+	if err != nil {
+		fmt.Printf("[FFmpeg] Warning: Failed to extract metadata from %s: %v\n", inputFile, err)
+	}
 
-			switch req.OutputFormat {
-			case "mp3":
-				args = append(args,
-					"-codec:a", "libmp3lame",
-					"-b:a", req.Bitrate,
-					"-map", "0:a",
-					"-id3v2_version", "3",
-				)
-			case "m4a":
-
-				codec := req.Codec
-				if codec == "" {
-					codec = "aac"
-				}
+	inputFile = norm.NFC.String(inputFile)
+	coverArtPath, err := ExtractCoverArt(inputFile)
+	if err != nil {
+		fmt.Printf("[FFmpeg] Warning: Failed to extract cover art from %s: %v\n", inputFile, err)
+	}
+	if coverArtPath != "" {
+		defer os.Remove(coverArtPath)
+	}
+	lyrics, err := ExtractLyrics(inputFile)
+	if err != nil {
+		fmt.Printf("[FFmpeg] Warning: Failed to extract lyrics from %s: %v\n", inputFile, err)
+	} else if lyrics != "" {
+		fmt.Printf("[FFmpeg] Lyrics extracted from %s: %d characters\n", inputFile, len(lyrics))
+	} else {
+		fmt.Printf("[FFmpeg] No lyrics found in %s\n", inputFile)
+	}
 
-				if codec == "alac" {
+	inputMetadata.Lyrics = lyrics
 
-					args = append(args,
-						"-codec:a", "alac",
-						"-map", "0:a",
-					)
-				} else {
+	args := []string{
+		"-i", inputFile,
+		"-y",
+	}
 
-					args = append(args,
-						"-codec:a", "aac",
-						"-b:a", req.Bitrate,
-						"-map", "0:a",
-					)
-				}
-			}
+	switch req.OutputFormat {
+	case "mp3":
+		args = append(args,
+			"-codec:a", "libmp3lame",
+			"-b:a", req.Bitrate,
+			"-map", "0:a",
+			"-id3v2_version", "3",
+		)
+	case "m4a":
 
-			args = append(args, outputFile)
+		codec := req.Codec
+		if codec == "" {
+			codec = "aac"
+		}
 
-			fmt.Printf("[FFmpeg] Converting: %s -> %s\n", inputFile, outputFile)
+		if codec == "alac" {
 
-			cmd := exec.Command(ffmpegPath, args...)
+			args = append(args,
+				"-codec:a", "alac",
+				"-map", "0:a",
+			)
+		} else {
 
-			setHideWindow(cmd)
-			output, err := cmd.CombinedOutput()
-			if err != nil {
-				result.Error = fmt.Sprintf("conversion failed: %s - %s", err.Error(), string(output))
-				result.Success = false
-				mu.Lock()
-				results[idx] = result
-				mu.Unlock()
+			args = append(args,
+				"-codec:a", "aac",
+				"-b:a", req.Bitrate,
+				"-map", "0:a",
+			)
+		}
+	case "alac":
+		args = append(args,
+			"-codec:a", "alac",
+			"-map", "0:a",
+		)
+	case "wav", "aiff":
+		args = append(args,
+			"-codec:a", pcmCodecForSource(inputFile, req.OutputFormat),
+			"-map", "0:a",
+		)
+	case "opus":
+		bitrate := req.Bitrate
+		if bitrate == "" {
+			bitrate = "160k"
+		}
+		args = append(args,
+			"-codec:a", "libopus",
+			"-b:a", bitrate,
+			"-vbr", "on",
+			"-map", "0:a",
+		)
+	case "ogg":
+		args = append(args, "-codec:a", "libvorbis", "-map", "0:a")
+		if req.Bitrate != "" {
+			args = append(args, "-b:a", req.Bitrate)
+		} else {
+			args = append(args, "-q:a", "6")
+		}
+	}
 
-				if coverArtPath != "" {
-					os.Remove(coverArtPath)
-				}
-				return
-			}
+	loudnessTarget := req.LoudnessTarget
+	if loudnessTarget == 0 {
+		loudnessTarget = GetLoudnessTargetSetting()
+	}
+	if loudnessTarget < 0 && isLoudnormTarget(req.OutputFormat, req.Codec) {
+		args = append(args, loudnormArgs(ffmpegPath, inputFile, req.OutputFormat, loudnessTarget)...)
+	}
 
-			if err := EmbedMetadataToConvertedFile(outputFile, inputMetadata, coverArtPath); err != nil {
-				fmt.Printf("[FFmpeg] Warning: Failed to embed metadata: %v\n", err)
-			} else {
-				fmt.Printf("[FFmpeg] Metadata embedded successfully\n")
-			}
+	args = append(args, outputFile)
 
-			if lyrics != "" {
-				if err := EmbedLyricsOnlyUniversal(outputFile, lyrics); err != nil {
-					fmt.Printf("[FFmpeg] Warning: Failed to embed lyrics: %v\n", err)
-				} else {
-					fmt.Printf("[FFmpeg] Lyrics embedded successfully\n")
-				}
-			}
+	fmt.Printf("[FFmpeg] Converting: %s -> %s\n", inputFile, outputFile)
 
-			if coverArtPath != "" {
-				os.Remove(coverArtPath)
-			}
+	cmd := exec.Command(ffmpegPath, args...)
+
+	setHideWindow(cmd)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		result.Error = fmt.Sprintf("conversion failed: %s - %s", err.Error(), string(output))
+		return result
+	}
 
-			result.Success = true
-			fmt.Printf("[FFmpeg] Successfully converted: %s\n", outputFile)
+	if err := EmbedMetadataToConvertedFile(outputFile, inputMetadata, coverArtPath); err != nil {
+		fmt.Printf("[FFmpeg] Warning: Failed to embed metadata: %v\n", err)
+	} else {
+		fmt.Printf("[FFmpeg] Metadata embedded successfully\n")
+	}
 
-			mu.Lock()
-			results[idx] = result
-			mu.Unlock()
-		}(i, inputFile)
+	if lyrics != "" && outputExt != ".opus" && outputExt != ".ogg" {
+		if err := EmbedLyricsOnlyUniversal(outputFile, lyrics); err != nil {
+			fmt.Printf("[FFmpeg] Warning: Failed to embed lyrics: %v\n", err)
+		} else {
+			fmt.Printf("[FFmpeg] Lyrics embedded successfully\n")
+		}
 	}
 
-	wg.Wait()
-	return results, nil
+	if req.DeleteOriginal {
+		if err := os.Remove(inputFile); err != nil {
+			fmt.Printf("[FFmpeg] Warning: Failed to remove original %s: %v\n", inputFile, err)
+		}
+	}
+
+	result.Success = true
+	fmt.Printf("[FFmpeg] Successfully converted: %s\n", outputFile)
+	return result
+}
+
+func NormalizeConvertTarget(format string) string {
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case "mp3", "m4a", "alac", "opus", "ogg", "wav", "aiff":
+		return format
+	case "aac":
+		return "m4a"
+	default:
+		return ""
+	}
+}
+
+func ConvertOutputExtension(format string) string {
+	switch strings.ToLower(format) {
+	case "alac":
+		return ".m4a"
+	default:
+		return "." + strings.ToLower(format)
+	}
+}
+
+func pcmCodecForSource(inputFile, format string) string {
+	bits := 16
+	if info, err := GetTrackMetadata(inputFile); err == nil && info.BitsPerSample > 16 {
+		bits = 24
+	}
+
+	if format == "aiff" {
+		return fmt.Sprintf("pcm_s%dbe", bits)
+	}
+	return fmt.Sprintf("pcm_s%dle", bits)
 }
 
 type AudioFileInfo struct {
-	Path     string `json:"path"`
-	Filename string `json:"filename"`
-	Format   string `json:"format"`
-	Size     int64  `json:"size"`
+	Path          string  `json:"path"`
+	Filename      string  `json:"filename"`
+	Format        string  `json:"format"`
+	Size          int64   `json:"size"`
+	Container     string  `json:"container,omitempty"`
+	Codec         string  `json:"codec,omitempty"`
+	CodecLongName string  `json:"codec_long_name,omitempty"`
+	Lossless      bool    `json:"lossless"`
+	SampleRate    int     `json:"sample_rate,omitempty"`
+	BitDepth      int     `json:"bit_depth,omitempty"`
+	Channels      int     `json:"channels,omitempty"`
+	ChannelLayout string  `json:"channel_layout,omitempty"`
+	Duration      float64 `json:"duration,omitempty"`
+	Bitrate       int     `json:"bitrate,omitempty"`
+	ProbeError    string  `json:"probe_error,omitempty"`
+}
+
+type ffprobeAudioOutput struct {
+	Streams []struct {
+		CodecName        string `json:"codec_name"`
+		CodecLongName    string `json:"codec_long_name"`
+		SampleRate       string `json:"sample_rate"`
+		Channels         int    `json:"channels"`
+		ChannelLayout    string `json:"channel_layout"`
+		BitsPerRawSample string `json:"bits_per_raw_sample"`
+		BitsPerSample    int    `json:"bits_per_sample"`
+		BitRate          string `json:"bit_rate"`
+		Duration         string `json:"duration"`
+	} `json:"streams"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+var losslessCodecs = map[string]bool{
+	"flac": true, "alac": true, "wavpack": true, "ape": true, "tta": true, "mlp": true, "truehd": true,
 }
 
 func GetAudioFileInfo(filePath string) (*AudioFileInfo, error) {
@@ -938,10 +1057,74 @@ func GetAudioFileInfo(filePath string) (*AudioFileInfo, error) {
 	}
 
 	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filePath), "."))
-	return &AudioFileInfo{
+	result := &AudioFileInfo{
 		Path:     filePath,
 		Filename: filepath.Base(filePath),
 		Format:   ext,
 		Size:     info.Size(),
-	}, nil
+	}
+
+	if err := probeAudioFileInfo(result); err != nil {
+		result.ProbeError = err.Error()
+	}
+	return result, nil
+}
+
+func probeAudioFileInfo(result *AudioFileInfo) error {
+	ffprobePath, err := GetFFprobePath()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(ffprobePath,
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=codec_name,codec_long_name,sample_rate,channels,channel_layout,bits_per_raw_sample,bits_per_sample,bit_rate,duration:format=format_name,duration,bit_rate",
+		"-of", "json",
+		result.Path,
+	)
+	setHideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var probe ffprobeAudioOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return fmt.Errorf("no audio stream found")
+	}
+
+	stream := probe.Streams[0]
+	result.Container = probe.Format.FormatName
+	result.Codec = stream.CodecName
+	result.CodecLongName = stream.CodecLongName
+	result.Channels = stream.Channels
+	result.ChannelLayout = stream.ChannelLayout
+	result.SampleRate, _ = strconv.Atoi(stream.SampleRate)
+
+	result.BitDepth, _ = strconv.Atoi(stream.BitsPerRawSample)
+	if result.BitDepth == 0 {
+		result.BitDepth = stream.BitsPerSample
+	}
+
+	result.Lossless = losslessCodecs[stream.CodecName] || strings.HasPrefix(stream.CodecName, "pcm_")
+	if !result.Lossless {
+		result.BitDepth = 0
+	}
+
+	duration := stream.Duration
+	if duration == "" || duration == "N/A" {
+		duration = probe.Format.Duration
+	}
+	result.Duration, _ = strconv.ParseFloat(duration, 64)
+
+	bitrate := stream.BitRate
+	if bitrate == "" || bitrate == "N/A" {
+		bitrate = probe.Format.BitRate
+	}
+	result.Bitrate, _ = strconv.Atoi(bitrate)
+	return nil
 }
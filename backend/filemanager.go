@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -354,32 +355,16 @@ func GenerateFilename(metadata *AudioMetadata, format string, ext string) string
 		return ""
 	}
 
-	result := format
-
-	year := metadata.Year
-	if len(year) >= 4 {
-		year = year[:4]
-	}
-
-	result = strings.ReplaceAll(result, "{title}", sanitizeFilenameForRename(metadata.Title))
-	result = strings.ReplaceAll(result, "{artist}", sanitizeFilenameForRename(metadata.Artist))
-	result = strings.ReplaceAll(result, "{album}", sanitizeFilenameForRename(metadata.Album))
-	result = strings.ReplaceAll(result, "{album_artist}", sanitizeFilenameForRename(metadata.AlbumArtist))
-	result = strings.ReplaceAll(result, "{year}", sanitizeFilenameForRename(year))
-	result = strings.ReplaceAll(result, "{date}", sanitizeFilenameForRename(metadata.Year))
-	result = strings.ReplaceAll(result, "{isrc}", sanitizeFilenameForRename(metadata.ISRC))
-
-	if metadata.TrackNumber > 0 {
-		result = strings.ReplaceAll(result, "{track}", fmt.Sprintf("%02d", metadata.TrackNumber))
-	} else {
-		result = strings.ReplaceAll(result, "{track}", "")
-	}
-
-	if metadata.DiscNumber > 0 {
-		result = strings.ReplaceAll(result, "{disc}", fmt.Sprintf("%d", metadata.DiscNumber))
-	} else {
-		result = strings.ReplaceAll(result, "{disc}", "")
-	}
+	result := RenderFilenameTemplate(format, FilenameTemplateData{
+		Title:       metadata.Title,
+		Artist:      metadata.Artist,
+		Album:       metadata.Album,
+		AlbumArtist: metadata.AlbumArtist,
+		ReleaseDate: metadata.Year,
+		ISRC:        metadata.ISRC,
+		Track:       metadata.TrackNumber,
+		Disc:        metadata.DiscNumber,
+	}, sanitizeFilenameForRename)
 
 	result = strings.TrimSpace(result)
 	result = strings.Join(strings.Fields(result), " ")
@@ -403,8 +388,36 @@ func sanitizeFilenameForRename(name string) string {
 	return strings.TrimSpace(result)
 }
 
+func isSameFile(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
+
+func uniqueRenamePath(oldPath, newPath string, reserved map[string]bool) string {
+	ext := filepath.Ext(newPath)
+	base := strings.TrimSuffix(newPath, ext)
+
+	candidate := newPath
+	for n := 2; ; n++ {
+		key := strings.ToLower(candidate)
+		if !reserved[key] && (candidate == oldPath || isSameFile(oldPath, candidate) || !fileExists(candidate)) {
+			reserved[key] = true
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, n, ext)
+	}
+}
+
 func PreviewRename(files []string, format string) []RenamePreview {
 	var previews []RenamePreview
+	reserved := make(map[string]bool)
 
 	for _, filePath := range files {
 		preview := RenamePreview{
@@ -430,8 +443,8 @@ func PreviewRename(files []string, format string) []RenamePreview {
 			continue
 		}
 
-		preview.NewName = newName
-		preview.NewPath = filepath.Join(filepath.Dir(filePath), newName)
+		preview.NewPath = uniqueRenamePath(filePath, filepath.Join(filepath.Dir(filePath), newName), reserved)
+		preview.NewName = filepath.Base(preview.NewPath)
 
 		previews = append(previews, preview)
 	}
@@ -452,6 +465,8 @@ func GetFileSizes(files []string) map[string]int64 {
 
 func RenameFiles(files []string, format string) []RenameResult {
 	var results []RenameResult
+	var entries []RenameLogEntry
+	reserved := make(map[string]bool)
 
 	for _, filePath := range files {
 		result := RenameResult{
@@ -476,28 +491,402 @@ func RenameFiles(files []string, format string) []RenameResult {
 			continue
 		}
 
-		newPath := filepath.Join(filepath.Dir(filePath), newName)
-		result.NewPath = newPath
+		result.NewPath = uniqueRenamePath(filePath, filepath.Join(filepath.Dir(filePath), newName), reserved)
+		if result.NewPath != filePath {
+			entries = append(entries, RenameLogEntry{OldPath: filePath, NewPath: result.NewPath})
+		}
+		results = append(results, result)
+	}
+
+	if len(entries) == 0 {
+		for i := range results {
+			results[i].Success = results[i].Error == ""
+		}
+		return results
+	}
+
+	batch, err := newRenameBatch(entries)
+	if err != nil {
+		fmt.Printf("Warning: failed to write rename log, undo will not be available: %v\n", err)
+	}
+
+	for i := range results {
+		result := &results[i]
+		if result.Error != "" {
+			continue
+		}
 
-		if newPath != filePath {
-			if _, err := os.Stat(newPath); err == nil {
-				result.Error = "File already exists"
-				result.Success = false
-				results = append(results, result)
+		if result.NewPath != result.OldPath {
+			if err := os.Rename(result.OldPath, result.NewPath); err != nil {
+				result.Error = err.Error()
 				continue
 			}
 		}
+		result.Success = true
+	}
+
+	if batch.ID != "" {
+		finishRenameBatch(batch, results)
+	}
+
+	return results
+}
+
+const DefaultOrganizeTemplate = "{album_artist}/{album}"
+
+type OrganizeMove struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type OrganizeResult struct {
+	Root        string         `json:"root"`
+	DryRun      bool           `json:"dry_run"`
+	Moves       []OrganizeMove `json:"moves"`
+	RemovedDirs []string       `json:"removed_dirs,omitempty"`
+}
+
+func removeEmptyDirs(dir, root string) []string {
+	var removed []string
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			break
+		}
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+		removed = append(removed, dir)
+	}
+	return removed
+}
+
+func organizeLyricsPath(audioPath string) string {
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".lrc"
+}
+
+func OrganizeLibrary(rootDir, folderTemplate string, dryRun bool) (*OrganizeResult, error) {
+	if strings.TrimSpace(folderTemplate) == "" {
+		folderTemplate = DefaultOrganizeTemplate
+	}
+
+	files, err := ListAudioFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrganizeResult{Root: rootDir, DryRun: dryRun}
+	reserved := make(map[string]bool)
+	var entries []RenameLogEntry
+
+	for _, file := range files {
+		move := OrganizeMove{OldPath: file.Path}
+
+		metadata, err := ReadAudioMetadata(file.Path)
+		if err != nil {
+			move.Error = err.Error()
+			result.Moves = append(result.Moves, move)
+			continue
+		}
+
+		targetDir := filepath.Join(rootDir, RenderFolderTemplate(folderTemplate, FilenameTemplateData{
+			Title:       metadata.Title,
+			Artist:      metadata.Artist,
+			Album:       metadata.Album,
+			AlbumArtist: metadata.AlbumArtist,
+			ReleaseDate: metadata.Year,
+			ISRC:        metadata.ISRC,
+			Track:       metadata.TrackNumber,
+			Disc:        metadata.DiscNumber,
+		}))
+		if filepath.Clean(filepath.Dir(file.Path)) == filepath.Clean(targetDir) {
+			reserved[strings.ToLower(file.Path)] = true
+			continue
+		}
+
+		move.NewPath = uniqueRenamePath(file.Path, filepath.Join(targetDir, file.Name), reserved)
+		entries = append(entries, RenameLogEntry{OldPath: file.Path, NewPath: move.NewPath})
+		if lyricsPath := organizeLyricsPath(file.Path); fileExists(lyricsPath) {
+			entries = append(entries, RenameLogEntry{OldPath: lyricsPath, NewPath: organizeLyricsPath(move.NewPath)})
+		}
+		result.Moves = append(result.Moves, move)
+	}
+
+	if dryRun || len(entries) == 0 {
+		return result, nil
+	}
+
+	batch, err := newRenameBatch(entries)
+	if err != nil {
+		fmt.Printf("Warning: failed to write rename log, undo will not be available: %v\n", err)
+	}
+
+	sourceDirs := make(map[string]bool)
+	var renamed []RenameResult
+	for i := range result.Moves {
+		move := &result.Moves[i]
+		if move.Error != "" {
+			continue
+		}
 
-		if err := os.Rename(filePath, newPath); err != nil {
+		if err := os.MkdirAll(filepath.Dir(move.NewPath), 0755); err != nil {
+			move.Error = err.Error()
+			continue
+		}
+		if err := os.Rename(move.OldPath, move.NewPath); err != nil {
+			move.Error = err.Error()
+			continue
+		}
+
+		move.Success = true
+		sourceDirs[filepath.Dir(move.OldPath)] = true
+		renamed = append(renamed, RenameResult{OldPath: move.OldPath, NewPath: move.NewPath, Success: true})
+
+		lyricsPath := organizeLyricsPath(move.OldPath)
+		newLyricsPath := organizeLyricsPath(move.NewPath)
+		if !fileExists(lyricsPath) || fileExists(newLyricsPath) {
+			continue
+		}
+		if err := os.Rename(lyricsPath, newLyricsPath); err != nil {
+			fmt.Printf("Warning: failed to move lyrics file %s: %v\n", lyricsPath, err)
+			continue
+		}
+		renamed = append(renamed, RenameResult{OldPath: lyricsPath, NewPath: newLyricsPath, Success: true})
+	}
+
+	if batch.ID != "" {
+		finishRenameBatch(batch, renamed)
+	}
+
+	for dir := range sourceDirs {
+		result.RemovedDirs = append(result.RemovedDirs, removeEmptyDirs(dir, rootDir)...)
+	}
+	sort.Strings(result.RemovedDirs)
+
+	return result, nil
+}
+
+type MetadataWriteRequest struct {
+	Path     string        `json:"path"`
+	Metadata AudioMetadata `json:"metadata"`
+}
+
+type MetadataWriteResult struct {
+	Path    string `json:"path"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+func formatTagNumber(value int) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+func withTagTotal(number int, existing string) string {
+	if number <= 0 {
+		return ""
+	}
+	if parts := strings.SplitN(existing, "/", 2); len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+		return fmt.Sprintf("%d/%s", number, strings.TrimSpace(parts[1]))
+	}
+	return strconv.Itoa(number)
+}
+
+func WriteAudioMetadata(filePath string, metadata AudioMetadata) error {
+	if !fileExists(filePath) {
+		return fmt.Errorf("file does not exist")
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		return embedExtraTagsToFLAC(filePath, map[string]string{
+			"TITLE":       metadata.Title,
+			"ARTIST":      metadata.Artist,
+			"ALBUM":       metadata.Album,
+			"ALBUMARTIST": metadata.AlbumArtist,
+			"TRACKNUMBER": formatTagNumber(metadata.TrackNumber),
+			"DISCNUMBER":  formatTagNumber(metadata.DiscNumber),
+			"DATE":        metadata.Year,
+			"ISRC":        metadata.ISRC,
+			"UPC":         metadata.UPC,
+		})
+	case ".mp3":
+		return writeMp3AudioMetadata(filePath, metadata)
+	case ".m4a":
+		return writeM4aAudioMetadata(filePath, metadata)
+	default:
+		return fmt.Errorf("unsupported file format: %s", filepath.Ext(filePath))
+	}
+}
+
+func writeMp3AudioMetadata(filePath string, metadata AudioMetadata) error {
+	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+	if err != nil {
+		return fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	existingText := func(frameID string) string {
+		if textFrame, ok := tag.GetLastFrame(frameID).(id3v2.TextFrame); ok {
+			return textFrame.Text
+		}
+		return ""
+	}
+
+	trackID := tag.CommonID("Track number/Position in set")
+	discID := tag.CommonID("Part of a set")
+	track := withTagTotal(metadata.TrackNumber, existingText(trackID))
+	disc := withTagTotal(metadata.DiscNumber, existingText(discID))
+
+	addMP3TextFrame(tag, "TIT2", metadata.Title)
+	addMP3TextFrame(tag, "TPE1", metadata.Artist)
+	addMP3TextFrame(tag, "TALB", metadata.Album)
+	addMP3TextFrame(tag, "TPE2", metadata.AlbumArtist)
+	addMP3TextFrame(tag, trackID, track)
+	addMP3TextFrame(tag, discID, disc)
+	addMP3TextFrame(tag, "TSRC", metadata.ISRC)
+
+	tag.DeleteFrames(tag.CommonID("Year"))
+	if year := strings.TrimSpace(metadata.Year); year != "" {
+		if tag.Version() == 4 {
+			tag.SetYear(year)
+		} else {
+			tag.SetYear(extractYear(year))
+		}
+	}
+
+	applyMP3ExtraTags(tag, map[string]string{"UPC": metadata.UPC})
+
+	if err := tag.Save(); err != nil {
+		return fmt.Errorf("failed to save MP3 tags: %w", err)
+	}
+	return nil
+}
+
+func writeM4aAudioMetadata(filePath string, metadata AudioMetadata) error {
+	current, err := ExtractFullMetadataFromFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read existing tags: %w", err)
+	}
+
+	track := formatTagNumber(metadata.TrackNumber)
+	if track != "" && current.TotalTracks > 0 {
+		track = fmt.Sprintf("%s/%d", track, current.TotalTracks)
+	}
+	disc := formatTagNumber(metadata.DiscNumber)
+	if disc != "" && current.TotalDiscs > 0 {
+		disc = fmt.Sprintf("%s/%d", disc, current.TotalDiscs)
+	}
+
+	if err := embedExtraTagsToM4A(filePath, map[string]string{
+		"TITLE":        metadata.Title,
+		"ARTIST":       metadata.Artist,
+		"ALBUM":        metadata.Album,
+		"ALBUM_ARTIST": metadata.AlbumArtist,
+		"TRACK":        track,
+		"DISC":         disc,
+		"DATE":         metadata.Year,
+	}); err != nil {
+		return err
+	}
+
+	return WriteITunesFreeformTags(filePath, map[string]string{
+		"ISRC":    metadata.ISRC,
+		"BARCODE": metadata.UPC,
+	})
+}
+
+func WriteAudioMetadataBatch(requests []MetadataWriteRequest) []MetadataWriteResult {
+	results := make([]MetadataWriteResult, 0, len(requests))
+	for _, req := range requests {
+		result := MetadataWriteResult{Path: req.Path}
+		if err := WriteAudioMetadata(req.Path, req.Metadata); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func applyAudioMetadataField(metadata *AudioMetadata, field, value string) error {
+	value = strings.TrimSpace(value)
+	parseNumber := func() (int, error) {
+		if value == "" {
+			return 0, nil
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			return 0, fmt.Errorf("invalid %s: %q", field, value)
+		}
+		return number, nil
+	}
+
+	switch field {
+	case "title":
+		metadata.Title = value
+	case "artist":
+		metadata.Artist = value
+	case "album":
+		metadata.Album = value
+	case "album_artist":
+		metadata.AlbumArtist = value
+	case "year":
+		metadata.Year = value
+	case "isrc":
+		metadata.ISRC = strings.ToUpper(value)
+	case "upc":
+		metadata.UPC = value
+	case "track_number":
+		number, err := parseNumber()
+		if err != nil {
+			return err
+		}
+		metadata.TrackNumber = number
+	case "disc_number":
+		number, err := parseNumber()
+		if err != nil {
+			return err
+		}
+		metadata.DiscNumber = number
+	default:
+		return fmt.Errorf("unknown metadata field: %s", field)
+	}
+	return nil
+}
+
+func WriteAudioMetadataFields(files []string, fields map[string]string) []MetadataWriteResult {
+	results := make([]MetadataWriteResult, 0, len(files))
+	for _, filePath := range files {
+		result := MetadataWriteResult{Path: filePath}
+
+		metadata, err := ReadAudioMetadata(filePath)
+		if err != nil {
 			result.Error = err.Error()
-			result.Success = false
 			results = append(results, result)
 			continue
 		}
 
-		result.Success = true
+		for field, value := range fields {
+			if err = applyAudioMetadataField(metadata, field, value); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = WriteAudioMetadata(filePath, *metadata)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
 		results = append(results, result)
 	}
-
 	return results
 }
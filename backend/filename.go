@@ -11,65 +11,18 @@ import (
 )
 
 func buildFormattedFilenameBase(trackName, artistName, albumName, albumArtist, releaseDate, filenameFormat, playlistName, playlistOwner, isrc string, includeTrackNumber bool, position, discNumber int, useAlbumTrackNumber bool) string {
-	safeTitle := SanitizeFilename(trackName)
-	safeArtist := SanitizeFilename(artistName)
-	safeAlbum := SanitizeFilename(albumName)
-	safeAlbumArtist := SanitizeFilename(albumArtist)
-	safeISRC := SanitizeOptionalFilename(isrc)
-
-	safePlaylist := SanitizeFilename(playlistName)
-	safeCreator := SanitizeFilename(playlistOwner)
-
-	year := ""
-	if len(releaseDate) >= 4 {
-		year = releaseDate[:4]
-	}
-
-	var filename string
-
-	if strings.Contains(filenameFormat, "{") {
-		filename = filenameFormat
-		filename = strings.ReplaceAll(filename, "{title}", safeTitle)
-		filename = strings.ReplaceAll(filename, "{artist}", safeArtist)
-		filename = strings.ReplaceAll(filename, "{album}", safeAlbum)
-		filename = strings.ReplaceAll(filename, "{album_artist}", safeAlbumArtist)
-		filename = strings.ReplaceAll(filename, "{year}", year)
-		filename = strings.ReplaceAll(filename, "{date}", SanitizeFilename(releaseDate))
-		filename = strings.ReplaceAll(filename, "{playlist}", safePlaylist)
-		filename = strings.ReplaceAll(filename, "{creator}", safeCreator)
-		filename = strings.ReplaceAll(filename, "{isrc}", safeISRC)
-
-		if discNumber > 0 {
-			filename = strings.ReplaceAll(filename, "{disc}", fmt.Sprintf("%d", discNumber))
-		} else {
-			filename = strings.ReplaceAll(filename, "{disc}", "")
-		}
-
-		if position > 0 {
-			filename = strings.ReplaceAll(filename, "{track}", fmt.Sprintf("%02d", position))
-		} else {
-
-			filename = regexp.MustCompile(`\{track\}\.\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*-\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*`).ReplaceAllString(filename, "")
-		}
-	} else {
-
-		switch filenameFormat {
-		case "artist-title":
-			filename = fmt.Sprintf("%s - %s", safeArtist, safeTitle)
-		case "title":
-			filename = safeTitle
-		default:
-			filename = fmt.Sprintf("%s - %s", safeTitle, safeArtist)
-		}
-
-		if includeTrackNumber && position > 0 {
-			filename = fmt.Sprintf("%02d. %s", position, filename)
-		}
-	}
-
-	return filename
+	return buildTemplatedFilename(filenameFormat, FilenameTemplateData{
+		Title:       trackName,
+		Artist:      artistName,
+		Album:       albumName,
+		AlbumArtist: albumArtist,
+		ReleaseDate: releaseDate,
+		Playlist:    playlistName,
+		Creator:     playlistOwner,
+		ISRC:        isrc,
+		Track:       position,
+		Disc:        discNumber,
+	}, includeTrackNumber, position, "%02d. ")
 }
 
 func BuildExpectedFilename(trackName, artistName, albumName, albumArtist, releaseDate, filenameFormat, playlistName, playlistOwner string, includeTrackNumber bool, position, discNumber int, useAlbumTrackNumber bool, extra ...string) string {
@@ -81,7 +34,30 @@ func BuildExpectedFilename(trackName, artistName, albumName, albumArtist, releas
 	return buildFormattedFilenameBase(trackName, artistName, albumName, albumArtist, releaseDate, filenameFormat, playlistName, playlistOwner, isrc, includeTrackNumber, position, discNumber, useAlbumTrackNumber) + ".flac"
 }
 
+func BuildFolderTemplatePath(folderTemplate, trackName, artistName, albumName, albumArtist, releaseDate, playlistName, isrc string, trackNumber, discNumber int) string {
+	if strings.TrimSpace(folderTemplate) == "" {
+		return ""
+	}
+	if IsVariousArtists(albumArtist) {
+		artistName = VariousArtistsName
+		albumArtist = VariousArtistsName
+	}
+
+	return RenderFolderTemplate(folderTemplate, FilenameTemplateData{
+		Title:       trackName,
+		Artist:      artistName,
+		Album:       albumName,
+		AlbumArtist: albumArtist,
+		ReleaseDate: releaseDate,
+		Playlist:    playlistName,
+		ISRC:        isrc,
+		Track:       trackNumber,
+		Disc:        discNumber,
+	})
+}
+
 func ResolveOutputPathForDownload(path string, redownloadWithSuffix bool) (string, bool) {
+	path = FitPathLength(path, GetMaxPathLengthSetting())
 	if !redownloadWithSuffix {
 		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
 			return path, true
@@ -158,7 +134,9 @@ func SanitizeFilename(name string) string {
 		sanitized = strings.ToValidUTF8(sanitized, "_")
 	}
 
-	return sanitized
+	sanitized = strings.TrimRight(truncateUTF8Bytes(sanitized, maxFilenameComponentBytes), ". ")
+
+	return escapeReservedName(sanitized)
 }
 
 func GetFirstArtist(artistString string) string {
@@ -0,0 +1,277 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type FilenameTemplateData struct {
+	Title       string
+	Artist      string
+	Album       string
+	AlbumArtist string
+	ReleaseDate string
+	Playlist    string
+	Creator     string
+	ISRC        string
+	Track       int
+	Disc        int
+	Quality     string
+	BitDepth    int
+	SampleRate  int
+	Service     string
+}
+
+var (
+	filenameTemplateVarPattern = regexp.MustCompile(`\{([a-z_]+)(?::(\d+))?\}`)
+	emptyTrackVarPatterns      = []*regexp.Regexp{
+		regexp.MustCompile(`\{track(?::\d+)?\}\.\s*`),
+		regexp.MustCompile(`\{track(?::\d+)?\}\s*-\s*`),
+		regexp.MustCompile(`\{track(?::\d+)?\}\s*`),
+	}
+)
+
+func IsFilenameTemplate(format string) bool {
+	return strings.Contains(format, "{")
+}
+
+func formatSampleRateKHz(sampleRate int) string {
+	if sampleRate <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(sampleRate)/1000, 'f', -1, 64)
+}
+
+// filenameQualityLabel maps each service's quality code onto one label set so
+// {quality} reads the same whichever service delivered the file.
+func filenameQualityLabel(data FilenameTemplateData) string {
+	switch {
+	case data.BitDepth > 16 || data.SampleRate > 48000:
+		return "Hi-Res"
+	case data.BitDepth == 16:
+		return "CD"
+	}
+	switch strings.ToUpper(strings.TrimSpace(data.Quality)) {
+	case "":
+		return ""
+	case "HI_RES_LOSSLESS", "HI_RES", "27", "7":
+		return "Hi-Res"
+	case "LOSSLESS", "6":
+		return "CD"
+	}
+	return "Lossy"
+}
+
+func templateWidth(width string, defaultWidth int) int {
+	if n, err := strconv.Atoi(width); err == nil && n > 0 {
+		return n
+	}
+	return defaultWidth
+}
+
+func padTemplateNumber(value int, width string, defaultWidth int) string {
+	if value <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%0*d", templateWidth(width, defaultWidth), value)
+}
+
+func templateFieldValue(data FilenameTemplateData, name, width string) (string, bool) {
+	switch name {
+	case "title":
+		return data.Title, true
+	case "artist":
+		return data.Artist, true
+	case "album":
+		return data.Album, true
+	case "album_artist", "albumartist":
+		return data.AlbumArtist, true
+	case "year":
+		if len(data.ReleaseDate) >= 4 {
+			return data.ReleaseDate[:4], true
+		}
+		return "", true
+	case "date":
+		return data.ReleaseDate, true
+	case "playlist":
+		return data.Playlist, true
+	case "creator":
+		return data.Creator, true
+	case "isrc":
+		return data.ISRC, true
+	case "track":
+		return padTemplateNumber(data.Track, width, 2), true
+	case "disc":
+		return padTemplateNumber(data.Disc, width, 1), true
+	case "quality":
+		return filenameQualityLabel(data), true
+	case "bit_depth":
+		return padTemplateNumber(data.BitDepth, width, 1), true
+	case "sample_rate":
+		return formatSampleRateKHz(data.SampleRate), true
+	case "service":
+		return data.Service, true
+	}
+	return "", false
+}
+
+func expandTemplateVars(format string, resolve func(name, width string) (string, bool)) string {
+	return filenameTemplateVarPattern.ReplaceAllStringFunc(format, func(match string) string {
+		parts := filenameTemplateVarPattern.FindStringSubmatch(match)
+		if value, ok := resolve(parts[1], parts[2]); ok {
+			return value
+		}
+		return match
+	})
+}
+
+func RenderFilenameTemplate(format string, data FilenameTemplateData, sanitize func(string) string) string {
+	if data.Track <= 0 {
+		for _, re := range emptyTrackVarPatterns {
+			format = re.ReplaceAllString(format, "")
+		}
+	}
+
+	return expandTemplateVars(format, func(name, width string) (string, bool) {
+		value, ok := templateFieldValue(data, name, width)
+		if !ok {
+			return "", false
+		}
+		switch name {
+		case "title", "artist", "album", "album_artist", "albumartist", "date", "playlist", "creator":
+			return sanitize(value), true
+		}
+		if strings.TrimSpace(value) == "" {
+			return "", true
+		}
+		return sanitize(value), true
+	})
+}
+
+func applyFilenameScriptSettings(data FilenameTemplateData) FilenameTemplateData {
+	convert := RomanizeText
+	switch {
+	case GetTransliterateFilenamesSetting():
+		convert = TransliterateText
+	case !GetRomanizeFilenamesSetting():
+		return data
+	}
+	for _, field := range []*string{&data.Title, &data.Artist, &data.Album, &data.AlbumArtist, &data.Playlist, &data.Creator} {
+		*field = convert(*field)
+	}
+	return data
+}
+
+// buildTemplatedFilename renders format for data. For the legacy named
+// formats the track prefix is only added when the track has a position, as
+// the per-service builders did before.
+func buildTemplatedFilename(format string, data FilenameTemplateData, includeTrackNumber bool, position int, trackPrefix string) string {
+	data = applyFilenameScriptSettings(data)
+	if IsFilenameTemplate(format) {
+		return RenderFilenameTemplate(format, data, SanitizeFilename)
+	}
+
+	title := SanitizeFilename(data.Title)
+	artist := SanitizeFilename(data.Artist)
+
+	var filename string
+	switch format {
+	case "artist-title":
+		filename = fmt.Sprintf("%s - %s", artist, title)
+	case "title":
+		filename = title
+	default:
+		filename = fmt.Sprintf("%s - %s", title, artist)
+	}
+
+	if includeTrackNumber && position > 0 && data.Track > 0 {
+		filename = fmt.Sprintf(trackPrefix, data.Track) + filename
+	}
+
+	return filename
+}
+
+func filenameTrackNumber(position, trackNumber int, useAlbumTrackNumber bool) int {
+	if useAlbumTrackNumber && trackNumber > 0 {
+		return trackNumber
+	}
+	return position
+}
+
+func RenderFolderTemplate(folderTemplate string, data FilenameTemplateData) string {
+	withDefault := func(value, fallback string) string {
+		value = strings.ReplaceAll(value, "/", " ")
+		if strings.TrimSpace(value) == "" {
+			return fallback
+		}
+		return value
+	}
+
+	data = applyFilenameScriptSettings(data)
+	if strings.TrimSpace(data.AlbumArtist) == "" {
+		data.AlbumArtist = data.Artist
+	}
+	if data.Disc <= 0 {
+		data.Disc = 1
+	}
+
+	result := expandTemplateVars(folderTemplate, func(name, width string) (string, bool) {
+		value, ok := templateFieldValue(data, name, width)
+		if !ok {
+			return "", false
+		}
+		switch name {
+		case "title":
+			return withDefault(value, "Unknown Title"), true
+		case "artist", "album_artist", "albumartist":
+			return withDefault(value, "Unknown Artist"), true
+		case "album":
+			return withDefault(value, "Unknown Album"), true
+		case "year":
+			return withDefault(value, "0000"), true
+		case "date":
+			return withDefault(value, "0000-00-00"), true
+		case "track":
+			if value == "" {
+				return strings.Repeat("0", templateWidth(width, 2)), true
+			}
+		}
+		return withDefault(value, ""), true
+	})
+
+	parts := strings.Split(result, "/")
+	sanitizedParts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		sanitizedParts = append(sanitizedParts, SanitizeFilename(part))
+	}
+
+	return filepath.Join(sanitizedParts...)
+}
+
+func ApplyMultiDiscFilenameFormat(format string) string {
+	if IsFilenameTemplate(format) {
+		if strings.Contains(format, "{disc") || !strings.Contains(format, "{track") {
+			return format
+		}
+		return strings.Replace(format, "{track", "{disc}-{track", 1)
+	}
+
+	switch format {
+	case "artist-title":
+		return "{disc}-{track}. {artist} - {title}"
+	case "title":
+		return "{disc}-{track}. {title}"
+	default:
+		return "{disc}-{track}. {title} - {artist}"
+	}
+}
+
+func DiscSubfolderName(discNumber int) string {
+	return fmt.Sprintf("Disc %d", discNumber)
+}
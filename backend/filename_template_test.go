@@ -0,0 +1,57 @@
+package backend
+
+import "testing"
+
+func TestBuildTemplatedFilename(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	base := FilenameTemplateData{Title: "Song", Artist: "Artist", Album: "Album"}
+	withTrack := func(track int) FilenameTemplateData {
+		data := base
+		data.Track = track
+		return data
+	}
+	withQuality := func(quality string, bitDepth, sampleRate int) FilenameTemplateData {
+		data := base
+		data.Quality = quality
+		data.BitDepth = bitDepth
+		data.SampleRate = sampleRate
+		return data
+	}
+
+	tests := []struct {
+		name               string
+		format             string
+		data               FilenameTemplateData
+		includeTrackNumber bool
+		position           int
+		want               string
+	}{
+		{"title-artist", "title-artist", base, false, 0, "Song - Artist"},
+		{"artist-title", "artist-title", base, false, 0, "Artist - Song"},
+		{"title", "title", base, false, 0, "Song"},
+		{"unknown format falls back to title-artist", "", base, false, 0, "Song - Artist"},
+		{"track prefix with position", "title-artist", withTrack(3), true, 3, "03. Song - Artist"},
+		{"album track number without position", "title-artist", withTrack(5), true, 0, "Song - Artist"},
+		{"track prefix disabled", "artist-title", withTrack(3), false, 3, "Artist - Song"},
+		{"template with track", "{track}. {title}", withTrack(7), false, 7, "07. Song"},
+		{"template without track number", "{track}. {title}", base, false, 0, "Song"},
+		{"template track dash without track number", "{track} - {artist} - {title}", base, false, 0, "Artist - Song"},
+		{"template track width", "{track:3} {title}", withTrack(7), false, 7, "007 Song"},
+		{"tidal lossless quality", "{title} [{quality}]", withQuality("LOSSLESS", 16, 44100), false, 0, "Song [CD]"},
+		{"qobuz cd quality", "{title} [{quality}]", withQuality("6", 16, 44100), false, 0, "Song [CD]"},
+		{"tidal hi-res quality", "{title} [{quality}]", withQuality("HI_RES_LOSSLESS", 24, 0), false, 0, "Song [Hi-Res]"},
+		{"qobuz hi-res quality", "{title} [{quality}]", withQuality("27", 24, 192000), false, 0, "Song [Hi-Res]"},
+		{"qobuz code without audio format", "{title} [{quality}]", withQuality("27", 0, 0), false, 0, "Song [Hi-Res]"},
+		{"youtube lossy quality", "{title} [{quality}]", withQuality(youtubeLossyTag, 0, 0), false, 0, "Song [Lossy]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildTemplatedFilename(tt.format, tt.data, tt.includeTrackNumber, tt.position, "%02d. ")
+			if got != tt.want {
+				t.Fatalf("buildTemplatedFilename(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-flac/go-flac"
+)
+
+const (
+	GaplessIssueUnknownLength  = "unknown_length"
+	GaplessIssueFormatMismatch = "format_mismatch"
+	GaplessIssueBlockMismatch  = "blocksize_mismatch"
+)
+
+type GaplessTrackInfo struct {
+	Path         string `json:"path"`
+	SampleRate   int    `json:"sample_rate"`
+	BitDepth     int    `json:"bit_depth"`
+	Channels     int    `json:"channels"`
+	BlockSize    int    `json:"block_size"`
+	TotalSamples int64  `json:"total_samples"`
+}
+
+type GaplessIssue struct {
+	Path     string `json:"path"`
+	Previous string `json:"previous,omitempty"`
+	Kind     string `json:"kind"`
+	Detail   string `json:"detail"`
+}
+
+type GaplessReport struct {
+	Folder   string             `json:"folder"`
+	Tracks   []GaplessTrackInfo `json:"tracks"`
+	Issues   []GaplessIssue     `json:"issues,omitempty"`
+	Repaired []string           `json:"repaired,omitempty"`
+	Errors   []string           `json:"errors,omitempty"`
+}
+
+func readGaplessTrackInfo(path string) (GaplessTrackInfo, error) {
+	info := GaplessTrackInfo{Path: path}
+
+	f, err := flac.ParseFile(path)
+	if err != nil {
+		return info, fmt.Errorf("failed to parse FLAC: %w", err)
+	}
+	streamInfo, err := f.GetStreamInfo()
+	if err != nil {
+		return info, fmt.Errorf("failed to read STREAMINFO: %w", err)
+	}
+
+	info.SampleRate = streamInfo.SampleRate
+	info.BitDepth = streamInfo.BitDepth
+	info.Channels = streamInfo.ChannelCount
+	info.BlockSize = streamInfo.BlockSizeMax
+	info.TotalSamples = streamInfo.SampleCount
+	return info, nil
+}
+
+func describeGaplessFormat(info GaplessTrackInfo) string {
+	return fmt.Sprintf("%d-bit/%dHz/%dch", info.BitDepth, info.SampleRate, info.Channels)
+}
+
+func CheckAlbumGapless(files []string) *GaplessReport {
+	report := &GaplessReport{}
+
+	var flacFiles []string
+	for _, file := range files {
+		if strings.EqualFold(filepath.Ext(file), ".flac") {
+			flacFiles = append(flacFiles, file)
+		}
+	}
+	sort.Strings(flacFiles)
+	if len(flacFiles) > 0 {
+		report.Folder = filepath.Dir(flacFiles[0])
+	}
+
+	var previous *GaplessTrackInfo
+	for _, file := range flacFiles {
+		info, err := readGaplessTrackInfo(file)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", filepath.Base(file), err))
+			continue
+		}
+		report.Tracks = append(report.Tracks, info)
+
+		if info.TotalSamples == 0 {
+			report.Issues = append(report.Issues, GaplessIssue{
+				Path:   file,
+				Kind:   GaplessIssueUnknownLength,
+				Detail: "STREAMINFO does not record the total sample count",
+			})
+		}
+
+		if previous != nil {
+			switch {
+			case info.SampleRate != previous.SampleRate || info.BitDepth != previous.BitDepth || info.Channels != previous.Channels:
+				report.Issues = append(report.Issues, GaplessIssue{
+					Path:     file,
+					Previous: previous.Path,
+					Kind:     GaplessIssueFormatMismatch,
+					Detail:   fmt.Sprintf("%s follows %s", describeGaplessFormat(info), describeGaplessFormat(*previous)),
+				})
+			case info.BlockSize != previous.BlockSize:
+				report.Issues = append(report.Issues, GaplessIssue{
+					Path:     file,
+					Previous: previous.Path,
+					Kind:     GaplessIssueBlockMismatch,
+					Detail:   fmt.Sprintf("block size %d follows %d", info.BlockSize, previous.BlockSize),
+				})
+			}
+		}
+		previous = &report.Tracks[len(report.Tracks)-1]
+	}
+
+	return report
+}
+
+func referenceGaplessFormat(tracks []GaplessTrackInfo) GaplessTrackInfo {
+	counts := make(map[string]int)
+	var reference GaplessTrackInfo
+	best := 0
+	for _, track := range tracks {
+		key := fmt.Sprintf("%s/%d", describeGaplessFormat(track), track.BlockSize)
+		counts[key]++
+		if counts[key] > best {
+			best = counts[key]
+			reference = track
+		}
+	}
+	return reference
+}
+
+func reencodeGaplessTrack(path string, reference GaplessTrackInfo) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	sampleFormat := "s16"
+	if reference.BitDepth > 16 {
+		sampleFormat = "s32"
+	}
+
+	tmpPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".gapless.tmp.flac"
+	defer os.Remove(tmpPath)
+
+	args := []string{
+		"-i", path,
+		"-y",
+		"-map", "0:a",
+		"-map", "0:v?",
+		"-map_metadata", "0",
+		"-c:v", "copy",
+		"-c:a", "flac",
+		"-ar", strconv.Itoa(reference.SampleRate),
+		"-ac", strconv.Itoa(reference.Channels),
+		"-sample_fmt", sampleFormat,
+	}
+	if reference.BitDepth > 16 {
+		args = append(args, "-bits_per_raw_sample", strconv.Itoa(reference.BitDepth))
+	}
+	if reference.BlockSize > 0 {
+		args = append(args, "-frame_size", strconv.Itoa(reference.BlockSize))
+	}
+	args = append(args, tmpPath)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg re-encode failed: %s - %w", string(output), err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+	return nil
+}
+
+func RepairAlbumGapless(report *GaplessReport) {
+	if report == nil || len(report.Issues) == 0 {
+		return
+	}
+
+	reference := referenceGaplessFormat(report.Tracks)
+	repaired := make(map[string]bool)
+	for _, track := range report.Tracks {
+		needsRepair := track.TotalSamples == 0 ||
+			track.SampleRate != reference.SampleRate ||
+			track.BitDepth != reference.BitDepth ||
+			track.Channels != reference.Channels ||
+			track.BlockSize != reference.BlockSize
+		if !needsRepair || repaired[track.Path] {
+			continue
+		}
+
+		fmt.Printf("[Gapless] Re-encoding %s to %s\n", filepath.Base(track.Path), describeGaplessFormat(reference))
+		if err := reencodeGaplessTrack(track.Path, reference); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", filepath.Base(track.Path), err))
+			continue
+		}
+		repaired[track.Path] = true
+		report.Repaired = append(report.Repaired, track.Path)
+	}
+}
+
+func CheckAlbumFolderGapless(folder string, repair bool) (*GaplessReport, error) {
+	files, err := listAlbumFolderAudioFiles(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	report := CheckAlbumGapless(files)
+	report.Folder = folder
+	if repair {
+		RepairAlbumGapless(report)
+	}
+	return report, nil
+}
@@ -0,0 +1,286 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-flac/go-flac"
+)
+
+const (
+	hiResUpgradeBitDepth = 24
+	hiResUpgradeQuality  = "27"
+)
+
+type HiResUpgradeCandidate struct {
+	Path             string `json:"path"`
+	ISRC             string `json:"isrc"`
+	Title            string `json:"title"`
+	Artist           string `json:"artist"`
+	BitDepth         int    `json:"bit_depth"`
+	SampleRate       int    `json:"sample_rate"`
+	Quality          string `json:"quality"`
+	Service          string `json:"service"`
+	TargetBitDepth   int    `json:"target_bit_depth"`
+	TargetSampleRate int    `json:"target_sample_rate"`
+	TargetQuality    string `json:"target_quality"`
+}
+
+type HiResUpgradeScan struct {
+	Root       string                  `json:"root"`
+	Scanned    int                     `json:"scanned"`
+	Standard   int                     `json:"standard"`
+	NoISRC     int                     `json:"no_isrc"`
+	Candidates []HiResUpgradeCandidate `json:"candidates"`
+	Cancelled  bool                    `json:"cancelled,omitempty"`
+}
+
+type HiResUpgradeResult struct {
+	Path       string `json:"path"`
+	Success    bool   `json:"success"`
+	OldQuality string `json:"old_quality,omitempty"`
+	NewQuality string `json:"new_quality,omitempty"`
+	ArchivedTo string `json:"archived_to,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func readFLACQuality(path string) (int, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	f, err := flac.ParseMetadata(file)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse FLAC: %w", err)
+	}
+	streamInfo, err := f.GetStreamInfo()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read STREAMINFO: %w", err)
+	}
+	return streamInfo.BitDepth, streamInfo.SampleRate, nil
+}
+
+func FindHiResUpgrades(ctx context.Context, rootDir string, workers int, progress func(LibraryScanProgress)) (*HiResUpgradeScan, error) {
+	files, err := ListAudioFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var flacFiles []string
+	for _, file := range files {
+		if strings.EqualFold(filepath.Ext(file.Path), ".flac") {
+			flacFiles = append(flacFiles, file.Path)
+		}
+	}
+
+	result := &HiResUpgradeScan{Root: rootDir}
+	candidates := make([]*HiResUpgradeCandidate, len(flacFiles))
+	var mu sync.Mutex
+
+	scanErr := scanLibraryFiles(ctx, len(flacFiles), workers, func(i int) {
+		path := flacFiles[i]
+		bitDepth, sampleRate, err := readFLACQuality(path)
+
+		mu.Lock()
+		result.Scanned++
+		mu.Unlock()
+		if err != nil || bitDepth >= hiResUpgradeBitDepth {
+			return
+		}
+
+		metadata, err := ReadAudioMetadata(path)
+		isrc := ""
+		if err == nil {
+			isrc = strings.ToUpper(strings.TrimSpace(metadata.ISRC))
+		}
+
+		mu.Lock()
+		result.Standard++
+		if isrc == "" {
+			result.NoISRC++
+		}
+		mu.Unlock()
+		if isrc == "" {
+			return
+		}
+
+		matrix, _ := CheckAvailability("", isrc)
+		availability := matrix.Service("qobuz")
+		if availability == nil || !availability.Available || !availability.QualityKnown || availability.BitDepth < hiResUpgradeBitDepth {
+			return
+		}
+		candidates[i] = &HiResUpgradeCandidate{
+			Path:             path,
+			ISRC:             isrc,
+			Title:            metadata.Title,
+			Artist:           metadata.Artist,
+			BitDepth:         bitDepth,
+			SampleRate:       sampleRate,
+			Quality:          formatAvailabilityQuality(bitDepth, sampleRate),
+			Service:          availability.Service,
+			TargetBitDepth:   availability.BitDepth,
+			TargetSampleRate: availability.SampleRate,
+			TargetQuality:    availability.QualityLabel,
+		}
+	}, func(scanned int, index int) {
+		if progress != nil {
+			progress(LibraryScanProgress{Scanned: scanned, Total: len(flacFiles), Current: flacFiles[index]})
+		}
+	})
+
+	for _, candidate := range candidates {
+		if candidate != nil {
+			result.Candidates = append(result.Candidates, *candidate)
+		}
+	}
+
+	if scanErr != nil {
+		result.Cancelled = true
+		return result, scanErr
+	}
+	return result, nil
+}
+
+func copyFLACTagBlocks(sourcePath, targetPath string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	sourceMeta, err := flac.ParseMetadata(source)
+	source.Close()
+	if err != nil {
+		return fmt.Errorf("failed to parse original FLAC: %w", err)
+	}
+
+	target, err := flac.ParseFile(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse upgraded FLAC: %w", err)
+	}
+
+	isTagBlock := func(block *flac.MetaDataBlock) bool {
+		return block.Type == flac.VorbisComment || block.Type == flac.Picture
+	}
+
+	kept := target.Meta[:0]
+	for _, block := range target.Meta {
+		if !isTagBlock(block) {
+			kept = append(kept, block)
+		}
+	}
+	target.Meta = kept
+	for _, block := range sourceMeta.Meta {
+		if isTagBlock(block) {
+			target.Meta = append(target.Meta, block)
+		}
+	}
+
+	if err := target.Save(targetPath); err != nil {
+		return fmt.Errorf("failed to save upgraded FLAC: %w", err)
+	}
+	return nil
+}
+
+func UpgradeFileToHiRes(path, archiveDir string) HiResUpgradeResult {
+	result := HiResUpgradeResult{Path: path}
+
+	bitDepth, sampleRate, err := readFLACQuality(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.OldQuality = formatAvailabilityQuality(bitDepth, sampleRate)
+	if bitDepth >= hiResUpgradeBitDepth {
+		result.Error = "file is already hi-res"
+		return result
+	}
+
+	metadata, err := ReadAudioMetadata(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	isrc := strings.ToUpper(strings.TrimSpace(metadata.ISRC))
+	if isrc == "" {
+		result.Error = "file has no ISRC tag"
+		return result
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(path), ".spotiflac-upgrade-")
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create temporary directory: %v", err)
+		return result
+	}
+	defer os.RemoveAll(tmpDir)
+
+	downloaded, err := NewQobuzDownloader().DownloadTrackWithISRC(isrc, tmpDir, hiResUpgradeQuality, "{title}", false, 0,
+		metadata.Title, metadata.Artist, metadata.Album, metadata.AlbumArtist, metadata.Year, false, "", false,
+		metadata.TrackNumber, metadata.DiscNumber, 0, 0, "", "", "", "", "", false, false, false, false)
+	if err != nil {
+		result.Error = fmt.Sprintf("download failed: %v", err)
+		return result
+	}
+	downloaded = strings.TrimPrefix(downloaded, "EXISTS:")
+
+	newBitDepth, newSampleRate, err := readFLACQuality(downloaded)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.NewQuality = formatAvailabilityQuality(newBitDepth, newSampleRate)
+	if newBitDepth < hiResUpgradeBitDepth {
+		result.Error = fmt.Sprintf("service delivered %s, keeping the original", result.NewQuality)
+		return result
+	}
+
+	if err := copyFLACTagBlocks(path, downloaded); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if archiveDir != "" {
+		if err := os.MkdirAll(archiveDir, 0755); err != nil {
+			result.Error = fmt.Sprintf("failed to create archive directory: %v", err)
+			return result
+		}
+		archivePath, _ := ResolveOutputPathForDownload(filepath.Join(archiveDir, filepath.Base(path)), true)
+		if err := os.Rename(path, archivePath); err != nil {
+			result.Error = fmt.Sprintf("failed to archive original: %v", err)
+			return result
+		}
+		result.ArchivedTo = archivePath
+	} else if err := os.Remove(path); err != nil {
+		result.Error = fmt.Sprintf("failed to remove original: %v", err)
+		return result
+	}
+
+	if err := os.Rename(downloaded, path); err != nil {
+		if result.ArchivedTo != "" {
+			_ = os.Rename(result.ArchivedTo, path)
+			result.ArchivedTo = ""
+		}
+		result.Error = fmt.Sprintf("failed to replace original: %v", err)
+		return result
+	}
+
+	result.Success = true
+	return result
+}
+
+func UpgradeFilesToHiRes(paths []string, archiveDir string) []HiResUpgradeResult {
+	results := make([]HiResUpgradeResult, 0, len(paths))
+	for i, path := range paths {
+		fmt.Printf("Upgrading [%d/%d]: %s\n", i+1, len(paths), path)
+		result := UpgradeFileToHiRes(path, archiveDir)
+		if !result.Success {
+			fmt.Printf("Warning: failed to upgrade %s: %s\n", path, result.Error)
+		}
+		results = append(results, result)
+	}
+	return results
+}
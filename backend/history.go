@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	bolt "go.etcd.io/bbolt"
@@ -23,6 +24,9 @@ type HistoryItem struct {
 	Path        string `json:"path"`
 	Source      string `json:"source"`
 	Timestamp   int64  `json:"timestamp"`
+	Playlist    string `json:"playlist,omitempty"`
+	PlaylistPos int    `json:"playlist_position,omitempty"`
+	Genre       string `json:"genre,omitempty"`
 }
 
 var historyDB *bolt.DB
@@ -148,6 +152,123 @@ func ClearHistory(appName string) error {
 	})
 }
 
+type HistoryQuery struct {
+	Text    string `json:"text"`
+	Title   string `json:"title"`
+	Artist  string `json:"artist"`
+	Album   string `json:"album"`
+	Source  string `json:"source"`
+	Quality string `json:"quality"`
+	Format  string `json:"format"`
+	From    int64  `json:"from"`
+	To      int64  `json:"to"`
+	Offset  int    `json:"offset"`
+	Limit   int    `json:"limit"`
+}
+
+type HistoryPage struct {
+	Items  []HistoryItem `json:"items"`
+	Total  int           `json:"total"`
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+}
+
+func historyFieldContains(value, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	return query == "" || strings.Contains(strings.ToLower(value), query)
+}
+
+func historyItemMatches(item HistoryItem, query HistoryQuery) bool {
+	if text := strings.TrimSpace(query.Text); text != "" {
+		haystack := strings.ToLower(strings.Join([]string{item.Title, item.Artists, item.Album, item.Playlist}, " "))
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			if !strings.Contains(haystack, word) {
+				return false
+			}
+		}
+	}
+
+	if !historyFieldContains(item.Title, query.Title) ||
+		!historyFieldContains(item.Artists, query.Artist) ||
+		!historyFieldContains(item.Album, query.Album) ||
+		!historyFieldContains(item.Quality, query.Quality) {
+		return false
+	}
+	if source := strings.TrimSpace(query.Source); source != "" && !strings.EqualFold(item.Source, source) {
+		return false
+	}
+	if format := strings.TrimSpace(query.Format); format != "" && !strings.EqualFold(item.Format, format) {
+		return false
+	}
+	if query.From > 0 && item.Timestamp < query.From {
+		return false
+	}
+	if query.To > 0 && item.Timestamp > query.To {
+		return false
+	}
+	return true
+}
+
+func QueryHistoryItems(query HistoryQuery, appName string) (*HistoryPage, error) {
+	items, err := GetHistoryItems(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]HistoryItem, 0, len(items))
+	for _, item := range items {
+		if historyItemMatches(item, query) {
+			matched = append(matched, item)
+		}
+	}
+
+	offset := min(max(query.Offset, 0), len(matched))
+	end := len(matched)
+	if query.Limit > 0 {
+		end = min(offset+query.Limit, len(matched))
+	}
+
+	return &HistoryPage{
+		Items:  matched[offset:end],
+		Total:  len(matched),
+		Offset: offset,
+		Limit:  query.Limit,
+	}, nil
+}
+
+func GetHistoryItem(id string, appName string) (*HistoryItem, error) {
+	if historyDB == nil {
+		if err := InitHistoryDB(appName); err != nil {
+			return nil, err
+		}
+	}
+
+	var item *HistoryItem
+	err := historyDB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(historyBucket))
+		if b == nil {
+			return nil
+		}
+		v := b.Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+		var decoded HistoryItem
+		if err := json.Unmarshal(v, &decoded); err != nil {
+			return err
+		}
+		item = &decoded
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("history item %s not found", id)
+	}
+	return item, nil
+}
+
 type FetchHistoryItem struct {
 	ID        string `json:"id"`
 	URL       string `json:"url"`
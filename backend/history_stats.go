@@ -0,0 +1,178 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type StatCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type ServiceStatistics struct {
+	Service    string      `json:"service"`
+	Downloads  int         `json:"downloads"`
+	Qualities  []StatCount `json:"qualities"`
+	Formats    []StatCount `json:"formats"`
+	TopArtists []StatCount `json:"top_artists"`
+	TopGenres  []StatCount `json:"top_genres"`
+}
+
+type ServiceBreakdown struct {
+	Name     string      `json:"name"`
+	Total    int         `json:"total"`
+	Services []StatCount `json:"services"`
+}
+
+type DownloadStatistics struct {
+	TotalDownloads int                 `json:"total_downloads"`
+	Services       []ServiceStatistics `json:"services"`
+	Artists        []ServiceBreakdown  `json:"artists"`
+	Genres         []ServiceBreakdown  `json:"genres"`
+	DataUsage      *DataUsage          `json:"data_usage,omitempty"`
+}
+
+type statCounter map[string]int
+
+func (c statCounter) add(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	c[name]++
+}
+
+func (c statCounter) sorted(limit int) []StatCount {
+	counts := make([]StatCount, 0, len(c))
+	for name, count := range c {
+		counts = append(counts, StatCount{Name: name, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+
+	if limit > 0 && len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
+type serviceStatsAccumulator struct {
+	downloads int
+	qualities statCounter
+	formats   statCounter
+	artists   statCounter
+	genres    statCounter
+}
+
+func buildServiceBreakdown(byName map[string]statCounter, limit int) []ServiceBreakdown {
+	breakdown := make([]ServiceBreakdown, 0, len(byName))
+	for name, services := range byName {
+		total := 0
+		for _, count := range services {
+			total += count
+		}
+		breakdown = append(breakdown, ServiceBreakdown{
+			Name:     name,
+			Total:    total,
+			Services: services.sorted(0),
+		})
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Total != breakdown[j].Total {
+			return breakdown[i].Total > breakdown[j].Total
+		}
+		return breakdown[i].Name < breakdown[j].Name
+	})
+
+	if limit > 0 && len(breakdown) > limit {
+		breakdown = breakdown[:limit]
+	}
+	return breakdown
+}
+
+func GetDownloadStatistics(appName string, limit int) (*DownloadStatistics, error) {
+	items, err := GetHistoryItems(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	separator := GetSeparator()
+	services := make(map[string]*serviceStatsAccumulator)
+	artistServices := make(map[string]statCounter)
+	genreServices := make(map[string]statCounter)
+
+	for _, item := range items {
+		service := strings.ToLower(strings.TrimSpace(item.Source))
+		if service == "" {
+			service = "unknown"
+		}
+
+		acc, ok := services[service]
+		if !ok {
+			acc = &serviceStatsAccumulator{
+				qualities: statCounter{},
+				formats:   statCounter{},
+				artists:   statCounter{},
+				genres:    statCounter{},
+			}
+			services[service] = acc
+		}
+
+		acc.downloads++
+		acc.qualities.add(item.Quality)
+		acc.formats.add(item.Format)
+
+		for _, artist := range SplitArtistCredits(item.Artists, separator) {
+			acc.artists.add(artist)
+			if artistServices[artist] == nil {
+				artistServices[artist] = statCounter{}
+			}
+			artistServices[artist].add(service)
+		}
+
+		for _, genre := range SplitMetadataValues(item.Genre, separator) {
+			acc.genres.add(genre)
+			if genreServices[genre] == nil {
+				genreServices[genre] = statCounter{}
+			}
+			genreServices[genre].add(service)
+		}
+	}
+
+	stats := &DownloadStatistics{
+		TotalDownloads: len(items),
+		Artists:        buildServiceBreakdown(artistServices, limit),
+		Genres:         buildServiceBreakdown(genreServices, limit),
+	}
+
+	for service, acc := range services {
+		stats.Services = append(stats.Services, ServiceStatistics{
+			Service:    service,
+			Downloads:  acc.downloads,
+			Qualities:  acc.qualities.sorted(0),
+			Formats:    acc.formats.sorted(0),
+			TopArtists: acc.artists.sorted(limit),
+			TopGenres:  acc.genres.sorted(limit),
+		})
+	}
+
+	sort.Slice(stats.Services, func(i, j int) bool {
+		return stats.Services[i].Downloads > stats.Services[j].Downloads
+	})
+
+	if usage, err := GetDataUsage(); err == nil {
+		stats.DataUsage = &usage
+	} else {
+		fmt.Printf("Warning: failed to read data usage: %v\n", err)
+	}
+
+	return stats, nil
+}
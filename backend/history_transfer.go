@@ -0,0 +1,315 @@
+package backend
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var historyCSVHeader = []string{
+	"id", "spotify_id", "title", "artists", "album", "duration", "cover_url", "quality",
+	"format", "path", "source", "timestamp", "playlist", "playlist_position", "genre",
+}
+
+type HistoryExport struct {
+	Version    int                `json:"version"`
+	ExportedAt int64              `json:"exported_at"`
+	Downloads  []HistoryItem      `json:"downloads"`
+	Fetches    []FetchHistoryItem `json:"fetches,omitempty"`
+}
+
+type HistoryImportResult struct {
+	Imported      int `json:"imported"`
+	Skipped       int `json:"skipped"`
+	FetchImported int `json:"fetch_imported"`
+	FetchSkipped  int `json:"fetch_skipped"`
+}
+
+func historyItemKey(item HistoryItem) string {
+	track := item.SpotifyID
+	if track == "" {
+		track = strings.ToLower(item.Title + "|" + item.Artists)
+	}
+	return fmt.Sprintf("%s|%s|%d", track, item.Path, item.Timestamp)
+}
+
+func ExportHistory(path string, appName string) (int, error) {
+	downloads, err := GetHistoryItems(appName)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		fetches, err := GetFetchHistoryItems(appName)
+		if err != nil {
+			return 0, err
+		}
+		data, err := json.MarshalIndent(HistoryExport{
+			Version:    1,
+			ExportedAt: time.Now().Unix(),
+			Downloads:  downloads,
+			Fetches:    fetches,
+		}, "", "  ")
+		if err != nil {
+			return 0, err
+		}
+		return len(downloads), os.WriteFile(path, data, 0644)
+	case ".csv":
+		f, err := os.Create(path)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+
+		writer := csv.NewWriter(f)
+		if err := writer.Write(historyCSVHeader); err != nil {
+			return 0, err
+		}
+		for _, item := range downloads {
+			record := []string{
+				item.ID,
+				item.SpotifyID,
+				item.Title,
+				item.Artists,
+				item.Album,
+				item.DurationStr,
+				item.CoverURL,
+				item.Quality,
+				item.Format,
+				item.Path,
+				item.Source,
+				strconv.FormatInt(item.Timestamp, 10),
+				item.Playlist,
+				strconv.Itoa(item.PlaylistPos),
+				item.Genre,
+			}
+			if err := writer.Write(record); err != nil {
+				return 0, err
+			}
+		}
+		writer.Flush()
+		return len(downloads), writer.Error()
+	default:
+		return 0, fmt.Errorf("unsupported history format: %s", filepath.Ext(path))
+	}
+}
+
+func loadHistoryExport(path string) (*HistoryExport, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var export HistoryExport
+		if err := json.Unmarshal(data, &export); err != nil {
+			var items []HistoryItem
+			if json.Unmarshal(data, &items) != nil {
+				return nil, fmt.Errorf("failed to parse history: %w", err)
+			}
+			export.Downloads = items
+		}
+		return &export, nil
+	case ".csv":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse history: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("history file is empty")
+		}
+
+		columns := make(map[string]int, len(records[0]))
+		for idx, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = idx
+		}
+		field := func(record []string, name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		export := &HistoryExport{}
+		for _, record := range records[1:] {
+			timestamp, _ := strconv.ParseInt(field(record, "timestamp"), 10, 64)
+			position, _ := strconv.Atoi(field(record, "playlist_position"))
+			export.Downloads = append(export.Downloads, HistoryItem{
+				ID:          field(record, "id"),
+				SpotifyID:   field(record, "spotify_id"),
+				Title:       field(record, "title"),
+				Artists:     field(record, "artists"),
+				Album:       field(record, "album"),
+				DurationStr: field(record, "duration"),
+				CoverURL:    field(record, "cover_url"),
+				Quality:     field(record, "quality"),
+				Format:      field(record, "format"),
+				Path:        field(record, "path"),
+				Source:      field(record, "source"),
+				Timestamp:   timestamp,
+				Playlist:    field(record, "playlist"),
+				PlaylistPos: position,
+				Genre:       field(record, "genre"),
+			})
+		}
+		return export, nil
+	default:
+		return nil, fmt.Errorf("unsupported history format: %s", filepath.Ext(path))
+	}
+}
+
+func trimHistoryBucket(b *bolt.Bucket, count int) error {
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && count > maxHistory; k, _ = c.First() {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+		count--
+	}
+	return nil
+}
+
+func ImportHistory(path string, appName string) (*HistoryImportResult, error) {
+	export, err := loadHistoryExport(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if historyDB == nil {
+		if err := InitHistoryDB(appName); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &HistoryImportResult{}
+	err = historyDB.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(historyBucket))
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool)
+		count := 0
+		if err := b.ForEach(func(k, v []byte) error {
+			count++
+			var item HistoryItem
+			if json.Unmarshal(v, &item) == nil {
+				seen[historyItemKey(item)] = true
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, item := range export.Downloads {
+			if item.Timestamp == 0 {
+				item.Timestamp = time.Now().Unix()
+			}
+			key := historyItemKey(item)
+			if seen[key] || (item.ID != "" && b.Get([]byte(item.ID)) != nil) {
+				result.Skipped++
+				continue
+			}
+			if item.ID == "" {
+				id, _ := b.NextSequence()
+				item.ID = fmt.Sprintf("%d-%d", time.Unix(item.Timestamp, 0).UnixNano(), id)
+			}
+
+			buf, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(item.ID), buf); err != nil {
+				return err
+			}
+			seen[key] = true
+			count++
+			result.Imported++
+		}
+		if err := trimHistoryBucket(b, count); err != nil {
+			return err
+		}
+
+		if len(export.Fetches) == 0 {
+			return nil
+		}
+
+		fb, err := tx.CreateBucketIfNotExists([]byte(fetchHistoryBucket))
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[string]FetchHistoryItem)
+		existingKeys := make(map[string][]byte)
+		fetchCount := 0
+		if err := fb.ForEach(func(k, v []byte) error {
+			fetchCount++
+			var item FetchHistoryItem
+			if json.Unmarshal(v, &item) == nil {
+				existing[item.Type+"|"+item.URL] = item
+				existingKeys[item.Type+"|"+item.URL] = append([]byte(nil), k...)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, item := range export.Fetches {
+			key := item.Type + "|" + item.URL
+			if current, ok := existing[key]; ok {
+				if current.Timestamp >= item.Timestamp {
+					result.FetchSkipped++
+					continue
+				}
+				if err := fb.Delete(existingKeys[key]); err != nil {
+					return err
+				}
+				fetchCount--
+			}
+			if item.ID == "" {
+				id, _ := fb.NextSequence()
+				item.ID = fmt.Sprintf("%d-%d", time.Unix(item.Timestamp, 0).UnixNano(), id)
+			}
+
+			buf, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			if err := fb.Put([]byte(item.ID), buf); err != nil {
+				return err
+			}
+			existing[key] = item
+			existingKeys[key] = []byte(item.ID)
+			fetchCount++
+			result.FetchImported++
+		}
+		return trimHistoryBucket(fb, fetchCount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
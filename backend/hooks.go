@@ -0,0 +1,167 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HookEventTrackComplete = "track_complete"
+	HookEventAlbumComplete = "album_complete"
+	HookEventTrackFailed   = "track_failed"
+
+	hookTimeout = 30 * time.Second
+)
+
+var defaultHookEvents = []string{HookEventTrackComplete, HookEventAlbumComplete, HookEventTrackFailed}
+
+var hookWG sync.WaitGroup
+
+type HookEvent struct {
+	Event      string   `json:"event"`
+	Timestamp  int64    `json:"timestamp"`
+	SpotifyID  string   `json:"spotify_id,omitempty"`
+	Track      string   `json:"track,omitempty"`
+	Artist     string   `json:"artist,omitempty"`
+	Album      string   `json:"album,omitempty"`
+	ISRC       string   `json:"isrc,omitempty"`
+	Service    string   `json:"service,omitempty"`
+	Quality    string   `json:"quality,omitempty"`
+	FilePath   string   `json:"file_path,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	Downloaded int      `json:"downloaded,omitempty"`
+	Skipped    int      `json:"skipped,omitempty"`
+	Failed     int      `json:"failed,omitempty"`
+	Files      []string `json:"files,omitempty"`
+}
+
+func hookEventEnabled(event string) bool {
+	for _, name := range GetHookEventsSetting() {
+		if strings.EqualFold(name, event) || name == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func postHookWebhook(url string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SpotiFLAC/"+AppVersion)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func hookEnvironment(event HookEvent) []string {
+	return append(os.Environ(),
+		"SPOTIFLAC_EVENT="+event.Event,
+		"SPOTIFLAC_SPOTIFY_ID="+event.SpotifyID,
+		"SPOTIFLAC_TRACK="+event.Track,
+		"SPOTIFLAC_ARTIST="+event.Artist,
+		"SPOTIFLAC_ALBUM="+event.Album,
+		"SPOTIFLAC_ISRC="+event.ISRC,
+		"SPOTIFLAC_SERVICE="+event.Service,
+		"SPOTIFLAC_FILE="+event.FilePath,
+		"SPOTIFLAC_ERROR="+event.Error,
+		"SPOTIFLAC_NAME="+event.Name,
+		"SPOTIFLAC_DOWNLOADED="+strconv.Itoa(event.Downloaded),
+		"SPOTIFLAC_FAILED="+strconv.Itoa(event.Failed),
+	)
+}
+
+func runHookCommand(command string, event HookEvent, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	setHideWindow(cmd)
+	cmd.Env = hookEnvironment(event)
+	cmd.Stdin = bytes.NewReader(payload)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func FireHook(event HookEvent) {
+	webhookURL := GetHookWebhookURLSetting()
+	command := GetHookCommandSetting()
+	if webhookURL == "" && command == "" {
+		return
+	}
+	if !hookEventEnabled(event.Event) {
+		return
+	}
+
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().Unix()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		fmt.Printf("Warning: failed to encode %s hook payload: %v\n", event.Event, err)
+		return
+	}
+
+	hookWG.Add(1)
+	go func() {
+		defer hookWG.Done()
+		if webhookURL != "" {
+			if err := postHookWebhook(webhookURL, payload); err != nil {
+				fmt.Printf("Warning: %s webhook failed: %v\n", event.Event, err)
+			}
+		}
+		if command != "" {
+			if err := runHookCommand(command, event, payload); err != nil {
+				fmt.Printf("Warning: %s hook command failed: %v\n", event.Event, err)
+			}
+		}
+	}()
+}
+
+func WaitForHooks(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		hookWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		fmt.Println("Warning: timed out waiting for hooks to finish")
+	}
+}
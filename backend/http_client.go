@@ -0,0 +1,120 @@
+package backend
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxConnsPerHost = 16
+	maxIdleConnsTotal      = 128
+	idleConnTimeout        = 90 * time.Second
+)
+
+var (
+	sharedTransportOnce sync.Once
+	sharedTransport     *http.Transport
+)
+
+func GetMaxConnectionsPerHostSetting() int {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return defaultMaxConnsPerHost
+	}
+	if value, ok := settings["maxConnectionsPerHost"].(float64); ok && value > 0 {
+		return int(value)
+	}
+	return defaultMaxConnsPerHost
+}
+
+func SharedTransport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		perHost := GetMaxConnectionsPerHostSetting()
+		sharedTransport = &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          maxIdleConnsTotal,
+			MaxIdleConnsPerHost:   perHost,
+			MaxConnsPerHost:       perHost,
+			IdleConnTimeout:       idleConnTimeout,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		}
+	})
+	return sharedTransport
+}
+
+func GetServiceHeadersSetting(service string) map[string]string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return nil
+	}
+
+	userAgent, _ := settings["userAgent"].(string)
+	all, _ := settings["serviceHeaders"].(map[string]interface{})
+	headers := make(map[string]string)
+	if userAgent = strings.TrimSpace(userAgent); userAgent != "" {
+		headers["User-Agent"] = userAgent
+	}
+	for _, key := range []string{"*", strings.ToLower(service)} {
+		if key == "" {
+			continue
+		}
+		entries, _ := all[key].(map[string]interface{})
+		for name, value := range entries {
+			if text, ok := value.(string); ok && strings.TrimSpace(name) != "" {
+				headers[http.CanonicalHeaderKey(strings.TrimSpace(name))] = text
+			}
+		}
+	}
+	return headers
+}
+
+type headerTransport struct {
+	service string
+	base    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if headers := GetServiceHeadersSetting(t.service); len(headers) > 0 {
+		req = req.Clone(req.Context())
+		for name, value := range headers {
+			if value == "" {
+				req.Header.Del(name)
+				continue
+			}
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if blockErr := detectCloudflareBlock(resp); blockErr != nil {
+		return nil, blockErr
+	}
+	resp.Body = &usageCountingBody{ReadCloser: resp.Body}
+	return resp, nil
+}
+
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return NewServiceHTTPClient("", timeout)
+}
+
+func NewServiceHTTPClient(service string, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &headerTransport{
+			service: service,
+			base:    SharedTransport(),
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed kanji_readings.txt
+var embeddedKanjiReadings string
+
+const userKanjiReadingsFile = "kanji_readings.txt"
+
+var (
+	kanjiReadingsOnce   sync.Once
+	kanjiReadings       map[string]string
+	kanjiReadingsMaxLen int
+)
+
+func parseKanjiReadings(data string, into map[string]string) {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		into[fields[0]] = fields[1]
+	}
+}
+
+func loadKanjiReadings() map[string]string {
+	kanjiReadingsOnce.Do(func() {
+		kanjiReadings = make(map[string]string)
+		parseKanjiReadings(embeddedKanjiReadings, kanjiReadings)
+
+		if appDir, err := GetAppDir(); err == nil {
+			if data, err := os.ReadFile(filepath.Join(appDir, userKanjiReadingsFile)); err == nil {
+				parseKanjiReadings(string(data), kanjiReadings)
+			}
+		}
+
+		for word := range kanjiReadings {
+			if n := len([]rune(word)); n > kanjiReadingsMaxLen {
+				kanjiReadingsMaxLen = n
+			}
+		}
+	})
+	return kanjiReadings
+}
+
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+func kanjiToKana(text string) string {
+	runes := []rune(text)
+	hasHan := false
+	for _, r := range runes {
+		if isHan(r) {
+			hasHan = true
+			break
+		}
+	}
+	if !hasHan {
+		return text
+	}
+
+	readings := loadKanjiReadings()
+	var sb strings.Builder
+	for i := 0; i < len(runes); {
+		if !isHan(runes[i]) {
+			sb.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		matched := false
+		for size := min(kanjiReadingsMaxLen, len(runes)-i); size > 0; size-- {
+			if reading, ok := readings[string(runes[i:i+size])]; ok {
+				sb.WriteString(reading)
+				i += size
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sb.WriteRune(runes[i])
+			i++
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,35 @@
+//go:build darwin
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func keychainGet(service, account string) (string, error) {
+	output, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func keychainSet(service, account, secret string) error {
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %q -a %q -w %q\n", service, account, secret))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return err
+	}
+	if message := strings.TrimSpace(strings.ReplaceAll(string(output), "security>", "")); message != "" {
+		return fmt.Errorf("security: %s", message)
+	}
+	return nil
+}
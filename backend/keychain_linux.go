@@ -0,0 +1,56 @@
+//go:build linux
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const secretToolTimeout = 15 * time.Second
+
+func keychainGet(service, account string) (string, error) {
+	secretTool, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return "", errKeychainUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretToolTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, secretTool, "lookup", "service", service, "account", account)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("secret-tool lookup timed out: %w", ctx.Err())
+		}
+		// secret-tool exits 1 without any output when no matching item exists;
+		// anything else (locked keyring, no D-Bus session) is a real failure.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stdout.Len() == 0 && strings.TrimSpace(stderr.String()) == "" {
+			return "", nil
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("secret-tool lookup failed: %s", message)
+		}
+		return "", fmt.Errorf("secret-tool lookup failed: %w", err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func keychainSet(service, account, secret string) error {
+	secretTool, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return errKeychainUnavailable
+	}
+	cmd := exec.Command(secretTool, "store", "--label="+service, "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	return cmd.Run()
+}
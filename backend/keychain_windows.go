@@ -0,0 +1,67 @@
+//go:build windows
+
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+func keychainBlobPath(service, account string) (string, error) {
+	appDir, err := EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, fmt.Sprintf("%s-%s.dpapi", service, account)), nil
+}
+
+func dpapiBlob(data []byte) *windows.DataBlob {
+	if len(data) == 0 {
+		return &windows.DataBlob{}
+	}
+	return &windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+}
+
+func dpapiResult(blob windows.DataBlob) []byte {
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(blob.Data)))
+	return append([]byte(nil), unsafe.Slice(blob.Data, blob.Size)...)
+}
+
+func keychainGet(service, account string) (string, error) {
+	path, err := keychainBlobPath(service, account)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var out windows.DataBlob
+	entropy := []byte(service + "/" + account)
+	if err := windows.CryptUnprotectData(dpapiBlob(encrypted), nil, dpapiBlob(entropy), 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", filepath.Base(path), err)
+	}
+	return string(dpapiResult(out)), nil
+}
+
+func keychainSet(service, account, secret string) error {
+	path, err := keychainBlobPath(service, account)
+	if err != nil {
+		return err
+	}
+
+	var out windows.DataBlob
+	entropy := []byte(service + "/" + account)
+	if err := windows.CryptProtectData(dpapiBlob([]byte(secret)), nil, dpapiBlob(entropy), 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", filepath.Base(path), err)
+	}
+	return os.WriteFile(path, dpapiResult(out), 0o600)
+}
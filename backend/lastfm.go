@@ -0,0 +1,410 @@
+package backend
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	lastfmAPIURL          = "https://ws.audioscrobbler.com/2.0/"
+	lastfmAuthURL         = "https://www.last.fm/api/auth/"
+	lastfmSessionFile     = "lastfm_session.json"
+	lastfmCallbackPort    = 43828
+	lastfmMaxPageSize     = 1000
+	lastfmDefaultLimit    = 500
+	lastfmDefaultTopLimit = 50
+)
+
+const (
+	LastfmPeriodOverall = "overall"
+	LastfmPeriod7Day    = "7day"
+	LastfmPeriod1Month  = "1month"
+	LastfmPeriod3Month  = "3month"
+	LastfmPeriod6Month  = "6month"
+	LastfmPeriod12Month = "12month"
+)
+
+var ErrLastfmNotLoggedIn = errors.New("not logged in to Last.fm")
+
+var lastfmPeriods = []string{LastfmPeriodOverall, LastfmPeriod7Day, LastfmPeriod1Month, LastfmPeriod3Month, LastfmPeriod6Month, LastfmPeriod12Month}
+
+type LastfmSession struct {
+	Username   string `json:"username"`
+	SessionKey string `json:"session_key"`
+}
+
+type LastfmAuthStatus struct {
+	LoggedIn bool   `json:"logged_in"`
+	Username string `json:"username,omitempty"`
+}
+
+type LastfmTrack struct {
+	Name      string `json:"name"`
+	Artist    string `json:"artist"`
+	PlayCount int    `json:"play_count,omitempty"`
+}
+
+type lastfmAPITrack struct {
+	Name      string `json:"name"`
+	PlayCount string `json:"playcount"`
+	Artist    struct {
+		Name string `json:"name"`
+		Text string `json:"#text"`
+	} `json:"artist"`
+}
+
+type lastfmAPIPage struct {
+	Attr struct {
+		Page       string `json:"page"`
+		TotalPages string `json:"totalPages"`
+	} `json:"@attr"`
+	Track []lastfmAPITrack `json:"track"`
+}
+
+var (
+	lastfmMu      sync.Mutex
+	lastfmSession *LastfmSession
+)
+
+func GetLastfmAPIKeySetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	apiKey, _ := settings["lastfmApiKey"].(string)
+	return strings.TrimSpace(apiKey)
+}
+
+func GetLastfmAPISecretSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	secret, _ := settings["lastfmApiSecret"].(string)
+	return strings.TrimSpace(secret)
+}
+
+func NormalizeLastfmPeriod(period string) string {
+	period = strings.ToLower(strings.TrimSpace(period))
+	for _, known := range lastfmPeriods {
+		if period == known {
+			return known
+		}
+	}
+	return ""
+}
+
+func getLastfmSessionPath() (string, error) {
+	dir, err := EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, lastfmSessionFile), nil
+}
+
+func loadLastfmSession() (*LastfmSession, error) {
+	if lastfmSession != nil {
+		return lastfmSession, nil
+	}
+
+	sessionPath, err := getLastfmSessionPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(sessionPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrLastfmNotLoggedIn
+		}
+		return nil, err
+	}
+
+	var session LastfmSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse Last.fm session: %w", err)
+	}
+	if session.SessionKey == "" {
+		return nil, ErrLastfmNotLoggedIn
+	}
+
+	lastfmSession = &session
+	return lastfmSession, nil
+}
+
+func saveLastfmSession(session *LastfmSession) error {
+	sessionPath, err := getLastfmSessionPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(sessionPath, data, 0o600); err != nil {
+		return err
+	}
+
+	lastfmSession = session
+	return nil
+}
+
+func LastfmLogout() error {
+	lastfmMu.Lock()
+	defer lastfmMu.Unlock()
+
+	lastfmSession = nil
+
+	sessionPath, err := getLastfmSessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(sessionPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func GetLastfmAuthStatus() LastfmAuthStatus {
+	lastfmMu.Lock()
+	defer lastfmMu.Unlock()
+
+	session, err := loadLastfmSession()
+	if err != nil {
+		return LastfmAuthStatus{}
+	}
+	return LastfmAuthStatus{LoggedIn: true, Username: session.Username}
+}
+
+func lastfmSignature(params url.Values, secret string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key == "format" || key == "callback" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteString(params.Get(key))
+	}
+	b.WriteString(secret)
+
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func lastfmCall(params url.Values, out interface{}) error {
+	params.Set("format", "json")
+
+	client := NewHTTPClient(20 * time.Second)
+	resp, err := client.Get(lastfmAPIURL + "?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("failed to reach Last.fm: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var apiErr struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("failed to decode Last.fm response: %w", err)
+	}
+	if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error != 0 {
+		return fmt.Errorf("last.fm error %d: %s", apiErr.Error, apiErr.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("last.fm returned HTTP %d", resp.StatusCode)
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func LastfmLogin(ctx context.Context, openURL func(string)) (LastfmAuthStatus, error) {
+	apiKey := GetLastfmAPIKeySetting()
+	secret := GetLastfmAPISecretSetting()
+	if apiKey == "" || secret == "" {
+		return LastfmAuthStatus{}, fmt.Errorf("last.fm API key and secret are not configured (set lastfmApiKey and lastfmApiSecret in settings)")
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", lastfmCallbackPort))
+	if err != nil {
+		return LastfmAuthStatus{}, fmt.Errorf("failed to start Last.fm callback listener: %w", err)
+	}
+
+	tokenCh := make(chan string, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			http.Error(w, "Missing token", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "SpotiFLAC is now connected to Last.fm. You can close this window.")
+		select {
+		case tokenCh <- token:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	params := url.Values{}
+	params.Set("api_key", apiKey)
+	params.Set("cb", fmt.Sprintf("http://127.0.0.1:%d/callback", lastfmCallbackPort))
+	authURL := lastfmAuthURL + "?" + params.Encode()
+
+	fmt.Printf("[LastfmAuth] Opening browser for login: %s\n", authURL)
+	if openURL != nil {
+		openURL(authURL)
+	}
+
+	var token string
+	select {
+	case token = <-tokenCh:
+	case <-ctx.Done():
+		return LastfmAuthStatus{}, fmt.Errorf("last.fm login timed out")
+	}
+
+	sessionParams := url.Values{}
+	sessionParams.Set("method", "auth.getSession")
+	sessionParams.Set("api_key", apiKey)
+	sessionParams.Set("token", token)
+	sessionParams.Set("api_sig", lastfmSignature(sessionParams, secret))
+
+	var sessionResp struct {
+		Session struct {
+			Name string `json:"name"`
+			Key  string `json:"key"`
+		} `json:"session"`
+	}
+	if err := lastfmCall(sessionParams, &sessionResp); err != nil {
+		return LastfmAuthStatus{}, err
+	}
+	if sessionResp.Session.Key == "" {
+		return LastfmAuthStatus{}, fmt.Errorf("last.fm did not return a session")
+	}
+
+	lastfmMu.Lock()
+	defer lastfmMu.Unlock()
+
+	session := &LastfmSession{Username: sessionResp.Session.Name, SessionKey: sessionResp.Session.Key}
+	if err := saveLastfmSession(session); err != nil {
+		return LastfmAuthStatus{}, fmt.Errorf("failed to save Last.fm session: %w", err)
+	}
+
+	fmt.Printf("[LastfmAuth] Logged in as %s\n", session.Username)
+	return LastfmAuthStatus{LoggedIn: true, Username: session.Username}, nil
+}
+
+func resolveLastfmUser(user string) (string, error) {
+	if user = strings.TrimSpace(user); user != "" {
+		return user, nil
+	}
+
+	lastfmMu.Lock()
+	defer lastfmMu.Unlock()
+
+	session, err := loadLastfmSession()
+	if err != nil {
+		return "", err
+	}
+	return session.Username, nil
+}
+
+func fetchLastfmTracks(method, user string, extra url.Values, limit int, listKey string) ([]LastfmTrack, error) {
+	apiKey := GetLastfmAPIKeySetting()
+	if apiKey == "" {
+		return nil, fmt.Errorf("last.fm API key is not configured (set lastfmApiKey in settings)")
+	}
+
+	user, err := resolveLastfmUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []LastfmTrack
+	for page := 1; len(tracks) < limit; page++ {
+		params := url.Values{}
+		for key, values := range extra {
+			params[key] = values
+		}
+		params.Set("method", method)
+		params.Set("user", user)
+		params.Set("api_key", apiKey)
+		params.Set("limit", strconv.Itoa(min(limit, lastfmMaxPageSize)))
+		params.Set("page", strconv.Itoa(page))
+
+		var resp map[string]lastfmAPIPage
+		if err := lastfmCall(params, &resp); err != nil {
+			return nil, err
+		}
+		result := resp[listKey]
+
+		for _, track := range result.Track {
+			artist := track.Artist.Name
+			if artist == "" {
+				artist = track.Artist.Text
+			}
+			playCount, _ := strconv.Atoi(track.PlayCount)
+			tracks = append(tracks, LastfmTrack{Name: track.Name, Artist: artist, PlayCount: playCount})
+			if len(tracks) >= limit {
+				break
+			}
+		}
+
+		totalPages, _ := strconv.Atoi(result.Attr.TotalPages)
+		if len(result.Track) == 0 || page >= totalPages {
+			break
+		}
+	}
+
+	return tracks, nil
+}
+
+func GetLastfmLovedTracks(user string, limit int) ([]LastfmTrack, error) {
+	if limit <= 0 {
+		limit = lastfmDefaultLimit
+	}
+	return fetchLastfmTracks("user.getLovedTracks", user, nil, limit, "lovedtracks")
+}
+
+func GetLastfmTopTracks(user, period string, limit int) ([]LastfmTrack, error) {
+	if limit <= 0 {
+		limit = lastfmDefaultTopLimit
+	}
+	normalized := NormalizeLastfmPeriod(period)
+	if period != "" && normalized == "" {
+		return nil, fmt.Errorf("unknown Last.fm period: %s (use %s)", period, strings.Join(lastfmPeriods, ", "))
+	}
+	if normalized == "" {
+		normalized = LastfmPeriodOverall
+	}
+
+	extra := url.Values{}
+	extra.Set("period", normalized)
+	return fetchLastfmTracks("user.getTopTracks", user, extra, limit, "toptracks")
+}
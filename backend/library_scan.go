@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+const (
+	libraryScanProgressInterval = 25
+	maxLibraryScanWorkers       = 8
+)
+
+type LibraryScanProgress struct {
+	Scanned int    `json:"scanned"`
+	Total   int    `json:"total"`
+	Current string `json:"current"`
+}
+
+func libraryScanWorkers(requested int) int {
+	if requested > 0 {
+		return requested
+	}
+	return min(runtime.NumCPU(), maxLibraryScanWorkers)
+}
+
+func scanLibraryFiles(ctx context.Context, total, workers int, scan func(index int), progress func(scanned, index int)) error {
+	if total == 0 {
+		return ctx.Err()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	scanned := 0
+
+	for range min(libraryScanWorkers(workers), total) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				scan(index)
+
+				mu.Lock()
+				scanned++
+				if progress != nil && (scanned%libraryScanProgressInterval == 0 || scanned == total) {
+					progress(scanned, index)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for index := range total {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- index:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+type LibraryTrack struct {
+	Path     string        `json:"path"`
+	Metadata AudioMetadata `json:"metadata"`
+}
+
+func ScanLibraryMetadata(ctx context.Context, rootDir string, workers int, progress func(LibraryScanProgress)) ([]LibraryTrack, error) {
+	files, err := ListAudioFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]LibraryTrack, len(files))
+	scanErr := scanLibraryFiles(ctx, len(files), workers, func(i int) {
+		tracks[i].Path = files[i].Path
+		if metadata, err := ReadAudioMetadata(files[i].Path); err == nil {
+			tracks[i].Metadata = *metadata
+		}
+	}, func(scanned int, index int) {
+		if progress != nil {
+			progress(LibraryScanProgress{Scanned: scanned, Total: len(files), Current: files[index].Path})
+		}
+	})
+
+	scanned := tracks[:0]
+	for _, track := range tracks {
+		if track.Path != "" {
+			scanned = append(scanned, track)
+		}
+	}
+	return scanned, scanErr
+}
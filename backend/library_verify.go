@@ -0,0 +1,398 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+)
+
+const (
+	LibraryFixModeEmbed   = "embed"
+	LibraryFixModeSidecar = "sidecar"
+)
+
+var sidecarCoverNames = []string{folderCoverFilename, "folder.jpg", "front.jpg", "cover.png", "folder.png"}
+
+type LibraryVerifyOptions struct {
+	CheckCover  bool   `json:"check_cover"`
+	CheckLyrics bool   `json:"check_lyrics"`
+	Fix         bool   `json:"fix"`
+	FixMode     string `json:"fix_mode"`
+	Workers     int    `json:"workers,omitempty"`
+}
+
+type LibraryVerifyItem struct {
+	Path           string   `json:"path"`
+	EmbeddedCover  bool     `json:"embedded_cover"`
+	SidecarCover   string   `json:"sidecar_cover,omitempty"`
+	EmbeddedLyrics bool     `json:"embedded_lyrics"`
+	SidecarLyrics  string   `json:"sidecar_lyrics,omitempty"`
+	MissingCover   bool     `json:"missing_cover"`
+	MissingLyrics  bool     `json:"missing_lyrics"`
+	Fixed          []string `json:"fixed,omitempty"`
+	Error          string   `json:"error,omitempty"`
+	FixError       string   `json:"fix_error,omitempty"`
+}
+
+type LibraryVerifyResult struct {
+	Root          string              `json:"root"`
+	Total         int                 `json:"total"`
+	Scanned       int                 `json:"scanned"`
+	Cancelled     bool                `json:"cancelled,omitempty"`
+	MissingCover  int                 `json:"missing_cover"`
+	MissingLyrics int                 `json:"missing_lyrics"`
+	Fixed         int                 `json:"fixed"`
+	Items         []LibraryVerifyItem `json:"items"`
+}
+
+func NormalizeLibraryFixMode(mode string) string {
+	if strings.ToLower(strings.TrimSpace(mode)) == LibraryFixModeSidecar {
+		return LibraryFixModeSidecar
+	}
+	return LibraryFixModeEmbed
+}
+
+func findSidecarCover(audioPath string) string {
+	base := strings.TrimSuffix(audioPath, filepath.Ext(audioPath))
+	for _, ext := range []string{".jpg", ".png"} {
+		if fileExists(base + ext) {
+			return base + ext
+		}
+	}
+
+	dir := filepath.Dir(audioPath)
+	for _, name := range sidecarCoverNames {
+		if candidate := filepath.Join(dir, name); fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func findSidecarLyrics(audioPath string) string {
+	if candidate := strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".lrc"; fileExists(candidate) {
+		return candidate
+	}
+	return ""
+}
+
+func flacEmbeddedArtAndLyrics(filePath string) (bool, bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, false, err
+	}
+	defer file.Close()
+
+	f, err := flac.ParseMetadata(file)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to parse FLAC file: %w", err)
+	}
+
+	hasCover, hasLyrics := false, false
+	for _, block := range f.Meta {
+		switch block.Type {
+		case flac.Picture:
+			hasCover = true
+		case flac.VorbisComment:
+			cmt, err := flacvorbis.ParseFromMetaDataBlock(*block)
+			if err != nil {
+				continue
+			}
+			for _, comment := range cmt.Comments {
+				parts := strings.SplitN(comment, "=", 2)
+				if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+					continue
+				}
+				switch strings.ToUpper(parts[0]) {
+				case "LYRICS", "UNSYNCEDLYRICS", "SYNCEDLYRICS":
+					hasLyrics = true
+				}
+			}
+		}
+	}
+	return hasCover, hasLyrics, nil
+}
+
+func mp3EmbeddedArtAndLyrics(filePath string) (bool, bool, error) {
+	tag, err := id3v2.Open(filePath, id3v2.Options{
+		Parse:       true,
+		ParseFrames: []string{"Attached picture", "Unsynchronised lyrics/text transcription"},
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	hasLyrics := false
+	for _, frame := range tag.GetFrames(tag.CommonID("Unsynchronised lyrics/text transcription")) {
+		if uslt, ok := frame.(id3v2.UnsynchronisedLyricsFrame); ok && strings.TrimSpace(uslt.Lyrics) != "" {
+			hasLyrics = true
+			break
+		}
+	}
+	return len(tag.GetFrames(tag.CommonID("Attached picture"))) > 0, hasLyrics, nil
+}
+
+func ffprobeEmbeddedArtAndLyrics(filePath string) (bool, bool, error) {
+	ffprobePath, err := GetFFprobePath()
+	if err != nil {
+		return false, false, err
+	}
+	if err := ValidateExecutable(ffprobePath); err != nil {
+		return false, false, fmt.Errorf("invalid ffprobe executable: %w", err)
+	}
+
+	cmd := exec.Command(ffprobePath,
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		filePath,
+	)
+	setHideWindow(cmd)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return false, false, err
+	}
+
+	var result struct {
+		Format struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType   string `json:"codec_type"`
+			Disposition struct {
+				AttachedPic int `json:"attached_pic"`
+			} `json:"disposition"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return false, false, err
+	}
+
+	hasCover := false
+	for _, stream := range result.Streams {
+		if stream.CodecType == "video" && stream.Disposition.AttachedPic == 1 {
+			hasCover = true
+			break
+		}
+	}
+
+	hasLyrics := false
+	for key, value := range result.Format.Tags {
+		switch strings.ToLower(key) {
+		case "lyrics", "unsyncedlyrics", "lyric", "©lyr":
+			if strings.TrimSpace(value) != "" {
+				hasLyrics = true
+			}
+		}
+	}
+	return hasCover, hasLyrics, nil
+}
+
+func EmbeddedArtAndLyrics(filePath string) (bool, bool, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		return flacEmbeddedArtAndLyrics(filePath)
+	case ".mp3":
+		return mp3EmbeddedArtAndLyrics(filePath)
+	default:
+		return ffprobeEmbeddedArtAndLyrics(filePath)
+	}
+}
+
+func fixLibraryCover(item *LibraryVerifyItem, mode string) (string, error) {
+	switch {
+	case mode == LibraryFixModeEmbed && !item.EmbeddedCover && item.SidecarCover != "":
+		if strings.ToLower(filepath.Ext(item.Path)) == ".m4a" {
+			return "", fmt.Errorf("embedding covers into M4A files is not supported")
+		}
+		if err := EmbedCoverArtOnly(item.Path, item.SidecarCover); err != nil {
+			return "", err
+		}
+		item.EmbeddedCover = true
+		return "embedded cover", nil
+	case mode == LibraryFixModeSidecar && item.SidecarCover == "" && item.EmbeddedCover:
+		extracted, err := ExtractCoverArt(item.Path)
+		if err != nil || extracted == "" {
+			return "", fmt.Errorf("failed to extract embedded cover: %v", err)
+		}
+		defer os.Remove(extracted)
+
+		data, err := os.ReadFile(extracted)
+		if err != nil {
+			return "", err
+		}
+		target := filepath.Join(filepath.Dir(item.Path), "cover"+coverFileExtension(data))
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return "", err
+		}
+		item.SidecarCover = target
+		return "wrote " + filepath.Base(target), nil
+	}
+	return "", nil
+}
+
+func fetchLibraryLyrics(filePath string) (string, error) {
+	metadata, err := ReadAudioMetadata(filePath)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(metadata.Title) == "" || strings.TrimSpace(metadata.Artist) == "" {
+		return "", fmt.Errorf("missing title or artist tag")
+	}
+
+	duration := 0
+	if seconds, err := GetAudioDuration(filePath); err == nil {
+		duration = int(seconds)
+	}
+
+	client := NewLyricsClient()
+	resp, _, err := client.FetchLyricsAllSources("", metadata.Title, metadata.Artist, metadata.Album, duration)
+	if err != nil {
+		return "", err
+	}
+	return client.ConvertToLRC(resp, metadata.Title, metadata.Artist), nil
+}
+
+func fixLibraryLyrics(item *LibraryVerifyItem, mode string) (string, error) {
+	lyricsPath := strings.TrimSuffix(item.Path, filepath.Ext(item.Path)) + ".lrc"
+
+	switch {
+	case mode == LibraryFixModeEmbed && !item.EmbeddedLyrics:
+		lyrics := ""
+		if item.SidecarLyrics != "" {
+			data, err := os.ReadFile(item.SidecarLyrics)
+			if err != nil {
+				return "", err
+			}
+			lyrics = string(data)
+		} else {
+			fetched, err := fetchLibraryLyrics(item.Path)
+			if err != nil {
+				return "", err
+			}
+			lyrics = fetched
+		}
+		if err := EmbedLyricsOnlyUniversal(item.Path, lyrics); err != nil {
+			return "", err
+		}
+		item.EmbeddedLyrics = true
+		return "embedded lyrics", nil
+	case mode == LibraryFixModeSidecar && item.SidecarLyrics == "":
+		lyrics := ""
+		if item.EmbeddedLyrics {
+			extracted, err := ExtractLyrics(item.Path)
+			if err != nil {
+				return "", err
+			}
+			lyrics = extracted
+		}
+		if strings.TrimSpace(lyrics) == "" {
+			fetched, err := fetchLibraryLyrics(item.Path)
+			if err != nil {
+				return "", err
+			}
+			lyrics = fetched
+		}
+		if err := os.WriteFile(lyricsPath, []byte(lyrics), 0644); err != nil {
+			return "", err
+		}
+		item.SidecarLyrics = lyricsPath
+		return "wrote " + filepath.Base(lyricsPath), nil
+	}
+	return "", nil
+}
+
+func verifyLibraryFile(filePath string, opts LibraryVerifyOptions) LibraryVerifyItem {
+	item := LibraryVerifyItem{Path: filePath}
+
+	embeddedCover, embeddedLyrics, err := EmbeddedArtAndLyrics(filePath)
+	if err != nil {
+		item.Error = err.Error()
+	}
+	item.EmbeddedCover = embeddedCover
+	item.EmbeddedLyrics = embeddedLyrics
+	item.SidecarCover = findSidecarCover(filePath)
+	item.SidecarLyrics = findSidecarLyrics(filePath)
+
+	if opts.Fix && item.Error == "" {
+		mode := NormalizeLibraryFixMode(opts.FixMode)
+		var fixErrors []string
+		if opts.CheckCover {
+			if fixed, err := fixLibraryCover(&item, mode); err != nil {
+				fixErrors = append(fixErrors, fmt.Sprintf("cover: %v", err))
+			} else if fixed != "" {
+				item.Fixed = append(item.Fixed, fixed)
+			}
+		}
+		if opts.CheckLyrics {
+			if fixed, err := fixLibraryLyrics(&item, mode); err != nil {
+				fixErrors = append(fixErrors, fmt.Sprintf("lyrics: %v", err))
+			} else if fixed != "" {
+				item.Fixed = append(item.Fixed, fixed)
+			}
+		}
+		item.FixError = strings.Join(fixErrors, "; ")
+	}
+
+	item.MissingCover = opts.CheckCover && !item.EmbeddedCover && item.SidecarCover == ""
+	item.MissingLyrics = opts.CheckLyrics && !item.EmbeddedLyrics && item.SidecarLyrics == ""
+	return item
+}
+
+func VerifyLibrary(ctx context.Context, rootDir string, opts LibraryVerifyOptions, progress func(LibraryScanProgress)) (*LibraryVerifyResult, error) {
+	if !opts.CheckCover && !opts.CheckLyrics {
+		opts.CheckCover = true
+		opts.CheckLyrics = true
+	}
+
+	files, err := ListAudioFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]LibraryVerifyItem, len(files))
+	scanErr := scanLibraryFiles(ctx, len(files), opts.Workers, func(i int) {
+		items[i] = verifyLibraryFile(files[i].Path, opts)
+	}, func(scanned int, index int) {
+		if progress != nil {
+			progress(LibraryScanProgress{Scanned: scanned, Total: len(files), Current: files[index].Path})
+		}
+	})
+
+	result := &LibraryVerifyResult{Root: rootDir, Total: len(files)}
+	for _, item := range items {
+		if item.Path == "" {
+			continue
+		}
+		result.Scanned++
+		if item.MissingCover {
+			result.MissingCover++
+		}
+		if item.MissingLyrics {
+			result.MissingLyrics++
+		}
+		if len(item.Fixed) > 0 {
+			result.Fixed++
+		}
+		if item.MissingCover || item.MissingLyrics || len(item.Fixed) > 0 || item.Error != "" || item.FixError != "" {
+			result.Items = append(result.Items, item)
+		}
+	}
+
+	if scanErr != nil {
+		result.Cancelled = true
+		return result, scanErr
+	}
+	return result, nil
+}
@@ -29,6 +29,18 @@ func (s *SongLinkClient) resolveSpotifyTrackLinks(spotifyTrackID string, region
 		links.ISRC = isrc
 	}
 
+	return s.completeTrackLinks(links, region, attempts)
+}
+
+func (s *SongLinkClient) resolveISRCTrackLinks(isrc string, region string) (*resolvedTrackLinks, error) {
+	return s.completeTrackLinks(&resolvedTrackLinks{ISRC: strings.TrimSpace(isrc)}, region, nil)
+}
+
+func (s *SongLinkClient) completeTrackLinks(links *resolvedTrackLinks, region string, attempts []string) (*resolvedTrackLinks, error) {
+	if region == "" {
+		region = ResolveCountryCode()
+	}
+
 	if links.ISRC != "" {
 		resolvers := orderedLinkResolvers()
 
@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	loudnormTruePeak = -1.5
+	loudnormRange    = 11.0
+)
+
+type loudnormMeasurement struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+func isLoudnormTarget(format, codec string) bool {
+	switch format {
+	case "mp3", "opus", "ogg":
+		return true
+	case "m4a":
+		return codec != "alac"
+	default:
+		return false
+	}
+}
+
+func formatLoudnormValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
+
+func measureLoudness(ffmpegPath, inputFile string, target float64) (*loudnormMeasurement, error) {
+	filter := fmt.Sprintf("loudnorm=I=%s:TP=%s:LRA=%s:print_format=json", formatLoudnormValue(target), formatLoudnormValue(loudnormTruePeak), formatLoudnormValue(loudnormRange))
+	cmd := exec.Command(ffmpegPath, "-hide_banner", "-nostats", "-i", inputFile, "-map", "0:a", "-af", filter, "-f", "null", "-")
+	setHideWindow(cmd)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("loudness measurement failed: %s - %w", string(output), err)
+	}
+
+	text := string(output)
+	start := strings.LastIndex(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("loudness measurement returned no data")
+	}
+
+	var measurement loudnormMeasurement
+	if err := json.Unmarshal([]byte(text[start:end+1]), &measurement); err != nil {
+		return nil, fmt.Errorf("failed to parse loudness measurement: %w", err)
+	}
+	if _, err := strconv.ParseFloat(measurement.InputI, 64); err != nil {
+		return nil, fmt.Errorf("loudness measurement is not usable (input_i=%s)", measurement.InputI)
+	}
+	return &measurement, nil
+}
+
+func buildLoudnormFilter(target float64, measurement *loudnormMeasurement) string {
+	return fmt.Sprintf(
+		"loudnorm=I=%s:TP=%s:LRA=%s:measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true",
+		formatLoudnormValue(target),
+		formatLoudnormValue(loudnormTruePeak),
+		formatLoudnormValue(loudnormRange),
+		measurement.InputI,
+		measurement.InputTP,
+		measurement.InputLRA,
+		measurement.InputThresh,
+		measurement.TargetOffset,
+	)
+}
+
+func loudnormArgs(ffmpegPath, inputFile, format string, target float64) []string {
+	measurement, err := measureLoudness(ffmpegPath, inputFile, target)
+	if err != nil {
+		fmt.Printf("[FFmpeg] Warning: skipping loudness normalization: %v\n", err)
+		return nil
+	}
+	fmt.Printf("[FFmpeg] Normalizing %s from %s LUFS to %s LUFS\n", inputFile, measurement.InputI, formatLoudnormValue(target))
+
+	args := []string{"-af", buildLoudnormFilter(target, measurement)}
+	if format == "opus" {
+		return append(args, "-ar", "48000")
+	}
+	if info, err := GetTrackMetadata(inputFile); err == nil && info.SampleRate > 0 {
+		return append(args, "-ar", strconv.Itoa(int(info.SampleRate)))
+	}
+	return append(args, "-ar", "44100")
+}
@@ -8,7 +8,6 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 )
@@ -26,9 +25,16 @@ type LRCLibResponse struct {
 }
 
 type LyricsLine struct {
-	StartTimeMs string `json:"startTimeMs"`
-	Words       string `json:"words"`
-	EndTimeMs   string `json:"endTimeMs"`
+	StartTimeMs string           `json:"startTimeMs"`
+	Words       string           `json:"words"`
+	EndTimeMs   string           `json:"endTimeMs"`
+	Syllables   []LyricsSyllable `json:"syllables,omitempty"`
+}
+
+type LyricsSyllable struct {
+	StartTimeMs int64  `json:"startTimeMs"`
+	EndTimeMs   int64  `json:"endTimeMs,omitempty"`
+	Text        string `json:"text"`
 }
 
 type LyricsResponse struct {
@@ -67,7 +73,7 @@ type LyricsClient struct {
 
 func NewLyricsClient() *LyricsClient {
 	return &LyricsClient{
-		httpClient: &http.Client{Timeout: 15 * time.Second},
+		httpClient: NewHTTPClient(15 * time.Second),
 	}
 }
 
@@ -144,9 +150,11 @@ func (c *LyricsClient) convertLRCLibToLyricsResponse(lrcLib *LRCLibResponse) *Ly
 				words := strings.TrimSpace(line[closeBracket+1:])
 
 				ms := lrcTimestampToMs(timestamp)
+				words, syllables := parseEnhancedLRCWords(words)
 				resp.Lines = append(resp.Lines, LyricsLine{
 					StartTimeMs: fmt.Sprintf("%d", ms),
 					Words:       words,
+					Syllables:   syllables,
 				})
 				continue
 			}
@@ -250,7 +258,7 @@ func hasLyrics(resp *LyricsResponse) bool {
 	return resp != nil && !resp.Error && len(resp.Lines) > 0
 }
 
-func (c *LyricsClient) FetchLyricsAllSources(spotifyID, trackName, artistName, albumName string, duration int) (*LyricsResponse, string, error) {
+func (c *LyricsClient) fetchFromLRCLib(trackName, artistName, albumName string, duration int) (*LyricsResponse, string, error) {
 
 	var unsyncedFallback *LyricsResponse
 	var unsyncedSource string
@@ -320,14 +328,44 @@ func (c *LyricsClient) FetchLyricsAllSources(spotifyID, trackName, artistName, a
 	}
 
 	if unsyncedFallback != nil {
-		fmt.Printf("   [LRCLIB] No synced found, using unsynced from: %s\n", unsyncedSource)
 		return unsyncedFallback, unsyncedSource + " (unsynced)", nil
 	}
 
+	return nil, "", fmt.Errorf("lyrics not found on LRCLIB")
+}
+
+func (c *LyricsClient) FetchLyricsAllSources(spotifyID, trackName, artistName, albumName string, duration int) (*LyricsResponse, string, error) {
+	var unsyncedFallback *LyricsResponse
+	var unsyncedSource string
+
+	for _, provider := range c.Providers() {
+		resp, source, err := provider.FetchLyrics(trackName, artistName, albumName, duration)
+		if err != nil || !hasLyrics(resp) {
+			fmt.Printf("   [%s] no lyrics\n", provider.Name())
+			continue
+		}
+		if isSynced(resp) {
+			return resp, source, nil
+		}
+		if unsyncedFallback == nil {
+			unsyncedFallback = resp
+			unsyncedSource = source
+		}
+	}
+
+	if unsyncedFallback != nil {
+		fmt.Printf("   No synced lyrics found, using unsynced from: %s\n", unsyncedSource)
+		return unsyncedFallback, unsyncedSource, nil
+	}
+
 	return nil, "", fmt.Errorf("lyrics not found in any source")
 }
 
 func (c *LyricsClient) ConvertToLRC(lyrics *LyricsResponse, trackName, artistName string) string {
+	return c.ConvertToLRCWithFormat(lyrics, trackName, artistName, GetLRCFormatSetting())
+}
+
+func (c *LyricsClient) ConvertToLRCWithFormat(lyrics *LyricsResponse, trackName, artistName, format string) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("[ti:%s]\n", trackName))
@@ -335,17 +373,31 @@ func (c *LyricsClient) ConvertToLRC(lyrics *LyricsResponse, trackName, artistNam
 	sb.WriteString("[by:SpotiFlac]\n")
 	sb.WriteString("\n")
 
+	romanization := GetLyricsRomanizationSetting()
 	for _, line := range lyrics.Lines {
 		if line.Words == "" {
 			continue
 		}
 
-		if line.StartTimeMs == "" {
-			sb.WriteString(fmt.Sprintf("%s\n", line.Words))
-		} else {
+		lines := []LyricsLine{line}
+		if romanization != LyricsRomanizationOff && NeedsRomanization(line.Words) {
+			if romanized := romanizeLyricsLine(line); romanized.Words != line.Words {
+				if romanization == LyricsRomanizationReplace {
+					lines = []LyricsLine{romanized}
+				} else {
+					lines = append(lines, romanized)
+				}
+			}
+		}
 
-			timestamp := msToLRCTimestamp(line.StartTimeMs)
-			sb.WriteString(fmt.Sprintf("%s%s\n", timestamp, line.Words))
+		for _, l := range lines {
+			if l.StartTimeMs == "" {
+				sb.WriteString(fmt.Sprintf("%s\n", l.Words))
+			} else {
+
+				timestamp := msToLRCTimestamp(l.StartTimeMs)
+				sb.WriteString(fmt.Sprintf("%s%s\n", timestamp, formatLRCLineWords(l, format)))
+			}
 		}
 	}
 
@@ -365,60 +417,16 @@ func msToLRCTimestamp(msStr string) string {
 }
 
 func buildLyricsFilename(trackName, artistName, albumName, albumArtist, releaseDate, filenameFormat, isrc string, includeTrackNumber bool, position, discNumber int) string {
-	safeTitle := sanitizeFilename(trackName)
-	safeArtist := sanitizeFilename(artistName)
-	safeAlbum := sanitizeFilename(albumName)
-	safeAlbumArtist := sanitizeFilename(albumArtist)
-	safeISRC := SanitizeOptionalFilename(isrc)
-
-	year := ""
-	if len(releaseDate) >= 4 {
-		year = releaseDate[:4]
-	}
-
-	var filename string
-
-	if strings.Contains(filenameFormat, "{") {
-		filename = filenameFormat
-		filename = strings.ReplaceAll(filename, "{title}", safeTitle)
-		filename = strings.ReplaceAll(filename, "{artist}", safeArtist)
-		filename = strings.ReplaceAll(filename, "{album}", safeAlbum)
-		filename = strings.ReplaceAll(filename, "{album_artist}", safeAlbumArtist)
-		filename = strings.ReplaceAll(filename, "{year}", year)
-		filename = strings.ReplaceAll(filename, "{date}", sanitizeFilename(releaseDate))
-		filename = strings.ReplaceAll(filename, "{isrc}", safeISRC)
-
-		if discNumber > 0 {
-			filename = strings.ReplaceAll(filename, "{disc}", fmt.Sprintf("%d", discNumber))
-		} else {
-			filename = strings.ReplaceAll(filename, "{disc}", "")
-		}
-
-		if position > 0 {
-			filename = strings.ReplaceAll(filename, "{track}", fmt.Sprintf("%02d", position))
-		} else {
-
-			filename = regexp.MustCompile(`\{track\}\.\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*-\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*`).ReplaceAllString(filename, "")
-		}
-	} else {
-
-		switch filenameFormat {
-		case "artist-title":
-			filename = fmt.Sprintf("%s - %s", safeArtist, safeTitle)
-		case "title":
-			filename = safeTitle
-		default:
-			filename = fmt.Sprintf("%s - %s", safeTitle, safeArtist)
-		}
-
-		if includeTrackNumber && position > 0 {
-			filename = fmt.Sprintf("%02d. %s", position, filename)
-		}
-	}
-
-	return filename + ".lrc"
+	return buildTemplatedFilename(filenameFormat, FilenameTemplateData{
+		Title:       trackName,
+		Artist:      artistName,
+		Album:       albumName,
+		AlbumArtist: albumArtist,
+		ReleaseDate: releaseDate,
+		ISRC:        isrc,
+		Track:       position,
+		Disc:        discNumber,
+	}, includeTrackNumber, position, "%02d. ") + ".lrc"
 }
 
 func findAudioFileForLyrics(dir, trackName, artistName string) string {
@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	LRCFormatStandard = "standard"
+	LRCFormatEnhanced = "enhanced"
+	LRCFormatA2       = "a2"
+)
+
+var enhancedLRCWordPattern = regexp.MustCompile(`<(\d+:\d+(?:\.\d+)?)>`)
+
+func NormalizeLRCFormat(format string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case LRCFormatEnhanced:
+		return LRCFormatEnhanced
+	case LRCFormatA2:
+		return LRCFormatA2
+	default:
+		return LRCFormatStandard
+	}
+}
+
+func formatLRCTime(ms int64) string {
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, (ms/1000)%60, (ms%1000)/10)
+}
+
+func parseEnhancedLRCWords(words string) (string, []LyricsSyllable) {
+	matches := enhancedLRCWordPattern.FindAllStringSubmatchIndex(words, -1)
+	if len(matches) == 0 {
+		return words, nil
+	}
+
+	var syllables []LyricsSyllable
+	for i, match := range matches {
+		textEnd := len(words)
+		if i+1 < len(matches) {
+			textEnd = matches[i+1][0]
+		}
+		startMs := lrcTimestampToMs(words[match[2]:match[3]])
+		if n := len(syllables); n > 0 && syllables[n-1].EndTimeMs == 0 {
+			syllables[n-1].EndTimeMs = startMs
+		}
+
+		text := strings.TrimSpace(words[match[1]:textEnd])
+		if text == "" {
+			continue
+		}
+		syllables = append(syllables, LyricsSyllable{StartTimeMs: startMs, Text: text})
+	}
+
+	plain := strings.Join(strings.Fields(enhancedLRCWordPattern.ReplaceAllString(words, " ")), " ")
+	return plain, syllables
+}
+
+func formatLRCLineWords(line LyricsLine, format string) string {
+	format = NormalizeLRCFormat(format)
+	if format == LRCFormatStandard || len(line.Syllables) == 0 {
+		return line.Words
+	}
+
+	var sb strings.Builder
+	for i, syllable := range line.Syllables {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(fmt.Sprintf("<%s> %s", formatLRCTime(syllable.StartTimeMs), syllable.Text))
+		if format == LRCFormatA2 && syllable.EndTimeMs > 0 {
+			next := int64(-1)
+			if i+1 < len(line.Syllables) {
+				next = line.Syllables[i+1].StartTimeMs
+			}
+			if syllable.EndTimeMs != next {
+				sb.WriteString(fmt.Sprintf(" <%s>", formatLRCTime(syllable.EndTimeMs)))
+			}
+		}
+	}
+	return " " + sb.String()
+}
@@ -0,0 +1,189 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const geniusSearchURL = "https://genius.com/api/search/song"
+
+var (
+	geniusBreakPattern   = regexp.MustCompile(`(?i)<br\s*/?>`)
+	geniusTagPattern     = regexp.MustCompile(`<[^>]+>`)
+	geniusSectionPattern = regexp.MustCompile(`^\[[^\]]*\]$`)
+)
+
+type geniusLyricsProvider struct {
+	httpClient *http.Client
+}
+
+type geniusSearchResponse struct {
+	Response struct {
+		Sections []struct {
+			Hits []struct {
+				Result struct {
+					Title         string `json:"title"`
+					URL           string `json:"url"`
+					PrimaryArtist struct {
+						Name string `json:"name"`
+					} `json:"primary_artist"`
+				} `json:"result"`
+			} `json:"hits"`
+		} `json:"sections"`
+	} `json:"response"`
+}
+
+func newGeniusLyricsProvider(httpClient *http.Client) *geniusLyricsProvider {
+	return &geniusLyricsProvider{httpClient: httpClient}
+}
+
+func (p *geniusLyricsProvider) Name() string {
+	return "Genius"
+}
+
+func (p *geniusLyricsProvider) FetchLyrics(trackName, artistName, albumName string, duration int) (*LyricsResponse, string, error) {
+	songURL, err := p.searchSong(simplifyTrackName(trackName), GetFirstArtist(artistName))
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, songURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch Genius page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("Genius returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read Genius page: %w", err)
+	}
+
+	text := extractGeniusLyrics(string(body))
+	if text == "" {
+		return nil, "", fmt.Errorf("no lyrics found on Genius page")
+	}
+
+	return plainLyricsResponse(text), "Genius (unsynced)", nil
+}
+
+func (p *geniusLyricsProvider) searchSong(trackName, artistName string) (string, error) {
+	query := strings.TrimSpace(trackName + " " + artistName)
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, geniusSearchURL+"?q="+url.QueryEscape(query), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Genius search failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Genius search returned status %d", resp.StatusCode)
+	}
+
+	var result geniusSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse Genius search: %w", err)
+	}
+
+	wantTitle := normalizeLyricsMatchText(trackName)
+	wantArtist := normalizeLyricsMatchText(artistName)
+	for _, section := range result.Response.Sections {
+		for _, hit := range section.Hits {
+			title := normalizeLyricsMatchText(hit.Result.Title)
+			artist := normalizeLyricsMatchText(hit.Result.PrimaryArtist.Name)
+			if hit.Result.URL == "" || !strings.Contains(title, wantTitle) {
+				continue
+			}
+			if strings.Contains(artist, wantArtist) || strings.Contains(wantArtist, artist) {
+				return hit.Result.URL, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no matching song on Genius")
+}
+
+func normalizeLyricsMatchText(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+func extractGeniusLyrics(page string) string {
+	var parts []string
+	for _, block := range extractHTMLBlocks(page, `data-lyrics-container="true"`) {
+		for _, excluded := range extractHTMLBlocks(block, `data-exclude-from-selection="true"`) {
+			block = strings.Replace(block, excluded, "", 1)
+		}
+		block = geniusBreakPattern.ReplaceAllString(block, "\n")
+		block = geniusTagPattern.ReplaceAllString(block, "")
+		parts = append(parts, html.UnescapeString(block))
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.Join(parts, "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || geniusSectionPattern.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func extractHTMLBlocks(page, marker string) []string {
+	var blocks []string
+	for offset := 0; ; {
+		idx := strings.Index(page[offset:], marker)
+		if idx < 0 {
+			return blocks
+		}
+		start := strings.LastIndex(page[:offset+idx], "<div")
+		if start < 0 {
+			return blocks
+		}
+
+		depth := 0
+		end := -1
+		for pos := start; pos < len(page); {
+			nextOpen := strings.Index(page[pos:], "<div")
+			nextClose := strings.Index(page[pos:], "</div>")
+			if nextClose < 0 {
+				break
+			}
+			if nextOpen >= 0 && nextOpen < nextClose {
+				depth++
+				pos += nextOpen + len("<div")
+				continue
+			}
+			depth--
+			pos += nextClose + len("</div>")
+			if depth == 0 {
+				end = pos
+				break
+			}
+		}
+		if end < 0 {
+			return blocks
+		}
+
+		blocks = append(blocks, page[start:end])
+		offset = end
+	}
+}
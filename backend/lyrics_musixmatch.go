@@ -0,0 +1,282 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	musixmatchBaseURL = "https://apic-desktop.musixmatch.com/ws/1.1/"
+	musixmatchAppID   = "web-desktop-app-v1.0"
+)
+
+var (
+	musixmatchTokenMu sync.Mutex
+	musixmatchToken   string
+)
+
+type musixmatchLyricsProvider struct {
+	httpClient *http.Client
+}
+
+type musixmatchEnvelope struct {
+	Message struct {
+		Header struct {
+			StatusCode int    `json:"status_code"`
+			Hint       string `json:"hint"`
+		} `json:"header"`
+		Body json.RawMessage `json:"body"`
+	} `json:"message"`
+}
+
+type musixmatchSubtitleLine struct {
+	Text string `json:"text"`
+	Time struct {
+		Total float64 `json:"total"`
+	} `json:"time"`
+}
+
+func newMusixmatchLyricsProvider(httpClient *http.Client) *musixmatchLyricsProvider {
+	return &musixmatchLyricsProvider{httpClient: httpClient}
+}
+
+func (p *musixmatchLyricsProvider) Name() string {
+	return "Musixmatch"
+}
+
+func (p *musixmatchLyricsProvider) get(method string, params url.Values) (*musixmatchEnvelope, error) {
+	params.Set("app_id", musixmatchAppID)
+	params.Set("format", "json")
+	params.Set("t", strconv.FormatInt(time.Now().UnixMilli(), 10))
+
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, musixmatchBaseURL+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Cookie", "x-mxm-token-guid=")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Musixmatch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Musixmatch returned status %d", resp.StatusCode)
+	}
+
+	var envelope musixmatchEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse Musixmatch response: %w", err)
+	}
+	if envelope.Message.Header.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Musixmatch %s failed: status %d %s", method, envelope.Message.Header.StatusCode, envelope.Message.Header.Hint)
+	}
+	return &envelope, nil
+}
+
+func (p *musixmatchLyricsProvider) userToken(refresh bool) (string, error) {
+	musixmatchTokenMu.Lock()
+	defer musixmatchTokenMu.Unlock()
+
+	if musixmatchToken != "" && !refresh {
+		return musixmatchToken, nil
+	}
+
+	envelope, err := p.get("token.get", url.Values{"user_language": {"en"}})
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		UserToken string `json:"user_token"`
+	}
+	if err := json.Unmarshal(envelope.Message.Body, &body); err != nil || body.UserToken == "" {
+		return "", fmt.Errorf("Musixmatch did not return a user token")
+	}
+
+	musixmatchToken = body.UserToken
+	return musixmatchToken, nil
+}
+
+func (p *musixmatchLyricsProvider) FetchLyrics(trackName, artistName, albumName string, duration int) (*LyricsResponse, string, error) {
+	params := url.Values{
+		"namespace":       {"lyrics_richsynched"},
+		"subtitle_format": {"mxm"},
+		"q_track":         {trackName},
+		"q_artist":        {GetFirstArtist(artistName)},
+		"q_album":         {albumName},
+	}
+	if duration > 0 {
+		params.Set("q_duration", strconv.Itoa(duration))
+	}
+
+	var envelope *musixmatchEnvelope
+	for attempt := 0; attempt < 2; attempt++ {
+		token, err := p.userToken(attempt > 0)
+		if err != nil {
+			return nil, "", err
+		}
+		params.Set("usertoken", token)
+
+		envelope, err = p.get("macro.subtitles.get", params)
+		if err == nil {
+			break
+		}
+		if attempt > 0 {
+			return nil, "", err
+		}
+	}
+
+	var body struct {
+		MacroCalls map[string]musixmatchEnvelope `json:"macro_calls"`
+	}
+	if err := json.Unmarshal(envelope.Message.Body, &body); err != nil {
+		return nil, "", fmt.Errorf("failed to parse Musixmatch lyrics: %w", err)
+	}
+
+	if GetLRCFormatSetting() != LRCFormatStandard {
+		if resp := p.fetchRichSync(body.MacroCalls["matcher.track.get"].Message.Body, params.Get("usertoken")); resp != nil {
+			return resp, "Musixmatch (word-synced)", nil
+		}
+	}
+
+	if subtitles, ok := body.MacroCalls["track.subtitles.get"]; ok {
+		if resp := parseMusixmatchSubtitles(subtitles.Message.Body); resp != nil {
+			return resp, "Musixmatch", nil
+		}
+	}
+
+	if lyrics, ok := body.MacroCalls["track.lyrics.get"]; ok {
+		var lyricsBody struct {
+			Lyrics struct {
+				Body string `json:"lyrics_body"`
+			} `json:"lyrics"`
+		}
+		if json.Unmarshal(lyrics.Message.Body, &lyricsBody) == nil {
+			text := lyricsBody.Lyrics.Body
+			if idx := strings.Index(text, "*******"); idx >= 0 {
+				text = text[:idx]
+			}
+			if strings.TrimSpace(text) != "" {
+				return plainLyricsResponse(text), "Musixmatch (unsynced)", nil
+			}
+		}
+	}
+
+	return nil, "", fmt.Errorf("no lyrics found on Musixmatch")
+}
+
+func parseMusixmatchSubtitles(raw json.RawMessage) *LyricsResponse {
+	var body struct {
+		SubtitleList []struct {
+			Subtitle struct {
+				Body string `json:"subtitle_body"`
+			} `json:"subtitle"`
+		} `json:"subtitle_list"`
+	}
+	if json.Unmarshal(raw, &body) != nil || len(body.SubtitleList) == 0 {
+		return nil
+	}
+
+	var lines []musixmatchSubtitleLine
+	if json.Unmarshal([]byte(body.SubtitleList[0].Subtitle.Body), &lines) != nil || len(lines) == 0 {
+		return nil
+	}
+
+	resp := &LyricsResponse{SyncType: "LINE_SYNCED"}
+	for _, line := range lines {
+		text := strings.TrimSpace(line.Text)
+		if text == "" {
+			continue
+		}
+		resp.Lines = append(resp.Lines, LyricsLine{
+			StartTimeMs: strconv.FormatInt(int64(line.Time.Total*1000), 10),
+			Words:       text,
+		})
+	}
+	if len(resp.Lines) == 0 {
+		return nil
+	}
+	return resp
+}
+
+type musixmatchRichSyncLine struct {
+	Start float64 `json:"ts"`
+	End   float64 `json:"te"`
+	Text  string  `json:"x"`
+	Words []struct {
+		Text   string  `json:"c"`
+		Offset float64 `json:"o"`
+	} `json:"l"`
+}
+
+func (p *musixmatchLyricsProvider) fetchRichSync(matcherBody json.RawMessage, token string) *LyricsResponse {
+	var matcher struct {
+		Track struct {
+			CommonTrackID int64 `json:"commontrack_id"`
+			HasRichSync   int   `json:"has_richsync"`
+		} `json:"track"`
+	}
+	if json.Unmarshal(matcherBody, &matcher) != nil || matcher.Track.HasRichSync == 0 || matcher.Track.CommonTrackID == 0 {
+		return nil
+	}
+
+	envelope, err := p.get("track.richsync.get", url.Values{
+		"commontrack_id": {strconv.FormatInt(matcher.Track.CommonTrackID, 10)},
+		"usertoken":      {token},
+	})
+	if err != nil {
+		return nil
+	}
+
+	var body struct {
+		RichSync struct {
+			Body string `json:"richsync_body"`
+		} `json:"richsync"`
+	}
+	var lines []musixmatchRichSyncLine
+	if json.Unmarshal(envelope.Message.Body, &body) != nil || json.Unmarshal([]byte(body.RichSync.Body), &lines) != nil {
+		return nil
+	}
+
+	resp := &LyricsResponse{SyncType: "LINE_SYNCED"}
+	for _, line := range lines {
+		text := strings.TrimSpace(line.Text)
+		if text == "" {
+			continue
+		}
+
+		var syllables []LyricsSyllable
+		for _, word := range line.Words {
+			startMs := int64((line.Start + word.Offset) * 1000)
+			if n := len(syllables); n > 0 && syllables[n-1].EndTimeMs == 0 {
+				syllables[n-1].EndTimeMs = startMs
+			}
+			if strings.TrimSpace(word.Text) == "" {
+				continue
+			}
+			syllables = append(syllables, LyricsSyllable{StartTimeMs: startMs, Text: strings.TrimSpace(word.Text)})
+		}
+		if n := len(syllables); n > 0 && syllables[n-1].EndTimeMs == 0 {
+			syllables[n-1].EndTimeMs = int64(line.End * 1000)
+		}
+
+		resp.Lines = append(resp.Lines, LyricsLine{
+			StartTimeMs: strconv.FormatInt(int64(line.Start*1000), 10),
+			EndTimeMs:   strconv.FormatInt(int64(line.End*1000), 10),
+			Words:       text,
+			Syllables:   syllables,
+		})
+	}
+	if len(resp.Lines) == 0 {
+		return nil
+	}
+	return resp
+}
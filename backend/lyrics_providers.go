@@ -0,0 +1,66 @@
+package backend
+
+import (
+	"strings"
+)
+
+type LyricsProvider interface {
+	Name() string
+	FetchLyrics(trackName, artistName, albumName string, duration int) (*LyricsResponse, string, error)
+}
+
+var defaultLyricsProviders = []string{"lrclib", "musixmatch", "genius"}
+
+var lyricsProviderFactories = map[string]func(*LyricsClient) LyricsProvider{
+	"lrclib":     func(c *LyricsClient) LyricsProvider { return &lrclibLyricsProvider{client: c} },
+	"musixmatch": func(c *LyricsClient) LyricsProvider { return newMusixmatchLyricsProvider(c.httpClient) },
+	"genius":     func(c *LyricsClient) LyricsProvider { return newGeniusLyricsProvider(c.httpClient) },
+}
+
+func IsKnownLyricsProvider(name string) bool {
+	_, ok := lyricsProviderFactories[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+func (c *LyricsClient) Providers() []LyricsProvider {
+	names := GetLyricsProvidersSetting()
+	providers := make([]LyricsProvider, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		factory, ok := lyricsProviderFactories[name]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		providers = append(providers, factory(c))
+	}
+	return providers
+}
+
+func plainLyricsResponse(text string) *LyricsResponse {
+	resp := &LyricsResponse{SyncType: "UNSYNCED"}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		resp.Lines = append(resp.Lines, LyricsLine{Words: line})
+	}
+	if len(resp.Lines) == 0 {
+		resp.Error = true
+	}
+	return resp
+}
+
+type lrclibLyricsProvider struct {
+	client *LyricsClient
+}
+
+func (p *lrclibLyricsProvider) Name() string {
+	return "LRCLIB"
+}
+
+func (p *lrclibLyricsProvider) FetchLyrics(trackName, artistName, albumName string, duration int) (*LyricsResponse, string, error) {
+	return p.client.fetchFromLRCLib(trackName, artistName, albumName, duration)
+}
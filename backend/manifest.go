@@ -0,0 +1,188 @@
+package backend
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ManifestStatusDownloaded = "downloaded"
+	ManifestStatusSkipped    = "skipped"
+	ManifestStatusFailed     = "failed"
+)
+
+type PlaylistManifestEntry struct {
+	Position  int    `json:"position"`
+	SpotifyID string `json:"spotify_id"`
+	Track     string `json:"track"`
+	Artist    string `json:"artist"`
+	Album     string `json:"album,omitempty"`
+	ISRC      string `json:"isrc,omitempty"`
+	Service   string `json:"service,omitempty"`
+	Quality   string `json:"quality,omitempty"`
+	FilePath  string `json:"file_path,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+type PlaylistManifest struct {
+	Name      string                  `json:"name"`
+	URL       string                  `json:"url,omitempty"`
+	Playlist  string                  `json:"playlist,omitempty"`
+	CreatedAt int64                   `json:"created_at"`
+	Entries   []PlaylistManifestEntry `json:"entries"`
+}
+
+var playlistManifestCSVHeader = []string{"position", "spotify_id", "track", "artist", "album", "isrc", "service", "quality", "file_path", "status", "error"}
+
+func NormalizeManifestFormat(format string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "csv":
+		return "csv"
+	case "json":
+		return "json"
+	default:
+		return ""
+	}
+}
+
+func BuildPlaylistManifestPath(outputDir, name, format string) string {
+	safeName := SanitizeFilename(name)
+	if safeName == "" {
+		safeName = "playlist"
+	}
+	return filepath.Join(outputDir, safeName+".manifest."+format)
+}
+
+func WritePlaylistManifest(path string, manifest PlaylistManifest) error {
+	if manifest.CreatedAt == 0 {
+		manifest.CreatedAt = time.Now().Unix()
+	}
+
+	for i := range manifest.Entries {
+		if manifest.Entries[i].ISRC == "" && manifest.Entries[i].SpotifyID != "" {
+			if isrc, err := GetCachedISRC(manifest.Entries[i].SpotifyID); err == nil {
+				manifest.Entries[i].ISRC = strings.ToUpper(strings.TrimSpace(isrc))
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0644)
+	case ".csv":
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		writer := csv.NewWriter(f)
+		if err := writer.Write(playlistManifestCSVHeader); err != nil {
+			return err
+		}
+		for _, entry := range manifest.Entries {
+			record := []string{
+				strconv.Itoa(entry.Position),
+				entry.SpotifyID,
+				entry.Track,
+				entry.Artist,
+				entry.Album,
+				entry.ISRC,
+				entry.Service,
+				entry.Quality,
+				entry.FilePath,
+				entry.Status,
+				entry.Error,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported manifest format: %s", filepath.Ext(path))
+	}
+}
+
+func LoadPlaylistManifest(path string) (*PlaylistManifest, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var manifest PlaylistManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		return &manifest, nil
+	case ".csv":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("manifest is empty")
+		}
+
+		columns := make(map[string]int, len(records[0]))
+		for idx, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = idx
+		}
+		field := func(record []string, name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		manifest := &PlaylistManifest{
+			Name: strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), ".manifest"),
+		}
+		for _, record := range records[1:] {
+			position, _ := strconv.Atoi(field(record, "position"))
+			manifest.Entries = append(manifest.Entries, PlaylistManifestEntry{
+				Position:  position,
+				SpotifyID: field(record, "spotify_id"),
+				Track:     field(record, "track"),
+				Artist:    field(record, "artist"),
+				Album:     field(record, "album"),
+				ISRC:      field(record, "isrc"),
+				Service:   field(record, "service"),
+				Quality:   field(record, "quality"),
+				FilePath:  field(record, "file_path"),
+				Status:    field(record, "status"),
+				Error:     field(record, "error"),
+			})
+		}
+		return manifest, nil
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s", filepath.Ext(path))
+	}
+}
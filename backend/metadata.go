@@ -17,27 +17,53 @@ import (
 )
 
 type Metadata struct {
-	Title       string
-	Artist      string
-	Album       string
-	AlbumArtist string
-	Separator   string
-	Date        string
-	ReleaseDate string
-	TrackNumber int
-	TotalTracks int
-	DiscNumber  int
-	TotalDiscs  int
-	URL         string
-	Comment     string
-	Copyright   string
-	Publisher   string
-	Composer    string
-	Lyrics      string
-	Description string
-	ISRC        string
-	UPC         string
-	Genre       string
+	Title        string
+	Artist       string
+	Album        string
+	AlbumArtist  string
+	Separator    string
+	Date         string
+	OriginalDate string
+	ReleaseDate  string
+	TrackNumber  int
+	TotalTracks  int
+	DiscNumber   int
+	TotalDiscs   int
+	URL          string
+	Comment      string
+	Copyright    string
+	Publisher    string
+	Composer     string
+	Lyrics       string
+	Description  string
+	ISRC         string
+	UPC          string
+	Genre        string
+	Compilation  bool
+	ExtraTags    map[string]string
+}
+
+func fillMissingMetadata(metadata *Metadata, fallback Metadata) {
+	fillString := func(target *string, value string) {
+		if strings.TrimSpace(*target) == "" {
+			*target = strings.TrimSpace(value)
+		}
+	}
+	fillInt := func(target *int, value int) {
+		if *target <= 0 {
+			*target = value
+		}
+	}
+
+	fillString(&metadata.AlbumArtist, fallback.AlbumArtist)
+	fillString(&metadata.Copyright, fallback.Copyright)
+	fillString(&metadata.Publisher, fallback.Publisher)
+	fillString(&metadata.Composer, fallback.Composer)
+	fillString(&metadata.Genre, fallback.Genre)
+	fillInt(&metadata.TrackNumber, fallback.TrackNumber)
+	fillInt(&metadata.TotalTracks, fallback.TotalTracks)
+	fillInt(&metadata.DiscNumber, fallback.DiscNumber)
+	fillInt(&metadata.TotalDiscs, fallback.TotalDiscs)
 }
 
 func resolveMetadataSeparator(separator string) string {
@@ -113,8 +139,32 @@ func EmbedMetadata(filepath string, metadata Metadata, coverPath string) error {
 		}
 	}
 
+	cmt := buildVorbisComment(metadata)
+
+	cmtBlock := cmt.Marshal()
+	if cmtIdx < 0 {
+		f.Meta = append(f.Meta, &cmtBlock)
+	} else {
+		f.Meta[cmtIdx] = &cmtBlock
+	}
+
+	if coverPath != "" && fileExists(coverPath) {
+		if err := embedCoverArt(f, coverPath); err != nil {
+			fmt.Printf("Warning: Failed to embed cover art: %v\n", err)
+		}
+	}
+
+	if err := f.Save(filepath); err != nil {
+		return fmt.Errorf("failed to save FLAC file: %w", err)
+	}
+
+	return nil
+}
+
+func buildVorbisComment(metadata Metadata) *flacvorbis.MetaDataBlockVorbisComment {
 	cmt := flacvorbis.New()
 	separator := resolveMetadataSeparator(metadata.Separator)
+	applyCompilationMetadata(&metadata)
 
 	if metadata.Title != "" {
 		_ = cmt.Add(flacvorbis.FIELD_TITLE, metadata.Title)
@@ -132,26 +182,36 @@ func EmbedMetadata(filepath string, metadata Metadata, coverPath string) error {
 	} else if metadata.AlbumArtist != "" {
 		_ = cmt.Add("ALBUMARTIST", metadata.AlbumArtist)
 	}
+	if metadata.Compilation {
+		_ = cmt.Add("COMPILATION", "1")
+	}
 	if metadata.Date != "" {
 		_ = cmt.Add(flacvorbis.FIELD_DATE, metadata.Date)
 	}
+	if metadata.OriginalDate != "" {
+		_ = cmt.Add("ORIGINALDATE", metadata.OriginalDate)
+		_ = cmt.Add("ORIGINALYEAR", extractYear(metadata.OriginalDate))
+	}
 	if metadata.TrackNumber > 0 {
 		_ = cmt.Add(flacvorbis.FIELD_TRACKNUMBER, strconv.Itoa(metadata.TrackNumber))
 	}
 	if metadata.TotalTracks > 0 {
 		_ = cmt.Add("TOTALTRACKS", strconv.Itoa(metadata.TotalTracks))
+		_ = cmt.Add("TRACKTOTAL", strconv.Itoa(metadata.TotalTracks))
 	}
 	if metadata.DiscNumber > 0 {
 		_ = cmt.Add("DISCNUMBER", strconv.Itoa(metadata.DiscNumber))
 	}
 	if metadata.TotalDiscs > 0 {
 		_ = cmt.Add("TOTALDISCS", strconv.Itoa(metadata.TotalDiscs))
+		_ = cmt.Add("DISCTOTAL", strconv.Itoa(metadata.TotalDiscs))
 	}
 	if metadata.Copyright != "" {
 		_ = cmt.Add("COPYRIGHT", metadata.Copyright)
 	}
 	if metadata.Publisher != "" {
 		_ = cmt.Add("PUBLISHER", metadata.Publisher)
+		_ = cmt.Add("LABEL", metadata.Publisher)
 	}
 	if composerValues := SplitArtistCredits(metadata.Composer, separator); len(composerValues) > 0 {
 		addVorbisTagValues(cmt, "COMPOSER", composerValues)
@@ -182,24 +242,9 @@ func EmbedMetadata(filepath string, metadata Metadata, coverPath string) error {
 		_ = cmt.Add("LYRICS", metadata.Lyrics)
 	}
 
-	cmtBlock := cmt.Marshal()
-	if cmtIdx < 0 {
-		f.Meta = append(f.Meta, &cmtBlock)
-	} else {
-		f.Meta[cmtIdx] = &cmtBlock
-	}
-
-	if coverPath != "" && fileExists(coverPath) {
-		if err := embedCoverArt(f, coverPath); err != nil {
-			fmt.Printf("Warning: Failed to embed cover art: %v\n", err)
-		}
-	}
+	applyVorbisExtraTags(cmt, metadata.ExtraTags)
 
-	if err := f.Save(filepath); err != nil {
-		return fmt.Errorf("failed to save FLAC file: %w", err)
-	}
-
-	return nil
+	return cmt
 }
 
 func embedCoverArt(f *flac.File, coverPath string) error {
@@ -212,7 +257,7 @@ func embedCoverArt(f *flac.File, coverPath string) error {
 		flacpicture.PictureTypeFrontCover,
 		"Cover",
 		imgData,
-		"image/jpeg",
+		CoverMIMEType(imgData),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create picture block: %w", err)
@@ -341,7 +386,7 @@ func extractCoverWithFFmpeg(filePath string) (string, error) {
 		return "", err
 	}
 
-	tmpFile, err := os.CreateTemp("", "cover-*.jpg")
+	tmpFile, err := os.CreateTemp(StagingDir(), "cover-*.jpg")
 	if err != nil {
 		return "", err
 	}
@@ -389,7 +434,7 @@ func extractCoverFromMp3(filePath string) (string, error) {
 		return "", fmt.Errorf("invalid picture frame")
 	}
 
-	tmpFile, err := os.CreateTemp("", "cover-*.jpg")
+	tmpFile, err := os.CreateTemp(StagingDir(), "cover-*.jpg")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
@@ -419,7 +464,7 @@ func extractCoverFromM4AOrFlac(filePath string) (string, error) {
 					continue
 				}
 
-				tmpFile, err := os.CreateTemp("", "cover-*.jpg")
+				tmpFile, err := os.CreateTemp(StagingDir(), "cover-*.jpg")
 				if err != nil {
 					return "", fmt.Errorf("failed to create temp file: %w", err)
 				}
@@ -574,6 +619,18 @@ func EmbedCoverArtOnly(filePath string, coverPath string) error {
 	ext := strings.ToLower(pathfilepath.Ext(filePath))
 
 	switch ext {
+	case ".flac":
+		f, err := flac.ParseFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to parse FLAC file: %w", err)
+		}
+		if err := embedCoverArt(f, coverPath); err != nil {
+			return err
+		}
+		if err := f.Save(filePath); err != nil {
+			return fmt.Errorf("failed to save FLAC file: %w", err)
+		}
+		return nil
 	case ".mp3":
 		return embedCoverToMp3(filePath, coverPath)
 	case ".m4a":
@@ -600,7 +657,7 @@ func embedCoverToMp3(filePath string, coverPath string) error {
 
 	pic := id3v2.PictureFrame{
 		Encoding:    id3v2.EncodingUTF8,
-		MimeType:    "image/jpeg",
+		MimeType:    CoverMIMEType(artwork),
 		PictureType: id3v2.PTFrontCover,
 		Description: "Front cover",
 		Picture:     artwork,
@@ -944,6 +1001,8 @@ func ExtractFullMetadataFromFile(filePath string) (Metadata, error) {
 			metadata.Album = value
 		case "album_artist", "albumartist":
 			metadata.AlbumArtist = value
+		case "compilation", "tcmp":
+			metadata.Compilation = value == "1"
 		case "date", "year":
 			if metadata.Date == "" || len(value) > len(metadata.Date) {
 				metadata.Date = value
@@ -1014,6 +1073,10 @@ func EmbedMetadataToConvertedFile(filePath string, metadata Metadata, coverPath
 		return embedMetadataToMP3(filePath, metadata, coverPath)
 	case ".m4a":
 		return embedMetadataToM4A(filePath, metadata, coverPath)
+	case ".ogg", ".opus":
+		return embedMetadataToOgg(filePath, metadata, coverPath)
+	case ".wav", ".aiff", ".aif":
+		return embedMetadataToPCM(filePath, metadata, coverPath)
 	default:
 		return fmt.Errorf("unsupported file format: %s", ext)
 	}
@@ -1025,7 +1088,19 @@ func embedMetadataToMP3(filePath string, metadata Metadata, coverPath string) er
 		return fmt.Errorf("failed to open MP3 file: %w", err)
 	}
 	defer tag.Close()
+
+	applyMP3Metadata(tag, metadata, coverPath)
+
+	if err := tag.Save(); err != nil {
+		return fmt.Errorf("failed to save MP3 tags: %w", err)
+	}
+
+	return nil
+}
+
+func applyMP3Metadata(tag *id3v2.Tag, metadata Metadata, coverPath string) {
 	separator := resolveMetadataSeparator(metadata.Separator)
+	applyCompilationMetadata(&metadata)
 
 	tag.DeleteFrames("TXXX")
 
@@ -1036,11 +1111,18 @@ func embedMetadataToMP3(filePath string, metadata Metadata, coverPath string) er
 		tag.SetAlbum(metadata.Album)
 	}
 	if metadata.Date != "" {
-		year := metadata.Date
-		if len(year) >= 4 {
-			year = year[:4]
+		if tag.Version() == 4 {
+			tag.SetYear(metadata.Date)
+		} else {
+			tag.SetYear(extractYear(metadata.Date))
+		}
+	}
+	if metadata.OriginalDate != "" {
+		if tag.Version() == 4 {
+			addMP3TextFrame(tag, "TDOR", metadata.OriginalDate)
+		} else {
+			addMP3TextFrame(tag, "TORY", extractYear(metadata.OriginalDate))
 		}
-		tag.SetYear(year)
 	}
 
 	artistText := joinMultiValueText(SplitArtistCredits(metadata.Artist, separator), separator, true)
@@ -1054,6 +1136,9 @@ func embedMetadataToMP3(filePath string, metadata Metadata, coverPath string) er
 		albumArtistText = strings.TrimSpace(metadata.AlbumArtist)
 	}
 	addMP3TextFrame(tag, "TPE2", albumArtistText)
+	if metadata.Compilation {
+		addMP3TextFrame(tag, "TCMP", "1")
+	}
 
 	if metadata.TrackNumber > 0 {
 		tag.DeleteFrames(tag.CommonID("Track number/Position in set"))
@@ -1116,7 +1201,7 @@ func embedMetadataToMP3(filePath string, metadata Metadata, coverPath string) er
 		if err == nil {
 			pic := id3v2.PictureFrame{
 				Encoding:    id3v2.EncodingUTF8,
-				MimeType:    "image/jpeg",
+				MimeType:    CoverMIMEType(artwork),
 				PictureType: id3v2.PTFrontCover,
 				Description: "Cover",
 				Picture:     artwork,
@@ -1133,11 +1218,7 @@ func embedMetadataToMP3(filePath string, metadata Metadata, coverPath string) er
 	}
 	addMP3TextFrame(tag, "TCON", genreText)
 
-	if err := tag.Save(); err != nil {
-		return fmt.Errorf("failed to save MP3 tags: %w", err)
-	}
-
-	return nil
+	applyMP3ExtraTags(tag, metadata.ExtraTags)
 }
 
 func embedMetadataToM4A(filePath string, metadata Metadata, coverPath string) error {
@@ -1154,7 +1235,6 @@ func embedMetadataToM4A(filePath string, metadata Metadata, coverPath string) er
 		"-i", filePath,
 		"-y",
 	}
-	separator := resolveMetadataSeparator(metadata.Separator)
 
 	if coverPath != "" && fileExists(coverPath) {
 		args = append(args, "-i", coverPath)
@@ -1163,6 +1243,95 @@ func embedMetadataToM4A(filePath string, metadata Metadata, coverPath string) er
 		args = append(args, "-map", "0", "-codec", "copy")
 	}
 
+	args = append(args, buildM4AMetadataArgs(metadata)...)
+
+	tmpOutputFile := strings.TrimSuffix(filePath, pathfilepath.Ext(filePath)) + ".tmp" + pathfilepath.Ext(filePath)
+	defer func() {
+		if _, err := os.Stat(tmpOutputFile); err == nil {
+			os.Remove(tmpOutputFile)
+		}
+	}()
+
+	args = append(args, "-f", "ipod", tmpOutputFile)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed to embed metadata: %s - %w", string(output), err)
+	}
+
+	if err := os.Rename(tmpOutputFile, filePath); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+
+	if err := WriteITunesFreeformTags(filePath, itunesFreeformTagsFromMetadata(metadata)); err != nil {
+		fmt.Printf("Warning: failed to write iTunes freeform tags: %v\n", err)
+	}
+
+	return nil
+}
+
+func embedMetadataToPCM(filePath string, metadata Metadata, coverPath string) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	ext := strings.ToLower(pathfilepath.Ext(filePath))
+	isAIFF := ext == ".aiff" || ext == ".aif"
+
+	args := []string{
+		"-i", filePath,
+		"-y",
+	}
+
+	if isAIFF && coverPath != "" && fileExists(coverPath) {
+		args = append(args, "-i", coverPath)
+		args = append(args, "-map", "0:a", "-map", "1", "-c:a", "copy", "-c:v", "copy", "-disposition:v:0", "attached_pic")
+	} else {
+		args = append(args, "-map", "0:a", "-codec", "copy")
+	}
+	if isAIFF {
+		args = append(args, "-write_id3v2", "1", "-id3v2_version", "3")
+	}
+
+	args = append(args, buildM4AMetadataArgs(metadata)...)
+
+	tmpOutputFile := strings.TrimSuffix(filePath, pathfilepath.Ext(filePath)) + ".tmp" + pathfilepath.Ext(filePath)
+	defer func() {
+		if _, err := os.Stat(tmpOutputFile); err == nil {
+			os.Remove(tmpOutputFile)
+		}
+	}()
+
+	args = append(args, tmpOutputFile)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed to embed metadata: %s - %w", string(output), err)
+	}
+
+	if err := os.Rename(tmpOutputFile, filePath); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+
+	return nil
+}
+
+func buildM4AMetadataArgs(metadata Metadata) []string {
+	var args []string
+	separator := resolveMetadataSeparator(metadata.Separator)
+	applyCompilationMetadata(&metadata)
+
 	if metadata.Title != "" {
 		args = append(args, "-metadata", "title="+metadata.Title)
 	}
@@ -1183,9 +1352,15 @@ func embedMetadataToM4A(filePath string, metadata Metadata, coverPath string) er
 	if albumArtistText != "" {
 		args = append(args, "-metadata", "album_artist="+albumArtistText)
 	}
+	if metadata.Compilation {
+		args = append(args, "-metadata", "compilation=1")
+	}
 	if metadata.Date != "" {
 		args = append(args, "-metadata", "date="+metadata.Date)
 	}
+	if metadata.OriginalDate != "" {
+		args = append(args, "-metadata", "originaldate="+metadata.OriginalDate)
+	}
 	if metadata.TrackNumber > 0 {
 		trackStr := strconv.Itoa(metadata.TrackNumber)
 		if metadata.TotalTracks > 0 {
@@ -1229,27 +1404,7 @@ func embedMetadataToM4A(filePath string, metadata Metadata, coverPath string) er
 	if comment := resolveMetadataComment(metadata); comment != "" {
 		args = append(args, "-metadata", "comment="+comment)
 	}
+	args = appendFFmpegExtraTagArgs(args, metadata.ExtraTags)
 
-	tmpOutputFile := strings.TrimSuffix(filePath, pathfilepath.Ext(filePath)) + ".tmp" + pathfilepath.Ext(filePath)
-	defer func() {
-		if _, err := os.Stat(tmpOutputFile); err == nil {
-			os.Remove(tmpOutputFile)
-		}
-	}()
-
-	args = append(args, "-f", "ipod", tmpOutputFile)
-
-	cmd := exec.Command(ffmpegPath, args...)
-	setHideWindow(cmd)
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("ffmpeg failed to embed metadata: %s - %w", string(output), err)
-	}
-
-	if err := os.Rename(tmpOutputFile, filePath); err != nil {
-		return fmt.Errorf("failed to replace original file: %w", err)
-	}
-
-	return nil
+	return args
 }
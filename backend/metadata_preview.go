@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+)
+
+type MetadataPreviewTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type MetadataPreview struct {
+	Format   string               `json:"format"`
+	Tags     []MetadataPreviewTag `json:"tags"`
+	CoverArt bool                 `json:"cover_art"`
+}
+
+func PreviewEmbeddedTags(metadata Metadata, format string, withCover bool) (*MetadataPreview, error) {
+	format = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(format), "."))
+	if format == "" {
+		format = "flac"
+	}
+
+	preview := &MetadataPreview{Format: format, CoverArt: withCover}
+	switch format {
+	case "flac":
+		for _, comment := range buildVorbisComment(metadata).Comments {
+			parts := strings.SplitN(comment, "=", 2)
+			if len(parts) == 2 {
+				preview.Tags = append(preview.Tags, MetadataPreviewTag{Key: parts[0], Value: parts[1]})
+			}
+		}
+	case "mp3":
+		tag := id3v2.NewEmptyTag()
+		applyMP3Metadata(tag, metadata, "")
+		preview.Tags = previewMP3Frames(tag)
+	case "m4a":
+		args := buildM4AMetadataArgs(metadata)
+		for i := 0; i+1 < len(args); i += 2 {
+			parts := strings.SplitN(args[i+1], "=", 2)
+			if args[i] == "-metadata" && len(parts) == 2 {
+				preview.Tags = append(preview.Tags, MetadataPreviewTag{Key: parts[0], Value: parts[1]})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file format: %s", format)
+	}
+
+	return preview, nil
+}
+
+func previewMP3Frames(tag *id3v2.Tag) []MetadataPreviewTag {
+	frames := tag.AllFrames()
+	ids := make([]string, 0, len(frames))
+	for id := range frames {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var tags []MetadataPreviewTag
+	add := func(key, value string) {
+		for _, part := range strings.Split(value, "\x00") {
+			tags = append(tags, MetadataPreviewTag{Key: key, Value: part})
+		}
+	}
+
+	for _, id := range ids {
+		for _, frame := range frames[id] {
+			switch f := frame.(type) {
+			case id3v2.TextFrame:
+				add(id, f.Text)
+			case id3v2.UserDefinedTextFrame:
+				add(id+":"+f.Description, f.Value)
+			case id3v2.CommentFrame:
+				add(id, f.Text)
+			}
+		}
+	}
+
+	return tags
+}
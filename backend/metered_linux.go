@@ -0,0 +1,29 @@
+//go:build linux
+
+package backend
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func detectMeteredConnection() (bool, error) {
+	nmcli, err := exec.LookPath("nmcli")
+	if err != nil {
+		return false, errMeteredUnknown
+	}
+	output, err := exec.Command(nmcli, "-t", "-f", "METERED", "general").Output()
+	if err != nil {
+		return false, fmt.Errorf("nmcli failed: %w", err)
+	}
+
+	value := strings.ToLower(strings.TrimSpace(string(output)))
+	switch {
+	case strings.HasPrefix(value, "yes"):
+		return true, nil
+	case strings.HasPrefix(value, "no"):
+		return false, nil
+	}
+	return false, errMeteredUnknown
+}
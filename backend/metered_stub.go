@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package backend
+
+func detectMeteredConnection() (bool, error) {
+	return false, errMeteredUnknown
+}
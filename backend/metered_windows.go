@@ -0,0 +1,28 @@
+//go:build windows
+
+package backend
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const meteredCostScript = `$p = [Windows.Networking.Connectivity.NetworkInformation,Windows.Networking.Connectivity,ContentType=WindowsRuntime]::GetInternetConnectionProfile(); if ($p) { $p.GetConnectionCost().NetworkCostType }`
+
+func detectMeteredConnection() (bool, error) {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", meteredCostScript)
+	setHideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("powershell failed: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(output))) {
+	case "fixed", "variable":
+		return true, nil
+	case "unrestricted":
+		return false, nil
+	}
+	return false, errMeteredUnknown
+}
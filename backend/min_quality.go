@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const PendingBetterSourcePrefix = "Pending better source"
+
+type MinQualityRule struct {
+	BitDepth   int
+	SampleRate int
+	Skip       bool
+}
+
+func (r MinQualityRule) Enabled() bool {
+	return r.BitDepth > 0
+}
+
+func (r MinQualityRule) String() string {
+	if r.SampleRate > 0 {
+		return fmt.Sprintf("%d-bit/%.1fkHz", r.BitDepth, float64(r.SampleRate)/1000)
+	}
+	if r.BitDepth == 16 {
+		return "lossless"
+	}
+	return fmt.Sprintf("%d-bit", r.BitDepth)
+}
+
+func GetMinQualitySetting() MinQualityRule {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return MinQualityRule{}
+	}
+
+	rule := MinQualityRule{Skip: true}
+	value, _ := settings["minQuality"].(string)
+	if strings.EqualFold(strings.TrimSpace(value), "lossless") {
+		rule.BitDepth = 16
+	} else {
+		rule.BitDepth, rule.SampleRate = ParseQualityLevel(value)
+	}
+	if skip, ok := settings["minQualitySkip"].(bool); ok {
+		rule.Skip = skip
+	}
+	return rule
+}
+
+func CheckMinQuality(filePath string, lossySource bool, rule MinQualityRule) string {
+	if !rule.Enabled() {
+		return ""
+	}
+	if lossySource {
+		return fmt.Sprintf("lossy source is below the minimum %s", rule)
+	}
+
+	var bitDepth, sampleRate int
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".mp3", ".aac", ".ogg", ".opus":
+		return fmt.Sprintf("lossy %s file is below the minimum %s", strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), "."), rule)
+	case ".flac":
+		depth, rate, err := readFLACQuality(filePath)
+		if err != nil {
+			fmt.Printf("Warning: could not verify quality of %s: %v\n", filepath.Base(filePath), err)
+			return ""
+		}
+		bitDepth, sampleRate = depth, rate
+	default:
+		meta, err := GetTrackMetadata(filePath)
+		if err != nil {
+			fmt.Printf("Warning: could not verify quality of %s: %v\n", filepath.Base(filePath), err)
+			return ""
+		}
+		if meta.BitsPerSample == 0 {
+			return fmt.Sprintf("lossy stream is below the minimum %s", rule)
+		}
+		bitDepth, sampleRate = int(meta.BitsPerSample), int(meta.SampleRate)
+	}
+
+	if bitDepth < rule.BitDepth || (rule.SampleRate > 0 && sampleRate < rule.SampleRate) {
+		return fmt.Sprintf("%d-bit/%.1fkHz is below the minimum %s", bitDepth, float64(sampleRate)/1000, rule)
+	}
+	return ""
+}
@@ -0,0 +1,269 @@
+package backend
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const itunesFreeformMean = "com.apple.iTunes"
+
+type mp4Box struct {
+	Type    string
+	Payload []byte
+}
+
+func parseMP4Boxes(data []byte) ([]mp4Box, error) {
+	var boxes []mp4Box
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, fmt.Errorf("truncated MP4 box header")
+		}
+		size := int(binary.BigEndian.Uint32(data[:4]))
+		boxType := string(data[4:8])
+		headerSize := 8
+		switch size {
+		case 0:
+			size = len(data)
+		case 1:
+			if len(data) < 16 {
+				return nil, fmt.Errorf("truncated MP4 box header")
+			}
+			size = int(binary.BigEndian.Uint64(data[8:16]))
+			headerSize = 16
+		}
+		if size < headerSize || size > len(data) {
+			return nil, fmt.Errorf("invalid size for MP4 box %q", boxType)
+		}
+		boxes = append(boxes, mp4Box{Type: boxType, Payload: data[headerSize:size]})
+		data = data[size:]
+	}
+	return boxes, nil
+}
+
+func serializeMP4Box(boxType string, payload []byte) []byte {
+	out := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(out[:4], uint32(8+len(payload)))
+	copy(out[4:8], boxType)
+	return append(out, payload...)
+}
+
+func serializeMP4Boxes(boxes []mp4Box) []byte {
+	var out []byte
+	for _, box := range boxes {
+		out = append(out, serializeMP4Box(box.Type, box.Payload)...)
+	}
+	return out
+}
+
+func buildITunesFreeformAtom(name, value string) []byte {
+	fullBox := func(content string) []byte {
+		return append([]byte{0, 0, 0, 0}, content...)
+	}
+	data := append([]byte{0, 0, 0, 1, 0, 0, 0, 0}, value...)
+
+	var payload []byte
+	payload = append(payload, serializeMP4Box("mean", fullBox(itunesFreeformMean))...)
+	payload = append(payload, serializeMP4Box("name", fullBox(name))...)
+	payload = append(payload, serializeMP4Box("data", data)...)
+	return serializeMP4Box("----", payload)
+}
+
+func freeformAtomName(payload []byte) string {
+	children, err := parseMP4Boxes(payload)
+	if err != nil {
+		return ""
+	}
+	for _, child := range children {
+		if child.Type == "name" && len(child.Payload) >= 4 {
+			return string(child.Payload[4:])
+		}
+	}
+	return ""
+}
+
+func applyFreeformTagsToIlst(ilst []byte, tags map[string]string) ([]byte, error) {
+	items, err := parseMP4Boxes(ilst)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := items[:0]
+	for _, item := range items {
+		if item.Type == "----" {
+			if _, replaced := tags[strings.ToUpper(freeformAtomName(item.Payload))]; replaced {
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+
+	out := serializeMP4Boxes(kept)
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if tags[name] == "" {
+			continue
+		}
+		out = append(out, buildITunesFreeformAtom(name, tags[name])...)
+	}
+	return out, nil
+}
+
+func replaceOrAppendMP4Box(boxes []mp4Box, boxType string, update func(payload []byte, found bool) ([]byte, error)) ([]mp4Box, error) {
+	for i, box := range boxes {
+		if box.Type == boxType {
+			payload, err := update(box.Payload, true)
+			if err != nil {
+				return nil, err
+			}
+			boxes[i].Payload = payload
+			return boxes, nil
+		}
+	}
+
+	payload, err := update(nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(boxes, mp4Box{Type: boxType, Payload: payload}), nil
+}
+
+func applyFreeformTagsToMoov(moov []byte, tags map[string]string) ([]byte, error) {
+	moovChildren, err := parseMP4Boxes(moov)
+	if err != nil {
+		return nil, err
+	}
+
+	moovChildren, err = replaceOrAppendMP4Box(moovChildren, "udta", func(udta []byte, _ bool) ([]byte, error) {
+		udtaChildren, err := parseMP4Boxes(udta)
+		if err != nil {
+			return nil, err
+		}
+		udtaChildren, err = replaceOrAppendMP4Box(udtaChildren, "meta", func(meta []byte, found bool) ([]byte, error) {
+			if !found {
+				hdlr := serializeMP4Box("hdlr", append(make([]byte, 8), append([]byte("mdirappl"), make([]byte, 9)...)...))
+				meta = append([]byte{0, 0, 0, 0}, hdlr...)
+			}
+			if len(meta) < 4 {
+				return nil, fmt.Errorf("invalid meta box")
+			}
+			metaChildren, err := parseMP4Boxes(meta[4:])
+			if err != nil {
+				return nil, err
+			}
+			metaChildren, err = replaceOrAppendMP4Box(metaChildren, "ilst", func(ilst []byte, _ bool) ([]byte, error) {
+				return applyFreeformTagsToIlst(ilst, tags)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return append(append([]byte{}, meta[:4]...), serializeMP4Boxes(metaChildren)...), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return serializeMP4Boxes(udtaChildren), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return serializeMP4Boxes(moovChildren), nil
+}
+
+func WriteITunesFreeformTags(filePath string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	normalized := make(map[string]string, len(tags))
+	for name, value := range tags {
+		name = normalizeExtraTagKey(name)
+		if name != "" {
+			normalized[name] = strings.TrimSpace(value)
+		}
+	}
+
+	file, err := os.OpenFile(filePath, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	header := make([]byte, 16)
+	for offset < info.Size() {
+		if _, err := file.ReadAt(header[:8], offset); err != nil {
+			return fmt.Errorf("failed to read MP4 box: %w", err)
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+		if size == 1 {
+			if _, err := file.ReadAt(header[8:16], offset+8); err != nil {
+				return fmt.Errorf("failed to read MP4 box: %w", err)
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		} else if size == 0 {
+			size = info.Size() - offset
+		}
+		if size < headerSize {
+			return fmt.Errorf("invalid size for MP4 box %q", boxType)
+		}
+
+		if boxType == "moov" {
+			if offset+size != info.Size() {
+				return fmt.Errorf("moov box is not at the end of the file")
+			}
+			moov := make([]byte, size-headerSize)
+			if _, err := file.ReadAt(moov, offset+headerSize); err != nil && err != io.EOF {
+				return fmt.Errorf("failed to read moov box: %w", err)
+			}
+			updated, err := applyFreeformTagsToMoov(moov, normalized)
+			if err != nil {
+				return err
+			}
+			if err := file.Truncate(offset); err != nil {
+				return err
+			}
+			_, err = file.WriteAt(serializeMP4Box("moov", updated), offset)
+			return err
+		}
+		offset += size
+	}
+
+	return fmt.Errorf("moov box not found")
+}
+
+func itunesFreeformTagsFromMetadata(metadata Metadata) map[string]string {
+	tags := make(map[string]string)
+	if metadata.ISRC != "" {
+		tags["ISRC"] = metadata.ISRC
+	}
+	if metadata.UPC != "" {
+		tags["BARCODE"] = metadata.UPC
+	}
+	if metadata.Publisher != "" {
+		tags["LABEL"] = metadata.Publisher
+	}
+	if metadata.OriginalDate != "" {
+		tags["ORIGINALDATE"] = metadata.OriginalDate
+	}
+	_, extraTags := normalizeExtraTags(metadata.ExtraTags)
+	for key, value := range extraTags {
+		tags[key] = value
+	}
+	return tags
+}
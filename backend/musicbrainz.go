@@ -270,9 +270,7 @@ func FetchMusicBrainzMetadata(isrc, title, artist, album string, useSingleGenre
 		musicBrainzInflightMu.Unlock()
 	}()
 
-	client := &http.Client{
-		Timeout: musicBrainzRequestTimeout,
-	}
+	client := NewHTTPClient(musicBrainzRequestTimeout)
 
 	query := fmt.Sprintf("isrc:%s", isrc)
 	mbResp, err := queryMusicBrainzRecordings(client, query)
@@ -0,0 +1,122 @@
+package backend
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	pathfilepath "path/filepath"
+	"strings"
+
+	"github.com/go-flac/flacpicture"
+)
+
+var ffmetadataEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n")
+
+func buildOggMetadataBlockPicture(coverPath string) (string, error) {
+	imgData, err := os.ReadFile(coverPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cover image: %w", err)
+	}
+
+	picture, err := flacpicture.NewFromImageData(
+		flacpicture.PictureTypeFrontCover,
+		"Cover",
+		imgData,
+		CoverMIMEType(imgData),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create picture block: %w", err)
+	}
+
+	pictureBlock := picture.Marshal()
+	return base64.StdEncoding.EncodeToString(pictureBlock.Data), nil
+}
+
+func buildOggFFMetadata(metadata Metadata, coverPath string) string {
+	separator := resolveMetadataSeparator(metadata.Separator)
+
+	var keys []string
+	values := make(map[string][]string)
+	for _, comment := range buildVorbisComment(metadata).Comments {
+		parts := strings.SplitN(comment, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToUpper(parts[0])
+		if _, exists := values[key]; !exists {
+			keys = append(keys, key)
+		}
+		values[key] = append(values[key], parts[1])
+	}
+
+	if coverPath != "" && fileExists(coverPath) {
+		if picture, err := buildOggMetadataBlockPicture(coverPath); err == nil {
+			keys = append(keys, "METADATA_BLOCK_PICTURE")
+			values["METADATA_BLOCK_PICTURE"] = []string{picture}
+		} else {
+			fmt.Printf("Warning: failed to build cover for Ogg file: %v\n", err)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(";FFMETADATA1\n")
+	for _, key := range keys {
+		sb.WriteString(ffmetadataEscaper.Replace(key))
+		sb.WriteString("=")
+		sb.WriteString(ffmetadataEscaper.Replace(strings.Join(values[key], separator)))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func embedMetadataToOgg(filePath string, metadata Metadata, coverPath string) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	ext := pathfilepath.Ext(filePath)
+	base := strings.TrimSuffix(filePath, ext)
+	metadataFile := base + ".ffmetadata.txt"
+	tmpOutputFile := base + ".tmp" + ext
+	defer os.Remove(metadataFile)
+	defer func() {
+		if _, err := os.Stat(tmpOutputFile); err == nil {
+			os.Remove(tmpOutputFile)
+		}
+	}()
+
+	if err := os.WriteFile(metadataFile, []byte(buildOggFFMetadata(metadata, coverPath)), 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+
+	args := []string{
+		"-i", filePath,
+		"-f", "ffmetadata", "-i", metadataFile,
+		"-y",
+		"-map", "0:a",
+		"-codec", "copy",
+		"-map_metadata", "1",
+		"-map_metadata:s:a:0", "1:g",
+		tmpOutputFile,
+	}
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed to embed metadata: %s - %w", string(output), err)
+	}
+
+	if err := os.Rename(tmpOutputFile, filePath); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+
+	return nil
+}
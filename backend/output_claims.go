@@ -0,0 +1,199 @@
+package backend
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	outputClaimSuffix   = ".claim"
+	outputClaimStaleAge = 2 * time.Hour
+	outputClaimPoll     = 500 * time.Millisecond
+)
+
+var (
+	outputDirLocks sync.Map
+
+	outputClaimsMu sync.Mutex
+	outputClaims   = make(map[string]chan struct{})
+)
+
+func outputDirLock(dir string) *sync.Mutex {
+	lock, _ := outputDirLocks.LoadOrStore(filepath.Clean(dir), &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+func EnsureOutputDir(dir string) error {
+	if dir == "" || dir == "." {
+		return nil
+	}
+
+	lock := outputDirLock(dir)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		if info, statErr := os.Stat(dir); statErr == nil && info.IsDir() {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func outputClaimKey(path string) string {
+	key := filepath.Clean(path)
+	if runtime.GOOS == "windows" {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+func outputClaimHost() string {
+	host, _ := os.Hostname()
+	return host
+}
+
+func isOutputClaimStale(claimPath string, info fs.FileInfo) bool {
+	if data, err := os.ReadFile(claimPath); err == nil {
+		var pid int
+		var host string
+		if n, _ := fmt.Sscan(string(data), &pid, &host); n == 2 && pid > 0 && host == outputClaimHost() {
+			return pid == os.Getpid() || !processAlive(pid)
+		}
+	}
+	return time.Since(info.ModTime()) > outputClaimStaleAge
+}
+
+func tryClaimOutputPath(path string) (func(), <-chan struct{}) {
+	key := outputClaimKey(path)
+
+	outputClaimsMu.Lock()
+	defer outputClaimsMu.Unlock()
+
+	if waitCh, ok := outputClaims[key]; ok {
+		return nil, waitCh
+	}
+
+	claimPath := path + outputClaimSuffix
+	claimFile, err := os.OpenFile(claimPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		if info, statErr := os.Stat(claimPath); statErr == nil && isOutputClaimStale(claimPath, info) {
+			_ = os.Remove(claimPath)
+			claimFile, err = os.OpenFile(claimPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		}
+	}
+	switch {
+	case os.IsExist(err):
+		return nil, nil
+	case err != nil:
+		claimPath = ""
+	default:
+		fmt.Fprintf(claimFile, "%d %s\n", os.Getpid(), outputClaimHost())
+		claimFile.Close()
+	}
+
+	done := make(chan struct{})
+	outputClaims[key] = done
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			outputClaimsMu.Lock()
+			delete(outputClaims, key)
+			outputClaimsMu.Unlock()
+			if claimPath != "" {
+				_ = os.Remove(claimPath)
+			}
+			close(done)
+		})
+	}, nil
+}
+
+func removeOrphanedOutputClaim(path string, info fs.FileInfo, cutoff time.Time, result *TempCleanupResult) {
+	outputClaimsMu.Lock()
+	defer outputClaimsMu.Unlock()
+
+	if _, held := outputClaims[outputClaimKey(strings.TrimSuffix(path, outputClaimSuffix))]; held || !isOutputClaimStale(path, info) {
+		return
+	}
+	removeStaleFile(path, info, cutoff, result)
+}
+
+func SweepOrphanedOutputClaims(downloadDirs []string) TempCleanupResult {
+	var result TempCleanupResult
+	for _, dir := range downloadDirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(strings.ToLower(d.Name()), outputClaimSuffix) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			removeOrphanedOutputClaim(path, info, time.Now(), &result)
+			return nil
+		})
+	}
+	if result.FilesRemoved > 0 {
+		fmt.Printf("[Cleanup] Removed %d orphaned output claim(s)\n", result.FilesRemoved)
+	}
+	return result
+}
+
+func hasDownloadedFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Size() > 0
+}
+
+func ReserveOutputPathForDownload(path string, redownloadWithSuffix bool) (string, bool, func()) {
+	path = FitPathLength(path, GetMaxPathLengthSetting())
+	if redownloadWithSuffix {
+		ext := filepath.Ext(path)
+		base := strings.TrimSuffix(path, ext)
+		candidate := path
+		for i := 1; ; i++ {
+			if !hasDownloadedFile(candidate) {
+				if release, _ := tryClaimOutputPath(candidate); release != nil {
+					return candidate, false, release
+				}
+			}
+			candidate = fmt.Sprintf("%s_%02d%s", base, i, ext)
+		}
+	}
+
+	announced := false
+	for {
+		if hasDownloadedFile(path) {
+			return path, true, func() {}
+		}
+
+		release, waitCh := tryClaimOutputPath(path)
+		if release != nil {
+			if hasDownloadedFile(path) {
+				release()
+				return path, true, func() {}
+			}
+			return path, false, release
+		}
+
+		if !announced {
+			fmt.Printf("Waiting for concurrent download of %s\n", filepath.Base(path))
+			announced = true
+		}
+		if waitCh != nil {
+			<-waitCh
+		} else {
+			time.Sleep(outputClaimPoll)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	partialDownloadSuffix = ".tmp"
+	orphanedPartialMinAge = 10 * time.Minute
+)
+
+var partialAudioMuxers = map[string]string{
+	".flac": "flac",
+	".m4a":  "ipod",
+	".mp3":  "mp3",
+	".opus": "opus",
+	".ogg":  "ogg",
+	".wav":  "wav",
+	".aiff": "aiff",
+	".aif":  "aiff",
+}
+
+func PartialDownloadPath(path string) string {
+	return path + partialDownloadSuffix
+}
+
+func commitPartialDownload(partialPath, finalPath string) error {
+	if err := os.Rename(partialPath, finalPath); err != nil {
+		os.Remove(partialPath)
+		return fmt.Errorf("failed to move completed download into place: %w", err)
+	}
+	return nil
+}
+
+func isPartialDownload(name string) bool {
+	lower := strings.ToLower(name)
+	if !strings.HasSuffix(lower, partialDownloadSuffix) {
+		return false
+	}
+	_, ok := partialAudioMuxers[filepath.Ext(strings.TrimSuffix(lower, partialDownloadSuffix))]
+	return ok
+}
+
+func partialDownloadMuxer(finalPath string) string {
+	return partialAudioMuxers[strings.ToLower(filepath.Ext(finalPath))]
+}
+
+func SweepOrphanedPartialDownloads(downloadDirs []string) TempCleanupResult {
+	var result TempCleanupResult
+	cutoff := time.Now().Add(-orphanedPartialMinAge)
+	for _, dir := range downloadDirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !isPartialDownload(d.Name()) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			removeStaleFile(path, info, cutoff, &result)
+			return nil
+		})
+	}
+	if result.FilesRemoved > 0 {
+		fmt.Printf("[Cleanup] Removed %d orphaned partial download(s), reclaimed %.2f MB\n", result.FilesRemoved, float64(result.BytesReclaimed)/(1024*1024))
+	}
+	return result
+}
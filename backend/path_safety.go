@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxFilenameComponentBytes = 240
+	defaultWindowsMaxPath     = 260
+	minTruncatedNameRunes     = 8
+)
+
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+var trackNumberPrefixPattern = regexp.MustCompile(`^\d{1,3}(?:[.\-]\d{1,3})?(?:\.\s*|\s+-\s+|\s+)`)
+
+func GetMaxPathLengthSetting() int {
+	defaultValue := 0
+	if runtime.GOOS == "windows" {
+		defaultValue = defaultWindowsMaxPath
+	}
+
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return defaultValue
+	}
+	if value, ok := settings["maxPathLength"].(float64); ok && value > 0 {
+		return int(value)
+	}
+	return defaultValue
+}
+
+func escapeReservedName(name string) string {
+	stem := name
+	if idx := strings.Index(stem, "."); idx >= 0 {
+		stem = stem[:idx]
+	}
+	if windowsReservedNames[strings.ToUpper(strings.TrimSpace(stem))] {
+		return stem + "_" + name[len(stem):]
+	}
+	return name
+}
+
+func truncateUTF8Bytes(value string, maxBytes int) string {
+	if len(value) <= maxBytes {
+		return value
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut]
+}
+
+func truncateRunes(value string, maxRunes int) string {
+	if maxRunes <= 0 {
+		return ""
+	}
+	runes := []rune(value)
+	if len(runes) <= maxRunes {
+		return value
+	}
+	return string(runes[:maxRunes])
+}
+
+func FitPathLength(path string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(path) <= maxLength {
+		return path
+	}
+
+	dir, name := filepath.Split(path)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	prefix := trackNumberPrefixPattern.FindString(stem)
+	rest := strings.TrimPrefix(stem, prefix)
+
+	overflow := utf8.RuneCountInString(path) - maxLength
+	keep := utf8.RuneCountInString(rest) - overflow
+	if keep < minTruncatedNameRunes {
+		keep = minTruncatedNameRunes
+	}
+
+	truncated := strings.TrimRight(truncateRunes(rest, keep), ". ")
+	if truncated == "" {
+		truncated = "Unknown"
+	}
+	return dir + prefix + truncated + ext
+}
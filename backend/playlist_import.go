@@ -0,0 +1,413 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	importFieldSpotifyID = "spotify_id"
+	importFieldTitle     = "title"
+	importFieldArtist    = "artist"
+	importFieldAlbum     = "album"
+	importFieldISRC      = "isrc"
+	importFieldDuration  = "duration"
+	importFieldPlaylist  = "playlist"
+
+	importDurationTolerance = 5000
+)
+
+var playlistImportAliases = map[string][]string{
+	importFieldSpotifyID: {"track uri", "spotify uri", "uri", "spotify id", "spotify track id", "track id", "spotify", "spotify url", "spotify link", "id"},
+	importFieldTitle:     {"track name", "track title", "track", "title", "name", "song", "song name", "song title"},
+	importFieldArtist:    {"artist name(s)", "artist names", "artist name", "artist", "artists", "artist(s)", "track artist"},
+	importFieldAlbum:     {"album name", "album title", "album"},
+	importFieldISRC:      {"isrc", "track isrc"},
+	importFieldDuration:  {"track duration (ms)", "duration (ms)", "duration ms", "duration", "length", "time"},
+	importFieldPlaylist:  {"playlist name", "playlist"},
+}
+
+type ImportedTrack struct {
+	Row        int    `json:"row"`
+	SpotifyID  string `json:"spotify_id,omitempty"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album,omitempty"`
+	ISRC       string `json:"isrc,omitempty"`
+	DurationMS int    `json:"duration_ms,omitempty"`
+}
+
+type ImportedPlaylist struct {
+	Name             string            `json:"name"`
+	Format           string            `json:"format"`
+	Columns          map[string]string `json:"columns"`
+	UnmatchedColumns []string          `json:"unmatched_columns,omitempty"`
+	Tracks           []ImportedTrack   `json:"tracks"`
+}
+
+func normalizeImportHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	header = strings.NewReplacer("_", " ", "-", " ", ".", " ").Replace(header)
+	return strings.Join(strings.Fields(header), " ")
+}
+
+func matchImportColumns(headers []string) (map[string]int, map[string]string, []string) {
+	normalized := make([]string, len(headers))
+	for i, header := range headers {
+		normalized[i] = normalizeImportHeader(header)
+	}
+
+	indexes := make(map[string]int)
+	columns := make(map[string]string)
+	used := make(map[int]bool)
+	for _, field := range []string{importFieldSpotifyID, importFieldTitle, importFieldArtist, importFieldAlbum, importFieldISRC, importFieldDuration, importFieldPlaylist} {
+		for _, alias := range playlistImportAliases[field] {
+			found := false
+			for i, header := range normalized {
+				if used[i] || header != alias {
+					continue
+				}
+				indexes[field] = i
+				columns[field] = headers[i]
+				used[i] = true
+				found = true
+				break
+			}
+			if found {
+				break
+			}
+		}
+	}
+
+	var unmatched []string
+	for i, header := range headers {
+		if !used[i] && strings.TrimSpace(header) != "" {
+			unmatched = append(unmatched, strings.TrimSpace(header))
+		}
+	}
+	return indexes, columns, unmatched
+}
+
+func importSpotifyID(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "spotify:local:") {
+		return ""
+	}
+	id, err := extractSpotifyTrackID(value)
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+func parseImportDuration(value string, header string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if strings.Contains(value, ":") {
+		total := 0
+		for _, part := range strings.Split(value, ":") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return 0
+			}
+			total = total*60 + n
+		}
+		return total * 1000
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 {
+		return 0
+	}
+	if strings.Contains(normalizeImportHeader(header), "ms") || number > 3600 {
+		return int(math.Round(number))
+	}
+	return int(math.Round(number * 1000))
+}
+
+func buildImportedTrack(row int, get func(field string) string, durationHeader string) ImportedTrack {
+	return ImportedTrack{
+		Row:        row,
+		SpotifyID:  importSpotifyID(get(importFieldSpotifyID)),
+		Title:      strings.TrimSpace(get(importFieldTitle)),
+		Artist:     strings.TrimSpace(get(importFieldArtist)),
+		Album:      strings.TrimSpace(get(importFieldAlbum)),
+		ISRC:       strings.ToUpper(strings.TrimSpace(get(importFieldISRC))),
+		DurationMS: parseImportDuration(get(importFieldDuration), durationHeader),
+	}
+}
+
+func missingImportColumnsError(unmatched []string) error {
+	return fmt.Errorf("no track columns recognized (need a Spotify URI/ID column, or title and artist columns); unmatched columns: %s", strings.Join(unmatched, ", "))
+}
+
+func sniffImportDelimiter(data []byte) rune {
+	firstLine := data
+	if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+		firstLine = data[:idx]
+	}
+	best, bestCount := ',', bytes.Count(firstLine, []byte(","))
+	for _, candidate := range []rune{'\t', ';'} {
+		if count := bytes.Count(firstLine, []byte(string(candidate))); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+func parseDelimitedPlaylist(data []byte, delimiter rune) (*ImportedPlaylist, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse playlist file: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("playlist file is empty")
+	}
+
+	indexes, columns, unmatched := matchImportColumns(records[0])
+	_, hasID := indexes[importFieldSpotifyID]
+	_, hasTitle := indexes[importFieldTitle]
+	_, hasArtist := indexes[importFieldArtist]
+	if !hasID && !(hasTitle && hasArtist) {
+		return nil, missingImportColumnsError(unmatched)
+	}
+
+	playlist := &ImportedPlaylist{Columns: columns, UnmatchedColumns: unmatched}
+	for rowIndex, record := range records[1:] {
+		get := func(field string) string {
+			idx, ok := indexes[field]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return record[idx]
+		}
+
+		if playlist.Name == "" {
+			playlist.Name = strings.TrimSpace(get(importFieldPlaylist))
+		}
+		track := buildImportedTrack(rowIndex+2, get, columns[importFieldDuration])
+		if track.SpotifyID == "" && track.Title == "" {
+			continue
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+	return playlist, nil
+}
+
+func importJSONString(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case map[string]interface{}:
+		for _, key := range []string{"name", "title", "uri", "id"} {
+			if text, ok := typed[key].(string); ok {
+				return text
+			}
+		}
+	case []interface{}:
+		var parts []string
+		for _, item := range typed {
+			if text := importJSONString(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+func parseJSONPlaylist(data []byte) (*ImportedPlaylist, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse playlist file: %w", err)
+	}
+
+	name := ""
+	var items []interface{}
+	switch typed := root.(type) {
+	case []interface{}:
+		items = typed
+	case map[string]interface{}:
+		name = importJSONString(typed["name"])
+		if name == "" {
+			name = importJSONString(typed["title"])
+		}
+		for _, key := range []string{"tracks", "items", "songs", "track_list"} {
+			if list, ok := typed[key].([]interface{}); ok {
+				items = list
+				break
+			}
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no tracks found in playlist file")
+	}
+
+	var headers []string
+	seen := make(map[string]bool)
+	for _, raw := range items {
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if nested, ok := object["track"].(map[string]interface{}); ok {
+			object = nested
+		}
+		for key := range object {
+			if !seen[key] {
+				seen[key] = true
+				headers = append(headers, key)
+			}
+		}
+	}
+
+	sort.Strings(headers)
+
+	indexes, columns, unmatched := matchImportColumns(headers)
+	_, hasID := indexes[importFieldSpotifyID]
+	_, hasTitle := indexes[importFieldTitle]
+	_, hasArtist := indexes[importFieldArtist]
+	if !hasID && !(hasTitle && hasArtist) {
+		return nil, missingImportColumnsError(unmatched)
+	}
+
+	playlist := &ImportedPlaylist{Name: strings.TrimSpace(name), Columns: columns, UnmatchedColumns: unmatched}
+	for i, raw := range items {
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if nested, ok := object["track"].(map[string]interface{}); ok {
+			object = nested
+		}
+		get := func(field string) string {
+			idx, ok := indexes[field]
+			if !ok {
+				return ""
+			}
+			return importJSONString(object[headers[idx]])
+		}
+
+		if playlist.Name == "" {
+			playlist.Name = strings.TrimSpace(get(importFieldPlaylist))
+		}
+		track := buildImportedTrack(i+1, get, columns[importFieldDuration])
+		if track.SpotifyID == "" && track.Title == "" {
+			continue
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+	return playlist, nil
+}
+
+func ParseCSVPlaylist(path string) (*ImportedPlaylist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var playlist *ImportedPlaylist
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case format == "json" || (len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{')):
+		format = "json"
+		playlist, err = parseJSONPlaylist(data)
+	case format == "tsv":
+		playlist, err = parseDelimitedPlaylist(data, '\t')
+	default:
+		delimiter := sniffImportDelimiter(data)
+		format = "csv"
+		if delimiter == '\t' {
+			format = "tsv"
+		}
+		playlist, err = parseDelimitedPlaylist(data, delimiter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.Format = format
+	if playlist.Name == "" {
+		playlist.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(playlist.Tracks) == 0 {
+		return nil, fmt.Errorf("no tracks found in playlist file")
+	}
+	return playlist, nil
+}
+
+func ResolveImportedTrack(ctx context.Context, track ImportedTrack) (string, error) {
+	if track.Title == "" || track.Artist == "" {
+		return "", fmt.Errorf("missing title or artist")
+	}
+
+	var best *SearchResult
+	bestScore := math.MaxInt
+	durationRejected := false
+	var lastErr error
+	for _, query := range BuildSearchQueries(track.Title, track.Artist) {
+		results, err := SearchSpotifyByType(ctx, query, "track", 10, 0)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for i := range results {
+			result := results[i]
+			if !SearchResultMatches(track.Title, track.Artist, result.Name, result.Artists) {
+				continue
+			}
+
+			score := 0
+			if track.DurationMS > 0 && result.Duration > 0 {
+				diff := result.Duration - track.DurationMS
+				if diff < 0 {
+					diff = -diff
+				}
+				if diff > importDurationTolerance {
+					durationRejected = true
+					continue
+				}
+				score = diff
+			}
+			if track.Album != "" && normalizeSearchText(track.Album) == normalizeSearchText(result.AlbumName) {
+				score -= importDurationTolerance
+			}
+			if score < bestScore {
+				best, bestScore = &result, score
+			}
+		}
+		if best != nil {
+			return best.ID, nil
+		}
+	}
+
+	if durationRejected {
+		return "", fmt.Errorf("no Spotify match within %ds of the expected duration", importDurationTolerance/1000)
+	}
+	if lastErr != nil {
+		return "", fmt.Errorf("spotify search failed: %w", lastErr)
+	}
+	return "", fmt.Errorf("no Spotify match")
+}
@@ -0,0 +1,393 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	spotifyEmbedURLFormat = "https://open.spotify.com/embed/%s/%s"
+	itunesSearchURL       = "https://itunes.apple.com/search"
+)
+
+var spotifyEmbedNextData = regexp.MustCompile(`(?s)<script[^>]*id="__NEXT_DATA__"[^>]*>(.*?)</script>`)
+
+type PodcastShowMetadata struct {
+	Name        string `json:"name"`
+	Publisher   string `json:"publisher,omitempty"`
+	Images      string `json:"images,omitempty"`
+	ExternalURL string `json:"external_urls"`
+}
+
+type EpisodeMetadata struct {
+	SpotifyID   string `json:"spotify_id"`
+	Name        string `json:"name"`
+	ShowName    string `json:"show_name"`
+	Description string `json:"description,omitempty"`
+	DurationMS  int    `json:"duration_ms"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	Images      string `json:"images,omitempty"`
+	ExternalURL string `json:"external_urls"`
+}
+
+type EpisodeResponse struct {
+	Type     string              `json:"type"`
+	Show     PodcastShowMetadata `json:"show"`
+	Episodes []EpisodeMetadata   `json:"episodes"`
+	Message  string              `json:"message"`
+}
+
+type podcastFeed struct {
+	Channel struct {
+		Items []struct {
+			Title     string `xml:"title"`
+			Enclosure struct {
+				URL  string `xml:"url,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"enclosure"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func embedEntityImage(entity map[string]interface{}) string {
+	best, bestWidth := "", -1
+	for _, keys := range [][]string{{"coverArt", "sources"}, {"visualIdentity", "image"}} {
+		container, _ := entity[keys[0]].(map[string]interface{})
+		sources, _ := container[keys[1]].([]interface{})
+		for _, raw := range sources {
+			source, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			width := 0
+			if w, ok := source["width"].(float64); ok {
+				width = int(w)
+			} else if w, ok := source["maxWidth"].(float64); ok {
+				width = int(w)
+			}
+			if imageURL := jsonString(source, "url"); imageURL != "" && width > bestWidth {
+				best, bestWidth = imageURL, width
+			}
+		}
+	}
+	return best
+}
+
+func embedEntityReleaseDate(entity map[string]interface{}) string {
+	date := jsonString(entity, "releaseDate", "isoString")
+	if date == "" {
+		date = jsonString(entity, "releaseDate")
+	}
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	return date
+}
+
+func embedEntityDuration(entity map[string]interface{}) int {
+	duration, _ := entity["duration"].(float64)
+	return int(duration)
+}
+
+func spotifyIDFromURI(uri string) string {
+	if idx := strings.LastIndex(uri, ":"); idx >= 0 {
+		return uri[idx+1:]
+	}
+	return ""
+}
+
+func fetchSpotifyEmbedEntity(ctx context.Context, client *http.Client, entityType, id string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(spotifyEmbedURLFormat, entityType, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", songLinkUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Spotify %s: %w", entityType, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("spotify %s returned HTTP %d", entityType, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	match := spotifyEmbedNextData.FindSubmatch(body)
+	if match == nil {
+		return nil, fmt.Errorf("spotify %s data not found in page", entityType)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(match[1], &data); err != nil {
+		return nil, fmt.Errorf("failed to decode Spotify %s data: %w", entityType, err)
+	}
+
+	uri := fmt.Sprintf("spotify:%s:%s", entityType, id)
+	var entity map[string]interface{}
+	walkJSONObjects(data, func(object map[string]interface{}) {
+		if entity == nil && jsonString(object, "uri") == uri && (jsonString(object, "name") != "" || jsonString(object, "title") != "") {
+			entity = object
+		}
+	})
+	if entity == nil {
+		return nil, fmt.Errorf("spotify %s %s not found", entityType, id)
+	}
+	return entity, nil
+}
+
+func (c *SpotifyMetadataClient) fetchPodcast(ctx context.Context, parsed spotifyURI) (*EpisodeResponse, error) {
+	entity, err := fetchSpotifyEmbedEntity(ctx, c.httpClient, parsed.Type, parsed.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := jsonString(entity, "name")
+	if name == "" {
+		name = jsonString(entity, "title")
+	}
+
+	if parsed.Type == "episode" {
+		showName := jsonString(entity, "subtitle")
+		showURI := jsonString(entity, "relatedEntityUri")
+		show := PodcastShowMetadata{Name: showName, Images: embedEntityImage(entity)}
+		if showID := spotifyIDFromURI(showURI); showID != "" {
+			show.ExternalURL = "https://open.spotify.com/show/" + showID
+		}
+
+		return &EpisodeResponse{
+			Type: "episode",
+			Show: show,
+			Episodes: []EpisodeMetadata{{
+				SpotifyID:   parsed.ID,
+				Name:        name,
+				ShowName:    showName,
+				Description: jsonString(entity, "description"),
+				DurationMS:  embedEntityDuration(entity),
+				ReleaseDate: embedEntityReleaseDate(entity),
+				Images:      show.Images,
+				ExternalURL: "https://open.spotify.com/episode/" + parsed.ID,
+			}},
+			Message: "This is a podcast episode. Podcasts are not available in lossless quality, but the episode audio can be downloaded from the show's public RSS feed when one exists.",
+		}, nil
+	}
+
+	show := PodcastShowMetadata{
+		Name:        name,
+		Publisher:   jsonString(entity, "subtitle"),
+		Images:      embedEntityImage(entity),
+		ExternalURL: "https://open.spotify.com/show/" + parsed.ID,
+	}
+	response := &EpisodeResponse{
+		Type:     "show",
+		Show:     show,
+		Episodes: []EpisodeMetadata{},
+		Message:  "This is a podcast show. Podcasts are not available in lossless quality; open an episode URL to download its audio from the show's public RSS feed.",
+	}
+
+	trackList, _ := entity["trackList"].([]interface{})
+	for _, raw := range trackList {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		episodeID := spotifyIDFromURI(jsonString(item, "uri"))
+		if episodeID == "" {
+			continue
+		}
+		response.Episodes = append(response.Episodes, EpisodeMetadata{
+			SpotifyID:   episodeID,
+			Name:        jsonString(item, "title"),
+			ShowName:    show.Name,
+			DurationMS:  embedEntityDuration(item),
+			ReleaseDate: embedEntityReleaseDate(item),
+			Images:      show.Images,
+			ExternalURL: "https://open.spotify.com/episode/" + episodeID,
+		})
+	}
+
+	return response, nil
+}
+
+func normalizePodcastTitle(title string) string {
+	title = strings.ToLower(strings.TrimSpace(title))
+	var b strings.Builder
+	for _, r := range title {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func findPodcastFeedURL(ctx context.Context, client *http.Client, showName string) (string, error) {
+	params := url.Values{}
+	params.Set("term", showName)
+	params.Set("media", "podcast")
+	params.Set("entity", "podcast")
+	params.Set("limit", "10")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, itunesSearchURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to search podcast directory: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("podcast directory returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Results []struct {
+			CollectionName string `json:"collectionName"`
+			FeedURL        string `json:"feedUrl"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode podcast directory response: %w", err)
+	}
+
+	want := normalizePodcastTitle(showName)
+	for _, candidate := range result.Results {
+		if candidate.FeedURL != "" && normalizePodcastTitle(candidate.CollectionName) == want {
+			return candidate.FeedURL, nil
+		}
+	}
+	return "", fmt.Errorf("no public feed found for %s (the show may be Spotify exclusive)", showName)
+}
+
+func FindPodcastEpisodeAudio(ctx context.Context, showName, episodeName string) (string, error) {
+	if strings.TrimSpace(showName) == "" || strings.TrimSpace(episodeName) == "" {
+		return "", fmt.Errorf("show and episode names are required")
+	}
+
+	client := NewHTTPClient(30 * time.Second)
+	feedURL, err := findPodcastFeedURL(ctx, client, showName)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", songLinkUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch podcast feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("podcast feed returned HTTP %d", resp.StatusCode)
+	}
+
+	var feed podcastFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return "", fmt.Errorf("failed to parse podcast feed: %w", err)
+	}
+
+	want := normalizePodcastTitle(episodeName)
+	var partial string
+	for _, item := range feed.Channel.Items {
+		if item.Enclosure.URL == "" {
+			continue
+		}
+		title := normalizePodcastTitle(item.Title)
+		if title == want {
+			return item.Enclosure.URL, nil
+		}
+		if partial == "" && title != "" && want != "" && (strings.Contains(title, want) || strings.Contains(want, title)) {
+			partial = item.Enclosure.URL
+		}
+	}
+	if partial != "" {
+		return partial, nil
+	}
+	return "", fmt.Errorf("episode %q was not found in the public feed for %s", episodeName, showName)
+}
+
+func podcastAudioExtension(audioURL string) string {
+	if parsed, err := url.Parse(audioURL); err == nil {
+		switch ext := strings.ToLower(path.Ext(parsed.Path)); ext {
+		case ".mp3", ".m4a", ".aac", ".ogg", ".opus":
+			return ext
+		}
+	}
+	return ".mp3"
+}
+
+func DownloadPodcastEpisode(ctx context.Context, episode EpisodeMetadata, outputDir string) (string, error) {
+	audioURL, err := FindPodcastEpisodeAudio(ctx, episode.ShowName, episode.Name)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(outputDir, SanitizeFilename(episode.ShowName))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	filePath := filepath.Join(dir, SanitizeFilename(episode.Name)+podcastAudioExtension(audioURL))
+	if _, err := os.Stat(filePath); err == nil {
+		fmt.Printf("[Podcast] Episode already exists: %s\n", filePath)
+		return filePath, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, audioURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", songLinkUserAgent)
+
+	resp, err := NewHTTPClient(0).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download episode: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("episode download returned HTTP %d", resp.StatusCode)
+	}
+
+	partPath := PartialDownloadPath(filePath)
+	out, err := os.Create(partPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		os.Remove(partPath)
+		return "", fmt.Errorf("failed to write episode: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(partPath)
+		return "", err
+	}
+	if err := os.Rename(partPath, filePath); err != nil {
+		os.Remove(partPath)
+		return "", err
+	}
+
+	fmt.Printf("[Podcast] Downloaded %s - %s\n", episode.ShowName, episode.Name)
+	return filePath, nil
+}
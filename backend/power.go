@@ -0,0 +1,160 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	suspendCheckInterval  = 5 * time.Second
+	suspendDetectionSlack = 30 * time.Second
+	maxTransferResumes    = 3
+)
+
+var (
+	transferCtxMu  sync.Mutex
+	transferCtx    context.Context
+	transferCancel context.CancelFunc
+)
+
+func init() {
+	transferCtx, transferCancel = context.WithCancel(context.Background())
+}
+
+func currentTransferContext() context.Context {
+	transferCtxMu.Lock()
+	defer transferCtxMu.Unlock()
+	return transferCtx
+}
+
+func interruptTransfers() {
+	transferCtxMu.Lock()
+	transferCancel()
+	transferCtx, transferCancel = context.WithCancel(context.Background())
+	transferCtxMu.Unlock()
+
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	SharedTransport().CloseIdleConnections()
+}
+
+func StartSuspendMonitor(ctx context.Context, onResume func(time.Duration)) {
+	go func() {
+		ticker := time.NewTicker(suspendCheckInterval)
+		defer ticker.Stop()
+
+		last := time.Now().Round(0)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			now := time.Now().Round(0)
+			gap := now.Sub(last)
+			last = now
+
+			if gap < suspendCheckInterval+suspendDetectionSlack {
+				continue
+			}
+
+			fmt.Printf("[Power] System resumed after %s, restarting in-flight transfers\n", gap.Round(time.Second))
+			interruptTransfers()
+			if onResume != nil {
+				onResume(gap)
+			}
+		}
+	}()
+}
+
+func DownloadURLToFile(client *http.Client, url, filePath string) (int64, error) {
+	partialPath := PartialDownloadPath(filePath)
+	written, segmented := tryDownloadSegmented(client, url, partialPath)
+	var err error
+	if !segmented {
+		written, err = downloadURLToPartialFile(client, url, partialPath)
+	}
+	if err != nil {
+		os.Remove(partialPath)
+		return written, err
+	}
+	if err := commitPartialDownload(partialPath, filePath); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+func downloadURLToPartialFile(client *http.Client, url, filePath string) (int64, error) {
+	var written int64
+	for attempt := 0; ; attempt++ {
+		ctx := currentTransferContext()
+
+		req, err := NewRequestWithDefaultHeaders(http.MethodGet, url, nil)
+		if err != nil {
+			return written, fmt.Errorf("failed to create download request: %w", err)
+		}
+		req = req.WithContext(ctx)
+		if written > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", written))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil && attempt < maxTransferResumes {
+				continue
+			}
+			return written, fmt.Errorf("failed to download file: %w", err)
+		}
+
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		switch {
+		case written > 0 && resp.StatusCode == http.StatusPartialContent:
+			flags = os.O_WRONLY | os.O_APPEND
+			fmt.Printf("Resuming download at %.2f MB\n", float64(written)/(1024*1024))
+		case resp.StatusCode == http.StatusOK:
+			written = 0
+		default:
+			resp.Body.Close()
+			return written, fmt.Errorf("download failed with status %d", resp.StatusCode)
+		}
+
+		out, err := os.OpenFile(filePath, flags, 0644)
+		if err != nil {
+			resp.Body.Close()
+			return written, fmt.Errorf("failed to create file: %w", err)
+		}
+
+		pw := NewProgressWriter(out)
+		pw.total = written
+		pw.lastPrinted = written
+		pw.lastBytes = written
+		_, copyErr := io.Copy(pw, resp.Body)
+		resp.Body.Close()
+		closeErr := out.Close()
+		written = pw.GetTotal()
+
+		if copyErr != nil {
+			if ctx.Err() != nil && attempt < maxTransferResumes {
+				fmt.Println("\nTransfer interrupted by system sleep, resuming...")
+				continue
+			}
+			if errors.Is(copyErr, context.Canceled) {
+				return written, fmt.Errorf("transfer interrupted by system sleep: %w", copyErr)
+			}
+			return written, fmt.Errorf("failed to write file: %w", copyErr)
+		}
+		if closeErr != nil {
+			return written, fmt.Errorf("failed to write file: %w", closeErr)
+		}
+
+		return written, nil
+	}
+}
@@ -31,6 +31,11 @@ type DownloadItem struct {
 	EndTime      int64          `json:"end_time"`
 	ErrorMessage string         `json:"error_message"`
 	FilePath     string         `json:"file_path"`
+	CoverURL     string         `json:"cover_url,omitempty"`
+	OutputDir    string         `json:"output_dir,omitempty"`
+	PlaylistName string         `json:"playlist_name,omitempty"`
+	Position     int            `json:"position,omitempty"`
+	Priority     int            `json:"priority"`
 }
 
 var (
@@ -67,6 +72,7 @@ type DownloadQueueInfo struct {
 	CompletedCount   int            `json:"completed_count"`
 	FailedCount      int            `json:"failed_count"`
 	SkippedCount     int            `json:"skipped_count"`
+	PauseReasons     []string       `json:"pause_reasons"`
 }
 
 func GetDownloadProgress() ProgressInfo {
@@ -203,6 +209,7 @@ func AddToQueue(id, trackName, artistName, albumName, spotifyID string) {
 	}
 
 	downloadQueue = append(downloadQueue, item)
+	sortQueuedByPriorityLocked()
 
 	sessionStartLock.Lock()
 	if sessionStartTime == 0 {
@@ -229,6 +236,42 @@ func StartDownloadItem(id string) {
 	currentItemLock.Unlock()
 }
 
+func SetDownloadItemCover(id, coverURL string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].CoverURL = coverURL
+			break
+		}
+	}
+}
+
+func SetDownloadItemContext(id, spotifyID, outputDir, playlistName string, position int) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID != id {
+			continue
+		}
+		if spotifyID != "" {
+			downloadQueue[i].SpotifyID = spotifyID
+		}
+		if outputDir != "" {
+			downloadQueue[i].OutputDir = outputDir
+		}
+		if playlistName != "" {
+			downloadQueue[i].PlaylistName = playlistName
+		}
+		if position > 0 {
+			downloadQueue[i].Position = position
+		}
+		break
+	}
+}
+
 func UpdateItemProgress(id string, progress, speed float64) {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
@@ -346,6 +389,7 @@ func GetDownloadQueue() DownloadQueueInfo {
 		CompletedCount:   completed,
 		FailedCount:      failed,
 		SkippedCount:     skipped,
+		PauseReasons:     GetPauseReasons(),
 	}
 }
 
@@ -396,18 +440,20 @@ func CancelAllQueuedItems() {
 	}
 }
 
-func ResetSessionIfComplete() {
+func HasPendingDownloads() bool {
 	downloadQueueLock.RLock()
-	hasActiveOrQueued := false
+	defer downloadQueueLock.RUnlock()
+
 	for _, item := range downloadQueue {
 		if item.Status == StatusQueued || item.Status == StatusDownloading {
-			hasActiveOrQueued = true
-			break
+			return true
 		}
 	}
-	downloadQueueLock.RUnlock()
+	return false
+}
 
-	if !hasActiveOrQueued {
+func ResetSessionIfComplete() {
+	if !HasPendingDownloads() {
 		sessionStartLock.Lock()
 		sessionStartTime = 0
 		sessionStartLock.Unlock()
@@ -417,3 +463,32 @@ func ResetSessionIfComplete() {
 		totalDownloadedLock.Unlock()
 	}
 }
+
+func GetDownloadItem(id string) (DownloadItem, bool) {
+	downloadQueueLock.RLock()
+	defer downloadQueueLock.RUnlock()
+
+	for _, item := range downloadQueue {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return DownloadItem{}, false
+}
+
+func RemoveDownloadItem(id string) error {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID != id {
+			continue
+		}
+		if downloadQueue[i].Status == StatusDownloading {
+			return fmt.Errorf("download %s is in progress", id)
+		}
+		downloadQueue = append(downloadQueue[:i], downloadQueue[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("download %s not found", id)
+}
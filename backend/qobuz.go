@@ -12,15 +12,16 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
 type QobuzDownloader struct {
-	client *http.Client
-	appID  string
+	client              *http.Client
+	appID               string
+	expectedDurationSec int
 }
 
 type QobuzSearchResponse struct {
@@ -51,6 +52,10 @@ type QobuzTrack struct {
 		Name string `json:"name"`
 		ID   int64  `json:"id"`
 	} `json:"performer"`
+	Composer struct {
+		Name string `json:"name"`
+		ID   int64  `json:"id"`
+	} `json:"composer"`
 	Album struct {
 		Title string `json:"title"`
 		ID    string `json:"id"`
@@ -126,10 +131,8 @@ var qobuzMusicDLDebugKeyTag = []byte{
 
 func NewQobuzDownloader() *QobuzDownloader {
 	return &QobuzDownloader{
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
-		appID: qobuzDefaultAPIAppID,
+		client: NewServiceHTTPClient("qobuz", 60*time.Second),
+		appID:  qobuzDefaultAPIAppID,
 	}
 }
 
@@ -205,9 +208,21 @@ func (q *QobuzDownloader) searchByISRC(isrc string) (*QobuzTrack, error) {
 		return &trackResp, nil
 	}
 
+	items, err := q.searchTracks(isrc, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("track not found for ISRC: %s", isrc)
+	}
+
+	return &items[0], nil
+}
+
+func (q *QobuzDownloader) searchTracks(query string, limit int) ([]QobuzTrack, error) {
 	resp, err := doQobuzSignedRequest(http.MethodGet, "track/search", url.Values{
-		"query": {isrc},
-		"limit": {"1"},
+		"query": {query},
+		"limit": {strconv.Itoa(limit)},
 	}, q.client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search track: %w", err)
@@ -238,11 +253,46 @@ func (q *QobuzDownloader) searchByISRC(isrc string) (*QobuzTrack, error) {
 		return nil, fmt.Errorf("failed to decode response: %w (response: %s)", err, bodyStr)
 	}
 
-	if len(searchResp.Tracks.Items) == 0 {
-		return nil, fmt.Errorf("track not found for ISRC: %s", isrc)
+	return searchResp.Tracks.Items, nil
+}
+
+func (q *QobuzDownloader) searchByMetadata(target VersionTarget) (*QobuzTrack, error) {
+	var matches []QobuzTrack
+	var candidates []VersionCandidate
+	seen := make(map[int64]bool)
+	for _, query := range BuildSearchQueries(target.Title, target.Artist) {
+		items, err := q.searchTracks(query, 10)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			if seen[items[i].ID] || !SearchResultMatches(target.Title, target.Artist, items[i].Title, items[i].Performer.Name) {
+				continue
+			}
+			seen[items[i].ID] = true
+			matches = append(matches, items[i])
+			candidates = append(candidates, VersionCandidate{
+				Title:       strings.TrimSpace(items[i].Title + " " + items[i].Version),
+				Artist:      items[i].Performer.Name,
+				Album:       items[i].Album.Title,
+				ReleaseDate: items[i].ReleaseDateOriginal,
+				DurationSec: items[i].Duration,
+			})
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+
+	if best := PickBestVersion(target, candidates); best >= 0 {
+		return &matches[best], nil
 	}
 
-	return &searchResp.Tracks.Items[0], nil
+	return nil, fmt.Errorf("track not found on Qobuz: %s - %s", target.Artist, target.Title)
+}
+
+func (q *QobuzDownloader) SetExpectedDuration(durationSec int) {
+	q.expectedDurationSec = durationSec
 }
 
 func buildQobuzAPIURL(apiBase string, trackID int64, quality string) string {
@@ -359,7 +409,7 @@ func (q *QobuzDownloader) DownloadFromMusicDL(trackID int64, quality string) (st
 
 func CheckQobuzMusicDLStatus(client *http.Client) bool {
 	if client == nil {
-		client = &http.Client{Timeout: 4 * time.Second}
+		client = NewServiceHTTPClient("qobuz", 4*time.Second)
 	}
 
 	downloader := &QobuzDownloader{client: client, appID: qobuzDefaultAPIAppID}
@@ -376,6 +426,16 @@ func (q *QobuzDownloader) GetDownloadURL(trackID int64, quality string, allowFal
 	fmt.Printf("Getting download URL for track ID: %d with requested quality: %s\n", trackID, qualityCode)
 
 	downloadFunc := func(qual string) (string, error) {
+		if HasQobuzAccount() {
+			fmt.Printf("Trying Qobuz account (Quality: %s)...\n", qual)
+			url, err := q.DownloadFromAccount(trackID, qual)
+			if err == nil {
+				fmt.Printf("✓ Success\n")
+				return url, nil
+			}
+			fmt.Printf("Qobuz account failed: %v, falling back to public providers\n", err)
+		}
+
 		type Provider struct {
 			Name string
 			API  string
@@ -473,41 +533,17 @@ func (q *QobuzDownloader) GetDownloadURL(trackID int64, quality string, allowFal
 func (q *QobuzDownloader) DownloadFile(url, filepath string) error {
 	fmt.Println("Starting file download...")
 
-	downloadClient := &http.Client{
-		Timeout: 5 * time.Minute,
-	}
-
-	req, err := NewRequestWithDefaultHeaders(http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create download request: %w", err)
-	}
-
-	resp, err := downloadClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
-	}
+	downloadClient := NewServiceHTTPClient("qobuz", 5*time.Minute)
 
 	fmt.Printf("Creating file: %s\n", filepath)
-	out, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer out.Close()
-
 	fmt.Println("Downloading...")
 
-	pw := NewProgressWriter(out)
-	_, err = io.Copy(pw, resp.Body)
+	total, err := DownloadURLToFile(downloadClient, url, filepath)
 	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return err
 	}
 
-	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
+	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(total)/(1024*1024))
 	return nil
 }
 
@@ -541,64 +577,37 @@ func (q *QobuzDownloader) DownloadCoverArt(coverURL, filepath string) error {
 	return err
 }
 
-func buildQobuzFilename(title, artist, album, albumArtist, releaseDate string, trackNumber, discNumber int, format string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, extra ...string) string {
-	var filename string
-	isrc := ""
-	if len(extra) > 0 {
-		isrc = SanitizeOptionalFilename(extra[0])
+func qobuzFilenameAudioFormat(track *QobuzTrack, quality string) (int, int) {
+	bitDepth := track.MaximumBitDepth
+	sampleRate := int(track.MaximumSamplingRate * 1000)
+	switch quality {
+	case "6":
+		return 16, 44100
+	case "7":
+		bitDepth = min(bitDepth, 24)
+		sampleRate = min(sampleRate, 96000)
 	}
-
-	numberToUse := position
-	if useAlbumTrackNumber && trackNumber > 0 {
-		numberToUse = trackNumber
+	if bitDepth <= 0 {
+		bitDepth = 16
 	}
-
-	year := ""
-	if len(releaseDate) >= 4 {
-		year = releaseDate[:4]
+	if sampleRate <= 0 {
+		sampleRate = 44100
 	}
+	return bitDepth, sampleRate
+}
 
-	if strings.Contains(format, "{") {
-		filename = format
-		filename = strings.ReplaceAll(filename, "{title}", title)
-		filename = strings.ReplaceAll(filename, "{artist}", artist)
-		filename = strings.ReplaceAll(filename, "{album}", album)
-		filename = strings.ReplaceAll(filename, "{album_artist}", albumArtist)
-		filename = strings.ReplaceAll(filename, "{year}", year)
-		filename = strings.ReplaceAll(filename, "{date}", SanitizeFilename(releaseDate))
-		filename = strings.ReplaceAll(filename, "{isrc}", isrc)
-
-		if discNumber > 0 {
-			filename = strings.ReplaceAll(filename, "{disc}", fmt.Sprintf("%d", discNumber))
-		} else {
-			filename = strings.ReplaceAll(filename, "{disc}", "")
-		}
-
-		if numberToUse > 0 {
-			filename = strings.ReplaceAll(filename, "{track}", fmt.Sprintf("%02d", numberToUse))
-		} else {
-
-			filename = regexp.MustCompile(`\{track\}\.\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*-\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*`).ReplaceAllString(filename, "")
-		}
-	} else {
-
-		switch format {
-		case "artist-title":
-			filename = fmt.Sprintf("%s - %s", artist, title)
-		case "title":
-			filename = title
-		default:
-			filename = fmt.Sprintf("%s - %s", title, artist)
-		}
+func GetQobuzOriginalReleaseDate(isrc string) (string, error) {
+	isrc = strings.TrimSpace(isrc)
+	if isrc == "" {
+		return "", fmt.Errorf("ISRC is required")
+	}
 
-		if includeTrackNumber && position > 0 {
-			filename = fmt.Sprintf("%02d. %s", numberToUse, filename)
-		}
+	track, err := NewQobuzDownloader().searchByISRC(isrc)
+	if err != nil {
+		return "", err
 	}
 
-	return filename + ".flac"
+	return strings.TrimSpace(track.ReleaseDateOriginal), nil
 }
 
 func (q *QobuzDownloader) DownloadTrack(spotifyID, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyTotalDiscs int, spotifyCopyright, spotifyPublisher, spotifyComposer, metadataSeparator, spotifyURL string, allowFallback bool, useFirstArtistOnly bool, useSingleGenre bool, embedGenre bool) (string, error) {
@@ -641,15 +650,24 @@ func (q *QobuzDownloader) DownloadTrackWithISRC(isrc, outputDir, quality, filena
 		close(metaChan)
 	}
 
-	if outputDir != "." {
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("failed to create output directory: %w", err)
-		}
+	if err := EnsureOutputDir(outputDir); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	track, err := q.searchByISRC(isrc)
 	if err != nil {
-		return "", err
+		fmt.Printf("ISRC lookup failed (%v), searching by title and artist...\n", err)
+		fallbackTrack, fallbackErr := q.searchByMetadata(VersionTarget{
+			Title:       spotifyTrackName,
+			Artist:      spotifyArtistName,
+			Album:       spotifyAlbumName,
+			ReleaseDate: spotifyReleaseDate,
+			DurationSec: q.expectedDurationSec,
+		})
+		if fallbackErr != nil {
+			return "", err
+		}
+		track = fallbackTrack
 	}
 
 	artists := spotifyArtistName
@@ -681,20 +699,31 @@ func (q *QobuzDownloader) DownloadTrackWithISRC(isrc, outputDir, quality, filena
 	}
 	fmt.Printf("Download URL obtained: %s\n", urlPreview)
 
-	safeArtist := sanitizeFilename(artists)
-	safeAlbumArtist := sanitizeFilename(spotifyAlbumArtist)
-
+	filenameArtist := artists
+	filenameAlbumArtist := spotifyAlbumArtist
 	if useFirstArtistOnly {
-		safeArtist = sanitizeFilename(GetFirstArtist(artists))
-		safeAlbumArtist = sanitizeFilename(GetFirstArtist(spotifyAlbumArtist))
+		filenameArtist = GetFirstArtist(artists)
+		filenameAlbumArtist = GetFirstArtist(spotifyAlbumArtist)
 	}
 
-	safeTitle := sanitizeFilename(trackTitle)
-	safeAlbum := sanitizeFilename(albumTitle)
-
-	filename := buildQobuzFilename(safeTitle, safeArtist, safeAlbum, safeAlbumArtist, spotifyReleaseDate, spotifyTrackNumber, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber, isrc)
+	bitDepth, sampleRate := qobuzFilenameAudioFormat(track, quality)
+	filename := buildTemplatedFilename(filenameFormat, FilenameTemplateData{
+		Title:       trackTitle,
+		Artist:      filenameArtist,
+		Album:       albumTitle,
+		AlbumArtist: filenameAlbumArtist,
+		ReleaseDate: spotifyReleaseDate,
+		ISRC:        isrc,
+		Track:       filenameTrackNumber(position, spotifyTrackNumber, useAlbumTrackNumber),
+		Disc:        spotifyDiscNumber,
+		Quality:     quality,
+		BitDepth:    bitDepth,
+		SampleRate:  sampleRate,
+		Service:     "qobuz",
+	}, includeTrackNumber, position, "%02d. ") + ".flac"
 	filepath := filepath.Join(outputDir, filename)
-	filepath, alreadyExists := ResolveOutputPathForDownload(filepath, GetRedownloadWithSuffixSetting())
+	filepath, alreadyExists, releaseOutput := ReserveOutputPathForDownload(filepath, GetRedownloadWithSuffixSetting())
+	defer releaseOutput()
 	if alreadyExists {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", filepath, float64(mustFileSize(filepath))/(1024*1024))
 		return "EXISTS:" + filepath, nil
@@ -704,6 +733,7 @@ func (q *QobuzDownloader) DownloadTrackWithISRC(isrc, outputDir, quality, filena
 	if err := q.DownloadFile(downloadURL, filepath); err != nil {
 		return "", fmt.Errorf("failed to download file: %w", err)
 	}
+	RecordDeliveredISRC(filepath, track.ISRC)
 
 	fmt.Printf("Downloaded: %s\n", filepath)
 
@@ -718,6 +748,7 @@ func (q *QobuzDownloader) DownloadTrackWithISRC(isrc, outputDir, quality, filena
 		} else {
 			defer os.Remove(coverPath)
 			fmt.Println("Spotify cover downloaded")
+			coverPath = PrepareCoverForEmbedding(coverPath)
 		}
 	}
 
@@ -741,28 +772,42 @@ func (q *QobuzDownloader) DownloadTrackWithISRC(isrc, outputDir, quality, filena
 		upc = strings.TrimSpace(identifiers.UPC)
 	}
 
-	metadata := Metadata{
-		Title:       trackTitle,
-		Artist:      artists,
-		Album:       albumTitle,
-		AlbumArtist: spotifyAlbumArtist,
-		Date:        spotifyReleaseDate,
-		TrackNumber: trackNumberToEmbed,
-		TotalTracks: spotifyTotalTracks,
-		DiscNumber:  spotifyDiscNumber,
-		TotalDiscs:  spotifyTotalDiscs,
-		URL:         spotifyURL,
-		Comment:     spotifyURL,
-		Copyright:   spotifyCopyright,
-		Publisher:   spotifyPublisher,
-		Composer:    spotifyComposer,
-		Separator:   metadataSeparator,
-		Description: "https://github.com/spotbye/SpotiFLAC",
-		ISRC:        isrc,
-		UPC:         upc,
-		Genre:       mbMeta.Genre,
+	releaseDate := spotifyReleaseDate
+	if releaseDate == "" {
+		releaseDate = track.ReleaseDateOriginal
 	}
 
+	metadata := Metadata{
+		Title:        trackTitle,
+		Artist:       artists,
+		Album:        albumTitle,
+		AlbumArtist:  spotifyAlbumArtist,
+		Date:         releaseDate,
+		OriginalDate: track.ReleaseDateOriginal,
+		TrackNumber:  trackNumberToEmbed,
+		TotalTracks:  spotifyTotalTracks,
+		DiscNumber:   spotifyDiscNumber,
+		TotalDiscs:   spotifyTotalDiscs,
+		URL:          spotifyURL,
+		Comment:      spotifyURL,
+		Copyright:    spotifyCopyright,
+		Publisher:    spotifyPublisher,
+		Composer:     spotifyComposer,
+		Separator:    metadataSeparator,
+		Description:  "https://github.com/spotbye/SpotiFLAC",
+		ISRC:         isrc,
+		UPC:          upc,
+		Genre:        mbMeta.Genre,
+	}
+	fillMissingMetadata(&metadata, Metadata{
+		AlbumArtist: track.Album.Artist.Name,
+		TrackNumber: track.TrackNumber,
+		DiscNumber:  track.MediaNumber,
+		Copyright:   track.Copyright,
+		Publisher:   track.Album.Label.Name,
+		Composer:    track.Composer.Name,
+	})
+
 	if err := EmbedMetadata(filepath, metadata, coverPath); err != nil {
 		return "", fmt.Errorf("failed to embed metadata: %w", err)
 	}
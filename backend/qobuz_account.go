@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type qobuzFileURLResponse struct {
+	URL          string  `json:"url"`
+	FormatID     int     `json:"format_id"`
+	MimeType     string  `json:"mime_type"`
+	BitDepth     int     `json:"bit_depth"`
+	SamplingRate float64 `json:"sampling_rate"`
+	Sample       bool    `json:"sample"`
+	Restrictions []struct {
+		Code string `json:"code"`
+	} `json:"restrictions"`
+}
+
+type qobuzLoginResponse struct {
+	UserAuthToken string `json:"user_auth_token"`
+	User          struct {
+		ID          int64  `json:"id"`
+		Login       string `json:"login"`
+		CountryCode string `json:"country_code"`
+		Credential  struct {
+			Label string `json:"label"`
+		} `json:"credential"`
+	} `json:"user"`
+}
+
+type QobuzAccountStatus struct {
+	LoggedIn bool   `json:"logged_in"`
+	UserID   string `json:"user_id,omitempty"`
+	Plan     string `json:"plan,omitempty"`
+}
+
+func HasQobuzAccount() bool {
+	_, ok := GetServiceCredential("qobuz")
+	return ok
+}
+
+func QobuzLogin(email, password string) (QobuzAccountStatus, error) {
+	email = strings.TrimSpace(email)
+	if email == "" || password == "" {
+		return QobuzAccountStatus{}, fmt.Errorf("email and password are required")
+	}
+
+	creds, err := getQobuzAPICredentials(false)
+	if err != nil {
+		return QobuzAccountStatus{}, err
+	}
+
+	passwordHash := md5.Sum([]byte(password))
+	params := url.Values{}
+	params.Set("username", email)
+	params.Set("password", hex.EncodeToString(passwordHash[:]))
+	params.Set("app_id", creds.AppID)
+
+	req, err := http.NewRequest(http.MethodPost, qobuzAPIBaseURL+"/user/login", strings.NewReader(params.Encode()))
+	if err != nil {
+		return QobuzAccountStatus{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", qobuzDefaultUA)
+	req.Header.Set("X-App-Id", creds.AppID)
+
+	resp, err := NewServiceHTTPClient("qobuz", 20*time.Second).Do(req)
+	if err != nil {
+		return QobuzAccountStatus{}, fmt.Errorf("qobuz login failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return QobuzAccountStatus{}, fmt.Errorf("qobuz login failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	var login qobuzLoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&login); err != nil {
+		return QobuzAccountStatus{}, fmt.Errorf("failed to parse qobuz login response: %w", err)
+	}
+	if login.UserAuthToken == "" {
+		return QobuzAccountStatus{}, fmt.Errorf("qobuz login did not return a user token")
+	}
+
+	userID := strconv.FormatInt(login.User.ID, 10)
+	if err := SetServiceCredential("qobuz", ServiceCredential{
+		Token:       login.UserAuthToken,
+		UserID:      userID,
+		CountryCode: login.User.CountryCode,
+	}); err != nil {
+		return QobuzAccountStatus{}, err
+	}
+
+	fmt.Printf("[Qobuz] Logged in as %s (%s)\n", login.User.Login, login.User.Credential.Label)
+	return QobuzAccountStatus{LoggedIn: true, UserID: userID, Plan: login.User.Credential.Label}, nil
+}
+
+func (q *QobuzDownloader) DownloadFromAccount(trackID int64, quality string) (string, error) {
+	params := url.Values{}
+	params.Set("track_id", strconv.FormatInt(trackID, 10))
+	params.Set("format_id", quality)
+	params.Set("intent", "stream")
+
+	resp, err := doQobuzSignedRequest(http.MethodGet, "track/getFileUrl", params, q.client)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return "", fmt.Errorf("qobuz account token was rejected, log in again")
+	default:
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return "", fmt.Errorf("qobuz getFileUrl failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	var fileURL qobuzFileURLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&fileURL); err != nil {
+		return "", fmt.Errorf("failed to parse qobuz getFileUrl response: %w", err)
+	}
+
+	if fileURL.Sample || fileURL.URL == "" {
+		codes := make([]string, 0, len(fileURL.Restrictions))
+		for _, restriction := range fileURL.Restrictions {
+			codes = append(codes, restriction.Code)
+		}
+		return "", fmt.Errorf("qobuz account cannot stream this track in full (%s)", strings.Join(codes, ", "))
+	}
+
+	if strconv.Itoa(fileURL.FormatID) != quality {
+		fmt.Printf("[Qobuz] Account allows format %d (%d-bit/%.1fkHz) instead of %s\n", fileURL.FormatID, fileURL.BitDepth, fileURL.SamplingRate, quality)
+	}
+	return fileURL.URL, nil
+}
@@ -264,6 +264,9 @@ func newQobuzSignedRequestWithCredentials(method string, path string, params url
 	req.Header.Set("User-Agent", qobuzDefaultUA)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-App-Id", creds.AppID)
+	if userCred, ok := GetServiceCredential("qobuz"); ok {
+		req.Header.Set("X-User-Auth-Token", userCred.Token)
+	}
 
 	return req, nil
 }
@@ -316,7 +319,7 @@ func getQobuzAPICredentials(forceRefresh bool) (*qobuzAPICredentials, error) {
 		return qobuzCachedCredentials, nil
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := NewServiceHTTPClient("qobuz", 30*time.Second)
 	scrapedCreds, scrapeErr := scrapeQobuzOpenCredentials(client)
 	if scrapeErr == nil {
 		if qobuzCredentialsSupportSignedMetadata(client, scrapedCreds) {
@@ -363,7 +366,7 @@ func newQobuzSignedRequest(method string, path string, params url.Values) (*http
 
 func doQobuzSignedRequest(method string, path string, params url.Values, client *http.Client) (*http.Response, error) {
 	if client == nil {
-		client = &http.Client{Timeout: 20 * time.Second}
+		client = NewServiceHTTPClient("qobuz", 20*time.Second)
 	}
 
 	call := func(forceRefresh bool) (*http.Response, error) {
@@ -392,7 +395,7 @@ func doQobuzSignedRequest(method string, path string, params url.Values, client
 }
 
 func doQobuzSignedJSONRequest(path string, params url.Values, target interface{}) error {
-	resp, err := doQobuzSignedRequest(http.MethodGet, path, params, &http.Client{Timeout: 20 * time.Second})
+	resp, err := doQobuzSignedRequest(http.MethodGet, path, params, NewServiceHTTPClient("qobuz", 20*time.Second))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,130 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	PriorityLow    = -1
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
+func ParseDownloadPriority(value string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "low":
+		return PriorityLow, nil
+	case "", "normal":
+		return PriorityNormal, nil
+	case "high":
+		return PriorityHigh, nil
+	}
+	return PriorityNormal, fmt.Errorf("unknown priority %q (use low, normal or high)", value)
+}
+
+func queuedSlotsLocked() ([]int, []DownloadItem) {
+	var slots []int
+	var items []DownloadItem
+	for i, item := range downloadQueue {
+		if item.Status == StatusQueued {
+			slots = append(slots, i)
+			items = append(items, item)
+		}
+	}
+	return slots, items
+}
+
+func writeQueuedSlotsLocked(slots []int, items []DownloadItem) {
+	for i, slot := range slots {
+		downloadQueue[slot] = items[i]
+	}
+}
+
+func sortQueuedByPriorityLocked() {
+	slots, items := queuedSlotsLocked()
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Priority > items[j].Priority
+	})
+	writeQueuedSlotsLocked(slots, items)
+}
+
+func moveQueuedToFrontLocked(match func(DownloadItem) bool) int {
+	slots, items := queuedSlotsLocked()
+	front := make([]DownloadItem, 0, len(items))
+	rest := make([]DownloadItem, 0, len(items))
+	for _, item := range items {
+		if match(item) {
+			item.Priority = PriorityHigh
+			front = append(front, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	if len(front) == 0 {
+		return 0
+	}
+	writeQueuedSlotsLocked(slots, append(front, rest...))
+	return len(front)
+}
+
+func SetDownloadItemPriority(id string, priority int) error {
+	priority = max(PriorityLow, min(priority, PriorityHigh))
+
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID != id {
+			continue
+		}
+		if downloadQueue[i].Status != StatusQueued {
+			return fmt.Errorf("download is %s, only queued items can be reprioritized", downloadQueue[i].Status)
+		}
+		downloadQueue[i].Priority = priority
+		sortQueuedByPriorityLocked()
+		return nil
+	}
+	return fmt.Errorf("download not found")
+}
+
+func MoveDownloadItemToFront(id string) error {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+
+	if moveQueuedToFrontLocked(func(item DownloadItem) bool { return item.ID == id }) == 0 {
+		return fmt.Errorf("no queued download with id %s", id)
+	}
+	return nil
+}
+
+func MoveAlbumToFront(albumName string) (int, error) {
+	albumName = strings.TrimSpace(albumName)
+	if albumName == "" {
+		return 0, fmt.Errorf("album name is required")
+	}
+
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+
+	moved := moveQueuedToFrontLocked(func(item DownloadItem) bool {
+		return strings.EqualFold(strings.TrimSpace(item.AlbumName), albumName)
+	})
+	if moved == 0 {
+		return 0, fmt.Errorf("no queued downloads for album %s", albumName)
+	}
+	return moved, nil
+}
+
+func NextQueuedItemID(pending func(id string) bool) (string, bool) {
+	downloadQueueLock.RLock()
+	defer downloadQueueLock.RUnlock()
+
+	for _, item := range downloadQueue {
+		if item.Status == StatusQueued && pending(item.ID) {
+			return item.ID, true
+		}
+	}
+	return "", false
+}
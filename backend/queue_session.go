@@ -0,0 +1,229 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const queueSessionFile = "queue-session.json"
+
+const (
+	PauseReasonUser     = "user"
+	PauseReasonSchedule = "schedule"
+	PauseReasonBudget   = "budget"
+)
+
+var (
+	pauseMu       sync.Mutex
+	pauseHolds    = make(map[string]bool)
+	pauseResumeCh = make(chan struct{})
+)
+
+type QueueSession struct {
+	SavedAt int64          `json:"saved_at"`
+	Items   []DownloadItem `json:"items"`
+}
+
+func setPauseHold(reason string, hold bool) bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	if pauseHolds[reason] == hold {
+		return false
+	}
+
+	wasPaused := len(pauseHolds) > 0
+	if hold {
+		pauseHolds[reason] = true
+	} else {
+		delete(pauseHolds, reason)
+	}
+
+	switch isPaused := len(pauseHolds) > 0; {
+	case isPaused && !wasPaused:
+		pauseResumeCh = make(chan struct{})
+	case !isPaused && wasPaused:
+		close(pauseResumeCh)
+	}
+	return true
+}
+
+func PauseDownloads() bool {
+	if !setPauseHold(PauseReasonUser, true) {
+		return false
+	}
+	fmt.Println("[Queue] Paused, in-flight downloads will finish first")
+	return true
+}
+
+func ResumeDownloads() bool {
+	if !setPauseHold(PauseReasonUser, false) {
+		return false
+	}
+	fmt.Println("[Queue] Resumed")
+	return true
+}
+
+func IsDownloadsPaused() bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	return len(pauseHolds) > 0
+}
+
+func IsPausedFor(reason string) bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	return pauseHolds[reason]
+}
+
+func GetPauseReasons() []string {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	reasons := make([]string, 0, len(pauseHolds))
+	for reason := range pauseHolds {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+func WaitWhilePaused(ctx context.Context) error {
+	for {
+		pauseMu.Lock()
+		paused := len(pauseHolds) > 0
+		resumeCh := pauseResumeCh
+		pauseMu.Unlock()
+		if !paused {
+			return nil
+		}
+
+		select {
+		case <-resumeCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func queueSessionPath() (string, error) {
+	dir, err := EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, queueSessionFile), nil
+}
+
+func SaveQueueSession() (int, error) {
+	downloadQueueLock.RLock()
+	var items []DownloadItem
+	for _, item := range downloadQueue {
+		if item.Status != StatusQueued && item.Status != StatusDownloading {
+			continue
+		}
+		item.Status = StatusQueued
+		item.Progress = 0
+		item.Speed = 0
+		item.StartTime = 0
+		items = append(items, item)
+	}
+	downloadQueueLock.RUnlock()
+
+	if len(items) == 0 {
+		return 0, DiscardQueueSession()
+	}
+
+	sessionPath, err := queueSessionPath()
+	if err != nil {
+		return 0, err
+	}
+	data, err := json.MarshalIndent(QueueSession{SavedAt: time.Now().Unix(), Items: items}, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	tmpPath := PartialDownloadPath(sessionPath)
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, sessionPath); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	fmt.Printf("[Queue] Saved %d unfinished download(s) for the next session\n", len(items))
+	return len(items), nil
+}
+
+func LoadQueueSession() (*QueueSession, error) {
+	sessionPath, err := queueSessionPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(sessionPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session QueueSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse saved queue session: %w", err)
+	}
+	if len(session.Items) == 0 {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+func RestoreQueueSession(session *QueueSession) []DownloadItem {
+	if session == nil {
+		return nil
+	}
+
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+
+	existing := make(map[string]bool, len(downloadQueue))
+	for _, item := range downloadQueue {
+		existing[item.ID] = true
+	}
+
+	var restored []DownloadItem
+	for _, item := range session.Items {
+		if item.SpotifyID == "" || existing[item.ID] {
+			continue
+		}
+		item.Status = StatusQueued
+		item.ErrorMessage = ""
+		downloadQueue = append(downloadQueue, item)
+		restored = append(restored, item)
+	}
+	if len(restored) == 0 {
+		return nil
+	}
+	sortQueuedByPriorityLocked()
+
+	sessionStartLock.Lock()
+	if sessionStartTime == 0 {
+		sessionStartTime = time.Now().Unix()
+	}
+	sessionStartLock.Unlock()
+	return restored
+}
+
+func DiscardQueueSession() error {
+	sessionPath, err := queueSessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(sessionPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
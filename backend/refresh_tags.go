@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var embeddedSpotifyTrackPattern = regexp.MustCompile(`open\.spotify\.com/(?:intl-[a-z]+/)?track/([A-Za-z0-9]{22})`)
+
+func EmbeddedSpotifyTrackID(metadata Metadata) string {
+	for _, value := range []string{metadata.URL, metadata.Comment, metadata.Description} {
+		if match := embeddedSpotifyTrackPattern.FindStringSubmatch(value); len(match) == 2 {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+func FetchQobuzTrackMetadata(isrc string) (Metadata, string, error) {
+	isrc = strings.ToUpper(strings.TrimSpace(isrc))
+	if isrc == "" {
+		return Metadata{}, "", fmt.Errorf("ISRC is required")
+	}
+
+	track, err := NewQobuzDownloader().searchByISRC(isrc)
+	if err != nil {
+		return Metadata{}, "", err
+	}
+
+	title := track.Title
+	if version := strings.TrimSpace(track.Version); version != "" && !strings.Contains(strings.ToLower(title), strings.ToLower(version)) {
+		title = fmt.Sprintf("%s (%s)", title, version)
+	}
+
+	metadata := Metadata{
+		Title:        title,
+		Artist:       track.Performer.Name,
+		Album:        track.Album.Title,
+		AlbumArtist:  track.Album.Artist.Name,
+		Date:         track.ReleaseDateOriginal,
+		OriginalDate: track.ReleaseDateOriginal,
+		TrackNumber:  track.TrackNumber,
+		DiscNumber:   track.MediaNumber,
+		Copyright:    track.Copyright,
+		Publisher:    track.Album.Label.Name,
+		ISRC:         isrc,
+	}
+
+	return metadata, track.Album.Image.Large, nil
+}
+
+func RefreshFileTags(filePath string, current Metadata, fresh Metadata, coverPath string) error {
+	if fresh.Lyrics == "" {
+		if lyrics, err := ExtractLyrics(filePath); err == nil {
+			fresh.Lyrics = lyrics
+		}
+	}
+
+	if fresh.Genre == "" {
+		fresh.Genre = current.Genre
+	}
+	if fresh.Composer == "" {
+		fresh.Composer = current.Composer
+	}
+	if fresh.Copyright == "" {
+		fresh.Copyright = current.Copyright
+	}
+	if fresh.Publisher == "" {
+		fresh.Publisher = current.Publisher
+	}
+	if fresh.UPC == "" {
+		fresh.UPC = current.UPC
+	}
+	if fresh.ISRC == "" {
+		fresh.ISRC = current.ISRC
+	}
+	if fresh.URL == "" {
+		fresh.URL = current.URL
+	}
+	if fresh.Comment == "" {
+		fresh.Comment = current.Comment
+	}
+	if fresh.Description == "" {
+		fresh.Description = current.Description
+	}
+
+	if coverPath == "" {
+		if existingCover, err := ExtractCoverArt(filePath); err == nil && existingCover != "" {
+			defer os.Remove(existingCover)
+			coverPath = existingCover
+		}
+	}
+
+	if err := EmbedMetadataToConvertedFile(filePath, fresh, coverPath); err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+const defaultCountryCode = "US"
+
+var (
+	countryCodePattern   = regexp.MustCompile(`^[A-Za-z]{2}$`)
+	localeCountryPattern = regexp.MustCompile(`^[A-Za-z]{2,3}[_-]([A-Za-z]{2})\b`)
+)
+
+func NormalizeCountryCode(code string) string {
+	code = strings.TrimSpace(code)
+	if !countryCodePattern.MatchString(code) {
+		return ""
+	}
+	return strings.ToUpper(code)
+}
+
+func detectLocaleCountryCode() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if match := localeCountryPattern.FindStringSubmatch(os.Getenv(key)); match != nil {
+			return strings.ToUpper(match[1])
+		}
+	}
+	return ""
+}
+
+func ResolveCountryCode() string {
+	if code := NormalizeCountryCode(GetCountryCodeSetting()); code != "" {
+		return code
+	}
+	if code := detectLocaleCountryCode(); code != "" {
+		return code
+	}
+	return defaultCountryCode
+}
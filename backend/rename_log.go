@@ -0,0 +1,234 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	renameLogDBFile = "rename_log.db"
+	renameLogBucket = "RenameLog"
+)
+
+type RenameLogEntry struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+}
+
+type RenameBatch struct {
+	ID        string           `json:"id"`
+	CreatedAt int64            `json:"created_at"`
+	Entries   []RenameLogEntry `json:"entries"`
+	Completed bool             `json:"completed"`
+	Undone    bool             `json:"undone"`
+}
+
+var (
+	renameLogDB   *bolt.DB
+	renameLogDBMu sync.Mutex
+)
+
+func InitRenameLogDB() error {
+	renameLogDBMu.Lock()
+	defer renameLogDBMu.Unlock()
+
+	if renameLogDB != nil {
+		return nil
+	}
+
+	appDir, err := EnsureAppDir()
+	if err != nil {
+		return err
+	}
+
+	dbPath := filepath.Join(appDir, renameLogDBFile)
+	db, err := bolt.Open(dbPath, 0o600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(renameLogBucket))
+		return err
+	}); err != nil {
+		db.Close()
+		return err
+	}
+
+	renameLogDB = db
+	return nil
+}
+
+func CloseRenameLogDB() {
+	renameLogDBMu.Lock()
+	defer renameLogDBMu.Unlock()
+
+	if renameLogDB != nil {
+		_ = renameLogDB.Close()
+		renameLogDB = nil
+	}
+}
+
+func getRenameLogDB() (*bolt.DB, error) {
+	if err := InitRenameLogDB(); err != nil {
+		return nil, err
+	}
+
+	renameLogDBMu.Lock()
+	defer renameLogDBMu.Unlock()
+	return renameLogDB, nil
+}
+
+func saveRenameBatch(batch RenameBatch) error {
+	db, err := getRenameLogDB()
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(renameLogBucket))
+		if err != nil {
+			return err
+		}
+
+		buf, err := json.Marshal(batch)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(batch.ID), buf)
+	})
+}
+
+func deleteRenameBatch(id string) error {
+	db, err := getRenameLogDB()
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(renameLogBucket))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+func lastRenameBatch() (*RenameBatch, error) {
+	db, err := getRenameLogDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var batch *RenameBatch
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(renameLogBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var decoded RenameBatch
+			if err := json.Unmarshal(v, &decoded); err != nil || decoded.Undone {
+				continue
+			}
+			batch = &decoded
+			return nil
+		}
+		return nil
+	})
+
+	return batch, err
+}
+
+func newRenameBatch(entries []RenameLogEntry) (RenameBatch, error) {
+	now := time.Now()
+	batch := RenameBatch{
+		ID:        fmt.Sprintf("%020d", now.UnixNano()),
+		CreatedAt: now.Unix(),
+		Entries:   entries,
+	}
+	return batch, saveRenameBatch(batch)
+}
+
+func finishRenameBatch(batch RenameBatch, results []RenameResult) {
+	done := make(map[string]bool, len(results))
+	for _, result := range results {
+		if result.Success && result.OldPath != result.NewPath {
+			done[result.OldPath] = true
+		}
+	}
+
+	entries := batch.Entries[:0]
+	for _, entry := range batch.Entries {
+		if done[entry.OldPath] {
+			entries = append(entries, entry)
+		}
+	}
+
+	var err error
+	if len(entries) == 0 {
+		err = deleteRenameBatch(batch.ID)
+	} else {
+		batch.Entries = entries
+		batch.Completed = true
+		err = saveRenameBatch(batch)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to update rename log: %v\n", err)
+	}
+}
+
+func UndoLastRename() ([]RenameResult, error) {
+	batch, err := lastRenameBatch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rename log: %w", err)
+	}
+	if batch == nil {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+
+	results := make([]RenameResult, 0, len(batch.Entries))
+	for i := len(batch.Entries) - 1; i >= 0; i-- {
+		entry := batch.Entries[i]
+		result := RenameResult{
+			OldPath: entry.NewPath,
+			NewPath: entry.OldPath,
+		}
+
+		switch {
+		case !fileExists(entry.NewPath):
+			if !batch.Completed && fileExists(entry.OldPath) {
+				continue
+			}
+			result.Error = "Renamed file no longer exists"
+		case fileExists(entry.OldPath):
+			result.Error = "Original path is taken by another file"
+		default:
+			if err := os.MkdirAll(filepath.Dir(entry.OldPath), 0755); err != nil {
+				result.Error = err.Error()
+			} else if err := os.Rename(entry.NewPath, entry.OldPath); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Success = true
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	batch.Undone = true
+	if err := saveRenameBatch(*batch); err != nil {
+		return results, fmt.Errorf("failed to update rename log: %w", err)
+	}
+
+	return results, nil
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/go-flac/go-flac"
 )
 
 type FlacInfo struct {
@@ -221,3 +223,87 @@ func ResampleAudio(req ResampleRequest) ([]ResampleResult, error) {
 	wg.Wait()
 	return results, nil
 }
+
+func downconvertTargetRate(sourceRate, maxRate int) int {
+	if maxRate <= 0 || sourceRate <= maxRate {
+		return sourceRate
+	}
+
+	family := 48000
+	if sourceRate%44100 == 0 {
+		family = 44100
+	}
+	for multiplier := maxRate / family; multiplier >= 1; multiplier-- {
+		if family*multiplier <= maxRate {
+			return family * multiplier
+		}
+	}
+	return maxRate
+}
+
+func DownconvertFLAC(filePath string, maxBitDepth, maxSampleRate int) (bool, error) {
+	f, err := flac.ParseFile(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse FLAC: %w", err)
+	}
+	streamInfo, err := f.GetStreamInfo()
+	if err != nil {
+		return false, fmt.Errorf("failed to read STREAMINFO: %w", err)
+	}
+
+	targetRate := downconvertTargetRate(streamInfo.SampleRate, maxSampleRate)
+	targetBits := streamInfo.BitDepth
+	if maxBitDepth > 0 && targetBits > maxBitDepth {
+		targetBits = maxBitDepth
+	}
+	if targetRate == streamInfo.SampleRate && targetBits == streamInfo.BitDepth {
+		return false, nil
+	}
+
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return false, fmt.Errorf("failed to get ffmpeg path: %w", err)
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return false, fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	filter := fmt.Sprintf("aresample=out_sample_rate=%d", targetRate)
+	if targetBits < streamInfo.BitDepth {
+		filter += ":dither_method=triangular_hp"
+	}
+
+	args := []string{
+		"-i", filePath,
+		"-y",
+		"-map", "0:a",
+		"-map", "0:v?",
+		"-map_metadata", "0",
+		"-c:v", "copy",
+		"-af", filter,
+		"-c:a", "flac",
+	}
+	if targetBits <= 16 {
+		args = append(args, "-sample_fmt", "s16")
+	} else {
+		args = append(args, "-sample_fmt", "s32", "-bits_per_raw_sample", strconv.Itoa(targetBits))
+	}
+
+	tmpPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".resample.tmp.flac"
+	defer os.Remove(tmpPath)
+	args = append(args, tmpPath)
+
+	fmt.Printf("[Resample] Downconverting %s: %d-bit/%dHz -> %d-bit/%dHz\n", filepath.Base(filePath), streamInfo.BitDepth, streamInfo.SampleRate, targetBits, targetRate)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("resampling failed: %s - %s", err.Error(), string(output))
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return false, fmt.Errorf("failed to replace original file: %w", err)
+	}
+	return true, nil
+}
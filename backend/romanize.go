@@ -0,0 +1,204 @@
+package backend
+
+import (
+	"strings"
+	"unicode"
+)
+
+var kanaRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "wi", "ゑ": "we", "を": "wo", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ゔ": "vu",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+}
+
+var (
+	hangulInitials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulMedials  = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulFinals   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+	hangulLiaison  = map[int]string{1: "g", 2: "kk", 4: "n", 7: "d", 8: "r", 16: "m", 17: "b", 19: "s", 20: "ss", 22: "j", 23: "ch", 24: "k", 25: "t", 26: "p"}
+)
+
+const (
+	hangulBase      = 0xAC00
+	hangulLast      = 0xD7A3
+	hangulSilentIdx = 11
+)
+
+func isKana(r rune) bool {
+	return unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
+
+func isHangulSyllable(r rune) bool {
+	return r >= hangulBase && r <= hangulLast
+}
+
+func katakanaToHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - 0x60
+	}
+	return r
+}
+
+func NeedsRomanization(text string) bool {
+	for _, r := range text {
+		if isKana(r) || isHangulSyllable(r) || isHan(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func RomanizeText(text string) string {
+	if !NeedsRomanization(text) {
+		return text
+	}
+	return romanizeHangul(romanizeKana(kanjiToKana(text)))
+}
+
+func romanizeKana(text string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+	geminate := false
+
+	for i := 0; i < len(runes); i++ {
+		r := katakanaToHiragana(runes[i])
+		if !isKana(runes[i]) {
+			sb.WriteRune(runes[i])
+			geminate = false
+			continue
+		}
+
+		switch r {
+		case 'っ':
+			geminate = true
+			continue
+		case 'ー':
+			out := sb.String()
+			if out != "" {
+				sb.WriteByte(out[len(out)-1])
+			}
+			continue
+		}
+
+		romaji := ""
+		if i+1 < len(runes) {
+			if pair, ok := kanaRomaji[string([]rune{r, katakanaToHiragana(runes[i+1])})]; ok {
+				romaji = pair
+				i++
+			}
+		}
+		if romaji == "" {
+			romaji = kanaRomaji[string(r)]
+		}
+		if romaji == "" {
+			sb.WriteRune(runes[i])
+			continue
+		}
+
+		if geminate {
+			if strings.HasPrefix(romaji, "ch") {
+				sb.WriteByte('t')
+			} else {
+				sb.WriteByte(romaji[0])
+			}
+			geminate = false
+		}
+		sb.WriteString(romaji)
+	}
+
+	return sb.String()
+}
+
+func romanizeHangul(text string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+
+	for i, r := range runes {
+		if !isHangulSyllable(r) {
+			sb.WriteRune(r)
+			continue
+		}
+
+		index := int(r - hangulBase)
+		initial := index / (21 * 28)
+		medial := (index % (21 * 28)) / 28
+		final := index % 28
+
+		sb.WriteString(hangulInitials[initial])
+		sb.WriteString(hangulMedials[medial])
+
+		if final == 0 {
+			continue
+		}
+		if i+1 < len(runes) && isHangulSyllable(runes[i+1]) && int(runes[i+1]-hangulBase)/(21*28) == hangulSilentIdx {
+			if liaison, ok := hangulLiaison[final]; ok {
+				sb.WriteString(liaison)
+				continue
+			}
+		}
+		sb.WriteString(hangulFinals[final])
+	}
+
+	return sb.String()
+}
+
+const (
+	LyricsRomanizationOff       = "off"
+	LyricsRomanizationAlongside = "alongside"
+	LyricsRomanizationReplace   = "replace"
+)
+
+func NormalizeLyricsRomanization(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case LyricsRomanizationAlongside:
+		return LyricsRomanizationAlongside
+	case LyricsRomanizationReplace:
+		return LyricsRomanizationReplace
+	default:
+		return LyricsRomanizationOff
+	}
+}
+
+func romanizeLyricsLine(line LyricsLine) LyricsLine {
+	romanized := line
+	romanized.Words = RomanizeText(line.Words)
+	if len(line.Syllables) > 0 {
+		romanized.Syllables = make([]LyricsSyllable, len(line.Syllables))
+		for i, syllable := range line.Syllables {
+			syllable.Text = RomanizeText(syllable.Text)
+			romanized.Syllables[i] = syllable
+		}
+	}
+	return romanized
+}
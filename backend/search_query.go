@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"strings"
+	"unicode"
+)
+
+func normalizeSearchText(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(value) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func BuildSearchQueries(trackName, artistName string) []string {
+	artist := GetFirstArtist(artistName)
+	candidates := []string{
+		trackName + " " + artist,
+		simplifyTrackName(trackName) + " " + artist,
+	}
+	if NeedsRomanization(trackName) || NeedsRomanization(artist) {
+		candidates = append(candidates,
+			RomanizeText(trackName)+" "+RomanizeText(artist),
+			RomanizeText(simplifyTrackName(trackName))+" "+artist,
+			simplifyTrackName(trackName)+" "+RomanizeText(artist),
+		)
+	}
+
+	queries := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		candidate = strings.Join(strings.Fields(candidate), " ")
+		key := strings.ToLower(candidate)
+		if candidate == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		queries = append(queries, candidate)
+	}
+	return queries
+}
+
+func searchTextMatches(want, got string) bool {
+	if want == "" || got == "" {
+		return false
+	}
+	return strings.Contains(got, want) || strings.Contains(want, got)
+}
+
+func SearchResultMatches(trackName, artistName, resultTitle, resultArtist string) bool {
+	title := normalizeSearchText(simplifyTrackName(trackName))
+	artist := normalizeSearchText(GetFirstArtist(artistName))
+	gotTitle := normalizeSearchText(resultTitle)
+	gotArtist := normalizeSearchText(resultArtist)
+
+	if searchTextMatches(title, gotTitle) && searchTextMatches(artist, gotArtist) {
+		return true
+	}
+
+	return searchTextMatches(normalizeSearchText(RomanizeText(title)), normalizeSearchText(RomanizeText(gotTitle))) &&
+		searchTextMatches(normalizeSearchText(RomanizeText(artist)), normalizeSearchText(RomanizeText(gotArtist)))
+}
+
+func TrackMatchKey(trackName, artistName string) string {
+	title := normalizeSearchText(simplifyTrackName(trackName))
+	artist := normalizeSearchText(GetFirstArtist(artistName))
+	if title == "" || artist == "" {
+		return ""
+	}
+	return title + "|" + artist
+}
@@ -0,0 +1,208 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	segmentedDownloadMinSize = 64 * 1024 * 1024
+	maxDownloadSegments      = 16
+)
+
+func GetDownloadSegmentsSetting() int {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return 0
+	}
+
+	segments, ok := settings["downloadSegments"].(float64)
+	if !ok || segments < 2 {
+		return 0
+	}
+	return min(int(segments), maxDownloadSegments)
+}
+
+func probeRangeSupport(ctx context.Context, client *http.Client, url string) (int64, bool) {
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, false
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, false
+	}
+	contentRange := resp.Header.Get("Content-Range")
+	idx := strings.LastIndex(contentRange, "/")
+	if idx < 0 {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(contentRange[idx+1:], 10, 64)
+	if err != nil || size <= 0 {
+		return 0, false
+	}
+	return size, true
+}
+
+type lockedProgress struct {
+	mu sync.Mutex
+	pw *ProgressWriter
+}
+
+func (p *lockedProgress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pw.Write(b)
+}
+
+func downloadSegmentRange(ctx context.Context, client *http.Client, url string, out *os.File, start, end int64, progress *lockedProgress) (int64, error) {
+	req, err := NewRequestWithDefaultHeaders(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("segment %d-%d returned status %d", start, end, resp.StatusCode)
+	}
+
+	writer := io.NewOffsetWriter(out, start)
+	buf := make([]byte, 256*1024)
+	want := end - start + 1
+	var got int64
+	for got < want {
+		n, readErr := resp.Body.Read(buf[:min(int64(len(buf)), want-got)])
+		if n > 0 {
+			if _, err := writer.Write(buf[:n]); err != nil {
+				return got, err
+			}
+			got += int64(n)
+			progress.Write(buf[:n])
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return got, readErr
+		}
+	}
+	if got != want {
+		return got, fmt.Errorf("segment %d-%d was truncated (%d of %d bytes)", start, end, got, want)
+	}
+	return got, nil
+}
+
+// downloadSegment fetches start-end, resuming from the last written byte when
+// the transfer is interrupted (e.g. by system sleep) instead of failing the
+// whole download. abort is cancelled once another segment has given up.
+func downloadSegment(abort context.Context, client *http.Client, url string, out *os.File, start, end int64, progress *lockedProgress) error {
+	offset := start
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithCancel(abort)
+		stop := context.AfterFunc(currentTransferContext(), cancel)
+		written, err := downloadSegmentRange(ctx, client, url, out, offset, end, progress)
+		stop()
+		cancel()
+
+		offset += written
+		if err == nil {
+			return nil
+		}
+		if abort.Err() != nil || attempt >= maxTransferResumes {
+			return err
+		}
+		fmt.Printf("\nSegment %d-%d interrupted (%v), resuming at byte %d\n", start, end, err, offset)
+	}
+}
+
+func downloadSegmented(client *http.Client, url, filePath string, size int64, segments int) (int64, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := out.Truncate(size); err != nil {
+		out.Close()
+		return 0, fmt.Errorf("failed to allocate file: %w", err)
+	}
+
+	fmt.Printf("Downloading %.2f MB in %d segments\n", float64(size)/(1024*1024), segments)
+	progress := &lockedProgress{pw: NewProgressWriterWithID(io.Discard, GetCurrentItemID())}
+	segmentSize := size / int64(segments)
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for i := 0; i < segments; i++ {
+		start := int64(i) * segmentSize
+		end := start + segmentSize - 1
+		if i == segments-1 {
+			end = size - 1
+		}
+
+		wg.Add(1)
+		go func(start, end int64) {
+			defer wg.Done()
+			if err := downloadSegment(ctx, client, url, out, start, end, progress); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	closeErr := out.Close()
+	if firstErr != nil {
+		return progress.pw.GetTotal(), firstErr
+	}
+	if closeErr != nil {
+		return progress.pw.GetTotal(), fmt.Errorf("failed to write file: %w", closeErr)
+	}
+	return size, nil
+}
+
+func tryDownloadSegmented(client *http.Client, url, filePath string) (int64, bool) {
+	segments := GetDownloadSegmentsSetting()
+	if segments < 2 {
+		return 0, false
+	}
+
+	size, ok := probeRangeSupport(currentTransferContext(), client, url)
+	if !ok || size < segmentedDownloadMinSize {
+		return 0, false
+	}
+
+	written, err := downloadSegmented(client, url, filePath, size, segments)
+	if err != nil {
+		fmt.Printf("\nWarning: segmented download failed (%v), falling back to a single stream\n", err)
+		os.Remove(filePath)
+		return 0, false
+	}
+	return written, true
+}
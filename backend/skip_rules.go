@@ -0,0 +1,135 @@
+package backend
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type SkipRules struct {
+	MinDurationSec int      `json:"min_duration_sec,omitempty"`
+	MaxDurationSec int      `json:"max_duration_sec,omitempty"`
+	TitleKeywords  []string `json:"title_keywords,omitempty"`
+	BlockedArtists []string `json:"blocked_artists,omitempty"`
+	LibraryIndex   string   `json:"library_index,omitempty"`
+}
+
+func (r SkipRules) Enabled() bool {
+	return r.MinDurationSec > 0 || r.MaxDurationSec > 0 || len(r.TitleKeywords) > 0 || len(r.BlockedArtists) > 0 || r.LibraryIndex != ""
+}
+
+func splitSkipList(raw string) []string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ';'
+	})
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			values = append(values, field)
+		}
+	}
+	return values
+}
+
+func GetSkipRulesSetting() SkipRules {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return SkipRules{}
+	}
+
+	var rules SkipRules
+	if value, ok := settings["skipMinDuration"].(float64); ok && value > 0 {
+		rules.MinDurationSec = int(value)
+	}
+	if value, ok := settings["skipMaxDuration"].(float64); ok && value > 0 {
+		rules.MaxDurationSec = int(value)
+	}
+	keywords, _ := settings["skipTitleKeywords"].(string)
+	rules.TitleKeywords = splitSkipList(keywords)
+	artists, _ := settings["skipArtists"].(string)
+	rules.BlockedArtists = splitSkipList(artists)
+	index, _ := settings["skipLibraryIndex"].(string)
+	rules.LibraryIndex = strings.TrimSpace(index)
+	return rules
+}
+
+func LoadLibraryISRCIndex(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	index := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		for _, isrc := range isrcPattern.FindAllString(strings.ToUpper(scanner.Text()), -1) {
+			index[isrc] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+type SkipMatcher struct {
+	rules SkipRules
+	owned map[string]bool
+}
+
+func NewSkipMatcher(rules SkipRules) *SkipMatcher {
+	matcher := &SkipMatcher{rules: rules}
+	if rules.LibraryIndex != "" {
+		owned, err := LoadLibraryISRCIndex(rules.LibraryIndex)
+		if err != nil {
+			fmt.Printf("Warning: failed to load library index %s: %v\n", rules.LibraryIndex, err)
+		} else {
+			matcher.owned = owned
+			fmt.Printf("[SkipRules] Loaded %d ISRC(s) from library index\n", len(owned))
+		}
+	}
+	return matcher
+}
+
+func (m *SkipMatcher) Match(track AlbumTrackMetadata) string {
+	if m == nil {
+		return ""
+	}
+
+	durationSec := (track.DurationMS + 500) / 1000
+	if m.rules.MinDurationSec > 0 && durationSec > 0 && durationSec < m.rules.MinDurationSec {
+		return fmt.Sprintf("shorter than %ds", m.rules.MinDurationSec)
+	}
+	if m.rules.MaxDurationSec > 0 && durationSec > m.rules.MaxDurationSec {
+		return fmt.Sprintf("longer than %ds", m.rules.MaxDurationSec)
+	}
+
+	title := strings.ToLower(track.Name)
+	for _, keyword := range m.rules.TitleKeywords {
+		if strings.Contains(title, keyword) {
+			return fmt.Sprintf("title contains %q", keyword)
+		}
+	}
+
+	if len(m.rules.BlockedArtists) > 0 {
+		artists := append(splitSkipList(track.Artists), strings.ToLower(strings.TrimSpace(track.Artists)))
+		for _, artist := range artists {
+			for _, blocked := range m.rules.BlockedArtists {
+				if artist == blocked {
+					return fmt.Sprintf("artist %s is blocked", artist)
+				}
+			}
+		}
+	}
+
+	if len(m.owned) > 0 && track.SpotifyID != "" {
+		if isrc := ResolveTrackISRC(track.SpotifyID); isrc != "" && m.owned[isrc] {
+			return fmt.Sprintf("ISRC %s is already in the library", isrc)
+		}
+	}
+
+	return ""
+}
@@ -24,9 +24,28 @@ type SongLinkClient struct {
 }
 
 type SongLinkURLs struct {
-	TidalURL  string `json:"tidal_url"`
-	AmazonURL string `json:"amazon_url"`
-	ISRC      string `json:"isrc"`
+	TidalURL  string             `json:"tidal_url"`
+	AmazonURL string             `json:"amazon_url"`
+	ISRC      string             `json:"isrc"`
+	Platforms *SongLinkPlatforms `json:"platforms,omitempty"`
+}
+
+type SongLinkPlatforms struct {
+	SpotifyID       string            `json:"spotify_id"`
+	PageURL         string            `json:"page_url,omitempty"`
+	SpotifyURL      string            `json:"spotify_url,omitempty"`
+	AppleMusicURL   string            `json:"apple_music_url,omitempty"`
+	ITunesURL       string            `json:"itunes_url,omitempty"`
+	YouTubeURL      string            `json:"youtube_url,omitempty"`
+	YouTubeMusicURL string            `json:"youtube_music_url,omitempty"`
+	AmazonMusicURL  string            `json:"amazon_music_url,omitempty"`
+	AmazonStoreURL  string            `json:"amazon_store_url,omitempty"`
+	TidalURL        string            `json:"tidal_url,omitempty"`
+	DeezerURL       string            `json:"deezer_url,omitempty"`
+	NapsterURL      string            `json:"napster_url,omitempty"`
+	PandoraURL      string            `json:"pandora_url,omitempty"`
+	SoundCloudURL   string            `json:"soundcloud_url,omitempty"`
+	All             map[string]string `json:"all"`
 }
 
 type TrackAvailability struct {
@@ -42,14 +61,17 @@ type TrackAvailability struct {
 }
 
 type songLinkAPIResponse struct {
+	PageURL         string `json:"pageUrl"`
 	LinksByPlatform map[string]struct {
 		URL string `json:"url"`
 	} `json:"linksByPlatform"`
 }
 
 type qobuzAvailabilityTrack struct {
-	ID    int64 `json:"id"`
-	Album struct {
+	ID                  int64   `json:"id"`
+	MaximumBitDepth     int     `json:"maximum_bit_depth"`
+	MaximumSamplingRate float64 `json:"maximum_sampling_rate"`
+	Album               struct {
 		ID          string `json:"id"`
 		Title       string `json:"title"`
 		URL         string `json:"url"`
@@ -59,9 +81,7 @@ type qobuzAvailabilityTrack struct {
 
 func NewSongLinkClient() *SongLinkClient {
 	return &SongLinkClient{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: NewServiceHTTPClient("songlink", 30*time.Second),
 	}
 }
 
@@ -88,6 +108,79 @@ func (s *SongLinkClient) GetAllURLsFromSpotify(spotifyTrackID string, region str
 	return urls, nil
 }
 
+func (s *SongLinkClient) GetPlatformLinksFromSpotify(spotifyTrackID string, region string) (*SongLinkPlatforms, error) {
+	if region == "" {
+		region = ResolveCountryCode()
+	}
+
+	resp, err := s.fetchSongLinkLinksByURL(fmt.Sprintf("https://open.spotify.com/track/%s", spotifyTrackID), region)
+	if err != nil {
+		links, resolveErr := s.resolveSpotifyTrackLinks(spotifyTrackID, region)
+		if links == nil || links.DeezerURL == "" {
+			if resolveErr != nil {
+				return nil, fmt.Errorf("%v | %v", err, resolveErr)
+			}
+			return nil, err
+		}
+
+		resp, err = s.fetchSongLinkLinksByURL(links.DeezerURL, region)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	platforms := newSongLinkPlatforms(spotifyTrackID, resp)
+	if len(platforms.All) == 0 {
+		return nil, fmt.Errorf("no platforms found")
+	}
+	return platforms, nil
+}
+
+func newSongLinkPlatforms(spotifyTrackID string, resp *songLinkAPIResponse) *SongLinkPlatforms {
+	platforms := &SongLinkPlatforms{
+		SpotifyID: spotifyTrackID,
+		PageURL:   resp.PageURL,
+		All:       make(map[string]string, len(resp.LinksByPlatform)),
+	}
+
+	for platform, link := range resp.LinksByPlatform {
+		linkURL := strings.TrimSpace(link.URL)
+		if linkURL == "" {
+			continue
+		}
+		platforms.All[platform] = linkURL
+
+		switch platform {
+		case "spotify":
+			platforms.SpotifyURL = linkURL
+		case "appleMusic":
+			platforms.AppleMusicURL = linkURL
+		case "itunes":
+			platforms.ITunesURL = linkURL
+		case "youtube":
+			platforms.YouTubeURL = linkURL
+		case "youtubeMusic":
+			platforms.YouTubeMusicURL = linkURL
+		case "amazonMusic":
+			platforms.AmazonMusicURL = normalizeAmazonMusicURL(linkURL)
+		case "amazonStore":
+			platforms.AmazonStoreURL = linkURL
+		case "tidal":
+			platforms.TidalURL = linkURL
+		case "deezer":
+			platforms.DeezerURL = normalizeDeezerTrackURL(linkURL)
+		case "napster":
+			platforms.NapsterURL = linkURL
+		case "pandora":
+			platforms.PandoraURL = linkURL
+		case "soundcloud":
+			platforms.SoundCloudURL = linkURL
+		}
+	}
+
+	return platforms
+}
+
 func (s *SongLinkClient) CheckTrackAvailability(spotifyTrackID string) (*TrackAvailability, error) {
 	links, err := s.resolveSpotifyTrackLinks(spotifyTrackID, "")
 
@@ -124,7 +217,11 @@ func (s *SongLinkClient) CheckTrackAvailability(spotifyTrackID string) (*TrackAv
 	}
 
 	if isrc != "" {
-		availability.Qobuz, availability.QobuzURL = checkQobuzAvailability(isrc)
+		var qobuzErr error
+		availability.Qobuz, availability.QobuzURL, qobuzErr = checkQobuzAvailability(isrc)
+		if qobuzErr != nil && err == nil {
+			err = qobuzErr
+		}
 	}
 
 	if availability.Tidal || availability.Amazon || availability.Deezer || availability.Qobuz {
@@ -190,7 +287,15 @@ func qobuzAlbumSlugURL(albumTitle string, albumID string) string {
 	return fmt.Sprintf("https://www.qobuz.com/album/%s/%s", slug, albumID)
 }
 
-func checkQobuzAvailability(isrc string) (bool, string) {
+func checkQobuzAvailability(isrc string) (bool, string, error) {
+	item, found, err := searchQobuzAvailability(isrc)
+	if !found {
+		return false, "", err
+	}
+	return true, qobuzAvailabilityURL(&item), nil
+}
+
+func searchQobuzAvailability(isrc string) (qobuzAvailabilityTrack, bool, error) {
 	var searchResp struct {
 		Tracks struct {
 			Total int                      `json:"total"`
@@ -202,14 +307,17 @@ func checkQobuzAvailability(isrc string) (bool, string) {
 		"query": {strings.TrimSpace(isrc)},
 		"limit": {"1"},
 	}, &searchResp); err != nil {
-		return false, ""
+		return qobuzAvailabilityTrack{}, false, fmt.Errorf("qobuz ISRC search failed: %w", err)
 	}
 
 	if searchResp.Tracks.Total == 0 || len(searchResp.Tracks.Items) == 0 {
-		return false, ""
+		return qobuzAvailabilityTrack{}, false, nil
 	}
 
-	item := searchResp.Tracks.Items[0]
+	return searchResp.Tracks.Items[0], true, nil
+}
+
+func qobuzAvailabilityURL(item *qobuzAvailabilityTrack) string {
 	qobuzURL := strings.TrimSpace(item.Album.URL)
 	if qobuzURL == "" {
 		qobuzURL = qobuzNormalizeRelativeURL(item.Album.RelativeURL)
@@ -221,7 +329,7 @@ func checkQobuzAvailability(isrc string) (bool, string) {
 		qobuzURL = fmt.Sprintf("https://www.qobuz.com/us-en/track/%d", item.ID)
 	}
 
-	return true, qobuzURL
+	return qobuzURL
 }
 
 func (s *SongLinkClient) GetDeezerURLFromSpotify(spotifyTrackID string) (string, error) {
@@ -270,7 +378,7 @@ func getDeezerISRC(deezerURL string) (string, error) {
 
 	apiURL := fmt.Sprintf("https://api.deezer.com/track/%s", trackID)
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := NewServiceHTTPClient("songlink", 10*time.Second)
 	resp, err := client.Get(apiURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to call Deezer API: %w", err)
@@ -31,7 +31,7 @@ type SpotifyClient struct {
 
 func NewSpotifyClient() *SpotifyClient {
 	return &SpotifyClient{
-		client:  &http.Client{Timeout: 30 * time.Second},
+		client:  NewServiceHTTPClient("spotify", 30*time.Second),
 		cookies: make(map[string]string),
 	}
 }
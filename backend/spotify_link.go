@@ -0,0 +1,109 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var spotifyShortLinkHosts = map[string]bool{
+	"spotify.link":     true,
+	"spoti.fi":         true,
+	"spotify.app.link": true,
+}
+
+var spotifyOpenURLPattern = regexp.MustCompile(`https?://open\.spotify\.com/[^"'\s<>\\]+`)
+
+var (
+	spotifyShortLinkMu    sync.Mutex
+	spotifyShortLinkCache = make(map[string]string)
+)
+
+func IsSpotifyShortLink(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	return spotifyShortLinkHosts[host] && len(cleanPathParts(parsed.Path)) > 0
+}
+
+func ExpandSpotifyShortLink(ctx context.Context, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	spotifyShortLinkMu.Lock()
+	cached, ok := spotifyShortLinkCache[raw]
+	spotifyShortLinkMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, raw, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", songLinkUserAgent)
+
+	resp, err := NewHTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand Spotify short link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	expanded := ""
+	if resp.Request != nil && resp.Request.URL != nil && NormalizeSpotifyURL(resp.Request.URL.String()) != "" {
+		expanded = resp.Request.URL.String()
+	} else {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return "", fmt.Errorf("failed to read Spotify short link page: %w", err)
+		}
+		for _, match := range spotifyOpenURLPattern.FindAllString(string(body), -1) {
+			candidate := html.UnescapeString(match)
+			if NormalizeSpotifyURL(candidate) != "" {
+				expanded = candidate
+				break
+			}
+		}
+	}
+
+	normalized := NormalizeSpotifyURL(expanded)
+	if normalized == "" {
+		return "", fmt.Errorf("short link %s does not point to a Spotify track, album, playlist or artist", raw)
+	}
+
+	spotifyShortLinkMu.Lock()
+	spotifyShortLinkCache[raw] = normalized
+	spotifyShortLinkMu.Unlock()
+
+	fmt.Printf("[SpotifyLink] Expanded %s -> %s\n", raw, normalized)
+	return normalized, nil
+}
+
+func ResolveSpotifyURL(ctx context.Context, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if IsExternalPlaylistURL(raw) {
+		return raw, nil
+	}
+	if IsSpotifyShortLink(raw) {
+		return ExpandSpotifyShortLink(ctx, raw)
+	}
+	if normalized := NormalizeSpotifyURL(raw); normalized != "" {
+		return normalized, nil
+	}
+	return "", errInvalidSpotifyURL
+}
@@ -27,7 +27,7 @@ type SpotifyMetadataClient struct {
 
 func NewSpotifyMetadataClient() *SpotifyMetadataClient {
 	return &SpotifyMetadataClient{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: NewServiceHTTPClient("spotify", 30*time.Second),
 		Separator:  ", ",
 	}
 }
@@ -370,6 +370,18 @@ func GetFilteredSpotifyData(ctx context.Context, spotifyURL string, batch bool,
 }
 
 func (c *SpotifyMetadataClient) GetFilteredData(ctx context.Context, spotifyURL string, batch bool, delay time.Duration, callback MetadataCallback) (interface{}, error) {
+	if IsExternalPlaylistURL(spotifyURL) {
+		return c.getExternalPlaylistData(ctx, spotifyURL, callback)
+	}
+
+	if IsSpotifyShortLink(spotifyURL) {
+		expanded, err := ExpandSpotifyShortLink(ctx, spotifyURL)
+		if err != nil {
+			return nil, err
+		}
+		spotifyURL = expanded
+	}
+
 	parsed, err := parseSpotifyURI(spotifyURL)
 	if err != nil {
 		return nil, err
@@ -393,6 +405,8 @@ func (c *SpotifyMetadataClient) getRawSpotifyData(ctx context.Context, parsed sp
 		return c.fetchTrack(ctx, parsed.ID)
 	case "artist_discography":
 		return c.fetchArtistDiscography(ctx, parsed, callback)
+	case "episode", "show":
+		return c.fetchPodcast(ctx, parsed)
 	case "artist":
 
 		discographyParsed := spotifyURI{Type: "artist_discography", ID: parsed.ID, DiscographyGroup: "all"}
@@ -412,6 +426,8 @@ func (c *SpotifyMetadataClient) processSpotifyData(ctx context.Context, raw inte
 		return c.formatTrackData(payload), nil
 	case *apiArtistResponse:
 		return c.formatArtistDiscographyData(ctx, payload, callback)
+	case *EpisodeResponse:
+		return *payload, nil
 	default:
 		return nil, errors.New("unknown raw payload type")
 	}
@@ -1421,14 +1437,18 @@ func parseSpotifyURI(input string) (spotifyURI, error) {
 		return spotifyURI{}, errInvalidSpotifyURL
 	}
 
-	if strings.HasPrefix(trimmed, "spotify:") {
+	if strings.HasPrefix(strings.ToLower(trimmed), "spotify:") {
 		parts := strings.Split(trimmed, ":")
+		if len(parts) == 5 && parts[1] == "user" && parts[3] == "playlist" {
+			parts = []string{parts[0], parts[3], parts[4]}
+		}
 		if len(parts) == 3 {
 			switch parts[1] {
-			case "album", "track", "playlist", "artist":
+			case "album", "track", "playlist", "artist", "episode", "show":
 				return spotifyURI{Type: parts[1], ID: parts[2]}, nil
 			}
 		}
+		return spotifyURI{}, errInvalidSpotifyURL
 	}
 
 	parsed, err := url.Parse(trimmed)
@@ -1460,7 +1480,7 @@ func parseSpotifyURI(input string) (spotifyURI, error) {
 
 	if len(parts) == 2 {
 		switch parts[0] {
-		case "album", "track", "playlist", "artist":
+		case "album", "track", "playlist", "artist", "episode", "show":
 			return spotifyURI{Type: parts[0], ID: parts[1]}, nil
 		}
 	}
@@ -1746,7 +1766,7 @@ func GetPreviewURL(trackID string) (string, error) {
 
 	embedURL := fmt.Sprintf("https://open.spotify.com/embed/track/%s", trackID)
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := NewServiceHTTPClient("spotify", 15*time.Second)
 	resp, err := client.Get(embedURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch embed page: %w", err)
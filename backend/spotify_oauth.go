@@ -0,0 +1,666 @@
+package backend
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	spotifyAuthorizeURL      = "https://accounts.spotify.com/authorize"
+	spotifyTokenURL          = "https://accounts.spotify.com/api/token"
+	spotifyWebAPIBaseURL     = "https://api.spotify.com/v1"
+	spotifyOAuthTokenFile    = "spotify_oauth.json"
+	spotifyCredentialService = "spotify"
+	spotifyOAuthCallbackPort = 43827
+	spotifyOAuthScopes       = "user-library-read playlist-read-private playlist-read-collaborative user-follow-read"
+)
+
+var ErrSpotifyNotLoggedIn = errors.New("not logged in to Spotify")
+
+type SpotifyOAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    int64  `json:"expires_at"`
+	Scope        string `json:"scope"`
+	ClientID     string `json:"client_id"`
+	DisplayName  string `json:"display_name,omitempty"`
+	UserID       string `json:"user_id,omitempty"`
+}
+
+type SpotifyAuthStatus struct {
+	LoggedIn    bool   `json:"logged_in"`
+	DisplayName string `json:"display_name,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+}
+
+type SpotifyUserPlaylist struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Owner       string `json:"owner"`
+	Images      string `json:"images"`
+	TotalTracks int    `json:"total_tracks"`
+	Public      bool   `json:"public"`
+	ExternalURL string `json:"external_urls"`
+}
+
+type spotifyWebAPIImage struct {
+	URL string `json:"url"`
+}
+
+type spotifyWebAPIArtist struct {
+	ID           string               `json:"id"`
+	Name         string               `json:"name"`
+	Genres       []string             `json:"genres"`
+	Followers    struct{ Total int }  `json:"followers"`
+	Images       []spotifyWebAPIImage `json:"images"`
+	ExternalURLs map[string]string    `json:"external_urls"`
+}
+
+type spotifyWebAPITrack struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	DurationMS  int                   `json:"duration_ms"`
+	TrackNumber int                   `json:"track_number"`
+	DiscNumber  int                   `json:"disc_number"`
+	Explicit    bool                  `json:"explicit"`
+	IsLocal     bool                  `json:"is_local"`
+	Type        string                `json:"type"`
+	Artists     []spotifyWebAPIArtist `json:"artists"`
+	ExternalIDs map[string]string     `json:"external_ids"`
+	Album       struct {
+		ID          string                `json:"id"`
+		Name        string                `json:"name"`
+		ReleaseDate string                `json:"release_date"`
+		TotalTracks int                   `json:"total_tracks"`
+		Images      []spotifyWebAPIImage  `json:"images"`
+		Artists     []spotifyWebAPIArtist `json:"artists"`
+	} `json:"album"`
+}
+
+var (
+	spotifyOAuthMu    sync.Mutex
+	spotifyOAuthToken *SpotifyOAuthToken
+)
+
+func getLegacySpotifyOAuthTokenPath() (string, error) {
+	dir, err := EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, spotifyOAuthTokenFile), nil
+}
+
+func GetSpotifyClientIDSetting() string {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+
+	clientID, _ := settings["spotifyClientId"].(string)
+	return strings.TrimSpace(clientID)
+}
+
+func loadSpotifyOAuthToken() (*SpotifyOAuthToken, error) {
+	if spotifyOAuthToken != nil {
+		return spotifyOAuthToken, nil
+	}
+
+	if err := migrateLegacySpotifyOAuthToken(); err != nil {
+		fmt.Printf("Warning: failed to migrate Spotify login: %v\n", err)
+	}
+
+	cred, ok := GetServiceCredential(spotifyCredentialService)
+	if !ok || cred.RefreshToken == "" {
+		return nil, ErrSpotifyNotLoggedIn
+	}
+
+	spotifyOAuthToken = &SpotifyOAuthToken{
+		AccessToken:  cred.Token,
+		RefreshToken: cred.RefreshToken,
+		ExpiresAt:    cred.ExpiresAt,
+		Scope:        cred.Scope,
+		ClientID:     cred.ClientID,
+		DisplayName:  cred.DisplayName,
+		UserID:       cred.UserID,
+	}
+	return spotifyOAuthToken, nil
+}
+
+func saveSpotifyOAuthToken(token *SpotifyOAuthToken) error {
+	if err := storeServiceCredential(spotifyCredentialService, ServiceCredential{
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.ExpiresAt,
+		Scope:        token.Scope,
+		ClientID:     token.ClientID,
+		DisplayName:  token.DisplayName,
+		UserID:       token.UserID,
+	}); err != nil {
+		return err
+	}
+
+	spotifyOAuthToken = token
+	return nil
+}
+
+func migrateLegacySpotifyOAuthToken() error {
+	tokenPath, err := getLegacySpotifyOAuthTokenPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(tokenPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var token SpotifyOAuthToken
+	if err := json.Unmarshal(data, &token); err == nil && token.RefreshToken != "" && token.AccessToken != "" {
+		if err := saveSpotifyOAuthToken(&token); err != nil {
+			return err
+		}
+	}
+	return os.Remove(tokenPath)
+}
+
+func SpotifyLogout() error {
+	spotifyOAuthMu.Lock()
+	defer spotifyOAuthMu.Unlock()
+
+	spotifyOAuthToken = nil
+
+	if tokenPath, err := getLegacySpotifyOAuthTokenPath(); err == nil {
+		if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return DeleteServiceCredential(spotifyCredentialService)
+}
+
+func GetSpotifyAuthStatus() SpotifyAuthStatus {
+	spotifyOAuthMu.Lock()
+	defer spotifyOAuthMu.Unlock()
+
+	token, err := loadSpotifyOAuthToken()
+	if err != nil {
+		return SpotifyAuthStatus{}
+	}
+
+	return SpotifyAuthStatus{
+		LoggedIn:    true,
+		DisplayName: token.DisplayName,
+		UserID:      token.UserID,
+		ExpiresAt:   token.ExpiresAt,
+	}
+}
+
+func generatePKCEPair() (string, string, error) {
+	buf := make([]byte, 64)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	verifier := base64.RawURLEncoding.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	return verifier, challenge, nil
+}
+
+func SpotifyLogin(ctx context.Context, openURL func(string)) (SpotifyAuthStatus, error) {
+	clientID := GetSpotifyClientIDSetting()
+	if clientID == "" {
+		return SpotifyAuthStatus{}, fmt.Errorf("spotify client ID is not configured (set spotifyClientId in settings)")
+	}
+
+	verifier, challenge, err := generatePKCEPair()
+	if err != nil {
+		return SpotifyAuthStatus{}, fmt.Errorf("failed to generate PKCE challenge: %w", err)
+	}
+
+	stateBuf := make([]byte, 16)
+	if _, err := rand.Read(stateBuf); err != nil {
+		return SpotifyAuthStatus{}, err
+	}
+	state := base64.RawURLEncoding.EncodeToString(stateBuf)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", spotifyOAuthCallbackPort))
+	if err != nil {
+		return SpotifyAuthStatus{}, fmt.Errorf("failed to start OAuth callback listener: %w", err)
+	}
+
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", spotifyOAuthCallbackPort)
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			errCh <- fmt.Errorf("spotify login failed: state mismatch")
+			return
+		}
+		if authErr := query.Get("error"); authErr != "" {
+			fmt.Fprintf(w, "Spotify login failed: %s. You can close this window.", authErr)
+			errCh <- fmt.Errorf("spotify login failed: %s", authErr)
+			return
+		}
+
+		fmt.Fprint(w, "SpotiFLAC is now connected to Spotify. You can close this window.")
+		codeCh <- query.Get("code")
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	params := url.Values{}
+	params.Set("client_id", clientID)
+	params.Set("response_type", "code")
+	params.Set("redirect_uri", redirectURI)
+	params.Set("code_challenge_method", "S256")
+	params.Set("code_challenge", challenge)
+	params.Set("scope", spotifyOAuthScopes)
+	params.Set("state", state)
+	authURL := spotifyAuthorizeURL + "?" + params.Encode()
+
+	fmt.Printf("[SpotifyAuth] Opening browser for login: %s\n", authURL)
+	if openURL != nil {
+		openURL(authURL)
+	}
+
+	var code string
+	select {
+	case code = <-codeCh:
+	case err := <-errCh:
+		return SpotifyAuthStatus{}, err
+	case <-ctx.Done():
+		return SpotifyAuthStatus{}, fmt.Errorf("spotify login timed out")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", clientID)
+	form.Set("code_verifier", verifier)
+
+	token, err := requestSpotifyOAuthToken(form, clientID, "")
+	if err != nil {
+		return SpotifyAuthStatus{}, err
+	}
+
+	spotifyOAuthMu.Lock()
+	defer spotifyOAuthMu.Unlock()
+
+	var profile struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := spotifyWebAPIGetWithToken(token.AccessToken, spotifyWebAPIBaseURL+"/me", &profile); err == nil {
+		token.UserID = profile.ID
+		token.DisplayName = profile.DisplayName
+	}
+
+	if err := saveSpotifyOAuthToken(token); err != nil {
+		return SpotifyAuthStatus{}, fmt.Errorf("failed to save Spotify token: %w", err)
+	}
+
+	fmt.Printf("[SpotifyAuth] Logged in as %s\n", token.DisplayName)
+	return SpotifyAuthStatus{
+		LoggedIn:    true,
+		DisplayName: token.DisplayName,
+		UserID:      token.UserID,
+		ExpiresAt:   token.ExpiresAt,
+	}, nil
+}
+
+func requestSpotifyOAuthToken(form url.Values, clientID string, previousRefreshToken string) (*SpotifyOAuthToken, error) {
+	req, err := http.NewRequest(http.MethodPost, spotifyTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := NewHTTPClient(15 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request Spotify token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("spotify token request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+		Scope        string `json:"scope"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse Spotify token response: %w", err)
+	}
+
+	refreshToken := payload.RefreshToken
+	if refreshToken == "" {
+		refreshToken = previousRefreshToken
+	}
+
+	return &SpotifyOAuthToken{
+		AccessToken:  payload.AccessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second).Unix(),
+		Scope:        payload.Scope,
+		ClientID:     clientID,
+	}, nil
+}
+
+func getSpotifyAccessToken() (string, error) {
+	spotifyOAuthMu.Lock()
+	defer spotifyOAuthMu.Unlock()
+
+	token, err := loadSpotifyOAuthToken()
+	if err != nil {
+		return "", err
+	}
+
+	if token.AccessToken != "" && time.Now().Unix() < token.ExpiresAt-60 {
+		return token.AccessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", token.RefreshToken)
+	form.Set("client_id", token.ClientID)
+
+	refreshed, err := requestSpotifyOAuthToken(form, token.ClientID, token.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh Spotify token: %w", err)
+	}
+	refreshed.DisplayName = token.DisplayName
+	refreshed.UserID = token.UserID
+
+	if err := saveSpotifyOAuthToken(refreshed); err != nil {
+		return "", err
+	}
+
+	return refreshed.AccessToken, nil
+}
+
+func spotifyWebAPIGetWithToken(accessToken string, rawURL string, target interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := NewHTTPClient(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("spotify API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, target)
+}
+
+func spotifyWebAPIGet(rawURL string, target interface{}) error {
+	accessToken, err := getSpotifyAccessToken()
+	if err != nil {
+		return err
+	}
+	return spotifyWebAPIGetWithToken(accessToken, rawURL, target)
+}
+
+func firstSpotifyWebAPIImage(images []spotifyWebAPIImage) string {
+	if len(images) == 0 {
+		return ""
+	}
+	return images[0].URL
+}
+
+func joinSpotifyWebAPIArtists(artists []spotifyWebAPIArtist, separator string) string {
+	names := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		if artist.Name != "" {
+			names = append(names, artist.Name)
+		}
+	}
+	return strings.Join(names, separator)
+}
+
+func convertSpotifyWebAPITrack(track spotifyWebAPITrack, separator string) AlbumTrackMetadata {
+	artistsData := make([]ArtistSimple, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		artistsData = append(artistsData, ArtistSimple{
+			ID:          artist.ID,
+			Name:        artist.Name,
+			ExternalURL: fmt.Sprintf("https://open.spotify.com/artist/%s", artist.ID),
+		})
+	}
+
+	var artistID, artistURL string
+	if len(track.Artists) > 0 {
+		artistID = track.Artists[0].ID
+		artistURL = fmt.Sprintf("https://open.spotify.com/artist/%s", artistID)
+	}
+
+	if isrc := strings.TrimSpace(track.ExternalIDs["isrc"]); isrc != "" && track.ID != "" {
+		PutCachedISRC(track.ID, isrc)
+	}
+
+	return AlbumTrackMetadata{
+		SpotifyID:   track.ID,
+		Artists:     joinSpotifyWebAPIArtists(track.Artists, separator),
+		Name:        track.Name,
+		AlbumName:   track.Album.Name,
+		AlbumArtist: joinSpotifyWebAPIArtists(track.Album.Artists, separator),
+		DurationMS:  track.DurationMS,
+		Images:      firstSpotifyWebAPIImage(track.Album.Images),
+		ReleaseDate: track.Album.ReleaseDate,
+		TrackNumber: track.TrackNumber,
+		TotalTracks: track.Album.TotalTracks,
+		DiscNumber:  track.DiscNumber,
+		ExternalURL: fmt.Sprintf("https://open.spotify.com/track/%s", track.ID),
+		AlbumID:     track.Album.ID,
+		AlbumURL:    fmt.Sprintf("https://open.spotify.com/album/%s", track.Album.ID),
+		ArtistID:    artistID,
+		ArtistURL:   artistURL,
+		ArtistsData: artistsData,
+		IsExplicit:  track.Explicit,
+	}
+}
+
+func fetchSpotifyWebAPITrackPages(firstURL string, separator string) ([]AlbumTrackMetadata, error) {
+	var tracks []AlbumTrackMetadata
+	nextURL := firstURL
+
+	for nextURL != "" {
+		var page struct {
+			Items []struct {
+				Track *spotifyWebAPITrack `json:"track"`
+			} `json:"items"`
+			Next string `json:"next"`
+		}
+		if err := spotifyWebAPIGet(nextURL, &page); err != nil {
+			return tracks, err
+		}
+
+		for _, item := range page.Items {
+			if item.Track == nil || item.Track.ID == "" || item.Track.IsLocal || (item.Track.Type != "" && item.Track.Type != "track") {
+				continue
+			}
+			tracks = append(tracks, convertSpotifyWebAPITrack(*item.Track, separator))
+		}
+
+		nextURL = page.Next
+	}
+
+	return tracks, nil
+}
+
+func GetSpotifyLikedSongs(separator string) (*PlaylistResponsePayload, error) {
+	if separator == "" {
+		separator = ", "
+	}
+
+	tracks, err := fetchSpotifyWebAPITrackPages(spotifyWebAPIBaseURL+"/me/tracks?limit=50", separator)
+	if err != nil {
+		return nil, err
+	}
+
+	var info PlaylistInfoMetadata
+	info.Tracks.Total = len(tracks)
+	info.Owner.Name = "Liked Songs"
+	info.Owner.DisplayName = GetSpotifyAuthStatus().DisplayName
+
+	return &PlaylistResponsePayload{
+		PlaylistInfo: info,
+		TrackList:    tracks,
+	}, nil
+}
+
+func GetSpotifyUserPlaylists() ([]SpotifyUserPlaylist, error) {
+	var playlists []SpotifyUserPlaylist
+	nextURL := spotifyWebAPIBaseURL + "/me/playlists?limit=50"
+
+	for nextURL != "" {
+		var page struct {
+			Items []struct {
+				ID     string               `json:"id"`
+				Name   string               `json:"name"`
+				Public bool                 `json:"public"`
+				Images []spotifyWebAPIImage `json:"images"`
+				Owner  struct {
+					DisplayName string `json:"display_name"`
+				} `json:"owner"`
+				Tracks struct {
+					Total int `json:"total"`
+				} `json:"tracks"`
+			} `json:"items"`
+			Next string `json:"next"`
+		}
+		if err := spotifyWebAPIGet(nextURL, &page); err != nil {
+			return playlists, err
+		}
+
+		for _, item := range page.Items {
+			playlists = append(playlists, SpotifyUserPlaylist{
+				ID:          item.ID,
+				Name:        item.Name,
+				Owner:       item.Owner.DisplayName,
+				Images:      firstSpotifyWebAPIImage(item.Images),
+				TotalTracks: item.Tracks.Total,
+				Public:      item.Public,
+				ExternalURL: fmt.Sprintf("https://open.spotify.com/playlist/%s", item.ID),
+			})
+		}
+
+		nextURL = page.Next
+	}
+
+	return playlists, nil
+}
+
+func GetSpotifyUserPlaylistTracks(playlistID string, separator string) (*PlaylistResponsePayload, error) {
+	if separator == "" {
+		separator = ", "
+	}
+
+	var meta struct {
+		Name        string               `json:"name"`
+		Description string               `json:"description"`
+		Images      []spotifyWebAPIImage `json:"images"`
+		Followers   struct {
+			Total int `json:"total"`
+		} `json:"followers"`
+		Owner struct {
+			DisplayName string `json:"display_name"`
+		} `json:"owner"`
+	}
+	if err := spotifyWebAPIGet(fmt.Sprintf("%s/playlists/%s?fields=name,description,images,followers,owner", spotifyWebAPIBaseURL, url.PathEscape(playlistID)), &meta); err != nil {
+		return nil, err
+	}
+
+	tracks, err := fetchSpotifyWebAPITrackPages(fmt.Sprintf("%s/playlists/%s/tracks?limit=100", spotifyWebAPIBaseURL, url.PathEscape(playlistID)), separator)
+	if err != nil {
+		return nil, err
+	}
+
+	var info PlaylistInfoMetadata
+	info.Tracks.Total = len(tracks)
+	info.Followers.Total = meta.Followers.Total
+	info.Owner.Name = meta.Name
+	info.Owner.DisplayName = meta.Owner.DisplayName
+	info.Cover = firstSpotifyWebAPIImage(meta.Images)
+	info.Description = meta.Description
+
+	return &PlaylistResponsePayload{
+		PlaylistInfo: info,
+		TrackList:    tracks,
+	}, nil
+}
+
+func GetSpotifyFollowedArtists() ([]SearchResult, error) {
+	var artists []SearchResult
+	nextURL := spotifyWebAPIBaseURL + "/me/following?type=artist&limit=50"
+
+	for nextURL != "" {
+		var page struct {
+			Artists struct {
+				Items []spotifyWebAPIArtist `json:"items"`
+				Next  string                `json:"next"`
+			} `json:"artists"`
+		}
+		if err := spotifyWebAPIGet(nextURL, &page); err != nil {
+			return artists, err
+		}
+
+		for _, artist := range page.Artists.Items {
+			artists = append(artists, SearchResult{
+				ID:          artist.ID,
+				Name:        artist.Name,
+				Type:        "artist",
+				Images:      firstSpotifyWebAPIImage(artist.Images),
+				ExternalURL: fmt.Sprintf("https://open.spotify.com/artist/%s", artist.ID),
+			})
+		}
+
+		nextURL = page.Artists.Next
+	}
+
+	return artists, nil
+}
@@ -0,0 +1,207 @@
+package backend
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	subsonicAPIVersion        = "1.16.1"
+	subsonicDurationTolerance = 3
+	subsonicScanDelay         = 30 * time.Second
+)
+
+type SubsonicConfig struct {
+	URL            string `json:"url"`
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	SkipOwned      bool   `json:"skip_owned"`
+	ScanOnDownload bool   `json:"scan_on_download"`
+}
+
+func (c SubsonicConfig) Configured() bool {
+	return c.URL != "" && c.Username != ""
+}
+
+type SubsonicSong struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album"`
+	Duration int    `json:"duration"`
+	Path     string `json:"path,omitempty"`
+	Suffix   string `json:"suffix,omitempty"`
+	BitRate  int    `json:"bitRate,omitempty"`
+}
+
+type subsonicError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type subsonicResponse struct {
+	Response struct {
+		Status        string         `json:"status"`
+		Error         *subsonicError `json:"error,omitempty"`
+		SearchResult3 struct {
+			Song []SubsonicSong `json:"song"`
+		} `json:"searchResult3"`
+	} `json:"subsonic-response"`
+}
+
+type SubsonicClient struct {
+	config     SubsonicConfig
+	httpClient *http.Client
+}
+
+func NewSubsonicClient(config SubsonicConfig) *SubsonicClient {
+	config.URL = strings.TrimRight(strings.TrimSpace(config.URL), "/")
+	return &SubsonicClient{
+		config:     config,
+		httpClient: NewHTTPClient(15 * time.Second),
+	}
+}
+
+func (c *SubsonicClient) call(endpoint string, params url.Values) (*subsonicResponse, error) {
+	if !c.config.Configured() {
+		return nil, fmt.Errorf("subsonic server is not configured")
+	}
+
+	saltBytes := make([]byte, 8)
+	if _, err := rand.Read(saltBytes); err != nil {
+		return nil, err
+	}
+	salt := hex.EncodeToString(saltBytes)
+	token := md5.Sum([]byte(c.config.Password + salt))
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("u", c.config.Username)
+	params.Set("t", hex.EncodeToString(token[:]))
+	params.Set("s", salt)
+	params.Set("v", subsonicAPIVersion)
+	params.Set("c", "SpotiFLAC")
+	params.Set("f", "json")
+
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/rest/%s?%s", c.config.URL, endpoint, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach subsonic server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("subsonic server returned HTTP %d", resp.StatusCode)
+	}
+
+	var decoded subsonicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode subsonic response: %w", err)
+	}
+	if decoded.Response.Status != "ok" {
+		if decoded.Response.Error != nil {
+			return nil, fmt.Errorf("subsonic error %d: %s", decoded.Response.Error.Code, decoded.Response.Error.Message)
+		}
+		return nil, fmt.Errorf("subsonic request failed")
+	}
+	return &decoded, nil
+}
+
+func (c *SubsonicClient) Ping() error {
+	_, err := c.call("ping", nil)
+	return err
+}
+
+func (c *SubsonicClient) FindTrack(title, artist string, durationSec int) (*SubsonicSong, error) {
+	params := url.Values{}
+	params.Set("query", title)
+	params.Set("songCount", "25")
+	params.Set("artistCount", "0")
+	params.Set("albumCount", "0")
+
+	decoded, err := c.call("search3", params)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, song := range decoded.Response.SearchResult3.Song {
+		if !SearchResultMatches(title, artist, song.Title, song.Artist) {
+			continue
+		}
+		if durationSec > 0 && song.Duration > 0 {
+			diff := song.Duration - durationSec
+			if diff < -subsonicDurationTolerance || diff > subsonicDurationTolerance {
+				continue
+			}
+		}
+		return &song, nil
+	}
+	return nil, nil
+}
+
+func (c *SubsonicClient) StartScan() error {
+	_, err := c.call("startScan", nil)
+	return err
+}
+
+var (
+	subsonicScanMu    sync.Mutex
+	subsonicScanTimer *time.Timer
+)
+
+func RequestSubsonicScan() {
+	config := GetSubsonicSetting()
+	if !config.Configured() || !config.ScanOnDownload {
+		return
+	}
+
+	subsonicScanMu.Lock()
+	defer subsonicScanMu.Unlock()
+
+	if subsonicScanTimer != nil {
+		subsonicScanTimer.Stop()
+	}
+	subsonicScanTimer = time.AfterFunc(subsonicScanDelay, func() {
+		if err := NewSubsonicClient(config).StartScan(); err != nil {
+			fmt.Printf("Warning: failed to start subsonic scan: %v\n", err)
+			return
+		}
+		fmt.Println("Subsonic library scan requested")
+	})
+}
+
+func FlushSubsonicScan() {
+	subsonicScanMu.Lock()
+	timer := subsonicScanTimer
+	subsonicScanTimer = nil
+	subsonicScanMu.Unlock()
+
+	if timer == nil || !timer.Stop() {
+		return
+	}
+	if err := NewSubsonicClient(GetSubsonicSetting()).StartScan(); err != nil {
+		fmt.Printf("Warning: failed to start subsonic scan: %v\n", err)
+	}
+}
+
+func FindOwnedSubsonicTrack(title, artist string, durationSec int) *SubsonicSong {
+	config := GetSubsonicSetting()
+	if !config.Configured() || !config.SkipOwned || strings.TrimSpace(title) == "" || strings.TrimSpace(artist) == "" {
+		return nil
+	}
+
+	song, err := NewSubsonicClient(config).FindTrack(title, artist, durationSec)
+	if err != nil {
+		fmt.Printf("Warning: subsonic lookup failed for %s - %s: %v\n", artist, title, err)
+		return nil
+	}
+	return song
+}
@@ -0,0 +1,187 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const tempCleanupInterval = 6 * time.Hour
+
+type TempCleanupResult struct {
+	FilesRemoved   int      `json:"files_removed"`
+	BytesReclaimed int64    `json:"bytes_reclaimed"`
+	Errors         []string `json:"errors,omitempty"`
+}
+
+const stagingDirName = "spotiflac"
+
+var legacyTempPrefixes = []string{
+	"spotiflac-",
+	"spotiflac_",
+}
+
+var stagingPartExts = map[string]bool{
+	".cue":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".pdf":  true,
+	".lrc":  true,
+}
+
+func StagingDir() string {
+	dir := filepath.Join(os.TempDir(), stagingDirName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return os.TempDir()
+	}
+	return dir
+}
+
+func isStagingArtifact(name string) bool {
+	lower := strings.ToLower(name)
+	if isPartialDownload(lower) || strings.HasSuffix(lower, outputClaimSuffix) {
+		return true
+	}
+	if base, ok := strings.CutSuffix(lower, ".cover.jpg"); ok {
+		return partialAudioMuxers[filepath.Ext(base)] != ""
+	}
+	if base, ok := strings.CutSuffix(lower, ".part"); ok {
+		if ext := filepath.Ext(base); ext != "" && strings.Trim(ext[1:], "0123456789") == "" {
+			base = strings.TrimSuffix(base, ext)
+		}
+		return stagingPartExts[filepath.Ext(base)]
+	}
+
+	ext := filepath.Ext(lower)
+	return partialAudioMuxers[ext] != "" && strings.HasSuffix(strings.TrimSuffix(lower, ext), ".tmp")
+}
+
+func isLegacyTempArtifact(name string) bool {
+	lower := strings.ToLower(name)
+	for _, prefix := range legacyTempPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeStaleFile(path string, info fs.FileInfo, cutoff time.Time, result *TempCleanupResult) {
+	if info.ModTime().After(cutoff) {
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
+		return
+	}
+	result.FilesRemoved++
+	result.BytesReclaimed += info.Size()
+}
+
+func removeStaleEntry(path string, info fs.FileInfo, cutoff time.Time, result *TempCleanupResult) {
+	if !info.IsDir() {
+		removeStaleFile(path, info, cutoff, result)
+		return
+	}
+	if info.ModTime().After(cutoff) {
+		return
+	}
+
+	var files int
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if fileInfo, err := d.Info(); err == nil {
+				files++
+				size += fileInfo.Size()
+			}
+		}
+		return nil
+	})
+	if err := os.RemoveAll(path); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
+		return
+	}
+	result.FilesRemoved += files
+	result.BytesReclaimed += size
+}
+
+func CleanupStaleTempFiles(downloadDirs []string, maxAge time.Duration) TempCleanupResult {
+	var result TempCleanupResult
+	if maxAge <= 0 {
+		return result
+	}
+	cutoff := time.Now().Add(-maxAge)
+
+	stagingDir := StagingDir()
+	if entries, err := os.ReadDir(stagingDir); err == nil {
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			removeStaleEntry(filepath.Join(stagingDir, entry.Name()), info, cutoff, &result)
+		}
+	}
+	if entries, err := os.ReadDir(os.TempDir()); err == nil {
+		for _, entry := range entries {
+			if !isLegacyTempArtifact(entry.Name()) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			removeStaleEntry(filepath.Join(os.TempDir(), entry.Name()), info, cutoff, &result)
+		}
+	}
+
+	for _, dir := range downloadDirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			switch {
+			case strings.HasSuffix(strings.ToLower(d.Name()), outputClaimSuffix):
+				removeOrphanedOutputClaim(path, info, cutoff, &result)
+			case isStagingArtifact(d.Name()):
+				removeStaleFile(path, info, cutoff, &result)
+			}
+			return nil
+		})
+	}
+
+	return result
+}
+
+func StartTempCleanupScheduler(ctx context.Context, downloadDirs func() []string, onCleanup func(TempCleanupResult)) {
+	go func() {
+		for {
+			result := CleanupStaleTempFiles(downloadDirs(), GetTempCleanupMaxAgeSetting())
+			if result.FilesRemoved > 0 {
+				fmt.Printf("[Cleanup] Removed %d stale temp file(s), reclaimed %.2f MB\n", result.FilesRemoved, float64(result.BytesReclaimed)/(1024*1024))
+				if onCleanup != nil {
+					onCleanup(result)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(tempCleanupInterval):
+			}
+		}
+	}()
+}
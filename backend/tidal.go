@@ -72,28 +72,37 @@ func getConfiguredTidalAPIAttemptList() ([]string, error) {
 	return result, err
 }
 
-func buildTidalOutputPath(outputDir, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyTrackNumber, spotifyDiscNumber int, isrcOverride string, useFirstArtistOnly bool) (string, bool, error) {
-	if outputDir != "." {
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", false, fmt.Errorf("directory error: %w", err)
-		}
+func buildTidalOutputPath(outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyTrackNumber, spotifyDiscNumber int, isrcOverride string, useFirstArtistOnly bool) (string, bool, func(), error) {
+	if err := EnsureOutputDir(outputDir); err != nil {
+		return "", false, nil, fmt.Errorf("directory error: %w", err)
 	}
 
-	artistNameForFile := sanitizeFilename(spotifyArtistName)
-	albumArtistForFile := sanitizeFilename(spotifyAlbumArtist)
+	artistNameForFile := spotifyArtistName
+	albumArtistForFile := spotifyAlbumArtist
 	if useFirstArtistOnly {
-		artistNameForFile = sanitizeFilename(GetFirstArtist(spotifyArtistName))
-		albumArtistForFile = sanitizeFilename(GetFirstArtist(spotifyAlbumArtist))
-	}
-
-	trackTitleForFile := sanitizeFilename(spotifyTrackName)
-	albumTitleForFile := sanitizeFilename(spotifyAlbumName)
-
-	filename := buildTidalFilename(trackTitleForFile, artistNameForFile, albumTitleForFile, albumArtistForFile, spotifyReleaseDate, spotifyTrackNumber, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber, isrcOverride)
+		artistNameForFile = GetFirstArtist(spotifyArtistName)
+		albumArtistForFile = GetFirstArtist(spotifyAlbumArtist)
+	}
+
+	bitDepth, sampleRate := tidalFilenameAudioFormat(quality)
+	filename := buildTemplatedFilename(filenameFormat, FilenameTemplateData{
+		Title:       spotifyTrackName,
+		Artist:      artistNameForFile,
+		Album:       spotifyAlbumName,
+		AlbumArtist: albumArtistForFile,
+		ReleaseDate: spotifyReleaseDate,
+		ISRC:        isrcOverride,
+		Track:       filenameTrackNumber(position, spotifyTrackNumber, useAlbumTrackNumber),
+		Disc:        spotifyDiscNumber,
+		Quality:     quality,
+		BitDepth:    bitDepth,
+		SampleRate:  sampleRate,
+		Service:     "tidal",
+	}, includeTrackNumber, position, "%02d. ") + ".flac"
 	outputFilename := filepath.Join(outputDir, filename)
 
-	outputFilename, alreadyExists := ResolveOutputPathForDownload(outputFilename, GetRedownloadWithSuffixSetting())
-	return outputFilename, alreadyExists, nil
+	outputFilename, alreadyExists, release := ReserveOutputPathForDownload(outputFilename, GetRedownloadWithSuffixSetting())
+	return outputFilename, alreadyExists, release, nil
 }
 
 func finalizeTidalDownload(outputFilename, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks, spotifyTotalDiscs int, spotifyCopyright, spotifyPublisher, spotifyComposer, metadataSeparator, isrcOverride, spotifyURL string, useSingleGenre bool, embedGenre bool) {
@@ -173,6 +182,7 @@ func finalizeTidalDownload(outputFilename, spotifyTrackName, spotifyArtistName,
 		} else {
 			defer os.Remove(coverPath)
 			fmt.Println("Spotify cover downloaded")
+			coverPath = PrepareCoverForEmbedding(coverPath)
 		}
 	}
 
@@ -220,9 +230,7 @@ func NewTidalDownloader(apiURL string) *TidalDownloader {
 	}
 
 	return &TidalDownloader{
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		client:     NewServiceHTTPClient("tidal", 5*time.Second),
 		timeout:    5 * time.Second,
 		maxRetries: 3,
 		apiURL:     apiURL,
@@ -276,7 +284,7 @@ func (t *TidalDownloader) GetTrackIDFromURL(tidalURL string) (int64, error) {
 func (t *TidalDownloader) GetDownloadURL(trackID int64, quality string) (string, error) {
 	fmt.Println("Fetching URL...")
 
-	url := fmt.Sprintf("%s/track/?id=%d&quality=%s", t.apiURL, trackID, quality)
+	url := fmt.Sprintf("%s/track/?id=%d&quality=%s&countryCode=%s", t.apiURL, trackID, quality, ResolveCountryCode())
 	fmt.Printf("Tidal API URL: %s\n", url)
 
 	req, err := NewRequestWithDefaultHeaders(http.MethodGet, url, nil)
@@ -342,35 +350,12 @@ func (t *TidalDownloader) DownloadFile(url, filepath string, quality string) err
 		return t.DownloadFromManifest(strings.TrimPrefix(url, "MANIFEST:"), filepath, quality)
 	}
 
-	req, err := NewRequestWithDefaultHeaders(http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := t.client.Do(req)
-
-	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
-	}
-
-	out, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer out.Close()
-
-	pw := NewProgressWriter(out)
-	_, err = io.Copy(pw, resp.Body)
+	total, err := DownloadURLToFile(t.client, url, filepath)
 	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return err
 	}
 
-	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
+	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(total)/(1024*1024))
 
 	fmt.Println("Download complete")
 	return nil
@@ -388,9 +373,7 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string, q
 		return fmt.Errorf("requested %s quality but Tidal provided lossy format (%s). Aborting download", quality, mimeType)
 	}
 
-	client := &http.Client{
-		Timeout: 120 * time.Second,
-	}
+	client := NewServiceHTTPClient("tidal", 120*time.Second)
 
 	doRequest := func(url string) (*http.Response, error) {
 		req, err := NewRequestWithDefaultHeaders(http.MethodGet, url, nil)
@@ -403,29 +386,12 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string, q
 	if directURL != "" && (strings.Contains(strings.ToLower(mimeType), "flac") || mimeType == "") {
 		fmt.Println("Downloading file...")
 
-		resp, err := doRequest(directURL)
-		if err != nil {
-			return fmt.Errorf("failed to download file: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("download failed with status %d", resp.StatusCode)
-		}
-
-		out, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
-		}
-		defer out.Close()
-
-		pw := NewProgressWriter(out)
-		_, err = io.Copy(pw, resp.Body)
+		total, err := DownloadURLToFile(client, directURL, outputPath)
 		if err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
+			return err
 		}
 
-		fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
+		fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(total)/(1024*1024))
 		fmt.Println("Download complete")
 		return nil
 	}
@@ -550,11 +516,13 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string, q
 		return fmt.Errorf("invalid ffmpeg executable: %w", err)
 	}
 
-	cmd := exec.Command(ffmpegPath, "-y", "-i", tempPath, "-vn", "-c:a", "flac", outputPath)
+	partialPath := PartialDownloadPath(outputPath)
+	cmd := exec.Command(ffmpegPath, "-y", "-i", tempPath, "-vn", "-c:a", "flac", "-f", partialDownloadMuxer(outputPath), partialPath)
 	setHideWindow(cmd)
 	var stderr strings.Builder
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
+		os.Remove(partialPath)
 
 		m4aPath := strings.TrimSuffix(outputPath, ".flac") + ".m4a"
 		os.Rename(tempPath, m4aPath)
@@ -562,6 +530,9 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string, q
 	}
 
 	os.Remove(tempPath)
+	if err := commitPartialDownload(partialPath, outputPath); err != nil {
+		return err
+	}
 	fmt.Println("Download complete")
 
 	return nil
@@ -579,10 +550,11 @@ func (t *TidalDownloader) DownloadByURL(tidalURL, outputDir, quality, filenameFo
 		return "", fmt.Errorf("no track ID found")
 	}
 
-	outputFilename, alreadyExists, err := buildTidalOutputPath(outputDir, filenameFormat, includeTrackNumber, position, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, useAlbumTrackNumber, spotifyTrackNumber, spotifyDiscNumber, isrcOverride, useFirstArtistOnly)
+	outputFilename, alreadyExists, releaseOutput, err := buildTidalOutputPath(outputDir, quality, filenameFormat, includeTrackNumber, position, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, useAlbumTrackNumber, spotifyTrackNumber, spotifyDiscNumber, isrcOverride, useFirstArtistOnly)
 	if err != nil {
 		return "", err
 	}
+	defer releaseOutput()
 	if alreadyExists {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", outputFilename, float64(mustFileSize(outputFilename))/(1024*1024))
 		return "EXISTS:" + outputFilename, nil
@@ -631,10 +603,11 @@ func (t *TidalDownloader) DownloadByURLWithFallback(tidalURL, outputDir, quality
 		return "", fmt.Errorf("no track ID found")
 	}
 
-	outputFilename, alreadyExists, err := buildTidalOutputPath(outputDir, filenameFormat, includeTrackNumber, position, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, useAlbumTrackNumber, spotifyTrackNumber, spotifyDiscNumber, isrcOverride, useFirstArtistOnly)
+	outputFilename, alreadyExists, releaseOutput, err := buildTidalOutputPath(outputDir, quality, filenameFormat, includeTrackNumber, position, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, useAlbumTrackNumber, spotifyTrackNumber, spotifyDiscNumber, isrcOverride, useFirstArtistOnly)
 	if err != nil {
 		return "", err
 	}
+	defer releaseOutput()
 	if alreadyExists {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", outputFilename, float64(mustFileSize(outputFilename))/(1024*1024))
 		return "EXISTS:" + outputFilename, nil
@@ -849,6 +822,15 @@ func (t *TidalDownloader) downloadWithRotatingAPIs(trackID int64, outputFilename
 			fmt.Printf("⚠ %s unavailable/failed on all APIs, falling back to %s...\n", quality, candidateQuality)
 		}
 
+		if HasTidalAccount() {
+			fmt.Printf("Trying Tidal account (Quality: %s)...\n", candidateQuality)
+			err := t.tryDownloadWithAccount(trackID, outputFilename, candidateQuality)
+			if err == nil {
+				return tidalAccountSource, nil
+			}
+			fmt.Printf("Tidal account failed: %v, falling back to public mirrors\n", err)
+		}
+
 		apiURL, err := t.tryDownloadAcrossTidalAPIs(trackID, outputFilename, candidateQuality, false)
 		if err == nil {
 			return apiURL, nil
@@ -934,62 +916,9 @@ func isTidalHiResQuality(quality string) bool {
 	return normalized == "HI_RES" || normalized == "HI_RES_LOSSLESS"
 }
 
-func buildTidalFilename(title, artist, album, albumArtist, releaseDate string, trackNumber, discNumber int, format string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, extra ...string) string {
-	var filename string
-	isrc := ""
-	if len(extra) > 0 {
-		isrc = SanitizeOptionalFilename(extra[0])
-	}
-
-	numberToUse := position
-	if useAlbumTrackNumber && trackNumber > 0 {
-		numberToUse = trackNumber
-	}
-
-	year := ""
-	if len(releaseDate) >= 4 {
-		year = releaseDate[:4]
+func tidalFilenameAudioFormat(quality string) (int, int) {
+	if isTidalHiResQuality(quality) {
+		return 24, 0
 	}
-
-	if strings.Contains(format, "{") {
-		filename = format
-		filename = strings.ReplaceAll(filename, "{title}", title)
-		filename = strings.ReplaceAll(filename, "{artist}", artist)
-		filename = strings.ReplaceAll(filename, "{album}", album)
-		filename = strings.ReplaceAll(filename, "{album_artist}", albumArtist)
-		filename = strings.ReplaceAll(filename, "{year}", year)
-		filename = strings.ReplaceAll(filename, "{date}", SanitizeFilename(releaseDate))
-		filename = strings.ReplaceAll(filename, "{isrc}", isrc)
-
-		if discNumber > 0 {
-			filename = strings.ReplaceAll(filename, "{disc}", fmt.Sprintf("%d", discNumber))
-		} else {
-			filename = strings.ReplaceAll(filename, "{disc}", "")
-		}
-
-		if numberToUse > 0 {
-			filename = strings.ReplaceAll(filename, "{track}", fmt.Sprintf("%02d", numberToUse))
-		} else {
-
-			filename = regexp.MustCompile(`\{track\}\.\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*-\s*`).ReplaceAllString(filename, "")
-			filename = regexp.MustCompile(`\{track\}\s*`).ReplaceAllString(filename, "")
-		}
-	} else {
-
-		switch format {
-		case "artist-title":
-			filename = fmt.Sprintf("%s - %s", artist, title)
-		case "title":
-			filename = title
-		default:
-			filename = fmt.Sprintf("%s - %s", title, artist)
-		}
-
-		if includeTrackNumber && position > 0 {
-			filename = fmt.Sprintf("%02d. %s", numberToUse, filename)
-		}
-	}
-
-	return filename + ".flac"
+	return 16, 44100
 }
@@ -0,0 +1,317 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	tidalAuthBaseURL   = "https://auth.tidal.com/v1/oauth2"
+	tidalAPIBaseURL    = "https://api.tidal.com/v1"
+	tidalOAuthScopes   = "r_usr w_usr w_sub"
+	tidalDeviceGrant   = "urn:ietf:params:oauth:grant-type:device_code"
+	tidalAccountSource = "tidal-account"
+)
+
+var tidalAccountMu sync.Mutex
+
+type TidalAuthStatus struct {
+	LoggedIn    bool   `json:"logged_in"`
+	UserID      string `json:"user_id,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+}
+
+type TidalDeviceCode struct {
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int    `json:"expires_in"`
+}
+
+type tidalDeviceAuthResponse struct {
+	DeviceCode              string `json:"deviceCode"`
+	UserCode                string `json:"userCode"`
+	VerificationURIComplete string `json:"verificationUriComplete"`
+	ExpiresIn               int    `json:"expiresIn"`
+	Interval                int    `json:"interval"`
+}
+
+type tidalTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Error        string `json:"error"`
+	User         struct {
+		UserID      int64  `json:"userId"`
+		CountryCode string `json:"countryCode"`
+	} `json:"user"`
+}
+
+type tidalPlaybackInfoResponse struct {
+	AudioQuality      string `json:"audioQuality"`
+	AssetPresentation string `json:"assetPresentation"`
+	ManifestMimeType  string `json:"manifestMimeType"`
+	Manifest          string `json:"manifest"`
+}
+
+func GetTidalClientCredentialsSetting() (string, string) {
+	settings, err := LoadConfigSettings()
+	if err != nil || settings == nil {
+		return "", ""
+	}
+
+	clientID, _ := settings["tidalClientId"].(string)
+	clientSecret, _ := settings["tidalClientSecret"].(string)
+	return strings.TrimSpace(clientID), strings.TrimSpace(clientSecret)
+}
+
+func HasTidalAccount() bool {
+	_, ok := GetServiceCredential("tidal")
+	return ok
+}
+
+func GetTidalAuthStatus() TidalAuthStatus {
+	cred, ok := GetServiceCredential("tidal")
+	if !ok {
+		return TidalAuthStatus{}
+	}
+	return TidalAuthStatus{
+		LoggedIn:    true,
+		UserID:      cred.UserID,
+		CountryCode: cred.CountryCode,
+		ExpiresAt:   cred.ExpiresAt,
+	}
+}
+
+func TidalLogout() error {
+	return DeleteServiceCredential("tidal")
+}
+
+func postTidalAuthForm(endpoint string, form url.Values, target interface{}) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, tidalAuthBaseURL+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := NewServiceHTTPClient("tidal", 15*time.Second).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return resp.StatusCode, fmt.Errorf("unexpected tidal auth response (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+func saveTidalToken(token tidalTokenResponse, previous ServiceCredential) error {
+	cred := previous
+	cred.Token = token.AccessToken
+	if token.RefreshToken != "" {
+		cred.RefreshToken = token.RefreshToken
+	}
+	if token.ExpiresIn > 0 {
+		cred.ExpiresAt = time.Now().Unix() + token.ExpiresIn
+	}
+	if token.User.UserID != 0 {
+		cred.UserID = strconv.FormatInt(token.User.UserID, 10)
+	}
+	if token.User.CountryCode != "" {
+		cred.CountryCode = token.User.CountryCode
+	}
+	return SetServiceCredential("tidal", cred)
+}
+
+func TidalDeviceLogin(ctx context.Context, onCode func(TidalDeviceCode)) (TidalAuthStatus, error) {
+	clientID, clientSecret := GetTidalClientCredentialsSetting()
+	if clientID == "" {
+		return TidalAuthStatus{}, fmt.Errorf("tidal client ID is not configured (set tidalClientId in settings)")
+	}
+
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	form.Set("scope", tidalOAuthScopes)
+
+	var device tidalDeviceAuthResponse
+	status, err := postTidalAuthForm("/device_authorization", form, &device)
+	if err != nil {
+		return TidalAuthStatus{}, fmt.Errorf("failed to start tidal device login: %w", err)
+	}
+	if status != http.StatusOK || device.DeviceCode == "" {
+		return TidalAuthStatus{}, fmt.Errorf("failed to start tidal device login: HTTP %d", status)
+	}
+
+	verificationURL := device.VerificationURIComplete
+	if verificationURL != "" && !strings.HasPrefix(verificationURL, "http") {
+		verificationURL = "https://" + verificationURL
+	}
+	fmt.Printf("[TidalAuth] Visit %s and confirm code %s\n", verificationURL, device.UserCode)
+	if onCode != nil {
+		onCode(TidalDeviceCode{UserCode: device.UserCode, VerificationURL: verificationURL, ExpiresIn: device.ExpiresIn})
+	}
+
+	interval := time.Duration(max(device.Interval, 2)) * time.Second
+	deadline := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+
+	form = url.Values{}
+	form.Set("client_id", clientID)
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+	form.Set("device_code", device.DeviceCode)
+	form.Set("grant_type", tidalDeviceGrant)
+	form.Set("scope", tidalOAuthScopes)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return TidalAuthStatus{}, fmt.Errorf("tidal login timed out")
+		case <-time.After(interval):
+		}
+		if device.ExpiresIn > 0 && time.Now().After(deadline) {
+			return TidalAuthStatus{}, fmt.Errorf("tidal login code expired")
+		}
+
+		var token tidalTokenResponse
+		status, err := postTidalAuthForm("/token", form, &token)
+		if err != nil {
+			return TidalAuthStatus{}, fmt.Errorf("tidal login failed: %w", err)
+		}
+		switch {
+		case status == http.StatusOK && token.AccessToken != "":
+			tidalAccountMu.Lock()
+			err := saveTidalToken(token, ServiceCredential{})
+			tidalAccountMu.Unlock()
+			if err != nil {
+				return TidalAuthStatus{}, fmt.Errorf("failed to save tidal token: %w", err)
+			}
+			status := GetTidalAuthStatus()
+			fmt.Printf("[TidalAuth] Logged in as user %s (%s)\n", status.UserID, status.CountryCode)
+			return status, nil
+		case token.Error == "authorization_pending":
+		case token.Error == "slow_down":
+			interval += 5 * time.Second
+		default:
+			return TidalAuthStatus{}, fmt.Errorf("tidal login failed: %s (HTTP %d)", token.Error, status)
+		}
+	}
+}
+
+func getTidalAccessToken() (ServiceCredential, error) {
+	tidalAccountMu.Lock()
+	defer tidalAccountMu.Unlock()
+
+	cred, ok := GetServiceCredential("tidal")
+	if !ok {
+		return ServiceCredential{}, fmt.Errorf("not logged in to Tidal")
+	}
+	if cred.ExpiresAt == 0 || time.Now().Unix() < cred.ExpiresAt-60 {
+		return cred, nil
+	}
+
+	clientID, clientSecret := GetTidalClientCredentialsSetting()
+	if cred.RefreshToken == "" || clientID == "" {
+		return ServiceCredential{}, fmt.Errorf("tidal session expired, log in again")
+	}
+
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+	form.Set("refresh_token", cred.RefreshToken)
+	form.Set("grant_type", "refresh_token")
+	form.Set("scope", tidalOAuthScopes)
+
+	var token tidalTokenResponse
+	status, err := postTidalAuthForm("/token", form, &token)
+	if err != nil {
+		return ServiceCredential{}, fmt.Errorf("failed to refresh tidal token: %w", err)
+	}
+	if status != http.StatusOK || token.AccessToken == "" {
+		return ServiceCredential{}, fmt.Errorf("failed to refresh tidal token: HTTP %d %s", status, token.Error)
+	}
+	if err := saveTidalToken(token, cred); err != nil {
+		return ServiceCredential{}, err
+	}
+
+	cred, _ = GetServiceCredential("tidal")
+	return cred, nil
+}
+
+func (t *TidalDownloader) GetAccountStreamURL(trackID int64, quality string) (string, error) {
+	cred, err := getTidalAccessToken()
+	if err != nil {
+		return "", err
+	}
+
+	countryCode := cred.CountryCode
+	if countryCode == "" {
+		countryCode = ResolveCountryCode()
+	}
+
+	params := url.Values{}
+	params.Set("audioquality", quality)
+	params.Set("playbackmode", "STREAM")
+	params.Set("assetpresentation", "FULL")
+	params.Set("countryCode", countryCode)
+	reqURL := fmt.Sprintf("%s/tracks/%d/playbackinfopostpaywall?%s", tidalAPIBaseURL, trackID, params.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+cred.Token)
+
+	resp, err := NewServiceHTTPClient("tidal", 15*time.Second).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("tidal playbackinfo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return "", fmt.Errorf("tidal playbackinfo failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	var info tidalPlaybackInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("failed to parse tidal playbackinfo: %w", err)
+	}
+	if info.Manifest == "" {
+		return "", fmt.Errorf("tidal playbackinfo did not include a manifest")
+	}
+	if info.AssetPresentation != "" && info.AssetPresentation != "FULL" {
+		return "", fmt.Errorf("tidal account can only play a %s preview of this track", strings.ToLower(info.AssetPresentation))
+	}
+	if info.AudioQuality != "" && info.AudioQuality != quality {
+		fmt.Printf("[Tidal] Account returned %s instead of %s\n", info.AudioQuality, quality)
+	}
+	return "MANIFEST:" + info.Manifest, nil
+}
+
+func (t *TidalDownloader) tryDownloadWithAccount(trackID int64, outputFilename, quality string) error {
+	streamURL, err := t.GetAccountStreamURL(trackID, quality)
+	if err != nil {
+		return err
+	}
+	if err := t.DownloadFile(streamURL, outputFilename, quality); err != nil {
+		cleanupTidalDownloadArtifacts(outputFilename)
+		return err
+	}
+	return nil
+}
@@ -114,7 +114,7 @@ func normalizeTidalAPIURLs(urls []string) []string {
 }
 
 func fetchTidalAPIURLsFromGist() ([]string, error) {
-	client := &http.Client{Timeout: 12 * time.Second}
+	client := NewServiceHTTPClient("tidal", 12*time.Second)
 	req, err := NewRequestWithDefaultHeaders(http.MethodGet, tidalAPIListGistURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create tidal api gist request: %w", err)
@@ -0,0 +1,75 @@
+package backend
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var cyrillicLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u", 'ђ': "dj", 'ј': "j",
+	'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz", 'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+}
+
+var greekLatin = map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+var latinSpecial = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th",
+	'ı': "i", 'ħ': "h", 'ŋ': "ng",
+	'‘': "'", '’': "'", '“': "\"", '”': "\"", '–': "-", '—': "-", '…': "...",
+}
+
+func transliterateRune(r rune) (string, bool) {
+	lower := unicode.ToLower(r)
+	for _, table := range []map[rune]string{cyrillicLatin, greekLatin, latinSpecial} {
+		latin, ok := table[lower]
+		if !ok {
+			continue
+		}
+		if lower != r && latin != "" {
+			latin = strings.ToUpper(latin[:1]) + latin[1:]
+		}
+		return latin, true
+	}
+	return "", false
+}
+
+func TransliterateText(text string) string {
+	if text == "" {
+		return text
+	}
+
+	var sb strings.Builder
+	for _, r := range norm.NFC.String(RomanizeText(text)) {
+		if r <= unicode.MaxASCII {
+			sb.WriteRune(r)
+			continue
+		}
+		if latin, ok := transliterateRune(r); ok {
+			sb.WriteString(latin)
+			continue
+		}
+		for _, d := range norm.NFD.String(string(r)) {
+			if d <= unicode.MaxASCII {
+				sb.WriteRune(d)
+			} else if latin, ok := transliterateRune(d); ok {
+				sb.WriteString(latin)
+			}
+		}
+	}
+
+	if strings.TrimSpace(sb.String()) == "" {
+		return text
+	}
+	return sb.String()
+}
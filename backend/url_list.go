@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var urlListPattern = regexp.MustCompile(`(?i)(spotify:(?:user:[^:\s]+:)?(?:track|album|playlist|artist|episode|show):[A-Za-z0-9]+|https?://\S+|open\.spotify\.com/\S+|(?:spotify\.link|spoti\.fi)/\S+)`)
+
+func NormalizeSpotifyURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(strings.ToLower(raw), "open.spotify.com/") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := parseSpotifyURI(raw)
+	if err != nil {
+		return ""
+	}
+	switch parsed.Type {
+	case "track", "album", "playlist", "artist", "episode", "show":
+		return fmt.Sprintf("https://open.spotify.com/%s/%s", parsed.Type, parsed.ID)
+	case "artist_discography":
+		return fmt.Sprintf("https://open.spotify.com/artist/%s/discography/%s", parsed.ID, parsed.DiscographyGroup)
+	}
+	return ""
+}
+
+func ExtractSpotifyURLs(text string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, match := range urlListPattern.FindAllString(text, -1) {
+		match = strings.TrimRight(match, `"'),;>`)
+		normalized := NormalizeSpotifyURL(match)
+		if normalized == "" && (IsExternalPlaylistURL(match) || IsSpotifyShortLink(match)) {
+			normalized = match
+		}
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		urls = append(urls, normalized)
+	}
+	return urls
+}
+
+func ParseURLList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var urls []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, url := range ExtractSpotifyURLs(line) {
+			if seen[url] {
+				continue
+			}
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no Spotify URLs found in %s", path)
+	}
+	return urls, nil
+}
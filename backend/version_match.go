@@ -0,0 +1,147 @@
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var versionKeywords = []string{
+	"remaster", "deluxe", "live", "acoustic", "demo", "instrumental", "remix",
+	"mono", "stereo", "anniversary", "expanded", "edit", "extended", "version",
+}
+
+type VersionTarget struct {
+	Title       string
+	Artist      string
+	Album       string
+	ReleaseDate string
+	DurationSec int
+}
+
+type VersionCandidate struct {
+	Title       string
+	Artist      string
+	Album       string
+	ReleaseDate string
+	DurationSec int
+}
+
+func releaseYear(date string) int {
+	date = strings.TrimSpace(date)
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+func versionKeywordSet(values ...string) map[string]bool {
+	set := make(map[string]bool)
+	text := normalizeSearchText(strings.Join(values, " "))
+	for _, keyword := range versionKeywords {
+		if strings.Contains(text, keyword) {
+			set[keyword] = true
+		}
+	}
+	return set
+}
+
+func ScoreVersion(target VersionTarget, candidate VersionCandidate) (int, string) {
+	score := 0
+	var reasons []string
+
+	wantAlbum := normalizeSearchText(target.Album)
+	gotAlbum := normalizeSearchText(candidate.Album)
+	if wantAlbum != "" && gotAlbum != "" {
+		switch {
+		case wantAlbum == gotAlbum:
+			score += 40
+			reasons = append(reasons, "album=exact")
+		case searchTextMatches(normalizeSearchText(simplifyTrackName(target.Album)), normalizeSearchText(simplifyTrackName(candidate.Album))):
+			score += 20
+			reasons = append(reasons, "album=partial")
+		default:
+			score -= 10
+			reasons = append(reasons, "album=different")
+		}
+	}
+
+	wantVersions := versionKeywordSet(target.Title, target.Album)
+	gotVersions := versionKeywordSet(candidate.Title, candidate.Album)
+	mismatched := 0
+	for keyword := range wantVersions {
+		if !gotVersions[keyword] {
+			mismatched++
+		}
+	}
+	for keyword := range gotVersions {
+		if !wantVersions[keyword] {
+			mismatched++
+		}
+	}
+	if mismatched > 0 {
+		score -= 15 * mismatched
+		reasons = append(reasons, fmt.Sprintf("version-tags=-%d", mismatched))
+	}
+
+	wantYear := releaseYear(target.ReleaseDate)
+	gotYear := releaseYear(candidate.ReleaseDate)
+	if wantYear > 0 && gotYear > 0 {
+		diff := wantYear - gotYear
+		if diff < 0 {
+			diff = -diff
+		}
+		switch {
+		case diff == 0:
+			score += 20
+		case diff == 1:
+			score += 10
+		default:
+			score -= 2 * min(diff, 10)
+		}
+		reasons = append(reasons, fmt.Sprintf("year=%d", gotYear))
+	}
+
+	if target.DurationSec > 0 && candidate.DurationSec > 0 {
+		diff := target.DurationSec - candidate.DurationSec
+		if diff < 0 {
+			diff = -diff
+		}
+		switch {
+		case diff <= 2:
+			score += 30
+		case diff <= 5:
+			score += 15
+		case diff <= 10:
+			score += 5
+		case diff > 30:
+			score -= 30
+		}
+		reasons = append(reasons, fmt.Sprintf("duration=±%ds", diff))
+	}
+
+	return score, strings.Join(reasons, ", ")
+}
+
+func PickBestVersion(target VersionTarget, candidates []VersionCandidate) int {
+	best := -1
+	bestScore := 0
+	bestReason := ""
+	for i, candidate := range candidates {
+		score, reason := ScoreVersion(target, candidate)
+		if best == -1 || score > bestScore {
+			best, bestScore, bestReason = i, score, reason
+		}
+	}
+
+	if best >= 0 {
+		chosen := candidates[best]
+		fmt.Printf("[VersionMatch] %s - %s: picked \"%s\" from \"%s\" (score %d of %d candidate(s); %s)\n",
+			target.Artist, target.Title, chosen.Title, chosen.Album, bestScore, len(candidates), bestReason)
+	}
+	return best
+}
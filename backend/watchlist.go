@@ -0,0 +1,250 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	watchlistDBFile            = "watchlist.db"
+	watchlistBucket            = "Watchlist"
+	defaultWatchIntervalMins   = 360
+	minimumWatchIntervalMins   = 15
+	watchlistEntryTypeAlbum    = "album"
+	watchlistEntryTypeArtist   = "artist"
+	watchlistEntryTypePlaylist = "playlist"
+)
+
+type WatchlistEntry struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Type          string   `json:"type"`
+	Name          string   `json:"name"`
+	KnownTrackIDs []string `json:"known_track_ids"`
+	KnownAlbumIDs []string `json:"known_album_ids,omitempty"`
+	AddedAt       int64    `json:"added_at"`
+	LastChecked   int64    `json:"last_checked"`
+	LastNewTracks int      `json:"last_new_tracks"`
+	LastNewAlbums []string `json:"last_new_albums,omitempty"`
+	LastError     string   `json:"last_error,omitempty"`
+}
+
+var (
+	watchlistDB   *bolt.DB
+	watchlistDBMu sync.Mutex
+)
+
+func InitWatchlistDB() error {
+	watchlistDBMu.Lock()
+	defer watchlistDBMu.Unlock()
+
+	if watchlistDB != nil {
+		return nil
+	}
+
+	appDir, err := EnsureAppDir()
+	if err != nil {
+		return err
+	}
+
+	dbPath := filepath.Join(appDir, watchlistDBFile)
+	db, err := bolt.Open(dbPath, 0o600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(watchlistBucket))
+		return err
+	}); err != nil {
+		db.Close()
+		return err
+	}
+
+	watchlistDB = db
+	return nil
+}
+
+func CloseWatchlistDB() {
+	watchlistDBMu.Lock()
+	defer watchlistDBMu.Unlock()
+
+	if watchlistDB != nil {
+		_ = watchlistDB.Close()
+		watchlistDB = nil
+	}
+}
+
+func getWatchlistDB() (*bolt.DB, error) {
+	if err := InitWatchlistDB(); err != nil {
+		return nil, err
+	}
+
+	watchlistDBMu.Lock()
+	defer watchlistDBMu.Unlock()
+	return watchlistDB, nil
+}
+
+func WatchlistEntryTypeFromURL(spotifyURL string) (string, string, error) {
+	parsed, err := parseSpotifyURI(spotifyURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch parsed.Type {
+	case "playlist":
+		return watchlistEntryTypePlaylist, parsed.ID, nil
+	case "album":
+		return watchlistEntryTypeAlbum, parsed.ID, nil
+	case "artist", "artist_discography":
+		return watchlistEntryTypeArtist, parsed.ID, nil
+	default:
+		return "", "", fmt.Errorf("cannot watch Spotify %s URLs", parsed.Type)
+	}
+}
+
+func SaveWatchlistEntry(entry WatchlistEntry) error {
+	if strings.TrimSpace(entry.ID) == "" {
+		return fmt.Errorf("watchlist entry ID is required")
+	}
+
+	db, err := getWatchlistDB()
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(watchlistBucket))
+		if err != nil {
+			return err
+		}
+
+		buf, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(entry.ID), buf)
+	})
+}
+
+func GetWatchlistEntry(id string) (*WatchlistEntry, error) {
+	db, err := getWatchlistDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *WatchlistEntry
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(watchlistBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		raw := bucket.Get([]byte(id))
+		if raw == nil {
+			return nil
+		}
+
+		var decoded WatchlistEntry
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return err
+		}
+		entry = &decoded
+		return nil
+	})
+
+	return entry, err
+}
+
+func GetWatchlistEntries() ([]WatchlistEntry, error) {
+	db, err := getWatchlistDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []WatchlistEntry
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(watchlistBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(_, v []byte) error {
+			var entry WatchlistEntry
+			if err := json.Unmarshal(v, &entry); err == nil {
+				entries = append(entries, entry)
+			}
+			return nil
+		})
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].AddedAt < entries[j].AddedAt
+	})
+
+	return entries, err
+}
+
+func DeleteWatchlistEntry(id string) error {
+	db, err := getWatchlistDB()
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(watchlistBucket))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+func (e *WatchlistEntry) NewTrackIDs(trackIDs []string) []string {
+	return unseenWatchlistIDs(e.KnownTrackIDs, trackIDs)
+}
+
+func (e *WatchlistEntry) MarkTracksKnown(trackIDs []string) {
+	e.KnownTrackIDs = mergeWatchlistIDs(e.KnownTrackIDs, trackIDs)
+}
+
+func (e *WatchlistEntry) NewAlbumIDs(albumIDs []string) []string {
+	return unseenWatchlistIDs(e.KnownAlbumIDs, albumIDs)
+}
+
+func (e *WatchlistEntry) MarkAlbumsKnown(albumIDs []string) {
+	e.KnownAlbumIDs = mergeWatchlistIDs(e.KnownAlbumIDs, albumIDs)
+}
+
+func unseenWatchlistIDs(knownIDs, ids []string) []string {
+	known := make(map[string]struct{}, len(knownIDs))
+	for _, id := range knownIDs {
+		known[id] = struct{}{}
+	}
+
+	var fresh []string
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, ok := known[id]; ok {
+			continue
+		}
+		known[id] = struct{}{}
+		fresh = append(fresh, id)
+	}
+
+	return fresh
+}
+
+func mergeWatchlistIDs(knownIDs, ids []string) []string {
+	return append(knownIDs, unseenWatchlistIDs(knownIDs, ids)...)
+}
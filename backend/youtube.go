@@ -0,0 +1,271 @@
+package backend
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	YouTubeFallbackFormatM4A  = "m4a"
+	YouTubeFallbackFormatFLAC = "flac"
+	youtubeLossyTag           = "LOSSY"
+	youtubeSourceLabel        = "YouTube Music"
+)
+
+type YouTubeDownloader struct{}
+
+func NewYouTubeDownloader() *YouTubeDownloader {
+	return &YouTubeDownloader{}
+}
+
+func NormalizeYouTubeFallbackFormat(format string) string {
+	if strings.EqualFold(strings.TrimSpace(format), YouTubeFallbackFormatFLAC) {
+		return YouTubeFallbackFormatFLAC
+	}
+	return YouTubeFallbackFormatM4A
+}
+
+func GetYtDlpPath() (string, error) {
+	executableName := "yt-dlp"
+	if runtime.GOOS == "windows" {
+		executableName = "yt-dlp.exe"
+	}
+
+	var candidates []string
+	if ffmpegDir, err := GetFFmpegDir(); err == nil {
+		localPath := filepath.Join(ffmpegDir, executableName)
+		if _, err := os.Stat(localPath); err == nil {
+			candidates = append(candidates, localPath)
+		}
+	}
+	if systemPath := resolveSystemExecutable(executableName); systemPath != "" {
+		candidates = append(candidates, systemPath)
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		if err := ValidateExecutable(candidate); err != nil {
+			lastErr = err
+			continue
+		}
+		cmd := exec.Command(candidate, "--version")
+		setHideWindow(cmd)
+		if err := cmd.Run(); err != nil {
+			lastErr = err
+			continue
+		}
+		return candidate, nil
+	}
+
+	if lastErr != nil {
+		return "", fmt.Errorf("no working %s executable found: %w", executableName, lastErr)
+	}
+	return "", fmt.Errorf("%s not found in app directory or system path", executableName)
+}
+
+func IsYouTubeMusicURL(rawURL string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	return host == "music.youtube.com" || host == "youtube.com" || host == "www.youtube.com" || host == "youtu.be"
+}
+
+func buildYouTubeMusicSearchURL(trackName, artistName string) string {
+	query := strings.TrimSpace(GetFirstArtist(artistName) + " " + trackName)
+	return "https://music.youtube.com/search?q=" + url.QueryEscape(query) + "#songs"
+}
+
+func (y *YouTubeDownloader) fetchAudio(source, format, tempDir string) (string, error) {
+	ytDlpPath, err := GetYtDlpPath()
+	if err != nil {
+		return "", err
+	}
+
+	audioFormat := "bestaudio"
+	if format == YouTubeFallbackFormatM4A {
+		audioFormat = "bestaudio[ext=m4a]/bestaudio"
+	}
+
+	args := []string{
+		"--no-progress",
+		"--no-warnings",
+		"--playlist-items", "1",
+		"-f", audioFormat,
+		"-o", filepath.Join(tempDir, "%(id)s.%(ext)s"),
+		"--print", "after_move:filepath",
+	}
+	if ffmpegPath, err := GetFFmpegPath(); err == nil {
+		args = append(args, "--ffmpeg-location", ffmpegPath)
+	}
+	args = append(args, source)
+
+	cmd := exec.Command(ytDlpPath, args...)
+	setHideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("yt-dlp failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("yt-dlp failed: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	filePath := strings.TrimSpace(lines[len(lines)-1])
+	if filePath == "" || !fileExists(filePath) {
+		return "", fmt.Errorf("no YouTube Music result found")
+	}
+	return filePath, nil
+}
+
+func convertYouTubeAudio(inputPath, outputPath, format string) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	args := []string{"-i", inputPath, "-y", "-map", "0:a:0", "-map_metadata", "-1"}
+	switch {
+	case format == YouTubeFallbackFormatFLAC:
+		args = append(args, "-codec:a", "flac")
+	case strings.EqualFold(filepath.Ext(inputPath), ".m4a"):
+		args = append(args, "-codec:a", "copy")
+	default:
+		args = append(args, "-codec:a", "aac", "-b:a", "256k")
+	}
+	partialPath := PartialDownloadPath(outputPath)
+	if muxer := partialDownloadMuxer(outputPath); muxer != "" {
+		args = append(args, "-f", muxer)
+	}
+	args = append(args, partialPath)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(partialPath)
+		return fmt.Errorf("ffmpeg conversion failed: %s - %w", string(output), err)
+	}
+	return commitPartialDownload(partialPath, outputPath)
+}
+
+func (y *YouTubeDownloader) Download(serviceURL, outputDir, format, filenameFormat, playlistName, playlistOwner string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, spotifyCoverURL string, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, embedMaxQualityCover bool, spotifyTotalDiscs int, spotifyCopyright, spotifyPublisher, spotifyComposer, metadataSeparator, isrc, spotifyURL string, useFirstArtistOnly bool) (string, error) {
+	format = NormalizeYouTubeFallbackFormat(format)
+
+	if err := EnsureOutputDir(outputDir); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	source := strings.TrimSpace(serviceURL)
+	if !IsYouTubeMusicURL(source) {
+		if spotifyTrackName == "" || spotifyArtistName == "" {
+			return "", fmt.Errorf("track name and artist are required to search YouTube Music")
+		}
+		source = buildYouTubeMusicSearchURL(spotifyTrackName, spotifyArtistName)
+	}
+
+	filenameArtist := spotifyArtistName
+	filenameAlbumArtist := spotifyAlbumArtist
+	if useFirstArtistOnly {
+		filenameArtist = GetFirstArtist(spotifyArtistName)
+		filenameAlbumArtist = GetFirstArtist(spotifyAlbumArtist)
+	}
+	templateData := FilenameTemplateData{
+		Title:       spotifyTrackName,
+		Artist:      filenameArtist,
+		Album:       spotifyAlbumName,
+		AlbumArtist: filenameAlbumArtist,
+		ReleaseDate: spotifyReleaseDate,
+		Playlist:    playlistName,
+		Creator:     playlistOwner,
+		ISRC:        isrc,
+		Track:       position,
+		Disc:        spotifyDiscNumber,
+		Quality:     youtubeLossyTag,
+		Service:     "youtube",
+	}
+	outputPath := filepath.Join(outputDir, buildTemplatedFilename(filenameFormat, templateData, includeTrackNumber, position, "%02d. ")+"."+format)
+
+	outputPath, alreadyExists, releaseOutput := ReserveOutputPathForDownload(outputPath, GetRedownloadWithSuffixSetting())
+	defer releaseOutput()
+	if alreadyExists {
+		fmt.Printf("File already exists: %s (%.2f MB)\n", outputPath, float64(mustFileSize(outputPath))/(1024*1024))
+		return "EXISTS:" + outputPath, nil
+	}
+
+	tempDir, err := os.MkdirTemp(StagingDir(), "spotiflac-youtube-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	fmt.Printf("Searching YouTube Music (lossy fallback): %s\n", source)
+	audioPath, err := y.fetchAudio(source, format, tempDir)
+	if err != nil {
+		return "", err
+	}
+
+	if err := convertYouTubeAudio(audioPath, outputPath, format); err != nil {
+		os.Remove(outputPath)
+		return "", err
+	}
+
+	coverPath := ""
+	if spotifyCoverURL != "" {
+		coverPath = outputPath + ".cover.jpg"
+		if err := NewCoverClient().DownloadCoverToPath(spotifyCoverURL, coverPath, embedMaxQualityCover); err != nil {
+			fmt.Printf("Warning: Failed to download Spotify cover: %v\n", err)
+			coverPath = ""
+		} else {
+			defer os.Remove(coverPath)
+			coverPath = PrepareCoverForEmbedding(coverPath)
+		}
+	}
+
+	trackNumberToEmbed := spotifyTrackNumber
+	if trackNumberToEmbed == 0 {
+		trackNumberToEmbed = 1
+	}
+
+	metadata := Metadata{
+		Title:       spotifyTrackName,
+		Artist:      spotifyArtistName,
+		Album:       spotifyAlbumName,
+		AlbumArtist: spotifyAlbumArtist,
+		Date:        spotifyReleaseDate,
+		TrackNumber: trackNumberToEmbed,
+		TotalTracks: spotifyTotalTracks,
+		DiscNumber:  spotifyDiscNumber,
+		TotalDiscs:  spotifyTotalDiscs,
+		URL:         spotifyURL,
+		Comment:     fmt.Sprintf("%s source: %s", youtubeLossyTag, youtubeSourceLabel),
+		Copyright:   spotifyCopyright,
+		Publisher:   spotifyPublisher,
+		Composer:    spotifyComposer,
+		Separator:   metadataSeparator,
+		Description: "https://github.com/spotbye/SpotiFLAC",
+		ISRC:        isrc,
+		ExtraTags: map[string]string{
+			"SOURCE":        youtubeSourceLabel,
+			"SOURCEQUALITY": youtubeLossyTag,
+		},
+	}
+
+	if err := EmbedMetadataToConvertedFile(outputPath, metadata, coverPath); err != nil {
+		fmt.Printf("Warning: Failed to embed metadata: %v\n", err)
+	} else {
+		fmt.Println("Metadata embedded successfully")
+	}
+
+	fmt.Println("Done")
+	return outputPath, nil
+}
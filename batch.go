@@ -0,0 +1,614 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+type batchDownloadSettings struct {
+	DownloadPath         string
+	Downloader           string
+	AutoOrder            string
+	AutoQuality          string
+	ServiceStrategy      string
+	QualityFloorBitDepth int
+	QualityFloorRate     int
+	TidalQuality         string
+	QobuzQuality         string
+	CustomTidalAPI       string
+	FolderTemplate       string
+	FilenameTemplate     string
+	Separator            string
+	TrackNumber          bool
+	EmbedLyrics          bool
+	EmbedMaxQualityCover bool
+	AllowFallback        bool
+	CreatePlaylistFolder bool
+	CreateM3U8File       bool
+	PreferOriginalYear   bool
+	ManifestFormat       string
+	UseFirstArtistOnly   bool
+	UseSingleGenre       bool
+	EmbedGenre           bool
+	ConvertTo            string
+	AllowedServices      []string
+	StrictService        bool
+	SkipRules            backend.SkipRules
+}
+
+type BatchDownloadResult struct {
+	URL        string                          `json:"url"`
+	Name       string                          `json:"name"`
+	Total      int                             `json:"total"`
+	Downloaded int                             `json:"downloaded"`
+	Skipped    int                             `json:"skipped"`
+	Failed     int                             `json:"failed"`
+	Files      []string                        `json:"files,omitempty"`
+	Errors     []string                        `json:"errors,omitempty"`
+	FailedIDs  []string                        `json:"failed_ids,omitempty"`
+	Manifest   string                          `json:"manifest,omitempty"`
+	Entries    []backend.PlaylistManifestEntry `json:"entries,omitempty"`
+}
+
+type spotifyTrackList struct {
+	URL          string
+	Name         string
+	Type         string
+	PlaylistName string
+	Positions    []int
+	ItemIDs      []string
+	OutputDirs   []string
+	Tracks       []backend.AlbumTrackMetadata
+	Albums       []backend.DiscographyAlbumMetadata
+}
+
+func loadBatchDownloadSettings() batchDownloadSettings {
+	settings := batchDownloadSettings{
+		DownloadPath:         backend.GetDefaultMusicPath(),
+		Downloader:           "auto",
+		AutoOrder:            "tidal-qobuz-amazon",
+		AutoQuality:          "16",
+		TidalQuality:         "LOSSLESS",
+		QobuzQuality:         "6",
+		FilenameTemplate:     "{title} - {artist}",
+		Separator:            "; ",
+		AllowFallback:        true,
+		CreatePlaylistFolder: true,
+	}
+
+	raw, err := backend.LoadConfigSettings()
+	if err != nil || raw == nil {
+		return settings
+	}
+
+	stringValue := func(key string, target *string) {
+		if value, ok := raw[key].(string); ok && strings.TrimSpace(value) != "" {
+			*target = strings.TrimSpace(value)
+		}
+	}
+	boolValue := func(key string, target *bool) {
+		if value, ok := raw[key].(bool); ok {
+			*target = value
+		}
+	}
+
+	stringValue("downloadPath", &settings.DownloadPath)
+	stringValue("downloader", &settings.Downloader)
+	stringValue("autoOrder", &settings.AutoOrder)
+	stringValue("autoQuality", &settings.AutoQuality)
+	stringValue("tidalQuality", &settings.TidalQuality)
+	stringValue("qobuzQuality", &settings.QobuzQuality)
+	stringValue("folderTemplate", &settings.FolderTemplate)
+	stringValue("filenameTemplate", &settings.FilenameTemplate)
+	boolValue("trackNumber", &settings.TrackNumber)
+	boolValue("embedLyrics", &settings.EmbedLyrics)
+	boolValue("embedMaxQualityCover", &settings.EmbedMaxQualityCover)
+	boolValue("allowFallback", &settings.AllowFallback)
+	boolValue("createPlaylistFolder", &settings.CreatePlaylistFolder)
+	boolValue("createM3u8File", &settings.CreateM3U8File)
+	boolValue("useFirstArtistOnly", &settings.UseFirstArtistOnly)
+	boolValue("useSingleGenre", &settings.UseSingleGenre)
+	boolValue("embedGenre", &settings.EmbedGenre)
+
+	if sep, ok := raw["separator"].(string); ok && sep == "comma" {
+		settings.Separator = ", "
+	}
+	settings.CustomTidalAPI = backend.GetCustomTidalAPISetting()
+	settings.PreferOriginalYear = backend.GetPreferOriginalYearSetting()
+	settings.ManifestFormat = backend.GetManifestFormatSetting()
+	settings.ServiceStrategy = backend.GetServiceStrategySetting()
+	settings.QualityFloorBitDepth, settings.QualityFloorRate = backend.GetQualityFloorSetting()
+	settings.AllowedServices, settings.StrictService = backend.GetAllowedServicesSetting()
+	settings.SkipRules = backend.GetSkipRulesSetting()
+
+	return settings
+}
+
+func trackMetadataToAlbumTrack(track backend.TrackMetadata) backend.AlbumTrackMetadata {
+	return backend.AlbumTrackMetadata{
+		SpotifyID:   track.SpotifyID,
+		Artists:     track.Artists,
+		Name:        track.Name,
+		AlbumName:   track.AlbumName,
+		AlbumArtist: track.AlbumArtist,
+		DurationMS:  track.DurationMS,
+		Images:      track.Images,
+		ReleaseDate: track.ReleaseDate,
+		TrackNumber: track.TrackNumber,
+		TotalTracks: track.TotalTracks,
+		DiscNumber:  track.DiscNumber,
+		TotalDiscs:  track.TotalDiscs,
+		ExternalURL: track.ExternalURL,
+		AlbumID:     track.AlbumID,
+		AlbumURL:    track.AlbumURL,
+		ArtistID:    track.ArtistID,
+		ArtistURL:   track.ArtistURL,
+		ArtistsData: track.ArtistsData,
+		UPC:         track.UPC,
+		PreviewURL:  track.PreviewURL,
+		IsExplicit:  track.IsExplicit,
+	}
+}
+
+func fetchSpotifyTrackList(ctx context.Context, spotifyURL string, separator string) (spotifyTrackList, error) {
+	if resolved, err := backend.ResolveSpotifyURL(ctx, spotifyURL); err == nil {
+		spotifyURL = resolved
+	}
+
+	data, err := backend.GetFilteredSpotifyData(ctx, spotifyURL, true, time.Second, separator, nil)
+	if err != nil {
+		return spotifyTrackList{}, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+
+	switch payload := data.(type) {
+	case backend.TrackResponse:
+		return spotifyTrackList{
+			URL:    spotifyURL,
+			Name:   payload.Track.Name,
+			Type:   "track",
+			Tracks: []backend.AlbumTrackMetadata{trackMetadataToAlbumTrack(payload.Track)},
+		}, nil
+	case *backend.AlbumResponsePayload:
+		return spotifyTrackList{
+			URL:    spotifyURL,
+			Name:   payload.AlbumInfo.Name,
+			Type:   "album",
+			Tracks: payload.TrackList,
+		}, nil
+	case backend.PlaylistResponsePayload:
+		name := strings.TrimSpace(payload.PlaylistInfo.Owner.Name)
+		return spotifyTrackList{
+			URL:          spotifyURL,
+			Name:         name,
+			Type:         "playlist",
+			PlaylistName: name,
+			Tracks:       payload.TrackList,
+		}, nil
+	case *backend.ArtistDiscographyPayload:
+		return spotifyTrackList{
+			URL:    spotifyURL,
+			Name:   payload.ArtistInfo.Name,
+			Type:   "artist",
+			Tracks: payload.TrackList,
+			Albums: payload.AlbumList,
+		}, nil
+	case backend.EpisodeResponse:
+		return spotifyTrackList{}, fmt.Errorf("%s is a podcast %s and has no tracks to download: %s", spotifyURL, payload.Type, payload.Message)
+	default:
+		return spotifyTrackList{}, fmt.Errorf("unsupported metadata response for %s", spotifyURL)
+	}
+}
+
+type batchTrackTarget struct {
+	ArtistName     string
+	AlbumArtist    string
+	OutputDir      string
+	TrackNumber    int
+	UseAlbumNumber bool
+}
+
+func resolveBatchTrackTarget(track backend.AlbumTrackMetadata, playlistName string, position int, settings batchDownloadSettings) batchTrackTarget {
+	artistName := track.Artists
+	albumArtist := track.AlbumArtist
+	if settings.UseFirstArtistOnly {
+		artistName = backend.GetFirstArtist(artistName)
+		albumArtist = backend.GetFirstArtist(albumArtist)
+	}
+
+	outputDir := settings.DownloadPath
+	folderTemplate := settings.FolderTemplate
+	hasSubfolder := strings.TrimSpace(folderTemplate) != ""
+	useAlbumSubfolder := strings.Contains(folderTemplate, "{album}") || strings.Contains(folderTemplate, "{album_artist}") || strings.Contains(folderTemplate, "{albumartist}") || strings.Contains(folderTemplate, "{playlist}")
+	if settings.CreatePlaylistFolder && playlistName != "" && !useAlbumSubfolder {
+		outputDir = filepath.Join(outputDir, backend.SanitizeFilename(playlistName))
+	}
+
+	trackNumberForTemplate := position
+	if hasSubfolder && track.TrackNumber > 0 {
+		trackNumberForTemplate = track.TrackNumber
+	}
+
+	if hasSubfolder {
+		isrc := ""
+		if strings.Contains(folderTemplate, "{isrc}") && track.SpotifyID != "" {
+			isrc = backend.ResolveTrackISRC(track.SpotifyID)
+		}
+		folderReleaseDate := track.ReleaseDate
+		if settings.PreferOriginalYear && (strings.Contains(folderTemplate, "{year}") || strings.Contains(folderTemplate, "{date}")) && track.SpotifyID != "" {
+			if isrc == "" {
+				isrc = backend.ResolveTrackISRC(track.SpotifyID)
+			}
+			if isrc != "" {
+				if originalDate, err := backend.GetQobuzOriginalReleaseDate(isrc); err == nil && originalDate != "" {
+					folderReleaseDate = originalDate
+				}
+			}
+		}
+		outputDir = filepath.Join(outputDir, backend.BuildFolderTemplatePath(folderTemplate, track.Name, artistName, track.AlbumName, albumArtist, folderReleaseDate, playlistName, isrc, trackNumberForTemplate, track.DiscNumber))
+	}
+
+	return batchTrackTarget{
+		ArtistName:     artistName,
+		AlbumArtist:    albumArtist,
+		OutputDir:      outputDir,
+		TrackNumber:    trackNumberForTemplate,
+		UseAlbumNumber: hasSubfolder,
+	}
+}
+
+func (a *App) downloadSpotifyTrack(ctx context.Context, track backend.AlbumTrackMetadata, playlistName string, position int, itemID, outputDir string, settings batchDownloadSettings) (response DownloadResponse, err error) {
+	target := resolveBatchTrackTarget(track, playlistName, position, settings)
+	if outputDir != "" {
+		target.OutputDir = outputDir
+	}
+	artistName := target.ArtistName
+
+	baseReq := DownloadRequest{
+		TrackName:            track.Name,
+		ArtistName:           artistName,
+		AlbumName:            track.AlbumName,
+		AlbumArtist:          target.AlbumArtist,
+		ReleaseDate:          track.ReleaseDate,
+		CoverURL:             track.Images,
+		OutputDir:            target.OutputDir,
+		FilenameFormat:       settings.FilenameTemplate,
+		TrackNumber:          settings.TrackNumber,
+		Position:             target.TrackNumber,
+		UseAlbumTrackNumber:  target.UseAlbumNumber,
+		SpotifyID:            track.SpotifyID,
+		EmbedLyrics:          settings.EmbedLyrics,
+		EmbedMaxQualityCover: settings.EmbedMaxQualityCover,
+		Duration:             (track.DurationMS + 500) / 1000,
+		SpotifyTrackNumber:   track.TrackNumber,
+		SpotifyDiscNumber:    track.DiscNumber,
+		SpotifyTotalTracks:   track.TotalTracks,
+		SpotifyTotalDiscs:    track.TotalDiscs,
+		AllowFallback:        settings.AllowFallback,
+		UseFirstArtistOnly:   settings.UseFirstArtistOnly,
+		UseSingleGenre:       settings.UseSingleGenre,
+		EmbedGenre:           settings.EmbedGenre,
+		Separator:            settings.Separator,
+		ConvertTo:            settings.ConvertTo,
+		AllowedServices:      settings.AllowedServices,
+		ItemID:               itemID,
+	}
+	if settings.StrictService {
+		baseReq.AllowFallback = false
+	}
+
+	if playlistName != "" {
+		baseReq.SourcePlaylist = playlistName
+		baseReq.PlaylistPosition = position
+	}
+
+	hookReq := baseReq
+	defer func() {
+		fireDownloadHook(hookReq, response, err)
+	}()
+
+	if settings.Downloader != "auto" {
+		req := baseReq
+		req.Service = settings.Downloader
+		switch req.Service {
+		case "tidal":
+			req.AudioFormat = settings.TidalQuality
+			req.TidalAPIURL = settings.CustomTidalAPI
+		case "qobuz":
+			req.AudioFormat = settings.QobuzQuality
+		}
+		hookReq = req
+		return a.downloadTrack(ctx, req)
+	}
+
+	if itemID == "" {
+		itemID = a.AddToDownloadQueue(track.SpotifyID, track.Name, artistName, track.AlbumName)
+	}
+	baseReq.ItemID = itemID
+	hookReq = baseReq
+
+	order := strings.Split(settings.AutoOrder, "-")
+	if strings.TrimSpace(settings.AutoOrder) == "" {
+		order = registeredServiceNames()
+	}
+	var availability *backend.AvailabilityMatrix
+	if track.SpotifyID != "" {
+		matrix, err := backend.CheckAvailability(track.SpotifyID, "")
+		if err != nil {
+			fmt.Printf("Failed to check availability for %s: %v\n", track.SpotifyID, err)
+		}
+		availability = matrix
+		if matrix != nil && matrix.ISRC != "" {
+			baseReq.ISRC = matrix.ISRC
+		}
+	}
+
+	tidalQuality := "LOSSLESS"
+	qobuzQuality := "6"
+	if settings.AutoQuality == "24" {
+		tidalQuality = "HI_RES_LOSSLESS"
+		qobuzQuality = "27"
+	}
+
+	if settings.ServiceStrategy == backend.ServiceStrategyBestQuality && availability != nil {
+		order = availability.RankServices(order, settings.QualityFloorBitDepth, settings.QualityFloorRate)
+		tidalQuality = "HI_RES_LOSSLESS"
+		qobuzQuality = "27"
+		fmt.Printf("[BestQuality] Service order for %s: %s\n", track.Name, strings.Join(order, " > "))
+	}
+
+	if backend.GetYouTubeFallbackSetting() && !settings.StrictService && !slices.Contains(order, "youtube") {
+		order = append(order, "youtube")
+	}
+	order = filterAllowedServices(order, settings.AllowedServices)
+
+	var fallbackErrors []string
+	lastResponse := DownloadResponse{Success: false, Error: "No matching services found", ItemID: itemID}
+	for _, service := range order {
+		req := baseReq
+		req.Service = service
+
+		switch service {
+		case "tidal":
+			if !availability.IsAvailable("tidal") {
+				continue
+			}
+			req.ServiceURL = availability.Service("tidal").URL
+			req.AudioFormat = tidalQuality
+			req.TidalAPIURL = settings.CustomTidalAPI
+		case "amazon":
+			if !availability.IsAvailable("amazon") {
+				continue
+			}
+			req.ServiceURL = availability.Service("amazon").URL
+		case "qobuz":
+			if availability.IsNotFound("qobuz") {
+				continue
+			}
+			req.AudioFormat = qobuzQuality
+		case "youtube":
+			if !backend.GetYouTubeFallbackSetting() && !slices.Contains(settings.AllowedServices, "youtube") {
+				continue
+			}
+			if rule := backend.GetMinQualitySetting(); rule.Enabled() && rule.Skip {
+				continue
+			}
+			req.ServiceURL = ""
+			req.AudioFormat = "LOSSY"
+			if len(fallbackErrors) > 0 {
+				fmt.Printf("No lossless source for %s, falling back to YouTube Music (lossy)\n", track.Name)
+			}
+		default:
+			if _, ok := lookupServiceDownloader(service); !ok {
+				continue
+			}
+		}
+
+		hookReq = req
+		response, err := a.downloadTrack(ctx, req)
+		if err == nil && response.Success {
+			return response, nil
+		}
+		if ctx.Err() != nil {
+			return response, ctx.Err()
+		}
+
+		errMsg := response.Error
+		if errMsg == "" && err != nil {
+			errMsg = err.Error()
+		}
+		fallbackErrors = append(fallbackErrors, fmt.Sprintf("[%s] %s", strings.ToUpper(service[:1])+service[1:], errMsg))
+		lastResponse = response
+		if settings.StrictService {
+			break
+		}
+	}
+
+	finalError := lastResponse.Error
+	if len(fallbackErrors) > 0 {
+		finalError = strings.Join(fallbackErrors, " | ")
+	}
+	backend.FailDownloadItem(itemID, finalError)
+	lastResponse.Error = finalError
+	return lastResponse, fmt.Errorf("%s", finalError)
+}
+
+func (a *App) downloadSpotifyTracks(ctx context.Context, list spotifyTrackList, settings batchDownloadSettings) BatchDownloadResult {
+	result := BatchDownloadResult{
+		URL:   list.URL,
+		Name:  list.Name,
+		Total: len(list.Tracks),
+	}
+
+	var skip *backend.SkipMatcher
+	if settings.SkipRules.Enabled() {
+		skip = backend.NewSkipMatcher(settings.SkipRules)
+	}
+
+	for i, track := range list.Tracks {
+		if ctx.Err() != nil {
+			result.Errors = append(result.Errors, ctx.Err().Error())
+			break
+		}
+
+		position := i + 1
+		if i < len(list.Positions) && list.Positions[i] > 0 {
+			position = list.Positions[i]
+		}
+		itemID, outputDir := "", ""
+		if i < len(list.ItemIDs) {
+			itemID = list.ItemIDs[i]
+		}
+		if i < len(list.OutputDirs) {
+			outputDir = list.OutputDirs[i]
+		}
+
+		if reason := skip.Match(track); reason != "" {
+			fmt.Printf("Skipping %s - %s: %s\n", track.Name, track.Artists, reason)
+			if itemID != "" {
+				backend.SkipDownloadItem(itemID, "")
+			}
+			result.Skipped++
+			result.Entries = append(result.Entries, backend.PlaylistManifestEntry{
+				Position:  position,
+				SpotifyID: track.SpotifyID,
+				Track:     track.Name,
+				Artist:    track.Artists,
+				Album:     track.AlbumName,
+				Status:    backend.ManifestStatusSkipped,
+				Error:     reason,
+			})
+			continue
+		}
+
+		response, err := a.downloadSpotifyTrack(ctx, track, list.PlaylistName, position, itemID, outputDir, settings)
+		entry := backend.PlaylistManifestEntry{
+			Position:  position,
+			SpotifyID: track.SpotifyID,
+			Track:     track.Name,
+			Artist:    track.Artists,
+			Album:     track.AlbumName,
+			Service:   response.Service,
+			Quality:   response.Quality,
+			FilePath:  response.File,
+		}
+		switch {
+		case err != nil || !response.Success:
+			result.Failed++
+			errMsg := response.Error
+			if errMsg == "" && err != nil {
+				errMsg = err.Error()
+			}
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: %s", track.Name, track.Artists, errMsg))
+			if track.SpotifyID != "" {
+				result.FailedIDs = append(result.FailedIDs, track.SpotifyID)
+			}
+			entry.Status = backend.ManifestStatusFailed
+			entry.Error = errMsg
+		case response.Skipped:
+			result.Skipped++
+			entry.Status = backend.ManifestStatusSkipped
+			entry.Error = response.Message
+		case response.AlreadyExists:
+			result.Skipped++
+			if response.File != "" {
+				result.Files = append(result.Files, response.File)
+			}
+			entry.Status = backend.ManifestStatusSkipped
+		default:
+			result.Downloaded++
+			result.Files = append(result.Files, response.File)
+			entry.Status = backend.ManifestStatusDownloaded
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	if list.Type == "album" && backend.GetGaplessCheckSetting() {
+		checkAlbumGapless(result.Files, backend.GetGaplessRepairSetting())
+	}
+	if list.Type == "album" && backend.GetCreateCueSheetSetting() {
+		writeAlbumCueSheets(result.Files)
+	}
+
+	playlistDir := settings.DownloadPath
+	if settings.CreatePlaylistFolder && list.PlaylistName != "" {
+		playlistDir = filepath.Join(playlistDir, backend.SanitizeFilename(list.PlaylistName))
+	}
+
+	if settings.CreateM3U8File && list.PlaylistName != "" && len(result.Files) > 0 {
+		fmt.Printf("Creating m3u8 playlist: %s\n", list.PlaylistName)
+		if err := a.CreateM3U8File(list.PlaylistName, playlistDir, result.Files); err != nil {
+			fmt.Printf("Warning: failed to create m3u8 playlist: %v\n", err)
+		}
+	}
+
+	if settings.ManifestFormat != "" && len(result.Entries) > 0 {
+		manifestPath := backend.BuildPlaylistManifestPath(playlistDir, list.Name, settings.ManifestFormat)
+		manifest := backend.PlaylistManifest{
+			Name:     list.Name,
+			URL:      list.URL,
+			Playlist: list.PlaylistName,
+			Entries:  result.Entries,
+		}
+		if err := backend.WritePlaylistManifest(manifestPath, manifest); err != nil {
+			fmt.Printf("Warning: failed to write manifest: %v\n", err)
+		} else {
+			result.Manifest = manifestPath
+		}
+	}
+
+	if list.Type == "album" || list.Type == "playlist" {
+		fireBatchHook(list.Name, list.Type, result.Files, result.Downloaded, result.Skipped, result.Failed)
+	}
+
+	return result
+}
+
+func groupFilesByFolder(files []string) ([]string, map[string][]string) {
+	byFolder := make(map[string][]string)
+	var folders []string
+	for _, file := range files {
+		folder := filepath.Dir(file)
+		if _, ok := byFolder[folder]; !ok {
+			folders = append(folders, folder)
+		}
+		byFolder[folder] = append(byFolder[folder], file)
+	}
+	return folders, byFolder
+}
+
+func writeAlbumCueSheets(files []string) {
+	folders, byFolder := groupFilesByFolder(files)
+	for _, folder := range folders {
+		cuePath, err := backend.WriteAlbumCueSheet(folder, byFolder[folder])
+		if err != nil {
+			fmt.Printf("Warning: failed to create cue sheet for %s: %v\n", folder, err)
+			continue
+		}
+		fmt.Printf("Cue sheet created: %s\n", cuePath)
+	}
+}
+
+func checkAlbumGapless(files []string, repair bool) {
+	folders, byFolder := groupFilesByFolder(files)
+	for _, folder := range folders {
+		report := backend.CheckAlbumGapless(byFolder[folder])
+		for _, issue := range report.Issues {
+			fmt.Printf("[Gapless] %s: %s (%s)\n", filepath.Base(issue.Path), issue.Kind, issue.Detail)
+		}
+		if repair {
+			backend.RepairAlbumGapless(report)
+			if len(report.Repaired) > 0 {
+				fmt.Printf("[Gapless] Repaired %d track(s) in %s\n", len(report.Repaired), folder)
+			}
+		}
+		for _, errMsg := range report.Errors {
+			fmt.Printf("Warning: gapless check: %s\n", errMsg)
+		}
+	}
+}
@@ -0,0 +1,1375 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func runCLI(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "serve":
+		return true, runHeadless(args[1:])
+	case "download":
+		return true, runDownloadCommand(args[1:])
+	case "track":
+		return true, runTrackCommand(args[1:])
+	case "search":
+		return true, runSearchCommand(args[1:])
+	case "config":
+		return true, runConfigCommand(args[1:])
+	case "credentials":
+		return true, runCredentialsCommand(args[1:])
+	case "retag":
+		return true, runRetagCommand(args[1:])
+	case "debug-bundle":
+		return true, runDebugBundleCommand(args[1:])
+	case "failed":
+		return true, runFailedCommand(args[1:])
+	case "retry":
+		return true, runRetryCommand(args[1:])
+	case "history":
+		return true, runHistoryCommand(args[1:])
+	case "upgrade":
+		return true, runUpgradeCommand(args[1:])
+	case "lastfm":
+		return true, runLastfmCommand(args[1:])
+	case "import":
+		return true, runImportCommand(args[1:])
+	case "podcast":
+		return true, runPodcastCommand(args[1:])
+	case "verify":
+		return true, runVerifyCommand(args[1:])
+	case "package":
+		return true, runPackageCommand(args[1:])
+	case "watch":
+		return true, runWatchCommand(args[1:])
+	case "liked":
+		return true, runLikedCommand(args[1:])
+	case "my-playlists":
+		return true, runMyPlaylistsCommand(args[1:])
+	case "help", "-h", "--help":
+		printCLIUsage()
+		return true, nil
+	}
+
+	for _, arg := range args {
+		if isSpotifyURLArg(arg) {
+			return true, runDownloadCommand(args)
+		}
+	}
+	return false, nil
+}
+
+func isSpotifyURLArg(arg string) bool {
+	arg = strings.TrimSpace(arg)
+	return strings.HasPrefix(arg, "spotify:") ||
+		strings.HasPrefix(arg, "https://open.spotify.com/") ||
+		strings.HasPrefix(arg, "http://open.spotify.com/") ||
+		strings.HasPrefix(arg, "open.spotify.com/") ||
+		backend.IsSpotifyShortLink(arg) ||
+		backend.IsExternalPlaylistURL(arg)
+}
+
+func printCLIUsage() {
+	fmt.Println(`Usage: SpotiFLAC [command] [flags]
+
+Without a command the desktop app is started.
+
+Commands:
+  download [flags] <spotify-url>   download a track, album, playlist or artist
+  track [flags] <spotify-url>      download a single track
+  search [flags] "artist title"    search Spotify tracks
+  config list|get|set|unset        view or change settings
+  credentials status|set|remove    manage your own Tidal or Qobuz tokens
+  debug-bundle [file.zip]          collect logs, redacted config and mirror health for bug reports
+  failed list|retry|clear          show, retry or clear downloads that failed
+  history <subcommand>             search, redownload, open, export or import download history
+  import [flags] <file>            download a list of URLs (.txt/.m3u) or a CSV, TSV or JSON playlist export
+  lastfm login|loved|top [flags]   download Last.fm loved or top tracks via Spotify search
+  liked [flags]                    download the liked songs of the logged-in Spotify account
+  my-playlists [flags] [id...]     list or download playlists of the logged-in Spotify account
+  package [flags] <album-dir>      pack an album folder into a zip or tar with an info.txt
+  podcast [flags] <episode-url>    download a podcast episode from its public RSS feed
+  retry [flags] <report.json>      retry the failed entries of a download report
+  retag [flags] <dir>              rewrite tags from matching Spotify metadata
+  upgrade [flags] <dir>            find 16-bit FLACs available in hi-res and replace them
+  verify [flags] <dir>             check downloaded files against stored checksums
+  watch list|add|remove|run        manage the watchlist and download new releases
+  serve [flags]                    run the REST API and scheduler without the GUI
+
+A bare Spotify URL is treated as "download <spotify-url>". spotify: URIs and
+spotify.link short links are expanded to open.spotify.com URLs. Apple Music and
+YouTube Music playlist URLs are accepted too and matched to Spotify tracks.`)
+}
+
+func newCLIApp() *App {
+	app := NewApp()
+	app.headless = true
+
+	if err := backend.InitHistoryDB("SpotiFLAC"); err != nil {
+		fmt.Printf("Failed to init history DB: %v\n", err)
+	}
+	if err := backend.InitISRCCacheDB(); err != nil {
+		fmt.Printf("Failed to init ISRC cache DB: %v\n", err)
+	}
+	if err := backend.InitProviderPriorityDB(); err != nil {
+		fmt.Printf("Failed to init provider priority DB: %v\n", err)
+	}
+	return app
+}
+
+func parseTrackSelection(spec string, total int) ([]int, error) {
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end := part, part
+		if idx := strings.Index(part, "-"); idx >= 0 {
+			start, end = strings.TrimSpace(part[:idx]), strings.TrimSpace(part[idx+1:])
+		}
+
+		from, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid track number %q", start)
+		}
+		to := total
+		if end != "" {
+			if to, err = strconv.Atoi(end); err != nil {
+				return nil, fmt.Errorf("invalid track number %q", end)
+			}
+		}
+		if from < 1 || to > total || from > to {
+			return nil, fmt.Errorf("track range %q is outside 1-%d", part, total)
+		}
+
+		for n := from; n <= to; n++ {
+			if !seen[n] {
+				seen[n] = true
+				indexes = append(indexes, n-1)
+			}
+		}
+	}
+
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no tracks selected")
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
+
+func promptTrackSelection(list spotifyTrackList) (string, error) {
+	fmt.Printf("\n%s (%d tracks)\n", list.Name, len(list.Tracks))
+	for i, track := range list.Tracks {
+		fmt.Printf("  %3d. %s - %s\n", i+1, track.Name, track.Artists)
+	}
+	fmt.Print("\nSelect tracks (e.g. 1,3,5-9; empty for all): ")
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func selectTracks(list spotifyTrackList, indexes []int) spotifyTrackList {
+	selected := list
+	selected.Tracks = make([]backend.AlbumTrackMetadata, 0, len(indexes))
+	selected.Positions = make([]int, 0, len(indexes))
+	for _, idx := range indexes {
+		position := idx + 1
+		if idx < len(list.Positions) && list.Positions[idx] > 0 {
+			position = list.Positions[idx]
+		}
+		selected.Tracks = append(selected.Tracks, list.Tracks[idx])
+		selected.Positions = append(selected.Positions, position)
+	}
+	return selected
+}
+
+type downloadCommandFlags struct {
+	output      *string
+	service     *string
+	onlyService *string
+	strict      *bool
+	convert     *string
+	dryRun      *bool
+}
+
+func registerDownloadFlags(fs *flag.FlagSet) *downloadCommandFlags {
+	return &downloadCommandFlags{
+		output:      fs.String("output", "", "download folder (defaults to the configured download path)"),
+		service:     fs.String("service", "", "auto, tidal, qobuz or amazon"),
+		onlyService: fs.String("only-service", "", "comma separated services to use, never falling back to others (e.g. qobuz)"),
+		strict:      fs.Bool("strict", false, "try only the first usable service and never lower the quality"),
+		convert:     fs.String("convert", "", "convert each track after download: mp3, m4a, alac, opus, ogg, wav, aiff or none"),
+		dryRun:      fs.Bool("dry-run", false, "show what would be downloaded without downloading"),
+	}
+}
+
+func (f *downloadCommandFlags) settings() (batchDownloadSettings, error) {
+	settings := loadBatchDownloadSettings()
+	if *f.output != "" {
+		settings.DownloadPath = *f.output
+	}
+	service, err := parseDownloaderChoice(*f.service)
+	if err != nil {
+		return settings, err
+	}
+	if service != "" {
+		settings.Downloader = service
+	}
+	if strings.TrimSpace(*f.onlyService) != "" {
+		allowed, err := parseAllowedServices(*f.onlyService)
+		if err != nil {
+			return settings, err
+		}
+		settings.AllowedServices = allowed
+		settings.Downloader = "auto"
+		if len(allowed) == 1 {
+			settings.Downloader = allowed[0]
+		}
+	}
+	if *f.strict {
+		settings.StrictService = true
+	}
+	if convert := strings.ToLower(strings.TrimSpace(*f.convert)); convert != "" {
+		if convert != "none" && convert != "off" && backend.NormalizeConvertTarget(convert) == "" {
+			return settings, fmt.Errorf("unknown convert format: %s", convert)
+		}
+		settings.ConvertTo = convert
+	}
+	return settings, nil
+}
+
+func (a *App) runCLIDownload(ctx context.Context, list spotifyTrackList, settings batchDownloadSettings, dryRun bool) error {
+	if dryRun {
+		printDryRunReport(a.dryRunTrackList(list, settings))
+		return nil
+	}
+
+	result := a.downloadSpotifyTracks(ctx, list, settings)
+	fmt.Printf("\n%s: %d downloaded, %d skipped, %d failed\n", result.Name, result.Downloaded, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Printf("  %s\n", msg)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d track(s) failed", result.Failed)
+	}
+	return nil
+}
+
+func runDownloadCommand(args []string) error {
+	fs := flag.NewFlagSet("download", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	tracks := fs.String("tracks", "", "tracks to download, e.g. 1,3,5-9")
+	interactive := fs.Bool("select", false, "choose tracks interactively")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: SpotiFLAC download [flags] <spotify-url>")
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	list, err := fetchSpotifyTrackList(ctx, fs.Arg(0), settings.Separator)
+	if err != nil {
+		return err
+	}
+	if len(list.Tracks) == 0 {
+		return fmt.Errorf("%s %q has no tracks", list.Type, list.Name)
+	}
+
+	spec := *tracks
+	if *interactive {
+		if spec, err = promptTrackSelection(list); err != nil {
+			return err
+		}
+	}
+	if spec != "" {
+		indexes, err := parseTrackSelection(spec, len(list.Tracks))
+		if err != nil {
+			return err
+		}
+		list = selectTracks(list, indexes)
+	}
+
+	return app.runCLIDownload(ctx, list, settings, *shared.dryRun)
+}
+
+func runTrackCommand(args []string) error {
+	fs := flag.NewFlagSet("track", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: SpotiFLAC track [flags] <spotify-track-url>")
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	list, err := fetchSpotifyTrackList(ctx, fs.Arg(0), settings.Separator)
+	if err != nil {
+		return err
+	}
+	if list.Type != "track" || len(list.Tracks) != 1 {
+		return fmt.Errorf("%s is a %s URL, use the download command instead", fs.Arg(0), list.Type)
+	}
+
+	return app.runCLIDownload(ctx, list, settings, *shared.dryRun)
+}
+
+func runPodcastCommand(args []string) error {
+	fs := flag.NewFlagSet("podcast", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: SpotiFLAC podcast [flags] <spotify-episode-url>")
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	episode, err := fetchPodcastEpisode(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *shared.dryRun {
+		audioURL, err := backend.FindPodcastEpisodeAudio(ctx, episode.ShowName, episode.Name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s - %s (%s)\n%s\n", episode.ShowName, episode.Name, formatTrackDuration(episode.DurationMS), audioURL)
+		return nil
+	}
+
+	filePath, err := backend.DownloadPodcastEpisode(ctx, episode, settings.DownloadPath)
+	if err != nil {
+		return err
+	}
+	fmt.Println(filePath)
+	return nil
+}
+
+func formatTrackDuration(durationMS int) string {
+	seconds := (durationMS + 500) / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func runSearchCommand(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	limit := fs.Int("limit", 10, "number of results")
+	download := fs.Int("download", 0, "download result N")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if query == "" {
+		return fmt.Errorf("usage: SpotiFLAC search [flags] \"artist title\"")
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	results, err := backend.SearchSpotifyByType(ctx, query, "track", *limit, 0)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no tracks found for %q", query)
+	}
+
+	for i, result := range results {
+		isrc := backend.ResolveTrackISRC(result.ID)
+		if isrc == "" {
+			isrc = "-"
+		}
+		fmt.Printf("%3d. %s - %s [%s]\n", i+1, result.Name, result.Artists, formatTrackDuration(result.Duration))
+		fmt.Printf("     %s | %s | %s\n", result.AlbumName, result.ID, isrc)
+	}
+
+	if *download == 0 {
+		return nil
+	}
+	if *download < 1 || *download > len(results) {
+		return fmt.Errorf("--download must be between 1 and %d", len(results))
+	}
+
+	list, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", results[*download-1].ID), settings.Separator)
+	if err != nil {
+		return err
+	}
+	return app.runCLIDownload(ctx, list, settings, *shared.dryRun)
+}
+
+func printRetagChanges(filePath, spotifyID, matchedBy string, changes []RetagFieldChange, errMsg string) {
+	if errMsg != "" {
+		fmt.Printf("\n%s\n  error: %s\n", filePath, errMsg)
+		return
+	}
+	fmt.Printf("\n%s\n  matched %s by %s\n", filePath, spotifyID, matchedBy)
+	if len(changes) == 0 {
+		fmt.Println("  no changes")
+		return
+	}
+	for _, change := range changes {
+		fmt.Printf("  %-12s %q -> %q\n", change.Field, change.Old, change.New)
+	}
+}
+
+func runRetagCommand(args []string) error {
+	fs := flag.NewFlagSet("retag", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "show the tag changes without writing them")
+	covers := fs.Bool("covers", false, "replace embedded covers with the Spotify artwork")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: SpotiFLAC retag [flags] <dir|file>...")
+	}
+
+	files := collectRefreshTagFiles(fs.Args())
+	if len(files) == 0 {
+		return fmt.Errorf("no audio files found")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	settings := loadBatchDownloadSettings()
+	failed := 0
+	if *dryRun {
+		for _, preview := range previewRetagFiles(ctx, files, settings) {
+			printRetagChanges(preview.FilePath, preview.SpotifyID, preview.MatchedBy, preview.Changes, preview.Error)
+			if preview.Error != "" {
+				failed++
+			}
+		}
+	} else {
+		for _, result := range retagFiles(ctx, files, settings, *covers) {
+			printRetagChanges(result.FilePath, result.SpotifyID, result.MatchedBy, result.Changes, result.Error)
+			if !result.Success {
+				failed++
+			}
+		}
+	}
+
+	fmt.Printf("\n%d file(s), %d matched, %d failed\n", len(files), len(files)-failed, failed)
+	return nil
+}
+
+func parseHistoryDate(value string, endOfDay bool) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return 0, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		day = day.Add(24*time.Hour - time.Second)
+	}
+	return day.Unix(), nil
+}
+
+func runHistorySearch(args []string) error {
+	fs := flag.NewFlagSet("history search", flag.ContinueOnError)
+	title := fs.String("title", "", "filter by title")
+	artist := fs.String("artist", "", "filter by artist")
+	album := fs.String("album", "", "filter by album")
+	service := fs.String("service", "", "filter by service")
+	quality := fs.String("quality", "", "filter by quality, e.g. 24-bit or 96.0kHz")
+	format := fs.String("format", "", "filter by format, e.g. flac or mp3")
+	since := fs.String("since", "", "only downloads on or after YYYY-MM-DD")
+	until := fs.String("until", "", "only downloads on or before YYYY-MM-DD")
+	limit := fs.Int("limit", 20, "number of results")
+	offset := fs.Int("offset", 0, "skip the first N results")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := backend.HistoryQuery{
+		Text:    strings.Join(fs.Args(), " "),
+		Title:   *title,
+		Artist:  *artist,
+		Album:   *album,
+		Source:  *service,
+		Quality: *quality,
+		Format:  *format,
+		Offset:  *offset,
+		Limit:   *limit,
+	}
+	var err error
+	if query.From, err = parseHistoryDate(*since, false); err != nil {
+		return err
+	}
+	if query.To, err = parseHistoryDate(*until, true); err != nil {
+		return err
+	}
+
+	if err := backend.InitHistoryDB("SpotiFLAC"); err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer backend.CloseHistoryDB()
+
+	page, err := backend.QueryHistoryItems(query, "SpotiFLAC")
+	if err != nil {
+		return err
+	}
+	for _, item := range page.Items {
+		fmt.Printf("%s  %s  %s - %s [%s] %s %s\n", item.ID, time.Unix(item.Timestamp, 0).Format("2006-01-02 15:04"),
+			item.Artists, item.Title, item.Album, item.Source, item.Quality)
+	}
+	fmt.Printf("\nShowing %d of %d match(es)\n", len(page.Items), page.Total)
+	return nil
+}
+
+func runHistoryRedownload(args []string) error {
+	fs := flag.NewFlagSet("history redownload", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: SpotiFLAC history redownload [flags] <id>...")
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	if *shared.dryRun {
+		list, result := historyTrackList(ctx, fs.Args(), settings)
+		for _, msg := range result.Errors {
+			fmt.Printf("  %s\n", msg)
+		}
+		printDryRunReport(app.dryRunTrackList(list, settings))
+		return nil
+	}
+
+	result := app.redownloadHistoryItems(ctx, fs.Args(), settings)
+	fmt.Printf("\n%d downloaded, %d skipped, %d failed\n", result.Downloaded, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Printf("  %s\n", msg)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d track(s) failed", result.Failed)
+	}
+	return nil
+}
+
+func runHistoryCommand(args []string) error {
+	usage := fmt.Errorf("usage: SpotiFLAC history search [flags] [text] | redownload [flags] <id>... | open <id> | export <file.json|file.csv> | import <file.json|file.csv>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "search", "list":
+		return runHistorySearch(args[1:])
+	case "redownload":
+		return runHistoryRedownload(args[1:])
+	case "open":
+		if len(args) != 2 {
+			return usage
+		}
+		if err := backend.InitHistoryDB("SpotiFLAC"); err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+		defer backend.CloseHistoryDB()
+
+		dir, err := revealHistoryItem(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Opened %s\n", dir)
+		return nil
+	case "export", "import":
+		if len(args) != 2 {
+			return usage
+		}
+		if err := backend.InitHistoryDB("SpotiFLAC"); err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+		defer backend.CloseHistoryDB()
+
+		if args[0] == "export" {
+			count, err := backend.ExportHistory(args[1], "SpotiFLAC")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d history item(s) to %s\n", count, args[1])
+			return nil
+		}
+
+		result, err := backend.ImportHistory(args[1], "SpotiFLAC")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d history item(s), skipped %d duplicate(s)\n", result.Imported, result.Skipped)
+		if result.FetchImported > 0 || result.FetchSkipped > 0 {
+			fmt.Printf("Imported %d fetch history item(s), skipped %d\n", result.FetchImported, result.FetchSkipped)
+		}
+		return nil
+	}
+	return usage
+}
+
+func runDebugBundleCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: SpotiFLAC debug-bundle [file.zip]")
+	}
+	path := defaultDebugBundleName()
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	if err := app.writeDebugBundle(path); err != nil {
+		return fmt.Errorf("failed to write debug bundle: %w", err)
+	}
+	fmt.Printf("Debug bundle saved to %s\n", path)
+	return nil
+}
+
+func runFailedCommand(args []string) error {
+	usage := fmt.Errorf("usage: SpotiFLAC failed list | retry [flags] | clear")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "list":
+		if err := backend.InitHistoryDB("SpotiFLAC"); err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+		defer backend.CloseHistoryDB()
+
+		failures, err := backend.GetFailedDownloads("SpotiFLAC")
+		if err != nil {
+			return err
+		}
+		for _, failure := range failures {
+			fmt.Printf("%s - %s", failure.ArtistName, failure.TrackName)
+			if failure.SpotifyID != "" {
+				fmt.Printf(" (%s)", failure.SpotifyID)
+			}
+			fmt.Printf("\n  last failed %s after %d attempt(s)\n", time.Unix(failure.LastFailedAt, 0).Format("2006-01-02 15:04"), len(failure.Attempts))
+			for _, attempt := range failure.Attempts {
+				fmt.Printf("  %-8s %-13s %s\n", attempt.Service, attempt.Code, attempt.Error)
+			}
+		}
+		fmt.Printf("\n%d failed download(s)\n", len(failures))
+		return nil
+	case "clear":
+		if err := backend.InitHistoryDB("SpotiFLAC"); err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+		defer backend.CloseHistoryDB()
+		return backend.ClearFailedDownloads("SpotiFLAC")
+	case "retry":
+		fs := flag.NewFlagSet("failed retry", flag.ContinueOnError)
+		shared := registerDownloadFlags(fs)
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		settings, err := shared.settings()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		app := newCLIApp()
+		defer app.shutdown(context.Background())
+
+		result, err := app.retryFailedDownloadLog(ctx, settings)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n%d downloaded, %d skipped, %d failed\n", result.Downloaded, result.Skipped, result.Failed)
+		for _, msg := range result.Errors {
+			fmt.Printf("  %s\n", msg)
+		}
+		if result.Failed > 0 {
+			return fmt.Errorf("%d track(s) failed", result.Failed)
+		}
+		return nil
+	}
+	return usage
+}
+
+func runRetryCommand(args []string) error {
+	fs := flag.NewFlagSet("retry", flag.ContinueOnError)
+	service := fs.String("service", "", "downloader to retry with (default: the configured downloader)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	usage := fmt.Errorf("usage: SpotiFLAC retry [--service name] <report.json>")
+	if fs.NArg() == 0 {
+		return usage
+	}
+	manifestPath := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usage
+	}
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	result, err := app.RetryFailedDownloads(manifestPath, *service)
+	if err != nil {
+		return err
+	}
+	if result.Total == 0 {
+		fmt.Printf("No failed entries in %s\n", manifestPath)
+		return nil
+	}
+	fmt.Printf("\n%d downloaded, %d skipped, %d failed\n", result.Downloaded, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Printf("  %s\n", msg)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d track(s) failed", result.Failed)
+	}
+	return nil
+}
+
+func runWatchCommand(args []string) error {
+	usage := fmt.Errorf("usage: SpotiFLAC watch list | add <spotify-url> | remove <id> | run [--loop]")
+	if len(args) == 0 {
+		return usage
+	}
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+	if err := backend.InitWatchlistDB(); err != nil {
+		return fmt.Errorf("failed to open watchlist: %w", err)
+	}
+
+	switch args[0] {
+	case "list":
+		entries, err := app.GetWatchlist()
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s  %s (%s)\n  %s\n", entry.ID, entry.Name, entry.Type, entry.URL)
+			if entry.LastChecked > 0 {
+				fmt.Printf("  last checked %s, %d new track(s)\n", time.Unix(entry.LastChecked, 0).Format("2006-01-02 15:04"), entry.LastNewTracks)
+			}
+			if entry.LastError != "" {
+				fmt.Printf("  last error: %s\n", entry.LastError)
+			}
+		}
+		fmt.Printf("\n%d watched item(s)\n", len(entries))
+		return nil
+	case "add":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: SpotiFLAC watch add <spotify-url>")
+		}
+		entry, err := app.AddToWatchlist(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Watching %s (%s), %d track(s) recorded as known\n", entry.Name, entry.ID, len(entry.KnownTrackIDs))
+		return nil
+	case "remove":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: SpotiFLAC watch remove <id>")
+		}
+		return app.RemoveFromWatchlist(args[1])
+	case "run":
+		fs := flag.NewFlagSet("watch run", flag.ContinueOnError)
+		loop := fs.Bool("loop", false, "keep running and check again every watchInterval minutes")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		for {
+			results, err := app.runWatchCycle(ctx)
+			if err != nil {
+				return err
+			}
+			var downloaded, skipped, failed int
+			for _, result := range results {
+				downloaded += result.Downloaded
+				skipped += result.Skipped
+				failed += result.Failed
+				for _, msg := range result.Errors {
+					fmt.Printf("  %s\n", msg)
+				}
+			}
+			fmt.Printf("\nChecked %d item(s): %d downloaded, %d skipped, %d failed\n", len(results), downloaded, skipped, failed)
+
+			if !*loop {
+				if failed > 0 {
+					return fmt.Errorf("%d track(s) failed", failed)
+				}
+				return nil
+			}
+
+			interval := backend.GetWatchIntervalSetting()
+			fmt.Printf("Next check in %s\n", interval)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	}
+	return usage
+}
+
+func runUpgradeCommand(args []string) error {
+	fs := flag.NewFlagSet("upgrade", flag.ContinueOnError)
+	apply := fs.Bool("apply", false, "download the hi-res versions and replace the originals")
+	archive := fs.String("archive", "", "move replaced originals into this folder instead of deleting them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: SpotiFLAC upgrade [flags] <dir>")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	scan, err := backend.FindHiResUpgrades(ctx, fs.Arg(0), 0, func(progress backend.LibraryScanProgress) {
+		fmt.Printf("Scanned %d/%d\n", progress.Scanned, progress.Total)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range scan.Candidates {
+		fmt.Printf("%s\n  %s -> %s on %s\n", candidate.Path, candidate.Quality, candidate.TargetQuality, candidate.Service)
+	}
+	fmt.Printf("\n%d FLAC file(s), %d below hi-res, %d without ISRC, %d upgradable\n",
+		scan.Scanned, scan.Standard, scan.NoISRC, len(scan.Candidates))
+
+	if !*apply || len(scan.Candidates) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(scan.Candidates))
+	for _, candidate := range scan.Candidates {
+		paths = append(paths, candidate.Path)
+	}
+
+	failed := 0
+	for _, result := range backend.UpgradeFilesToHiRes(paths, *archive) {
+		if !result.Success {
+			failed++
+			continue
+		}
+		fmt.Printf("Upgraded %s (%s -> %s)\n", result.Path, result.OldQuality, result.NewQuality)
+	}
+	fmt.Printf("\n%d upgraded, %d failed\n", len(paths)-failed, failed)
+	return nil
+}
+
+func runVerifyCommand(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", false, "print every checked file, not only problems")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: SpotiFLAC verify [flags] <dir>")
+	}
+
+	report, err := backend.VerifyChecksums(fs.Arg(0), func(path, status string) {
+		if *verbose || status != "ok" {
+			fmt.Printf("%-8s %s\n", status, path)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if report.Checked == 0 {
+		return fmt.Errorf("no checksum files found in %s", fs.Arg(0))
+	}
+
+	fmt.Printf("\n%d checked, %d ok, %d mismatched, %d missing\n", report.Checked, report.OK, report.Mismatch, report.Missing)
+	if report.Mismatch > 0 || report.Missing > 0 {
+		return fmt.Errorf("%d file(s) failed verification", report.Mismatch+report.Missing)
+	}
+	return nil
+}
+
+func runPackageCommand(args []string) error {
+	fs := flag.NewFlagSet("package", flag.ContinueOnError)
+	format := fs.String("format", "zip", "archive format: zip or tar")
+	output := fs.String("o", "", "archive path (default: next to the album folder)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: SpotiFLAC package [flags] <album-dir>")
+	}
+
+	result, err := backend.PackageAlbumFolder(fs.Arg(0), *format, *output)
+	if err != nil {
+		return err
+	}
+	for _, msg := range result.Errors {
+		fmt.Printf("Warning: %s\n", msg)
+	}
+	fmt.Printf("Packaged %d track(s) into %s (%.2f MB)\n", len(result.Tracks), result.Archive, float64(result.Size)/(1024*1024))
+	return nil
+}
+
+func parseConfigValue(raw string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err == nil {
+		return value
+	}
+	return raw
+}
+
+func formatConfigValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func runConfigCommand(args []string) error {
+	usage := fmt.Errorf("usage: SpotiFLAC config list | get <key> | set <key> <value> | unset <key>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	app := NewApp()
+	settings, err := app.LoadSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s = %s\n", key, formatConfigValue(settings[key]))
+		}
+		return nil
+	case args[0] == "get" && len(args) == 2:
+		value, ok := settings[args[1]]
+		if !ok {
+			return fmt.Errorf("%s is not set", args[1])
+		}
+		fmt.Println(formatConfigValue(value))
+		return nil
+	case args[0] == "set" && len(args) == 3:
+		settings[args[1]] = parseConfigValue(args[2])
+	case args[0] == "unset" && len(args) == 2:
+		delete(settings, args[1])
+	default:
+		return usage
+	}
+
+	if err := app.SaveSettings(settings); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	return nil
+}
+
+func printDryRunReport(report DryRunReport) {
+	fmt.Printf("\n%s %q: %d track(s)\n", report.Type, report.Name, report.Total)
+	for _, track := range report.Tracks {
+		status := "missing"
+		switch {
+		case track.Exists:
+			status = "exists"
+		case track.Excluded != "":
+			status = "skip"
+		case track.Service != "":
+			status = track.Service
+		}
+		fmt.Printf("  [%-7s] %s\n", status, track.FilePath)
+	}
+	fmt.Printf("\n%d to download (~%.1f MB), %d existing, %d excluded, %d unavailable\n", report.ToDownload, float64(report.EstimatedBytes)/(1024*1024), report.Existing, report.Excluded, report.Unavailable)
+}
+
+func runLastfmDownload(mode string, args []string) error {
+	fs := flag.NewFlagSet("lastfm "+mode, flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	user := fs.String("user", "", "Last.fm username (defaults to the logged-in account)")
+	limit := fs.Int("limit", 0, "maximum number of tracks to fetch")
+	period := new(string)
+	if mode == "top" {
+		period = fs.String("period", backend.LastfmPeriodOverall, "time range: overall, 7day, 1month, 3month, 6month or 12month")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: SpotiFLAC lastfm %s [flags]", mode)
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	var tracks []backend.LastfmTrack
+	name := "Last.fm Loved Tracks"
+	if mode == "top" {
+		tracks, err = backend.GetLastfmTopTracks(*user, *period, *limit)
+		name = lastfmTopTracksName(*period)
+	} else {
+		tracks, err = backend.GetLastfmLovedTracks(*user, *limit)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Fetched %d track(s) from Last.fm\n", len(tracks))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	if *shared.dryRun {
+		list, result := lastfmTrackList(ctx, name, tracks, settings)
+		for _, msg := range result.Errors {
+			fmt.Printf("  %s\n", msg)
+		}
+		printDryRunReport(app.dryRunTrackList(list, settings))
+		return nil
+	}
+
+	result := app.downloadLastfmTracks(ctx, name, tracks, settings)
+	fmt.Printf("\n%d downloaded, %d skipped, %d failed\n", result.Downloaded, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Printf("  %s\n", msg)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d track(s) failed", result.Failed)
+	}
+	return nil
+}
+
+func downloadAccountTrackList(list spotifyTrackList, settings batchDownloadSettings, dryRun bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	if dryRun {
+		printDryRunReport(app.dryRunTrackList(list, settings))
+		return nil
+	}
+
+	result := app.downloadSpotifyTracks(ctx, list, settings)
+	fmt.Printf("\n%d downloaded, %d skipped, %d failed\n", result.Downloaded, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Printf("  %s\n", msg)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d track(s) failed", result.Failed)
+	}
+	return nil
+}
+
+func runLikedCommand(args []string) error {
+	fs := flag.NewFlagSet("liked", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: SpotiFLAC liked [flags]")
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	payload, err := backend.GetSpotifyLikedSongs(settings.Separator)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Fetched %d liked song(s)\n", len(payload.TrackList))
+
+	return downloadAccountTrackList(spotifyTrackList{
+		URL:          "spotify:user:collection",
+		Name:         "Liked Songs",
+		Type:         "playlist",
+		PlaylistName: "Liked Songs",
+		Tracks:       payload.TrackList,
+	}, settings, *shared.dryRun)
+}
+
+func runMyPlaylistsCommand(args []string) error {
+	fs := flag.NewFlagSet("my-playlists", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		playlists, err := backend.GetSpotifyUserPlaylists()
+		if err != nil {
+			return err
+		}
+		for _, playlist := range playlists {
+			fmt.Printf("%s  %s by %s (%d tracks)\n", playlist.ID, playlist.Name, playlist.Owner, playlist.TotalTracks)
+		}
+		fmt.Printf("\n%d playlist(s), download with: SpotiFLAC my-playlists <id>\n", len(playlists))
+		return nil
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, playlistID := range fs.Args() {
+		payload, err := backend.GetSpotifyUserPlaylistTracks(playlistID, settings.Separator)
+		if err != nil {
+			fmt.Printf("%s: %v\n", playlistID, err)
+			failed++
+			continue
+		}
+
+		name := strings.TrimSpace(payload.PlaylistInfo.Owner.Name)
+		fmt.Printf("Fetched %d track(s) from %s\n", len(payload.TrackList), name)
+		if err := downloadAccountTrackList(spotifyTrackList{
+			URL:          fmt.Sprintf("https://open.spotify.com/playlist/%s", playlistID),
+			Name:         name,
+			Type:         "playlist",
+			PlaylistName: name,
+			Tracks:       payload.TrackList,
+		}, settings, *shared.dryRun); err != nil {
+			fmt.Printf("%s: %v\n", name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d playlist(s) failed", failed)
+	}
+	return nil
+}
+
+func runLastfmCommand(args []string) error {
+	usage := fmt.Errorf("usage: SpotiFLAC lastfm login | logout | status | loved [flags] | top [flags]")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "login":
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		fmt.Println("Open the URL below in a browser and allow access to finish logging in.")
+		status, err := backend.LastfmLogin(ctx, nil)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged in to Last.fm as %s\n", status.Username)
+		return nil
+	case "logout":
+		return backend.LastfmLogout()
+	case "status":
+		status := backend.GetLastfmAuthStatus()
+		if !status.LoggedIn {
+			fmt.Println("Not logged in to Last.fm")
+			return nil
+		}
+		fmt.Printf("Logged in to Last.fm as %s\n", status.Username)
+		return nil
+	case "loved", "top":
+		return runLastfmDownload(args[0], args[1:])
+	}
+	return usage
+}
+
+func isURLListFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".m3u", ".m3u8":
+		return true
+	}
+	return false
+}
+
+func runImportURLList(ctx context.Context, app *App, path string, settings batchDownloadSettings, dryRun bool) error {
+	urls, err := backend.ParseURLList(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Read %d unique URL(s) from %s\n", len(urls), path)
+
+	if dryRun {
+		lists, result := expandURLList(ctx, urls, settings)
+		for _, msg := range result.Errors {
+			fmt.Printf("  %s\n", msg)
+		}
+		for _, list := range lists {
+			printDryRunReport(app.dryRunTrackList(list, settings))
+		}
+		return nil
+	}
+
+	result := app.downloadURLList(ctx, urls, settings)
+	fmt.Printf("\n%d downloaded, %d skipped, %d failed\n", result.Downloaded, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Printf("  %s\n", msg)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d item(s) failed", result.Failed)
+	}
+	return nil
+}
+
+func runImportCommand(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	shared := registerDownloadFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: SpotiFLAC import [flags] <urls.txt|list.m3u|file.csv|file.tsv|file.json>")
+	}
+
+	settings, err := shared.settings()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if isURLListFile(fs.Arg(0)) {
+		app := newCLIApp()
+		defer app.shutdown(context.Background())
+		return runImportURLList(ctx, app, fs.Arg(0), settings, *shared.dryRun)
+	}
+
+	playlist, err := backend.ParseCSVPlaylist(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Read %d track(s) from %s (%s)\n", len(playlist.Tracks), playlist.Name, playlist.Format)
+	if len(playlist.UnmatchedColumns) > 0 {
+		fmt.Printf("Ignored columns: %s\n", strings.Join(playlist.UnmatchedColumns, ", "))
+	}
+
+	app := newCLIApp()
+	defer app.shutdown(context.Background())
+
+	if *shared.dryRun {
+		list, result := importedPlaylistTrackList(ctx, playlist, settings)
+		for _, msg := range result.Errors {
+			fmt.Printf("  %s\n", msg)
+		}
+		printDryRunReport(app.dryRunTrackList(list, settings))
+		return nil
+	}
+
+	result := app.downloadImportedPlaylist(ctx, playlist, settings)
+	fmt.Printf("\n%d downloaded, %d skipped, %d failed\n", result.Downloaded, result.Skipped, result.Failed)
+	for _, msg := range result.Errors {
+		fmt.Printf("  %s\n", msg)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d track(s) failed", result.Failed)
+	}
+	return nil
+}
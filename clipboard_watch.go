@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	clipboardPollInterval = 1500 * time.Millisecond
+	clipboardQueueSize    = 64
+)
+
+type clipboardLinkResult struct {
+	URL    string              `json:"url"`
+	Result BatchDownloadResult `json:"result"`
+}
+
+func (a *App) startClipboardWatcher(ctx context.Context) {
+	links := make(chan string, clipboardQueueSize)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case url := <-links:
+				result := a.downloadURLList(ctx, []string{url}, loadBatchDownloadSettings())
+				a.emitEvent("clipboard-link-done", clipboardLinkResult{URL: url, Result: result})
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(clipboardPollInterval)
+		defer ticker.Stop()
+
+		last := ""
+		primed := false
+		seen := make(map[string]bool)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !backend.GetClipboardWatcherSetting() {
+				primed = false
+				continue
+			}
+
+			text, err := runtime.ClipboardGetText(a.ctx)
+			if err != nil {
+				continue
+			}
+			if !primed {
+				last, primed = text, true
+				continue
+			}
+			if text == last {
+				continue
+			}
+			last = text
+
+			for _, url := range backend.ExtractSpotifyURLs(text) {
+				if seen[url] {
+					continue
+				}
+				seen[url] = true
+
+				select {
+				case links <- url:
+					fmt.Printf("[Clipboard] Queued %s\n", url)
+					a.emitEvent("clipboard-link-queued", url)
+				default:
+					fmt.Printf("Warning: clipboard queue is full, dropping %s\n", url)
+				}
+			}
+		}
+	}()
+}
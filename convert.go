@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func resolveConvertTarget(requested string) string {
+	switch strings.ToLower(strings.TrimSpace(requested)) {
+	case "":
+		return backend.GetConvertToSetting()
+	case "none", "off":
+		return ""
+	default:
+		return backend.NormalizeConvertTarget(requested)
+	}
+}
+
+func convertedTrackPath(path, target string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + backend.ConvertOutputExtension(target)
+}
+
+func convertDownloadedTrack(filename, target string) string {
+	if strings.EqualFold(filepath.Ext(filename), backend.ConvertOutputExtension(target)) {
+		return filename
+	}
+
+	fmt.Printf("Converting downloaded track to %s...\n", strings.ToUpper(target))
+	converted, err := backend.ConvertDownloadedFile(filename, backend.ConvertAudioRequest{
+		OutputFormat:   target,
+		Bitrate:        backend.GetConvertBitrateSetting(),
+		DeleteOriginal: backend.GetConvertDeleteOriginalSetting(),
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to convert %s to %s: %v\n", filepath.Base(filename), target, err)
+		return filename
+	}
+	return converted
+}
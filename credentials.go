@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/term"
+)
+
+func (a *App) SaveServiceCredential(service, token, userID string) error {
+	return backend.SetServiceCredential(service, backend.ServiceCredential{
+		Token:  token,
+		UserID: strings.TrimSpace(userID),
+	})
+}
+
+func (a *App) RemoveServiceCredential(service string) error {
+	return backend.DeleteServiceCredential(service)
+}
+
+func (a *App) GetServiceCredentialStatuses() []backend.CredentialStatus {
+	return backend.GetCredentialStatuses()
+}
+
+func (a *App) QobuzLogin(email, password string) (backend.QobuzAccountStatus, error) {
+	return backend.QobuzLogin(email, password)
+}
+
+func (a *App) TidalLogin() (backend.TidalAuthStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	return backend.TidalDeviceLogin(ctx, func(code backend.TidalDeviceCode) {
+		a.emitEvent("tidal-device-code", code)
+		if a.ctx != nil && code.VerificationURL != "" {
+			runtime.BrowserOpenURL(a.ctx, code.VerificationURL)
+		}
+	})
+}
+
+func (a *App) TidalLogout() error {
+	return backend.TidalLogout()
+}
+
+func (a *App) GetTidalAuthStatus() backend.TidalAuthStatus {
+	return backend.GetTidalAuthStatus()
+}
+
+func runCredentialsCommand(args []string) error {
+	usage := fmt.Errorf("usage: SpotiFLAC credentials status | set <service> [user-id] | remove <service> | qobuz-login <email> | tidal-login")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "status":
+		for _, status := range backend.GetCredentialStatuses() {
+			if !status.Present {
+				fmt.Printf("%-8s not set\n", status.Service)
+				continue
+			}
+			fmt.Printf("%-8s set (%s)", status.Service, status.Storage)
+			if status.UserID != "" {
+				fmt.Printf(", user %s", status.UserID)
+			}
+			fmt.Println()
+		}
+		return nil
+	case "set":
+		if len(args) < 2 || len(args) > 3 {
+			return usage
+		}
+		userID := ""
+		if len(args) > 2 {
+			userID = args[2]
+		}
+		token, err := readPassword(fmt.Sprintf("%s token: ", strings.ToLower(args[1])))
+		if err != nil {
+			return err
+		}
+		if err := backend.SetServiceCredential(args[1], backend.ServiceCredential{Token: token, UserID: userID}); err != nil {
+			return err
+		}
+		fmt.Printf("Saved %s credentials\n", strings.ToLower(args[1]))
+		return nil
+	case "qobuz-login":
+		if len(args) < 2 {
+			return usage
+		}
+		password, err := readPassword("Qobuz password: ")
+		if err != nil {
+			return err
+		}
+		status, err := backend.QobuzLogin(args[1], password)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged in to Qobuz as user %s (%s)\n", status.UserID, status.Plan)
+		return nil
+	case "tidal-login":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		status, err := backend.TidalDeviceLogin(ctx, func(code backend.TidalDeviceCode) {
+			fmt.Printf("Open %s and confirm the code %s to finish logging in.\n", code.VerificationURL, code.UserCode)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged in to Tidal as user %s\n", status.UserID)
+		return nil
+	case "remove":
+		if len(args) < 2 {
+			return usage
+		}
+		return backend.DeleteServiceCredential(args[1])
+	}
+	return usage
+}
+
+func readPassword(prompt string) (string, error) {
+	fmt.Print(prompt)
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		return string(password), nil
+	}
+
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		return "", err
+	}
+	return strings.TrimRight(password, "\r\n"), nil
+}
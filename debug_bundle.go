@@ -0,0 +1,198 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var (
+	debugBundleSecretKey = regexp.MustCompile(`(?i)(token|secret|password|passwd|cookie|credential|session|auth|apikey|api_key|key)$`)
+	debugBundleURLKey    = regexp.MustCompile(`(?i)(url|uri|webhook|endpoint|api)$`)
+)
+
+// redactDebugURL keeps only the scheme and host of a URL setting, since
+// webhook URLs (Discord, Telegram, ...) carry their secret in the path.
+func redactDebugURL(value string) string {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || parsed.Host == "" {
+		return "[redacted]"
+	}
+	if parsed.Path == "" && parsed.RawQuery == "" && parsed.User == nil {
+		return parsed.Scheme + "://" + parsed.Host
+	}
+	return parsed.Scheme + "://" + parsed.Host + "/[redacted]"
+}
+
+type debugBundleMirror struct {
+	Service string `json:"service"`
+	Online  bool   `json:"online"`
+}
+
+func redactDebugSettings(settings map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			redacted[key] = redactDebugSettings(typed)
+			continue
+		case string:
+			if debugBundleSecretKey.MatchString(key) && typed != "" {
+				redacted[key] = "[redacted]"
+				continue
+			}
+			if debugBundleURLKey.MatchString(key) && typed != "" {
+				redacted[key] = redactDebugURL(typed)
+				continue
+			}
+			redacted[key] = redactDiagnosticText(typed)
+			continue
+		}
+		if debugBundleSecretKey.MatchString(key) {
+			redacted[key] = "[redacted]"
+			continue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+func debugBundleSystemInfo() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SpotiFLAC Debug Bundle - %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	b.WriteString(strings.Repeat("-", 50) + "\n")
+	fmt.Fprintf(&b, "Version:  %s\n", backend.AppVersion)
+	fmt.Fprintf(&b, "Platform: %s/%s\n", goruntime.GOOS, goruntime.GOARCH)
+	fmt.Fprintf(&b, "Go:       %s\n", goruntime.Version())
+	fmt.Fprintf(&b, "CPUs:     %d\n", goruntime.NumCPU())
+
+	if version, err := backend.GetFFmpegVersion(); err == nil {
+		fmt.Fprintf(&b, "FFmpeg:   %s\n", version)
+	} else {
+		fmt.Fprintf(&b, "FFmpeg:   %v\n", err)
+	}
+
+	return b.String()
+}
+
+func (a *App) debugBundleMirrors() []debugBundleMirror {
+	settings := loadBatchDownloadSettings()
+	var mirrors []debugBundleMirror
+	for _, service := range []string{"tidal", "qobuz", "amazon"} {
+		apiURL := ""
+		if service == "tidal" {
+			apiURL = settings.CustomTidalAPI
+		}
+		mirrors = append(mirrors, debugBundleMirror{Service: service, Online: a.CheckAPIStatus(service, apiURL)})
+	}
+	return mirrors
+}
+
+func writeDebugBundleJSON(zw *zip.Writer, name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeDebugBundleFile(zw, name, redactDiagnosticText(string(data)))
+}
+
+func writeDebugBundleFile(zw *zip.Writer, name, content string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+func (a *App) writeDebugBundle(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	if err := writeDebugBundleFile(zw, "system.txt", debugBundleSystemInfo()); err != nil {
+		return err
+	}
+
+	settings, err := backend.LoadConfigSettings()
+	if err != nil || settings == nil {
+		settings = map[string]interface{}{}
+	}
+	if err := writeDebugBundleJSON(zw, "config.json", redactDebugSettings(settings)); err != nil {
+		return err
+	}
+
+	mirrors := map[string]interface{}{
+		"status": a.debugBundleMirrors(),
+		"amazon": backend.GetAmazonMirrorHealth(),
+	}
+	if err := writeDebugBundleJSON(zw, "mirror_health.json", mirrors); err != nil {
+		return err
+	}
+
+	if err := writeDebugBundleJSON(zw, "download_queue.json", backend.GetDownloadQueue()); err != nil {
+		return err
+	}
+
+	if failures, err := backend.GetFailedDownloads("SpotiFLAC"); err == nil {
+		if err := writeDebugBundleJSON(zw, "failed_downloads.json", failures); err != nil {
+			return err
+		}
+	}
+
+	consoleLog := backend.GetConsoleLog()
+	if consoleLog == "" {
+		consoleLog = "console capture is not active in this mode\n"
+	}
+	if err := writeDebugBundleFile(zw, "console.log", redactDiagnosticText(consoleLog)); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func defaultDebugBundleName() string {
+	return fmt.Sprintf("SpotiFLAC_%s_Debug.zip", time.Now().Format("20060102_150405"))
+}
+
+func (a *App) ExportDebugBundle() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		DefaultFilename: defaultDebugBundleName(),
+		Title:           "Export Debug Bundle",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "ZIP Archives (*.zip)",
+				Pattern:     "*.zip",
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open save dialog: %v", err)
+	}
+	if path == "" {
+		return "Export cancelled", nil
+	}
+
+	if err := a.writeDebugBundle(path); err != nil {
+		return "", fmt.Errorf("failed to write debug bundle: %v", err)
+	}
+
+	return fmt.Sprintf("Debug bundle saved to %s", path), nil
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	diagnosticStatusOK      = "ok"
+	diagnosticStatusWarning = "warning"
+	diagnosticStatusError   = "error"
+	diagnosticStatusSkipped = "skipped"
+)
+
+type DiagnosticStage struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type DiagnosticReport struct {
+	URL         string            `json:"url"`
+	GeneratedAt string            `json:"generated_at"`
+	AppVersion  string            `json:"app_version"`
+	Platform    string            `json:"platform"`
+	Downloader  string            `json:"downloader"`
+	Stages      []DiagnosticStage `json:"stages"`
+	Text        string            `json:"text"`
+}
+
+var (
+	diagnosticIPv4Pattern  = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	diagnosticTokenPattern = regexp.MustCompile(`(?i)((?:token|access_token|refresh_token|secret|sig|signature|key|auth)=)[^&\s"]+`)
+	diagnosticBearer       = regexp.MustCompile(`(?i)(bearer\s+)[a-z0-9._\-]+`)
+	diagnosticHeader       = regexp.MustCompile(`(?im)\b((?:x-[a-z0-9-]*(?:token|key|secret|auth)[a-z0-9-]*|authorization|proxy-authorization|cookie|set-cookie)\s*:\s*)[^\r\n]+`)
+	diagnosticJSONSecret   = regexp.MustCompile(`(?i)("[a-z0-9_\-]*(?:token|secret|password|apikey|api_key)"\s*:\s*")[^"]*(")`)
+	diagnosticTokenPhrase  = regexp.MustCompile(`(?i)\b((?:api\s+)?token\s+)[a-z0-9._\-]{16,}`)
+	diagnosticWebhookURL   = regexp.MustCompile(`(?i)\b(https?://[^\s/"']+)/[^\s"']*(?:webhooks?|hooks|bot\d)[^\s"']*`)
+)
+
+func redactDiagnosticText(text string) string {
+	if homeDir, err := os.UserHomeDir(); err == nil && homeDir != "" {
+		text = strings.ReplaceAll(text, homeDir, "~")
+	}
+	text = diagnosticWebhookURL.ReplaceAllString(text, "${1}/[redacted]")
+	text = diagnosticTokenPattern.ReplaceAllString(text, "${1}[redacted]")
+	text = diagnosticBearer.ReplaceAllString(text, "${1}[redacted]")
+	text = diagnosticHeader.ReplaceAllString(text, "${1}[redacted]")
+	text = diagnosticJSONSecret.ReplaceAllString(text, "${1}[redacted]${2}")
+	text = diagnosticTokenPhrase.ReplaceAllString(text, "${1}[redacted]")
+	text = diagnosticIPv4Pattern.ReplaceAllString(text, "x.x.x.x")
+	return text
+}
+
+func runDiagnosticStage(report *DiagnosticReport, name string, fn func() (string, string)) {
+	start := time.Now()
+	status, detail := fn()
+	report.Stages = append(report.Stages, DiagnosticStage{
+		Name:       name,
+		Status:     status,
+		Detail:     redactDiagnosticText(detail),
+		DurationMS: time.Since(start).Milliseconds(),
+	})
+}
+
+func formatDiagnosticReport(report DiagnosticReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SpotiFLAC Diagnostic Report - %s\n", report.GeneratedAt)
+	b.WriteString(strings.Repeat("-", 50) + "\n")
+	fmt.Fprintf(&b, "Version:    %s\n", report.AppVersion)
+	fmt.Fprintf(&b, "Platform:   %s\n", report.Platform)
+	fmt.Fprintf(&b, "Downloader: %s\n", report.Downloader)
+	fmt.Fprintf(&b, "URL:        %s\n\n", report.URL)
+
+	for _, stage := range report.Stages {
+		fmt.Fprintf(&b, "[%s] %s (%dms)\n", strings.ToUpper(stage.Status), stage.Name, stage.DurationMS)
+		if stage.Detail != "" {
+			for _, line := range strings.Split(stage.Detail, "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func (a *App) RunDiagnostics(spotifyURL string) (DiagnosticReport, error) {
+	spotifyURL = strings.TrimSpace(spotifyURL)
+	if spotifyURL == "" {
+		return DiagnosticReport{}, fmt.Errorf("URL is required")
+	}
+
+	settings := loadBatchDownloadSettings()
+	report := DiagnosticReport{
+		URL:         spotifyURL,
+		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+		AppVersion:  backend.AppVersion,
+		Platform:    goruntime.GOOS + "/" + goruntime.GOARCH,
+		Downloader:  settings.Downloader,
+	}
+	if settings.Downloader == "auto" {
+		report.Downloader = "auto (" + settings.AutoOrder + ")"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var list spotifyTrackList
+	var track backend.AlbumTrackMetadata
+	haveTrack := false
+	runDiagnosticStage(&report, "Spotify metadata", func() (string, string) {
+		var err error
+		list, err = fetchSpotifyTrackList(ctx, spotifyURL, settings.Separator)
+		if err != nil {
+			return diagnosticStatusError, err.Error()
+		}
+		if len(list.Tracks) == 0 {
+			return diagnosticStatusError, fmt.Sprintf("%s %q has no tracks", list.Type, list.Name)
+		}
+		track = list.Tracks[0]
+		haveTrack = true
+		detail := fmt.Sprintf("%s %q, %d track(s)", list.Type, list.Name, len(list.Tracks))
+		if list.Type != "track" {
+			detail += fmt.Sprintf("\nchecking first track: %s - %s", track.Name, track.Artists)
+		}
+		return diagnosticStatusOK, detail
+	})
+
+	isrc := ""
+	runDiagnosticStage(&report, "ISRC resolution", func() (string, string) {
+		if !haveTrack {
+			return diagnosticStatusSkipped, "no track metadata"
+		}
+		isrc = backend.ResolveTrackISRC(track.SpotifyID)
+		if isrc == "" {
+			return diagnosticStatusWarning, "ISRC could not be resolved (Qobuz and ISRC-based checks will fail)"
+		}
+		return diagnosticStatusOK, isrc
+	})
+
+	var availability *backend.TrackAvailability
+	runDiagnosticStage(&report, "song.link resolution", func() (string, string) {
+		if !haveTrack {
+			return diagnosticStatusSkipped, "no track metadata"
+		}
+		result, err := runWithTimeout(30*time.Second, func() (*backend.TrackAvailability, error) {
+			return backend.NewSongLinkClient().CheckTrackAvailability(track.SpotifyID)
+		})
+		availability = result
+		if availability == nil {
+			return diagnosticStatusError, fmt.Sprintf("%v", err)
+		}
+
+		detail := fmt.Sprintf("tidal=%t amazon=%t qobuz=%t deezer=%t", availability.Tidal, availability.Amazon, availability.Qobuz, availability.Deezer)
+		if err != nil {
+			return diagnosticStatusWarning, detail + "\n" + err.Error()
+		}
+		return diagnosticStatusOK, detail
+	})
+
+	for _, service := range strings.Split(settings.AutoOrder, "-") {
+		if settings.Downloader != "auto" && service != settings.Downloader {
+			continue
+		}
+
+		runDiagnosticStage(&report, fmt.Sprintf("%s availability", service), func() (string, string) {
+			if availability == nil {
+				return diagnosticStatusSkipped, "availability unknown"
+			}
+			var available bool
+			var link string
+			switch service {
+			case "tidal":
+				available, link = availability.Tidal, availability.TidalURL
+			case "amazon":
+				available, link = availability.Amazon, availability.AmazonURL
+			case "qobuz":
+				available, link = availability.Qobuz, availability.QobuzURL
+			default:
+				return diagnosticStatusSkipped, "unknown service"
+			}
+			if !available {
+				return diagnosticStatusWarning, "track not found on " + service
+			}
+			return diagnosticStatusOK, link
+		})
+
+		runDiagnosticStage(&report, fmt.Sprintf("%s mirror health", service), func() (string, string) {
+			apiURL := ""
+			if service == "tidal" {
+				apiURL = settings.CustomTidalAPI
+			}
+			if a.CheckAPIStatus(service, apiURL) {
+				return diagnosticStatusOK, "at least one endpoint responded"
+			}
+			return diagnosticStatusError, "no endpoint responded"
+		})
+	}
+
+	target := batchTrackTarget{}
+	expectedPath := ""
+	runDiagnosticStage(&report, "Expected filename", func() (string, string) {
+		if !haveTrack {
+			return diagnosticStatusSkipped, "no track metadata"
+		}
+		target = resolveBatchTrackTarget(track, list.PlaylistName, 1, settings)
+		filename := backend.BuildExpectedFilename(track.Name, target.ArtistName, track.AlbumName, target.AlbumArtist, track.ReleaseDate, settings.FilenameTemplate, "", "", settings.TrackNumber, target.TrackNumber, track.DiscNumber, target.UseAlbumNumber, isrc)
+		expectedPath = filepath.Join(backend.SanitizeFolderPath(target.OutputDir), filename)
+		return diagnosticStatusOK, expectedPath
+	})
+
+	runDiagnosticStage(&report, "Existing file check", func() (string, string) {
+		if !haveTrack {
+			return diagnosticStatusSkipped, "no track metadata"
+		}
+		results := a.CheckFilesExistence(target.OutputDir, settings.DownloadPath, []CheckFileExistenceRequest{{
+			SpotifyID:           track.SpotifyID,
+			TrackName:           track.Name,
+			ArtistName:          target.ArtistName,
+			AlbumName:           track.AlbumName,
+			AlbumArtist:         target.AlbumArtist,
+			ReleaseDate:         track.ReleaseDate,
+			ISRC:                isrc,
+			TrackNumber:         track.TrackNumber,
+			DiscNumber:          track.DiscNumber,
+			Position:            target.TrackNumber,
+			UseAlbumTrackNumber: target.UseAlbumNumber,
+			FilenameFormat:      settings.FilenameTemplate,
+			IncludeTrackNumber:  settings.TrackNumber,
+		}})
+		if len(results) > 0 && results[0].Exists {
+			return diagnosticStatusOK, "already downloaded: " + results[0].FilePath
+		}
+		return diagnosticStatusOK, "not downloaded yet"
+	})
+
+	report.Text = formatDiagnosticReport(report)
+	return report, nil
+}
+
+func (a *App) ExportDiagnosticReport(spotifyURL string) (string, error) {
+	report, err := a.RunDiagnostics(spotifyURL)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		DefaultFilename: fmt.Sprintf("SpotiFLAC_%s_Diagnostics.txt", time.Now().Format("20060102_150405")),
+		Title:           "Export Diagnostic Report",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Text Files (*.txt)",
+				Pattern:     "*.txt",
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open save dialog: %v", err)
+	}
+	if path == "" {
+		return "Export cancelled", nil
+	}
+
+	if err := os.WriteFile(path, []byte(report.Text), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %v", err)
+	}
+
+	return fmt.Sprintf("Diagnostic report saved to %s", path), nil
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+type serviceDownloadContext struct {
+	MetadataSeparator string
+	SpotifyURL        string
+	ISRC              func() string
+}
+
+type ServiceDownloader interface {
+	Name() string
+	Probe(req DownloadRequest) error
+	Download(req DownloadRequest, ctx serviceDownloadContext) (string, error)
+}
+
+type experimentalServiceDownloader interface {
+	Experimental() bool
+}
+
+type isrcServiceDownloader interface {
+	NeedsISRC() bool
+}
+
+type lossyServiceDownloader interface {
+	Lossy() bool
+}
+
+var (
+	serviceDownloadersMu sync.RWMutex
+	serviceDownloaders   = make(map[string]ServiceDownloader)
+	serviceDownloadOrder []string
+)
+
+func RegisterServiceDownloader(downloader ServiceDownloader) {
+	name := strings.ToLower(strings.TrimSpace(downloader.Name()))
+
+	serviceDownloadersMu.Lock()
+	defer serviceDownloadersMu.Unlock()
+
+	if _, exists := serviceDownloaders[name]; !exists {
+		serviceDownloadOrder = append(serviceDownloadOrder, name)
+	}
+	serviceDownloaders[name] = downloader
+}
+
+func isServiceDownloaderEnabled(downloader ServiceDownloader) bool {
+	experimental, ok := downloader.(experimentalServiceDownloader)
+	return !ok || !experimental.Experimental() || backend.GetExperimentalDownloadersSetting()
+}
+
+func lookupServiceDownloader(name string) (ServiceDownloader, bool) {
+	serviceDownloadersMu.RLock()
+	downloader, ok := serviceDownloaders[strings.ToLower(strings.TrimSpace(name))]
+	serviceDownloadersMu.RUnlock()
+
+	if !ok || !isServiceDownloaderEnabled(downloader) {
+		return nil, false
+	}
+	return downloader, true
+}
+
+func registeredServiceNames() []string {
+	serviceDownloadersMu.RLock()
+	defer serviceDownloadersMu.RUnlock()
+
+	names := make([]string, 0, len(serviceDownloadOrder))
+	for _, name := range serviceDownloadOrder {
+		if isServiceDownloaderEnabled(serviceDownloaders[name]) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func isServiceAllowed(service string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	service = strings.ToLower(strings.TrimSpace(service))
+	for _, name := range allowed {
+		if strings.ToLower(strings.TrimSpace(name)) == service {
+			return true
+		}
+	}
+	return false
+}
+
+func filterAllowedServices(order, allowed []string) []string {
+	if len(allowed) == 0 {
+		return order
+	}
+	filtered := make([]string, 0, len(order))
+	for _, service := range order {
+		if isServiceAllowed(service, allowed) {
+			filtered = append(filtered, service)
+		}
+	}
+	for _, service := range allowed {
+		if !isServiceAllowed(service, filtered) {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered
+}
+
+func parseAllowedServices(raw string) ([]string, error) {
+	services := backend.ParseServiceList(raw)
+	for _, service := range services {
+		if _, ok := lookupServiceDownloader(service); !ok {
+			return nil, fmt.Errorf("unknown service: %s (use %s)", service, strings.Join(registeredServiceNames(), ", "))
+		}
+	}
+	return services, nil
+}
+
+func parseDownloaderChoice(service string) (string, error) {
+	service = strings.ToLower(strings.TrimSpace(service))
+	if service == "" || service == "auto" {
+		return service, nil
+	}
+	if _, ok := lookupServiceDownloader(service); !ok {
+		return "", fmt.Errorf("unknown service: %s (use auto, %s)", service, strings.Join(registeredServiceNames(), ", "))
+	}
+	return service, nil
+}
+
+func (a *App) GetRegisteredServices() []string {
+	names := registeredServiceNames()
+	sort.Strings(names)
+	return names
+}
+
+func serviceNeedsISRC(downloader ServiceDownloader) bool {
+	needsISRC, ok := downloader.(isrcServiceDownloader)
+	return ok && needsISRC.NeedsISRC()
+}
+
+func serviceIsLossy(downloader ServiceDownloader) bool {
+	lossy, ok := downloader.(lossyServiceDownloader)
+	return ok && lossy.Lossy()
+}
+
+func init() {
+	RegisterServiceDownloader(tidalServiceDownloader{})
+	RegisterServiceDownloader(qobuzServiceDownloader{})
+	RegisterServiceDownloader(amazonServiceDownloader{})
+	RegisterServiceDownloader(youtubeServiceDownloader{})
+}
+
+type tidalServiceDownloader struct{}
+
+func (tidalServiceDownloader) Name() string {
+	return "tidal"
+}
+
+func (tidalServiceDownloader) Probe(req DownloadRequest) error {
+	if req.ServiceURL == "" && req.SpotifyID == "" {
+		return fmt.Errorf("Tidal URL or Spotify ID is required for Tidal")
+	}
+	return nil
+}
+
+func (tidalServiceDownloader) Download(req DownloadRequest, ctx serviceDownloadContext) (string, error) {
+	if req.TidalAPIURL == "" || req.TidalAPIURL == "auto" {
+		downloader := backend.NewTidalDownloader("")
+		if req.ServiceURL != "" {
+			return downloader.DownloadByURLWithFallback(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, req.ISRC, ctx.SpotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
+		}
+		return downloader.Download(req.SpotifyID, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, req.ISRC, ctx.SpotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
+	}
+
+	downloader := backend.NewTidalDownloader(req.TidalAPIURL)
+	if req.ServiceURL != "" {
+		return downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, req.ISRC, ctx.SpotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
+	}
+	return downloader.Download(req.SpotifyID, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, req.ISRC, ctx.SpotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
+}
+
+type qobuzServiceDownloader struct{}
+
+func (qobuzServiceDownloader) Name() string {
+	return "qobuz"
+}
+
+func (qobuzServiceDownloader) NeedsISRC() bool {
+	return true
+}
+
+func (qobuzServiceDownloader) Probe(req DownloadRequest) error {
+	if req.SpotifyID == "" && req.ISRC == "" {
+		return fmt.Errorf("Spotify ID is required for Qobuz")
+	}
+	return nil
+}
+
+func (qobuzServiceDownloader) Download(req DownloadRequest, ctx serviceDownloadContext) (string, error) {
+	isrc := strings.TrimSpace(req.ISRC)
+	if isrc == "" {
+		fmt.Println("Waiting for ISRC (Qobuz dependency)...")
+		isrc = ctx.ISRC()
+	}
+	downloader := backend.NewQobuzDownloader()
+	downloader.SetExpectedDuration(req.Duration)
+	quality := req.AudioFormat
+	if quality == "" {
+		quality = "6"
+	}
+	return downloader.DownloadTrackWithISRC(isrc, req.OutputDir, quality, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, ctx.SpotifyURL, req.AllowFallback, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
+}
+
+type amazonServiceDownloader struct{}
+
+func (amazonServiceDownloader) Name() string {
+	return "amazon"
+}
+
+func (amazonServiceDownloader) Probe(req DownloadRequest) error {
+	if req.ServiceURL == "" && req.SpotifyID == "" {
+		return fmt.Errorf("Amazon URL or Spotify ID is required for Amazon")
+	}
+	return nil
+}
+
+func (amazonServiceDownloader) Download(req DownloadRequest, ctx serviceDownloadContext) (string, error) {
+	downloader := backend.NewAmazonDownloader()
+	if req.ServiceURL != "" {
+		return downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.PlaylistName, req.PlaylistOwner, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.CoverURL, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, req.ISRC, ctx.SpotifyURL, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
+	}
+	return downloader.DownloadBySpotifyID(req.SpotifyID, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.PlaylistName, req.PlaylistOwner, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.CoverURL, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, req.ISRC, ctx.SpotifyURL, req.UseFirstArtistOnly, req.UseSingleGenre, req.EmbedGenre)
+}
+
+type youtubeServiceDownloader struct{}
+
+func (youtubeServiceDownloader) Name() string {
+	return "youtube"
+}
+
+func (youtubeServiceDownloader) Lossy() bool {
+	return true
+}
+
+func (youtubeServiceDownloader) Probe(req DownloadRequest) error {
+	if !backend.GetYouTubeFallbackSetting() {
+		return fmt.Errorf("YouTube Music fallback is disabled")
+	}
+	if !backend.IsYouTubeMusicURL(req.ServiceURL) && (req.TrackName == "" || req.ArtistName == "") {
+		return fmt.Errorf("track name and artist are required for YouTube Music")
+	}
+	return nil
+}
+
+func (youtubeServiceDownloader) Download(req DownloadRequest, ctx serviceDownloadContext) (string, error) {
+	downloader := backend.NewYouTubeDownloader()
+	return downloader.Download(req.ServiceURL, req.OutputDir, backend.GetYouTubeFallbackFormatSetting(), req.FilenameFormat, req.PlaylistName, req.PlaylistOwner, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.CoverURL, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover, req.SpotifyTotalDiscs, req.Copyright, req.Publisher, req.Composer, ctx.MetadataSeparator, req.ISRC, ctx.SpotifyURL, req.UseFirstArtistOnly)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+type DryRunTrack struct {
+	Position       int      `json:"position"`
+	SpotifyID      string   `json:"spotify_id"`
+	Track          string   `json:"track"`
+	Artist         string   `json:"artist"`
+	Album          string   `json:"album"`
+	FilePath       string   `json:"file_path"`
+	Exists         bool     `json:"exists"`
+	Excluded       string   `json:"excluded,omitempty"`
+	Available      []string `json:"available,omitempty"`
+	Service        string   `json:"service,omitempty"`
+	EstimatedBytes int64    `json:"estimated_bytes,omitempty"`
+}
+
+type DryRunReport struct {
+	URL            string        `json:"url"`
+	Name           string        `json:"name"`
+	Type           string        `json:"type"`
+	Total          int           `json:"total"`
+	Existing       int           `json:"existing"`
+	Excluded       int           `json:"excluded"`
+	ToDownload     int           `json:"to_download"`
+	Unavailable    int           `json:"unavailable"`
+	EstimatedBytes int64         `json:"estimated_bytes"`
+	Tracks         []DryRunTrack `json:"tracks"`
+}
+
+func isHiResDownloadQuality(service string, settings batchDownloadSettings) bool {
+	switch service {
+	case "tidal":
+		return strings.HasPrefix(strings.ToUpper(settings.TidalQuality), "HI_RES")
+	case "qobuz":
+		return settings.QobuzQuality == "7" || settings.QobuzQuality == "27"
+	default:
+		return settings.AutoQuality == "24"
+	}
+}
+
+func estimateDownloadSize(durationMS int, hiRes bool) int64 {
+	kbps := int64(1000)
+	if hiRes {
+		kbps = 2800
+	}
+	return int64(durationMS) * kbps / 8
+}
+
+func selectDryRunService(availability *backend.TrackAvailability, settings batchDownloadSettings) (string, []string) {
+	if availability == nil {
+		return "", nil
+	}
+
+	var available []string
+	if availability.Tidal {
+		available = append(available, "tidal")
+	}
+	if availability.Qobuz {
+		available = append(available, "qobuz")
+	}
+	if availability.Amazon {
+		available = append(available, "amazon")
+	}
+
+	order := []string{settings.Downloader}
+	if settings.Downloader == "auto" {
+		order = strings.Split(settings.AutoOrder, "-")
+	}
+	for _, service := range order {
+		for _, candidate := range available {
+			if candidate == service {
+				return service, available
+			}
+		}
+	}
+	return "", available
+}
+
+func (a *App) DryRunDownload(spotifyURL string) (DryRunReport, error) {
+	spotifyURL = strings.TrimSpace(spotifyURL)
+	if spotifyURL == "" {
+		return DryRunReport{}, fmt.Errorf("URL is required")
+	}
+
+	settings := loadBatchDownloadSettings()
+	list, err := fetchSpotifyTrackList(context.Background(), spotifyURL, settings.Separator)
+	if err != nil {
+		return DryRunReport{}, err
+	}
+
+	return a.dryRunTrackList(list, settings), nil
+}
+
+func (a *App) dryRunTrackList(list spotifyTrackList, settings batchDownloadSettings) DryRunReport {
+	report := DryRunReport{
+		URL:   list.URL,
+		Name:  list.Name,
+		Type:  list.Type,
+		Total: len(list.Tracks),
+	}
+
+	requestsByDir := make(map[string][]CheckFileExistenceRequest)
+	indexesByDir := make(map[string][]int)
+	for i, track := range list.Tracks {
+		position := i + 1
+		if i < len(list.Positions) && list.Positions[i] > 0 {
+			position = list.Positions[i]
+		}
+
+		target := resolveBatchTrackTarget(track, list.PlaylistName, position, settings)
+		filename := backend.BuildExpectedFilename(track.Name, target.ArtistName, track.AlbumName, target.AlbumArtist, track.ReleaseDate, settings.FilenameTemplate, list.PlaylistName, "", settings.TrackNumber, target.TrackNumber, track.DiscNumber, target.UseAlbumNumber)
+		report.Tracks = append(report.Tracks, DryRunTrack{
+			Position:  position,
+			SpotifyID: track.SpotifyID,
+			Track:     track.Name,
+			Artist:    track.Artists,
+			Album:     track.AlbumName,
+			FilePath:  filepath.Join(backend.SanitizeFolderPath(target.OutputDir), filename),
+		})
+
+		requestsByDir[target.OutputDir] = append(requestsByDir[target.OutputDir], CheckFileExistenceRequest{
+			SpotifyID:           track.SpotifyID,
+			TrackName:           track.Name,
+			ArtistName:          target.ArtistName,
+			AlbumName:           track.AlbumName,
+			AlbumArtist:         target.AlbumArtist,
+			ReleaseDate:         track.ReleaseDate,
+			TrackNumber:         track.TrackNumber,
+			DiscNumber:          track.DiscNumber,
+			Position:            target.TrackNumber,
+			UseAlbumTrackNumber: target.UseAlbumNumber,
+			FilenameFormat:      settings.FilenameTemplate,
+			IncludeTrackNumber:  settings.TrackNumber,
+		})
+		indexesByDir[target.OutputDir] = append(indexesByDir[target.OutputDir], i)
+	}
+
+	for dir, requests := range requestsByDir {
+		results := a.CheckFilesExistence(dir, settings.DownloadPath, requests)
+		for j, result := range results {
+			if j >= len(indexesByDir[dir]) || !result.Exists {
+				continue
+			}
+			dryRunTrack := &report.Tracks[indexesByDir[dir][j]]
+			dryRunTrack.Exists = true
+			dryRunTrack.FilePath = result.FilePath
+		}
+	}
+
+	var skip *backend.SkipMatcher
+	if settings.SkipRules.Enabled() {
+		skip = backend.NewSkipMatcher(settings.SkipRules)
+	}
+
+	songLink := backend.NewSongLinkClient()
+	for i := range report.Tracks {
+		dryRunTrack := &report.Tracks[i]
+		if dryRunTrack.Exists {
+			report.Existing++
+			continue
+		}
+		if dryRunTrack.Excluded = skip.Match(list.Tracks[i]); dryRunTrack.Excluded != "" {
+			report.Excluded++
+			continue
+		}
+
+		fmt.Printf("Checking availability [%d/%d]: %s - %s\n", i+1, len(report.Tracks), dryRunTrack.Track, dryRunTrack.Artist)
+		availability, _ := songLink.CheckTrackAvailability(dryRunTrack.SpotifyID)
+		dryRunTrack.Service, dryRunTrack.Available = selectDryRunService(availability, settings)
+		if dryRunTrack.Service == "" {
+			report.Unavailable++
+			continue
+		}
+
+		dryRunTrack.EstimatedBytes = estimateDownloadSize(list.Tracks[i].DurationMS, isHiResDownloadQuality(dryRunTrack.Service, settings))
+		report.ToDownload++
+		report.EstimatedBytes += dryRunTrack.EstimatedBytes
+	}
+
+	return report
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func recordDownloadOutcome(req DownloadRequest, response DownloadResponse, err error) {
+	if (req.SpotifyID == "" && req.TrackName == "") || errors.Is(err, context.Canceled) {
+		return
+	}
+	key := backend.FailedDownloadKey(req.SpotifyID, req.TrackName, req.ArtistName)
+
+	if err == nil && response.Success {
+		if clearErr := backend.ClearDownloadFailure(key, "SpotiFLAC"); clearErr != nil {
+			fmt.Printf("Warning: failed to update failure log: %v\n", clearErr)
+		}
+		return
+	}
+
+	errMsg := response.Error
+	if errMsg == "" && err != nil {
+		errMsg = err.Error()
+	}
+	failure := backend.FailedDownload{
+		Key:              key,
+		SpotifyID:        req.SpotifyID,
+		TrackName:        req.TrackName,
+		ArtistName:       req.ArtistName,
+		AlbumName:        req.AlbumName,
+		ISRC:             req.ISRC,
+		Playlist:         req.SourcePlaylist,
+		PlaylistPosition: req.PlaylistPosition,
+	}
+	attempt := backend.DownloadAttempt{Service: req.Service, Error: errMsg}
+	if recordErr := backend.RecordDownloadFailure(failure, attempt, "SpotiFLAC"); recordErr != nil {
+		fmt.Printf("Warning: failed to record download failure: %v\n", recordErr)
+	}
+}
+
+func (a *App) retryFailedDownloadLog(ctx context.Context, settings batchDownloadSettings) (BatchDownloadResult, error) {
+	failures, err := backend.GetFailedDownloads("SpotiFLAC")
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+
+	settings.ManifestFormat = ""
+	settings.CreateM3U8File = false
+
+	result := BatchDownloadResult{Name: "Failed downloads"}
+	lists := make(map[string]*spotifyTrackList)
+	var order []string
+	for _, failure := range failures {
+		if failure.SpotifyID == "" {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: missing Spotify ID", failure.TrackName, failure.ArtistName))
+			continue
+		}
+
+		trackList, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", failure.SpotifyID), settings.Separator)
+		if err != nil || len(trackList.Tracks) == 0 {
+			result.Failed++
+			result.FailedIDs = append(result.FailedIDs, failure.SpotifyID)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: %v", failure.TrackName, failure.ArtistName, err))
+			continue
+		}
+
+		list, ok := lists[failure.Playlist]
+		if !ok {
+			list = &spotifyTrackList{Name: failure.Playlist, Type: "track", PlaylistName: failure.Playlist}
+			if failure.Playlist != "" {
+				list.Type = "playlist"
+			}
+			lists[failure.Playlist] = list
+			order = append(order, failure.Playlist)
+		}
+		list.Tracks = append(list.Tracks, trackList.Tracks[0])
+		list.Positions = append(list.Positions, failure.PlaylistPosition)
+	}
+
+	for _, name := range order {
+		list := lists[name]
+		if name != "" {
+			fmt.Printf("Retrying %d failed track(s) from %s\n", len(list.Tracks), name)
+		} else {
+			fmt.Printf("Retrying %d failed track(s)\n", len(list.Tracks))
+		}
+		retried := a.downloadSpotifyTracks(ctx, *list, settings)
+		result.Downloaded += retried.Downloaded
+		result.Skipped += retried.Skipped
+		result.Failed += retried.Failed
+		result.Files = append(result.Files, retried.Files...)
+		result.Errors = append(result.Errors, retried.Errors...)
+		result.FailedIDs = append(result.FailedIDs, retried.FailedIDs...)
+		result.Entries = append(result.Entries, retried.Entries...)
+	}
+	result.Total = result.Downloaded + result.Skipped + result.Failed
+
+	return result, nil
+}
+
+func (a *App) GetFailedDownloads() ([]backend.FailedDownload, error) {
+	return backend.GetFailedDownloads("SpotiFLAC")
+}
+
+func (a *App) ClearFailedDownloads() error {
+	return backend.ClearFailedDownloads("SpotiFLAC")
+}
+
+func (a *App) RetryAllFailedDownloads(service string) (BatchDownloadResult, error) {
+	settings := loadBatchDownloadSettings()
+	service, err := parseDownloaderChoice(service)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+	if service != "" {
+		settings.Downloader = service
+	}
+
+	return a.retryFailedDownloadLog(context.Background(), settings)
+}
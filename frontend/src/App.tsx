@@ -5,8 +5,8 @@ import { Search, X, ArrowUp } from "lucide-react";
 import { TooltipProvider } from "@/components/ui/tooltip";
 import { getSettings, getSettingsWithDefaults, loadSettings, saveSettings, applyThemeMode, applyFont } from "@/lib/settings";
 import { applyTheme } from "@/lib/themes";
-import { OpenFolder, CheckFFmpegInstalled, DownloadFFmpeg, GetRecentFetches, SaveRecentFetches } from "../wailsjs/go/main/App";
-import { EventsOn, EventsOff, Quit } from "../wailsjs/runtime/runtime";
+import { OpenFolder, CheckFFmpegInstalled, DownloadFFmpeg, GetRecentFetches, SaveRecentFetches, DownloadURLListFile, DownloadPlaylistFile, GetPreviousQueueSession, ResumePreviousQueueSession, DiscardPreviousQueueSession } from "../wailsjs/go/main/App";
+import { EventsOn, EventsOff, Quit, OnFileDrop, OnFileDropOff } from "../wailsjs/runtime/runtime";
 import { toastWithSound as toast } from "@/lib/toast-with-sound";
 import { TitleBar } from "@/components/TitleBar";
 import { Sidebar, type PageType } from "@/components/Sidebar";
@@ -123,6 +123,20 @@ function parseStoredHistory(value: string | null): HistoryItem[] {
         return [];
     }
 }
+async function importDroppedLists(paths: string[]) {
+    for (const path of paths) {
+        const name = path.split(/[\\/]/).pop() || path;
+        const isURLList = /\.(txt|m3u8?)$/i.test(path);
+        toast.info(`Importing ${name}...`);
+        try {
+            const result = isURLList ? await DownloadURLListFile(path) : await DownloadPlaylistFile(path);
+            toast.success(`${name}: ${result.downloaded} downloaded, ${result.skipped} skipped, ${result.failed} failed`);
+        }
+        catch (err) {
+            toast.error(`Failed to import ${name}: ${err}`);
+        }
+    }
+}
 function App() {
     const [currentPage, setCurrentPage] = useState<PageType>("main");
     const contentScrollRef = useRef<HTMLDivElement | null>(null);
@@ -225,6 +239,116 @@ function App() {
         contentScrollRef.current?.scrollTo({ top: 0, behavior: "auto" });
         setShowScrollTop(false);
     }, [currentPage]);
+    useEffect(() => {
+        if (currentPage !== "main")
+            return;
+        OnFileDrop((_x, _y, paths) => {
+            const lists = (paths || []).filter((path) => /\.(txt|m3u8?|csv|tsv|json)$/i.test(path));
+            if (lists.length === 0)
+                return;
+            void importDroppedLists(lists);
+        }, false);
+        return () => {
+            OnFileDropOff();
+        };
+    }, [currentPage]);
+    useEffect(() => {
+        EventsOn("clipboard-link-queued", (url: string) => {
+            toast.info(`Added from clipboard: ${url}`);
+        });
+        EventsOn("clipboard-link-done", (payload: {
+            url: string;
+            result: {
+                name: string;
+                downloaded: number;
+                skipped: number;
+                failed: number;
+            };
+        }) => {
+            const { result } = payload;
+            toast.success(`${result.name || payload.url}: ${result.downloaded} downloaded, ${result.skipped} skipped, ${result.failed} failed`);
+        });
+        EventsOn("network-status", (payload: {
+            online: boolean;
+        }) => {
+            if (payload.online) {
+                toast.success("Network restored, resuming downloads");
+            }
+            else {
+                toast.warning("Network connection lost, downloads paused until it returns");
+            }
+        });
+        EventsOn("download-schedule", (payload: {
+            allowed: boolean;
+            reason?: string;
+        }) => {
+            if (payload.allowed) {
+                toast.info("Download window open, resuming queue");
+            }
+            else {
+                toast.info(`Queue paused: ${payload.reason}`);
+            }
+        });
+        EventsOn("data-budget", (payload: {
+            exceeded: boolean;
+            reason?: string;
+        }) => {
+            if (payload.exceeded) {
+                toast.warning(`Queue paused: ${payload.reason}`);
+            }
+            else {
+                toast.info("Data budget available again, resuming queue");
+            }
+        });
+        EventsOn("watchlist-new-tracks", (result: {
+            name: string;
+            downloaded: number;
+            skipped: number;
+            failed: number;
+        }) => {
+            toast.success(`New releases in ${result.name}: ${result.downloaded} downloaded, ${result.skipped} skipped, ${result.failed} failed`);
+        });
+        EventsOn("queue-session-resumed", (result: {
+            downloaded: number;
+            skipped: number;
+            failed: number;
+        }) => {
+            toast.success(`Previous session: ${result.downloaded} downloaded, ${result.skipped} skipped, ${result.failed} failed`);
+        });
+        return () => {
+            EventsOff("clipboard-link-queued");
+            EventsOff("clipboard-link-done");
+            EventsOff("network-status");
+            EventsOff("watchlist-new-tracks");
+            EventsOff("queue-session-resumed");
+            EventsOff("download-schedule");
+            EventsOff("data-budget");
+        };
+    }, []);
+    useEffect(() => {
+        GetPreviousQueueSession().then((session) => {
+            if (!session || session.items.length === 0)
+                return;
+            const count = session.items.length;
+            toast.info(`${count} download${count === 1 ? "" : "s"} left unfinished last time`, {
+                duration: Infinity,
+                action: {
+                    label: "Resume",
+                    onClick: () => {
+                        ResumePreviousQueueSession().then((queued) => {
+                            toast.info(`Resuming ${queued} track${queued === 1 ? "" : "s"} from the previous session`);
+                        }).catch((err: unknown) => toast.error(`Failed to resume previous session: ${err}`));
+                    },
+                },
+                cancel: {
+                    label: "Discard",
+                    onClick: () => {
+                        DiscardPreviousQueueSession().catch(() => { });
+                    },
+                },
+            });
+        }).catch(() => { });
+    }, []);
     useEffect(() => {
         setSelectedTracks([]);
         setSearchQuery("");
@@ -1,9 +1,9 @@
 import { useEffect, useState } from "react";
-import { X, Download, CheckCircle2, XCircle, Clock, FileCheck, Trash2, HardDrive, Zap, Timer, FileDown } from "lucide-react";
+import { X, Download, CheckCircle2, XCircle, Clock, FileCheck, Trash2, HardDrive, Zap, Timer, FileDown, ArrowUpToLine, ChevronsUp, Pause, Play } from "lucide-react";
 import { Button } from "@/components/ui/button";
 import { Dialog, DialogContent, DialogHeader, DialogTitle, } from "@/components/ui/dialog";
 import { Badge } from "@/components/ui/badge";
-import { GetDownloadQueue, ClearCompletedDownloads, ClearAllDownloads, ExportFailedDownloads } from "../../wailsjs/go/main/App";
+import { GetDownloadQueue, ClearCompletedDownloads, ClearAllDownloads, ExportFailedDownloads, MoveDownloadToFront, MoveAlbumToFront, PauseAll, ResumeAll, IsDownloadsPaused } from "../../wailsjs/go/main/App";
 import { toastWithSound as toast } from "@/lib/toast-with-sound";
 import { backend } from "../../wailsjs/go/models";
 interface DownloadQueueProps {
@@ -21,7 +21,9 @@ export function DownloadQueue({ isOpen, onClose }: DownloadQueueProps) {
         completed_count: 0,
         failed_count: 0,
         skipped_count: 0,
+        pause_reasons: [],
     }));
+    const [isPaused, setIsPaused] = useState(false);
     useEffect(() => {
         if (!isOpen)
             return;
@@ -35,9 +37,27 @@ export function DownloadQueue({ isOpen, onClose }: DownloadQueueProps) {
             }
         };
         fetchQueue();
+        IsDownloadsPaused().then(setIsPaused).catch(() => { });
         const interval = setInterval(fetchQueue, 500);
         return () => clearInterval(interval);
     }, [isOpen]);
+    const handleTogglePause = async () => {
+        try {
+            if (isPaused) {
+                await ResumeAll();
+                setIsPaused(false);
+                toast.success("Downloads resumed");
+            }
+            else {
+                await PauseAll();
+                setIsPaused(true);
+                toast.info("Downloads paused, in-flight tracks will finish first");
+            }
+        }
+        catch (error) {
+            toast.error(`Failed to ${isPaused ? "resume" : "pause"} downloads: ${error}`);
+        }
+    };
     const handleClearHistory = async () => {
         try {
             await ClearCompletedDownloads();
@@ -59,6 +79,27 @@ export function DownloadQueue({ isOpen, onClose }: DownloadQueueProps) {
             console.error("Failed to reset queue:", error);
         }
     };
+    const handleMoveToFront = async (itemID: string) => {
+        try {
+            await MoveDownloadToFront(itemID);
+            const info = await GetDownloadQueue();
+            setQueueInfo(info);
+        }
+        catch (error) {
+            toast.error(`Failed to move download: ${error}`);
+        }
+    };
+    const handleMoveAlbumToFront = async (albumName: string) => {
+        try {
+            const moved = await MoveAlbumToFront(albumName);
+            const info = await GetDownloadQueue();
+            setQueueInfo(info);
+            toast.success(`Moved ${moved} track${moved === 1 ? "" : "s"} from ${albumName} to the front`);
+        }
+        catch (error) {
+            toast.error(`Failed to move album: ${error}`);
+        }
+    };
     const handleExportFailed = async () => {
         try {
             const message = await ExportFailedDownloads();
@@ -135,6 +176,10 @@ export function DownloadQueue({ isOpen, onClose }: DownloadQueueProps) {
         <div className="flex items-center justify-between mb-4">
           <DialogTitle className="text-lg font-semibold hover:text-primary transition-colors cursor-pointer" onClick={handleReset}>Download Queue</DialogTitle>
           <div className="flex items-center gap-2">
+            {(isPaused || queueInfo.queued_count > 0 || queueInfo.is_downloading) && (<Button variant="ghost" size="sm" className="h-7 text-xs gap-1.5" onClick={handleTogglePause}>
+              {isPaused ? <Play className="h-3 w-3"/> : <Pause className="h-3 w-3"/>}
+              {isPaused ? "Resume All" : "Pause All"}
+            </Button>)}
             {(queueInfo.completed_count > 0 || queueInfo.failed_count > 0 || queueInfo.skipped_count > 0) && (<Button variant="ghost" size="sm" className="h-7 text-xs gap-1.5" onClick={handleClearHistory}>
               <Trash2 className="h-3 w-3"/>
               Clear History
@@ -171,6 +216,14 @@ export function DownloadQueue({ isOpen, onClose }: DownloadQueueProps) {
             <span className="text-muted-foreground">Failed:</span>
             <span className="font-semibold">{queueInfo.failed_count}</span>
           </div>
+          {queueInfo.pause_reasons?.includes("schedule") && (<div className="flex items-center gap-1.5 text-muted-foreground">
+              <Pause className="h-3.5 w-3.5"/>
+              <span>Outside download schedule</span>
+            </div>)}
+          {queueInfo.pause_reasons?.includes("budget") && (<div className="flex items-center gap-1.5 text-muted-foreground">
+              <Pause className="h-3.5 w-3.5"/>
+              <span>Data budget reached</span>
+            </div>)}
         </div>
 
 
@@ -224,7 +277,17 @@ export function DownloadQueue({ isOpen, onClose }: DownloadQueueProps) {
                       {item.album_name && ` • ${item.album_name}`}
                     </p>
                   </div>
-                  {getStatusBadge(item.status)}
+                  <div className="flex items-center gap-1">
+                    {item.status === "queued" && (<>
+                        <Button variant="ghost" size="icon" className="h-6 w-6" title="Download next" onClick={() => handleMoveToFront(item.id)}>
+                          <ArrowUpToLine className="h-3.5 w-3.5"/>
+                        </Button>
+                        {item.album_name && (<Button variant="ghost" size="icon" className="h-6 w-6" title="Download this album next" onClick={() => handleMoveAlbumToFront(item.album_name)}>
+                            <ChevronsUp className="h-3.5 w-3.5"/>
+                          </Button>)}
+                      </>)}
+                    {getStatusBadge(item.status)}
+                  </div>
                 </div>
 
 
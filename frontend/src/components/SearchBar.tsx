@@ -426,12 +426,15 @@ export function SearchBar({ url, loading, onUrlChange, onFetch, onFetchUrl, hist
         const trimmed = text.trim();
         if (!trimmed)
             return true;
-        const isUrl = /^(https?:\/\/|www\.)/i.test(trimmed) || /^spotify:/i.test(trimmed);
+        const isUrl = /^(https?:\/\/|www\.)/i.test(trimmed) || /^spotify:/i.test(trimmed) || /^(spotify\.link|spoti\.fi)\//i.test(trimmed);
         if (!isUrl)
             return true;
         return (trimmed.includes("spotify.com") ||
             trimmed.includes("spotify.link") ||
-            trimmed.startsWith("spotify:"));
+            trimmed.includes("spoti.fi") ||
+            trimmed.startsWith("spotify:") ||
+            /music\.apple\.com\/.*\/playlist\//i.test(trimmed) ||
+            /(music\.)?youtube\.com\/playlist\?/i.test(trimmed));
     };
     const handlePaste = (e: React.ClipboardEvent<HTMLInputElement>) => {
         if (searchMode)
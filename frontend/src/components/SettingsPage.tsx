@@ -356,6 +356,16 @@ export function SettingsPage({ onUnsavedChangesChange, onResetRequest, }: Settin
                   Sound Effects
                 </Label>
               </div>
+
+              <div className="flex items-center gap-3">
+                <Switch id="clipboard-watcher" checked={tempSettings.clipboardWatcher} onCheckedChange={(checked) => setTempSettings((prev) => ({
+                ...prev,
+                clipboardWatcher: checked,
+            }))}/>
+                <Label htmlFor="clipboard-watcher" className="cursor-pointer text-sm font-normal">
+                  Watch Clipboard for Spotify Links
+                </Label>
+              </div>
             </div>
 
             <div className="space-y-4">
@@ -596,6 +606,154 @@ export function SettingsPage({ onUnsavedChangesChange, onResetRequest, }: Settin
                       </Label>
                   </div>)}
 
+                <div className="space-y-2 pt-2">
+                  <Label>Minimum Quality</Label>
+                  <div className="flex items-center gap-3">
+                    <Select value={tempSettings.minQuality || "off"} onValueChange={(value) => setTempSettings((prev) => ({
+                ...prev,
+                minQuality: value === "off" ? "" : value,
+            }))}>
+                      <SelectTrigger className="h-9 w-fit">
+                        <SelectValue />
+                      </SelectTrigger>
+                      <SelectContent>
+                        <SelectItem value="off">Off</SelectItem>
+                        <SelectItem value="lossless">Any Lossless</SelectItem>
+                        <SelectItem value="16/44.1">16-bit/44.1kHz</SelectItem>
+                        <SelectItem value="24/48">24-bit/48kHz</SelectItem>
+                        <SelectItem value="24/96">24-bit/96kHz</SelectItem>
+                      </SelectContent>
+                    </Select>
+                    {tempSettings.minQuality && (<div className="flex items-center gap-2">
+                        <Switch id="min-quality-skip" checked={tempSettings.minQualitySkip} onCheckedChange={(checked) => setTempSettings((prev) => ({
+                    ...prev,
+                    minQualitySkip: checked,
+                }))}/>
+                        <Label htmlFor="min-quality-skip" className="text-sm font-normal cursor-pointer">
+                          Skip Instead of Degrade
+                        </Label>
+                      </div>)}
+                  </div>
+                </div>
+
+                <div className="space-y-2 pt-2">
+                  <Label>Parallel Connections</Label>
+                  <Select value={String(tempSettings.downloadSegments || 0)} onValueChange={(value) => setTempSettings((prev) => ({
+                ...prev,
+                downloadSegments: Number(value),
+            }))}>
+                    <SelectTrigger className="h-9 w-fit">
+                      <SelectValue />
+                    </SelectTrigger>
+                    <SelectContent>
+                      <SelectItem value="0">Single Stream</SelectItem>
+                      <SelectItem value="2">2 Connections</SelectItem>
+                      <SelectItem value="4">4 Connections</SelectItem>
+                      <SelectItem value="8">8 Connections</SelectItem>
+                    </SelectContent>
+                  </Select>
+                  <p className="text-xs text-muted-foreground">
+                    Used for files over 64 MB on hosts that support range requests.
+                  </p>
+                </div>
+
+                <div className="space-y-2 pt-2">
+                  <Label htmlFor="max-connections-per-host">Connections per Host</Label>
+                  <InputWithContext id="max-connections-per-host" type="number" min={0} value={tempSettings.maxConnectionsPerHost || ""} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                maxConnectionsPerHost: Math.max(0, Number(e.target.value) || 0),
+            }))} placeholder="Default (16)"/>
+                  <p className="text-xs text-muted-foreground">
+                    Connections are pooled and reused across tracks. Takes effect after restart.
+                  </p>
+                </div>
+
+                <div className="space-y-2 pt-2">
+                  <Label>Download Schedule</Label>
+                  <Select value={tempSettings.downloadScheduleMode} onValueChange={(value: "off" | "window" | "unmetered" | "window-or-unmetered") => setTempSettings((prev) => ({
+                ...prev,
+                downloadScheduleMode: value,
+            }))}>
+                    <SelectTrigger className="h-9 w-fit">
+                      <SelectValue />
+                    </SelectTrigger>
+                    <SelectContent>
+                      <SelectItem value="off">Any Time</SelectItem>
+                      <SelectItem value="window">Only Within Hours</SelectItem>
+                      <SelectItem value="unmetered">Only on Unmetered Networks</SelectItem>
+                      <SelectItem value="window-or-unmetered">Within Hours or on Unmetered Networks</SelectItem>
+                    </SelectContent>
+                  </Select>
+                  {(tempSettings.downloadScheduleMode === "window" || tempSettings.downloadScheduleMode === "window-or-unmetered") && (<div className="flex items-center gap-2 pt-1">
+                      <InputWithContext id="download-window-start" type="time" className="h-9 w-fit" value={tempSettings.downloadWindowStart} onChange={(e) => setTempSettings((prev) => ({
+                    ...prev,
+                    downloadWindowStart: e.target.value,
+                }))}/>
+                      <span className="text-sm text-muted-foreground">to</span>
+                      <InputWithContext id="download-window-end" type="time" className="h-9 w-fit" value={tempSettings.downloadWindowEnd} onChange={(e) => setTempSettings((prev) => ({
+                    ...prev,
+                    downloadWindowEnd: e.target.value,
+                }))}/>
+                    </div>)}
+                  <p className="text-xs text-muted-foreground">
+                    The queue pauses automatically outside the schedule. In-flight tracks finish first.
+                  </p>
+                </div>
+
+                <div className="space-y-2 pt-2">
+                  <Label htmlFor="data-budget-daily">Data Budget (MB)</Label>
+                  <div className="flex items-center gap-2">
+                    <InputWithContext id="data-budget-daily" type="number" min={0} className="h-9 w-32" value={tempSettings.dataBudgetDailyMB || ""} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                dataBudgetDailyMB: Math.max(0, Number(e.target.value) || 0),
+            }))} placeholder="Per day"/>
+                    <InputWithContext id="data-budget-monthly" type="number" min={0} className="h-9 w-32" value={tempSettings.dataBudgetMonthlyMB || ""} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                dataBudgetMonthlyMB: Math.max(0, Number(e.target.value) || 0),
+            }))} placeholder="Per month"/>
+                  </div>
+                  <div className="flex items-center gap-2">
+                    <Switch id="data-budget-metered-only" checked={tempSettings.dataBudgetMeteredOnly} onCheckedChange={(checked) => setTempSettings((prev) => ({
+                ...prev,
+                dataBudgetMeteredOnly: checked,
+            }))}/>
+                    <Label htmlFor="data-budget-metered-only" className="text-sm font-normal cursor-pointer">
+                      Only Count Metered Connections
+                    </Label>
+                  </div>
+                  <p className="text-xs text-muted-foreground">
+                    The queue pauses once the budget is used up. Leave empty for no limit.
+                  </p>
+                </div>
+
+                <div className="space-y-2 pt-2">
+                  <Label htmlFor="user-agent">User-Agent</Label>
+                  <InputWithContext id="user-agent" value={tempSettings.userAgent} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                userAgent: e.target.value,
+            }))} placeholder="Default browser User-Agent"/>
+                  <p className="text-xs text-muted-foreground">
+                    Per-service headers can be set under serviceHeaders in config.json.
+                  </p>
+                </div>
+
+                {tempSettings.downloader === "auto" && (<div className="space-y-2 pt-2">
+                    <Label htmlFor="allowed-services">Allowed Services</Label>
+                    <InputWithContext id="allowed-services" value={tempSettings.allowedServices} onChange={(e) => setTempSettings((prev) => ({
+                    ...prev,
+                    allowedServices: e.target.value,
+                }))} placeholder="All services (e.g. qobuz or tidal,qobuz)"/>
+                    <div className="flex items-center gap-3 pt-1">
+                      <Switch id="strict-service" checked={tempSettings.strictService} onCheckedChange={(checked) => setTempSettings((prev) => ({
+                    ...prev,
+                    strictService: checked,
+                }))}/>
+                      <Label htmlFor="strict-service" className="text-sm font-normal cursor-pointer">
+                        Strict Mode (No Service Fallback)
+                      </Label>
+                    </div>
+                  </div>)}
+
                 {(tempSettings.downloader === "auto" || tempSettings.downloader === "tidal") && (<div className="space-y-2 pt-2">
                     <Label>Custom Instance</Label>
                     <div className="flex items-center gap-2">
@@ -858,6 +1016,65 @@ export function SettingsPage({ onUnsavedChangesChange, onResetRequest, }: Settin
                   </span>
                 </p>)}
               </div>
+
+              <div className="space-y-2">
+                <Label htmlFor="max-path-length">Max Path Length</Label>
+                <InputWithContext id="max-path-length" type="number" min={0} value={tempSettings.maxPathLength || ""} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                maxPathLength: Math.max(0, Number(e.target.value) || 0),
+            }))} placeholder="Auto (260 on Windows)"/>
+                <p className="text-xs text-muted-foreground">
+                  Longer paths are shortened, keeping the track number and extension.
+                </p>
+              </div>
+
+              <div className="space-y-2">
+                <Label>Checksums</Label>
+                <Select value={tempSettings.checksumMode || "off"} onValueChange={(value) => setTempSettings((prev) => ({
+                ...prev,
+                checksumMode: value === "off" ? "" : value as "sha256" | "sfv",
+            }))}>
+                  <SelectTrigger className="h-9 w-fit">
+                    <SelectValue />
+                  </SelectTrigger>
+                  <SelectContent>
+                    <SelectItem value="off">Off</SelectItem>
+                    <SelectItem value="sha256">SHA-256 (checksums.sha256)</SelectItem>
+                    <SelectItem value="sfv">CRC32 (checksums.sfv)</SelectItem>
+                  </SelectContent>
+                </Select>
+                <p className="text-xs text-muted-foreground">
+                  Written per album folder. Run "SpotiFLAC verify &lt;dir&gt;" to re-check an archived library.
+                </p>
+              </div>
+
+              <div className="border-t pt-2"/>
+
+              <div className="space-y-2">
+                <Label>Skip Rules</Label>
+                <div className="flex items-center gap-2">
+                  <InputWithContext id="skip-min-duration" type="number" min={0} value={tempSettings.skipMinDuration || ""} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                skipMinDuration: Math.max(0, Number(e.target.value) || 0),
+            }))} placeholder="Min seconds"/>
+                  <InputWithContext id="skip-max-duration" type="number" min={0} value={tempSettings.skipMaxDuration || ""} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                skipMaxDuration: Math.max(0, Number(e.target.value) || 0),
+            }))} placeholder="Max seconds"/>
+                </div>
+                <InputWithContext id="skip-title-keywords" value={tempSettings.skipTitleKeywords} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                skipTitleKeywords: e.target.value,
+            }))} placeholder="Skip title keywords (e.g. sped up, 8D audio)"/>
+                <InputWithContext id="skip-artists" value={tempSettings.skipArtists} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                skipArtists: e.target.value,
+            }))} placeholder="Blocked artists, comma separated"/>
+                <InputWithContext id="skip-library-index" value={tempSettings.skipLibraryIndex} onChange={(e) => setTempSettings((prev) => ({
+                ...prev,
+                skipLibraryIndex: e.target.value,
+            }))} placeholder="Library ISRC index file (skip owned tracks)"/>
+              </div>
             </div>
           </div>)}
         
@@ -2,7 +2,7 @@ import { useState, useRef } from "react";
 import { downloadCover } from "@/lib/api";
 import { getSettings, parseTemplate, type TemplateData } from "@/lib/settings";
 import { toastWithSound as toast } from "@/lib/toast-with-sound";
-import { joinPath, sanitizePath, getFirstArtist } from "@/lib/utils";
+import { joinPath, sanitizePath, getFirstArtist, isVariousArtists } from "@/lib/utils";
 import { logger } from "@/lib/logger";
 import type { TrackMetadata } from "@/types/api";
 export function useCover() {
@@ -31,8 +31,9 @@ export function useCover() {
             const yearValue = releaseDate?.substring(0, 4);
             const displayArtist = settings.useFirstArtistOnly && artistName ? getFirstArtist(artistName) : artistName;
             const displayAlbumArtist = settings.useFirstArtistOnly && albumArtist ? getFirstArtist(albumArtist) : albumArtist;
+            const folderArtist = isVariousArtists(displayAlbumArtist) ? "Various Artists" : displayArtist;
             const templateData: TemplateData = {
-                artist: displayArtist?.replace(/\//g, placeholder),
+                artist: folderArtist?.replace(/\//g, placeholder),
                 album: albumName?.replace(/\//g, placeholder),
                 album_artist: displayAlbumArtist?.replace(/\//g, placeholder) || displayArtist?.replace(/\//g, placeholder),
                 title: trackName?.replace(/\//g, placeholder),
@@ -42,7 +43,7 @@ export function useCover() {
                 playlist: playlistName?.replace(/\//g, placeholder),
             };
             const folderTemplate = settings.folderTemplate || "";
-            const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{playlist}");
+            const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{albumartist}") || folderTemplate.includes("{playlist}");
             if (settings.createPlaylistFolder && playlistName && (!isAlbum || !useAlbumSubfolder)) {
                 outputDir = joinPath(os, outputDir, sanitizePath(playlistName.replace(/\//g, " "), os));
             }
@@ -133,8 +134,9 @@ export function useCover() {
                 const yearValue = track.release_date?.substring(0, 4);
                 const displayArtist = settings.useFirstArtistOnly && track.artists ? getFirstArtist(track.artists) : track.artists;
                 const displayAlbumArtist = settings.useFirstArtistOnly && track.album_artist ? getFirstArtist(track.album_artist) : track.album_artist;
+                const folderArtist = isVariousArtists(displayAlbumArtist) ? "Various Artists" : displayArtist;
                 const templateData: TemplateData = {
-                    artist: displayArtist?.replace(/\//g, placeholder),
+                    artist: folderArtist?.replace(/\//g, placeholder),
                     album: track.album_name?.replace(/\//g, placeholder),
                     album_artist: displayAlbumArtist?.replace(/\//g, placeholder) || displayArtist?.replace(/\//g, placeholder),
                     title: track.name?.replace(/\//g, placeholder),
@@ -144,7 +146,7 @@ export function useCover() {
                     playlist: playlistName?.replace(/\//g, placeholder),
                 };
                 const folderTemplate = settings.folderTemplate || "";
-                const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{playlist}");
+                const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{albumartist}") || folderTemplate.includes("{playlist}");
                 if (settings.createPlaylistFolder && playlistName && (!isAlbum || !useAlbumSubfolder)) {
                     outputDir = joinPath(os, outputDir, sanitizePath(playlistName.replace(/\//g, " "), os));
                 }
@@ -2,9 +2,9 @@ import { useState, useRef } from "react";
 import { downloadTrack, fetchSpotifyMetadata } from "@/lib/api";
 import { getSettings, parseTemplate, type TemplateData } from "@/lib/settings";
 import { toastWithSound as toast } from "@/lib/toast-with-sound";
-import { joinPath, sanitizePath, getFirstArtist } from "@/lib/utils";
+import { joinPath, sanitizePath, getFirstArtist, isVariousArtists } from "@/lib/utils";
 import { logger } from "@/lib/logger";
-import type { TrackMetadata } from "@/types/api";
+import type { TrackMetadata, DownloadRequest, DownloadResponse } from "@/types/api";
 interface CheckFileExistenceRequest {
     spotify_id: string;
     track_name: string;
@@ -32,7 +32,11 @@ interface FileExistenceResult {
 const CheckFilesExistence = (outputDir: string, rootDir: string, tracks: CheckFileExistenceRequest[]): Promise<FileExistenceResult[]> => (window as any)["go"]["main"]["App"]["CheckFilesExistence"](outputDir, rootDir, tracks);
 const SkipDownloadItem = (itemID: string, filePath: string): Promise<void> => (window as any)["go"]["main"]["App"]["SkipDownloadItem"](itemID, filePath);
 const CreateM3U8File = (playlistName: string, outputDir: string, filePaths: string[]): Promise<void> => (window as any)["go"]["main"]["App"]["CreateM3U8File"](playlistName, outputDir, filePaths);
+const NotifyBatchComplete = (name: string, listType: string, files: string[], downloaded: number, skipped: number, failed: number): Promise<void> => (window as any)["go"]["main"]["App"]["NotifyBatchComplete"](name, listType, files, downloaded, skipped, failed);
+const SetDownloadItemPlaylist = (itemID: string, playlistName: string, position: number): Promise<void> => (window as any)["go"]["main"]["App"]["SetDownloadItemPlaylist"](itemID, playlistName, position);
+const NotifyTrackOutcome = (request: Partial<DownloadRequest>, response: DownloadResponse): Promise<void> => (window as any)["go"]["main"]["App"]["NotifyTrackOutcome"](request, response);
 const GetTrackISRC = (spotifyId: string): Promise<string> => (window as any)["go"]["main"]["App"]["GetTrackISRC"](spotifyId);
+const CheckSkipRules = (tracks: TrackMetadata[]): Promise<Record<string, string>> => (window as any)["go"]["main"]["App"]["CheckSkipRules"](tracks);
 async function resolveTemplateISRC(settings: {
     folderTemplate?: string;
     filenameTemplate?: string;
@@ -65,6 +69,33 @@ function getTidalAudioFormat(settings: any, mode: "single" | "auto"): "LOSSLESS"
 function shouldFetchStreamingURLs(order: string[]): boolean {
     return order.includes("amazon") || order.includes("tidal");
 }
+function getAllowedServices(settings: any): string[] {
+    return String(settings.allowedServices || "").toLowerCase().split(/[\s,;-]+/).filter(Boolean);
+}
+function getServiceOrder(settings: any): string[] {
+    const order: string[] = (settings.autoOrder || "tidal-amazon-qobuz").split("-");
+    const allowed = getAllowedServices(settings);
+    if (allowed.length === 0)
+        return order;
+    return [...order.filter((s) => allowed.includes(s)), ...allowed.filter((s) => !order.includes(s))];
+}
+async function takeNextInQueueOrder<T>(pending: T[], itemIDOf: (track: T) => string): Promise<T> {
+    try {
+        const { GetDownloadQueue } = await import("../../wailsjs/go/main/App");
+        const info = await GetDownloadQueue();
+        const order = new Map((info.queue || []).map((item, index) => [item.id, index]));
+        const rank = (track: T) => order.get(itemIDOf(track)) ?? Number.MAX_SAFE_INTEGER;
+        let next = 0;
+        for (let i = 1; i < pending.length; i++) {
+            if (rank(pending[i]) < rank(pending[next]))
+                next = i;
+        }
+        return pending.splice(next, 1)[0];
+    }
+    catch {
+        return pending.shift() as T;
+    }
+}
 export function useDownload(region: string) {
     const [downloadProgress, setDownloadProgress] = useState<number>(0);
     const [downloadRemainingCount, setDownloadRemainingCount] = useState<number>(0);
@@ -126,8 +157,9 @@ export function useDownload(region: string) {
             ? getFirstArtist(albumArtist)
             : albumArtist;
         const resolvedTemplateISRC = await resolveTemplateISRC(settings, spotifyId || id);
+        const folderArtist = isVariousArtists(displayAlbumArtist) ? "Various Artists" : displayArtist;
         const templateData: TemplateData = {
-            artist: displayArtist?.replace(/\//g, placeholder),
+            artist: folderArtist?.replace(/\//g, placeholder),
             album: albumName?.replace(/\//g, placeholder),
             album_artist: displayAlbumArtist?.replace(/\//g, placeholder) || displayArtist?.replace(/\//g, placeholder),
             title: trackName?.replace(/\//g, placeholder),
@@ -138,7 +170,7 @@ export function useDownload(region: string) {
             playlist: playlistName?.replace(/\//g, placeholder),
         };
         const folderTemplate = settings.folderTemplate || "";
-        const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{playlist}");
+        const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{albumartist}") || folderTemplate.includes("{playlist}");
         if (settings.createPlaylistFolder && playlistName && !useAlbumSubfolder) {
             outputDir = joinPath(os, outputDir, sanitizePath(playlistName.replace(/\//g, " "), os));
         }
@@ -193,7 +225,7 @@ export function useDownload(region: string) {
             itemID = await AddToDownloadQueue(id, trackName || "", displayArtist || "", albumName || "");
         }
         if (service === "auto") {
-            const order = (settings.autoOrder || "tidal-amazon-qobuz").split("-");
+            const order = getServiceOrder(settings);
             let streamingURLs: any = null;
             if (spotifyId && shouldFetchStreamingURLs(order)) {
                 try {
@@ -212,6 +244,8 @@ export function useDownload(region: string) {
             const is24Bit = (settings.autoQuality || "24") === "24";
             const qobuzQuality = is24Bit ? "27" : "6";
             for (const s of order) {
+                if (settings.strictService && fallbackErrors.length > 0)
+                    break;
                 if (s === "tidal" && streamingURLs?.tidal_url) {
                     try {
                         logger.debug(`trying Tidal for: ${trackName} - ${artistName}`);
@@ -408,6 +442,7 @@ export function useDownload(region: string) {
             use_first_artist_only: settings.useFirstArtistOnly,
             use_single_genre: settings.useSingleGenre,
             embed_genre: settings.embedGenre,
+            allowed_services: getAllowedServices(settings),
         });
         if (!singleServiceResponse.success && itemID) {
             const { MarkDownloadItemFailed } = await import("../../wailsjs/go/main/App");
@@ -450,8 +485,9 @@ export function useDownload(region: string) {
             ? getFirstArtist(albumArtist)
             : albumArtist;
         const resolvedTemplateISRC = await resolveTemplateISRC(settings, spotifyId);
+        const folderArtist = isVariousArtists(displayAlbumArtist) ? "Various Artists" : displayArtist;
         const templateData: TemplateData = {
-            artist: displayArtist?.replace(/\//g, placeholder),
+            artist: folderArtist?.replace(/\//g, placeholder),
             album: albumName?.replace(/\//g, placeholder),
             album_artist: displayAlbumArtist?.replace(/\//g, placeholder) || displayArtist?.replace(/\//g, placeholder),
             title: trackName?.replace(/\//g, placeholder),
@@ -462,7 +498,7 @@ export function useDownload(region: string) {
             playlist: folderName?.replace(/\//g, placeholder),
         };
         const folderTemplate = settings.folderTemplate || "";
-        const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{playlist}");
+        const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{albumartist}") || folderTemplate.includes("{playlist}");
         if (settings.createPlaylistFolder && folderName && (!isAlbum || !useAlbumSubfolder)) {
             outputDir = joinPath(os, outputDir, sanitizePath(folderName.replace(/\//g, " "), os));
         }
@@ -477,7 +513,7 @@ export function useDownload(region: string) {
             }
         }
         if (service === "auto") {
-            const order = (settings.autoOrder || "tidal-amazon-qobuz").split("-");
+            const order = getServiceOrder(settings);
             let streamingURLs: any = null;
             if (spotifyId && shouldFetchStreamingURLs(order)) {
                 try {
@@ -496,6 +532,8 @@ export function useDownload(region: string) {
             const is24Bit = (settings.autoQuality || "24") === "24";
             const qobuzQuality = is24Bit ? "27" : "6";
             for (const s of order) {
+                if (settings.strictService && fallbackErrors.length > 0)
+                    break;
                 if (s === "tidal" && streamingURLs?.tidal_url) {
                     try {
                         logger.debug(`trying Tidal for: ${trackName} - ${artistName}`);
@@ -689,6 +727,7 @@ export function useDownload(region: string) {
             use_first_artist_only: settings.useFirstArtistOnly,
             use_single_genre: settings.useSingleGenre,
             embed_genre: settings.embedGenre,
+            allowed_services: getAllowedServices(settings),
         });
         if (!singleServiceResponse.success && itemID) {
             const { MarkDownloadItemFailed } = await import("../../wailsjs/go/main/App");
@@ -708,8 +747,9 @@ export function useDownload(region: string) {
         try {
             const releaseYear = releaseDate?.substring(0, 4);
             const response = await downloadWithAutoFallback(id, settings, trackName, artistName, albumName, playlistName, position, spotifyId, durationMs, releaseYear, albumArtist || "", releaseDate, coverUrl, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks, spotifyTotalDiscs, copyright, publisher);
+            NotifyTrackOutcome({ spotify_id: spotifyId || id, track_name: trackName, artist_name: displayArtist, album_name: albumName, service: response.service as DownloadRequest["service"] }, response).catch(() => { });
             if (response.success) {
-                if (response.already_exists) {
+                if (response.already_exists || response.skipped) {
                     toast.info(response.message);
                     setSkippedTracks((prev) => new Set(prev).add(id));
                 }
@@ -791,6 +831,14 @@ export function useDownload(region: string) {
             }
         }
         logger.info(`found ${existingSpotifyIDs.size} existing files`);
+        const excludedTracks = await CheckSkipRules(selectedTrackObjects).catch(() => ({} as Record<string, string>));
+        for (const [trackID, reason] of Object.entries(excludedTracks)) {
+            if (!existingSpotifyIDs.has(trackID)) {
+                logger.info(`skipping ${trackID}: ${reason}`);
+                existingSpotifyIDs.add(trackID);
+                existingFilePaths.set(trackID, "");
+            }
+        }
         const { AddToDownloadQueue } = await import("../../wailsjs/go/main/App");
         const itemIDs: string[] = [];
         for (const id of selectedTracks) {
@@ -801,6 +849,9 @@ export function useDownload(region: string) {
             const displayArtist = settings.useFirstArtistOnly && track.artists ? getFirstArtist(track.artists) : track.artists;
             const itemID = await AddToDownloadQueue(trackID, track.name || "", displayArtist || "", track.album_name || "");
             itemIDs.push(itemID);
+            if (folderName && !isAlbum) {
+                SetDownloadItemPlaylist(itemID, folderName, selectedTracks.indexOf(id) + 1).catch(() => { });
+            }
             if (existingSpotifyIDs.has(trackID)) {
                 const filePath = existingFilePaths.get(trackID) || "";
                 setTimeout(() => SkipDownloadItem(itemID, filePath), 10);
@@ -817,12 +868,13 @@ export function useDownload(region: string) {
         let skippedCount = existingSpotifyIDs.size;
         const total = selectedTracks.length;
         updateBatchProgress(skippedCount, total);
+        const pendingTracks = [...tracksToDownload];
         for (let i = 0; i < tracksToDownload.length; i++) {
             if (shouldStopDownloadRef.current) {
                 toast.info(`Download stopped. ${successCount} tracks downloaded, ${tracksToDownload.length - i} remaining.`);
                 break;
             }
-            const track = tracksToDownload[i];
+            const track = await takeNextInQueueOrder(pendingTracks, (t) => itemIDs[selectedTracks.indexOf(t.spotify_id || "")]);
             const id = track.spotify_id || "";
             const originalIndex = selectedTracks.indexOf(id);
             const itemID = itemIDs[originalIndex];
@@ -832,8 +884,9 @@ export function useDownload(region: string) {
             try {
                 const releaseYear = track.release_date?.substring(0, 4);
                 const response = await downloadWithItemID(settings, itemID, track.name, track.artists, track.album_name, folderName, originalIndex + 1, track.spotify_id, track.duration_ms, isAlbum, releaseYear, track.album_artist || "", track.release_date, track.images, track.track_number, track.disc_number, track.total_tracks, track.total_discs, track.copyright, track.publisher);
+                NotifyTrackOutcome({ spotify_id: track.spotify_id, track_name: track.name, artist_name: displayArtist, album_name: track.album_name, service: response.service as DownloadRequest["service"] }, response).catch(() => { });
                 if (response.success) {
-                    if (response.already_exists) {
+                    if (response.already_exists || response.skipped) {
                         skippedCount++;
                         logger.info(`skipped: ${track.name} - ${displayArtist} (already exists)`);
                         setSkippedTracks((prev) => new Set(prev).add(id));
@@ -894,6 +947,10 @@ export function useDownload(region: string) {
             }
         }
         logger.info(`batch complete: ${successCount} downloaded, ${skippedCount} skipped, ${errorCount} failed`);
+        if (folderName) {
+            const paths = selectedTrackObjects.map((t) => finalFilePaths.get(t.spotify_id || "") || "").filter((p) => p !== "");
+            NotifyBatchComplete(folderName, isAlbum ? "album" : "playlist", paths, successCount, skippedCount, errorCount).catch(() => { });
+        }
         if (errorCount === 0 && skippedCount === 0) {
             toast.success(`Downloaded ${successCount} tracks successfully`);
         }
@@ -967,12 +1024,23 @@ export function useDownload(region: string) {
             }
         }
         logger.info(`found ${existingSpotifyIDs.size} existing files`);
+        const excludedTracks = await CheckSkipRules(tracksWithId).catch(() => ({} as Record<string, string>));
+        for (const [trackID, reason] of Object.entries(excludedTracks)) {
+            if (!existingSpotifyIDs.has(trackID)) {
+                logger.info(`skipping ${trackID}: ${reason}`);
+                existingSpotifyIDs.add(trackID);
+                existingFilePaths.set(trackID, "");
+            }
+        }
         const { AddToDownloadQueue } = await import("../../wailsjs/go/main/App");
         const itemIDs: string[] = [];
         for (const track of tracksWithId) {
             const displayArtist = settings.useFirstArtistOnly && track.artists ? getFirstArtist(track.artists) : track.artists;
             const itemID = await AddToDownloadQueue(track.spotify_id || "", track.name || "", displayArtist || "", track.album_name || "");
             itemIDs.push(itemID);
+            if (folderName && !isAlbum) {
+                SetDownloadItemPlaylist(itemID, folderName, itemIDs.length).catch(() => { });
+            }
             const trackID = track.spotify_id || "";
             if (existingSpotifyIDs.has(trackID)) {
                 const filePath = existingFilePaths.get(trackID) || "";
@@ -990,12 +1058,13 @@ export function useDownload(region: string) {
         let skippedCount = existingSpotifyIDs.size;
         const total = tracksWithId.length;
         updateBatchProgress(skippedCount, total);
+        const pendingTracks = [...tracksToDownload];
         for (let i = 0; i < tracksToDownload.length; i++) {
             if (shouldStopDownloadRef.current) {
                 toast.info(`Download stopped. ${successCount} tracks downloaded, ${tracksToDownload.length - i} remaining.`);
                 break;
             }
-            const track = tracksToDownload[i];
+            const track = await takeNextInQueueOrder(pendingTracks, (t) => itemIDs[tracksWithId.findIndex((candidate) => candidate.spotify_id === t.spotify_id)]);
             const originalIndex = tracksWithId.findIndex((t) => t.spotify_id === track.spotify_id);
             const itemID = itemIDs[originalIndex];
             const trackId = track.spotify_id || "";
@@ -1005,8 +1074,9 @@ export function useDownload(region: string) {
             try {
                 const releaseYear = track.release_date?.substring(0, 4);
                 const response = await downloadWithItemID(settings, itemID, track.name, track.artists, track.album_name, folderName, originalIndex + 1, track.spotify_id, track.duration_ms, isAlbum, releaseYear, track.album_artist || "", track.release_date, track.images, track.track_number, track.disc_number, track.total_tracks, track.total_discs, track.copyright, track.publisher);
+                NotifyTrackOutcome({ spotify_id: track.spotify_id, track_name: track.name, artist_name: displayArtist, album_name: track.album_name, service: response.service as DownloadRequest["service"] }, response).catch(() => { });
                 if (response.success) {
-                    if (response.already_exists) {
+                    if (response.already_exists || response.skipped) {
                         skippedCount++;
                         logger.info(`skipped: ${track.name} - ${displayArtist} (already exists)`);
                         setSkippedTracks((prev) => new Set(prev).add(trackId));
@@ -1061,6 +1131,9 @@ export function useDownload(region: string) {
             }
         }
         logger.info(`batch complete: ${successCount} downloaded, ${skippedCount} skipped, ${errorCount} failed`);
+        if (folderName) {
+            NotifyBatchComplete(folderName, isAlbum ? "album" : "playlist", finalFilePaths.filter(p => p !== ""), successCount, skippedCount, errorCount).catch(() => { });
+        }
         if (errorCount === 0 && skippedCount === 0) {
             toast.success(`Downloaded ${successCount} tracks successfully`);
         }
@@ -12,6 +12,7 @@ export function useDownloadQueueData() {
         completed_count: 0,
         failed_count: 0,
         skipped_count: 0,
+        pause_reasons: [],
     }));
     useEffect(() => {
         const fetchQueue = async () => {
@@ -2,7 +2,7 @@ import { useState, useRef } from "react";
 import { downloadLyrics } from "@/lib/api";
 import { getSettings, parseTemplate, type TemplateData } from "@/lib/settings";
 import { toastWithSound as toast } from "@/lib/toast-with-sound";
-import { joinPath, sanitizePath, getFirstArtist } from "@/lib/utils";
+import { joinPath, sanitizePath, getFirstArtist, isVariousArtists } from "@/lib/utils";
 import { logger } from "@/lib/logger";
 import type { TrackMetadata } from "@/types/api";
 const GetTrackISRC = (spotifyId: string): Promise<string> => (window as any)["go"]["main"]["App"]["GetTrackISRC"](spotifyId);
@@ -53,8 +53,9 @@ export function useLyrics() {
             const displayArtist = settings.useFirstArtistOnly && artistName ? getFirstArtist(artistName) : artistName;
             const displayAlbumArtist = settings.useFirstArtistOnly && albumArtist ? getFirstArtist(albumArtist) : albumArtist;
             const resolvedTemplateISRC = await resolveTemplateISRC(settings, spotifyId);
+            const folderArtist = isVariousArtists(displayAlbumArtist) ? "Various Artists" : displayArtist;
             const templateData: TemplateData = {
-                artist: displayArtist?.replace(/\//g, placeholder),
+                artist: folderArtist?.replace(/\//g, placeholder),
                 album: albumName?.replace(/\//g, placeholder),
                 album_artist: displayAlbumArtist?.replace(/\//g, placeholder) || displayArtist?.replace(/\//g, placeholder),
                 title: trackName?.replace(/\//g, placeholder),
@@ -65,7 +66,7 @@ export function useLyrics() {
                 playlist: playlistName?.replace(/\//g, placeholder),
             };
             const folderTemplate = settings.folderTemplate || "";
-            const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{playlist}");
+            const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{albumartist}") || folderTemplate.includes("{playlist}");
             if (settings.createPlaylistFolder && playlistName && (!isAlbum || !useAlbumSubfolder)) {
                 outputDir = joinPath(os, outputDir, sanitizePath(playlistName.replace(/\//g, " "), os));
             }
@@ -157,8 +158,9 @@ export function useLyrics() {
                 const displayArtist = settings.useFirstArtistOnly && track.artists ? getFirstArtist(track.artists) : track.artists;
                 const displayAlbumArtist = settings.useFirstArtistOnly && track.album_artist ? getFirstArtist(track.album_artist) : track.album_artist;
                 const resolvedTemplateISRC = await resolveTemplateISRC(settings, id);
+                const folderArtist = isVariousArtists(displayAlbumArtist) ? "Various Artists" : displayArtist;
                 const templateData: TemplateData = {
-                    artist: displayArtist?.replace(/\//g, placeholder),
+                    artist: folderArtist?.replace(/\//g, placeholder),
                     album: track.album_name?.replace(/\//g, placeholder),
                     album_artist: displayAlbumArtist?.replace(/\//g, placeholder) || displayArtist?.replace(/\//g, placeholder),
                     title: track.name?.replace(/\//g, placeholder),
@@ -169,7 +171,7 @@ export function useLyrics() {
                     playlist: playlistName?.replace(/\//g, placeholder),
                 };
                 const folderTemplate = settings.folderTemplate || "";
-                const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{playlist}");
+                const useAlbumSubfolder = folderTemplate.includes("{album}") || folderTemplate.includes("{album_artist}") || folderTemplate.includes("{albumartist}") || folderTemplate.includes("{playlist}");
                 if (settings.createPlaylistFolder && playlistName && (!isAlbum || !useAlbumSubfolder)) {
                     outputDir = joinPath(os, outputDir, sanitizePath(playlistName.replace(/\//g, " "), os));
                 }
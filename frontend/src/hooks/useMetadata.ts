@@ -4,7 +4,8 @@ import { toastWithSound as toast } from "@/lib/toast-with-sound";
 import { logger } from "@/lib/logger";
 import { AddFetchHistory, SearchSpotifyByType } from "../../wailsjs/go/main/App";
 import { EventsOff, EventsOn } from "../../wailsjs/runtime/runtime";
-import type { SpotifyMetadataResponse } from "@/types/api";
+import type { EpisodeResponse, SpotifyMetadataResponse } from "@/types/api";
+const DownloadPodcastEpisode = (url: string): Promise<string> => (window as any)["go"]["main"]["App"]["DownloadPodcastEpisode"](url);
 export function useMetadata() {
     const [loading, setLoading] = useState(false);
     const [metadata, setMetadata] = useState<SpotifyMetadataResponse | null>(null);
@@ -105,13 +106,13 @@ export function useMetadata() {
         return () => EventsOff("metadata-stream");
     }, []);
     const getUrlType = (url: string): string => {
-        if (url.includes("/track/"))
+        if (url.includes("/track/") || url.includes(":track:"))
             return "track";
-        if (url.includes("/album/"))
+        if (url.includes("/album/") || url.includes(":album:"))
             return "album";
-        if (url.includes("/playlist/"))
+        if (url.includes("/playlist/") || url.includes("/playlist?") || url.includes(":playlist:"))
             return "playlist";
-        if (url.includes("/artist/"))
+        if (url.includes("/artist/") || url.includes(":artist:"))
             return "artist";
         return "unknown";
     };
@@ -177,6 +178,21 @@ export function useMetadata() {
             const timeout = urlType === "artist" ? 60 : 300;
             const data = await fetchSpotifyMetadata(url, true, 1.0, timeout);
             const elapsed = ((Date.now() - startTime) / 1000).toFixed(2);
+            if ("episodes" in data) {
+                const podcast = data as unknown as EpisodeResponse;
+                logger.info(`podcast ${podcast.type} detected: ${podcast.show.name}`);
+                setMetadata(null);
+                toast.info(podcast.message, podcast.type === "episode" ? {
+                    duration: 15000,
+                    action: {
+                        label: "Download Episode",
+                        onClick: () => {
+                            void downloadPodcastEpisode(url);
+                        },
+                    },
+                } : undefined);
+                return;
+            }
             if ("playlist_info" in data) {
                 const playlistInfo = data.playlist_info;
                 if (!playlistInfo.owner.name && playlistInfo.tracks.total === 0 && data.track_list.length === 0) {
@@ -226,6 +242,16 @@ export function useMetadata() {
             setLoading(false);
         }
     };
+    const downloadPodcastEpisode = async (url: string) => {
+        toast.info("Downloading podcast episode...");
+        try {
+            const filePath = await DownloadPodcastEpisode(url);
+            toast.success(`Episode saved to ${filePath}`);
+        }
+        catch (err) {
+            toast.error(`Failed to download episode: ${err}`);
+        }
+    };
     const loadFromCache = (cachedData: string) => {
         try {
             const data = JSON.parse(cachedData);
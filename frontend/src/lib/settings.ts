@@ -36,6 +36,7 @@ export interface Settings {
     albumSubfolder?: boolean;
     trackNumber: boolean;
     sfxEnabled: boolean;
+    clipboardWatcher: boolean;
     embedLyrics: boolean;
     embedMaxQualityCover: boolean;
     operatingSystem: "Windows" | "linux/MacOS";
@@ -45,6 +46,31 @@ export interface Settings {
     autoOrder: "tidal-qobuz-amazon" | "tidal-amazon-qobuz" | "qobuz-tidal-amazon" | "qobuz-amazon-tidal" | "amazon-tidal-qobuz" | "amazon-qobuz-tidal" | string;
     autoQuality: "16" | "24";
     allowFallback: boolean;
+    allowedServices: string;
+    strictService: boolean;
+    minQuality: string;
+    minQualitySkip: boolean;
+    downloadSegments: number;
+    maxConnectionsPerHost: number;
+    userAgent: string;
+    serviceHeaders: Record<string, Record<string, string>>;
+    downloadScheduleMode: "off" | "window" | "unmetered" | "window-or-unmetered";
+    downloadWindowStart: string;
+    downloadWindowEnd: string;
+    dataBudgetDailyMB: number;
+    dataBudgetMonthlyMB: number;
+    dataBudgetMeteredOnly: boolean;
+    apiServerEnabled: boolean;
+    apiServerPort: number;
+    apiServerToken: string;
+    apiServerAllowedOrigins: string;
+    skipMinDuration: number;
+    skipMaxDuration: number;
+    skipTitleKeywords: string;
+    skipArtists: string;
+    skipLibraryIndex: string;
+    maxPathLength: number;
+    checksumMode: "" | "sha256" | "sfv";
     createPlaylistFolder: boolean;
     playlistOwnerFolderName: boolean;
     createM3u8File: boolean;
@@ -179,6 +205,7 @@ export const DEFAULT_SETTINGS: Settings = {
     filenameTemplate: "{title} - {artist}",
     trackNumber: false,
     sfxEnabled: true,
+    clipboardWatcher: false,
     embedLyrics: false,
     embedMaxQualityCover: false,
     operatingSystem: detectOS(),
@@ -188,6 +215,31 @@ export const DEFAULT_SETTINGS: Settings = {
     autoOrder: "tidal-qobuz-amazon",
     autoQuality: "16",
     allowFallback: true,
+    allowedServices: "",
+    strictService: false,
+    minQuality: "",
+    minQualitySkip: true,
+    downloadSegments: 0,
+    maxConnectionsPerHost: 0,
+    userAgent: "",
+    serviceHeaders: {},
+    downloadScheduleMode: "off",
+    downloadWindowStart: "01:00",
+    downloadWindowEnd: "07:00",
+    dataBudgetDailyMB: 0,
+    dataBudgetMonthlyMB: 0,
+    dataBudgetMeteredOnly: false,
+    apiServerEnabled: false,
+    apiServerPort: 0,
+    apiServerToken: "",
+    apiServerAllowedOrigins: "",
+    skipMinDuration: 0,
+    skipMaxDuration: 0,
+    skipTitleKeywords: "",
+    skipArtists: "",
+    skipLibraryIndex: "",
+    maxPathLength: 0,
+    checksumMode: "",
     createPlaylistFolder: true,
     playlistOwnerFolderName: false,
     createM3u8File: false,
@@ -730,10 +782,10 @@ export function parseTemplate(template: string, data: TemplateData): string {
     result = result.replace(/\{title\}/g, data.title || "Unknown Title");
     result = result.replace(/\{artist\}/g, data.artist || "Unknown Artist");
     result = result.replace(/\{album\}/g, data.album || "Unknown Album");
-    result = result.replace(/\{album_artist\}/g, data.album_artist || data.artist || "Unknown Artist");
+    result = result.replace(/\{album_?artist\}/g, data.album_artist || data.artist || "Unknown Artist");
     result = result.replace(/\{isrc\}/g, data.isrc || "");
-    result = result.replace(/\{track\}/g, data.track ? String(data.track).padStart(2, "0") : "00");
-    result = result.replace(/\{disc\}/g, data.disc ? String(data.disc) : "1");
+    result = result.replace(/\{track(?::(\d+))?\}/g, (_, width) => String(data.track || 0).padStart(width ? Number(width) : 2, "0"));
+    result = result.replace(/\{disc(?::(\d+))?\}/g, (_, width) => String(data.disc || 1).padStart(width ? Number(width) : 1, "0"));
     result = result.replace(/\{year\}/g, data.year || "0000");
     result = result.replace(/\{date\}/g, data.date || "0000-00-00");
     result = result.replace(/\{playlist\}/g, data.playlist || "");
@@ -46,6 +46,12 @@ export function openExternal(url: string) {
         }
     }
 }
+export function isVariousArtists(artistString?: string): boolean {
+    if (!artistString)
+        return false;
+    const normalized = artistString.toLowerCase().replace(/[^\p{L}\p{N}]+/gu, " ").trim();
+    return ["various artists", "various", "va", "v a", "verschiedene", "artistes divers", "varios artistas"].includes(normalized);
+}
 export function getFirstArtist(artistString: string): string {
     if (!artistString)
         return artistString;
@@ -109,6 +109,28 @@ export interface ArtistResponse {
         popularity: number;
     };
 }
+export interface PodcastShow {
+    name: string;
+    publisher?: string;
+    images?: string;
+    external_urls: string;
+}
+export interface PodcastEpisode {
+    spotify_id: string;
+    name: string;
+    show_name: string;
+    description?: string;
+    duration_ms: number;
+    release_date?: string;
+    images?: string;
+    external_urls: string;
+}
+export interface EpisodeResponse {
+    type: "episode" | "show";
+    show: PodcastShow;
+    episodes: PodcastEpisode[];
+    message: string;
+}
 export type SpotifyMetadataResponse = TrackResponse | AlbumResponse | PlaylistResponse | ArtistDiscographyResponse | ArtistResponse;
 export interface DownloadRequest {
     service: "tidal" | "qobuz" | "amazon";
@@ -144,6 +166,7 @@ export interface DownloadRequest {
     use_first_artist_only?: boolean;
     use_single_genre?: boolean;
     embed_genre?: boolean;
+    allowed_services?: string[];
 }
 export interface DownloadResponse {
     success: boolean;
@@ -151,7 +174,10 @@ export interface DownloadResponse {
     file?: string;
     error?: string;
     already_exists?: boolean;
+    skipped?: boolean;
     item_id?: string;
+    service?: string;
+    quality?: string;
 }
 export interface HealthResponse {
     status: string;
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+type GapTrack struct {
+	SpotifyID string `json:"spotify_id"`
+	Name      string `json:"name"`
+	Artists   string `json:"artists"`
+	AlbumName string `json:"album_name"`
+	ISRC      string `json:"isrc,omitempty"`
+	Source    string `json:"source"`
+	Position  int    `json:"position"`
+}
+
+type GapSource struct {
+	URL     string `json:"url"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Total   int    `json:"total"`
+	Present int    `json:"present"`
+	Missing int    `json:"missing"`
+	Error   string `json:"error,omitempty"`
+}
+
+type GapAnalysisResult struct {
+	Folder     string                `json:"folder"`
+	LocalFiles int                   `json:"local_files"`
+	Sources    []GapSource           `json:"sources"`
+	Missing    []GapTrack            `json:"missing"`
+	Downloads  []BatchDownloadResult `json:"downloads,omitempty"`
+}
+
+type localTrackIndex struct {
+	isrcs map[string]bool
+	keys  map[string]bool
+}
+
+func buildLocalTrackIndex(tracks []backend.LibraryTrack) localTrackIndex {
+	index := localTrackIndex{
+		isrcs: make(map[string]bool, len(tracks)),
+		keys:  make(map[string]bool, len(tracks)),
+	}
+	for _, track := range tracks {
+		if isrc := strings.ToUpper(strings.TrimSpace(track.Metadata.ISRC)); isrc != "" {
+			index.isrcs[isrc] = true
+		}
+		if key := backend.TrackMatchKey(track.Metadata.Title, track.Metadata.Artist); key != "" {
+			index.keys[key] = true
+		}
+	}
+	return index
+}
+
+func (idx localTrackIndex) contains(track backend.AlbumTrackMetadata) (bool, string) {
+	if key := backend.TrackMatchKey(track.Name, track.Artists); key != "" && idx.keys[key] {
+		return true, ""
+	}
+	if len(idx.isrcs) == 0 || track.SpotifyID == "" {
+		return false, ""
+	}
+	isrc := backend.ResolveTrackISRC(track.SpotifyID)
+	return isrc != "" && idx.isrcs[isrc], isrc
+}
+
+func (a *App) analyzeLibraryGaps(ctx context.Context, folder string, urls []string, download bool, progress func(backend.LibraryScanProgress)) (*GapAnalysisResult, error) {
+	local, err := backend.ScanLibraryMetadata(ctx, folder, 0, progress)
+	if err != nil {
+		return nil, err
+	}
+	index := buildLocalTrackIndex(local)
+
+	settings := loadBatchDownloadSettings()
+	result := &GapAnalysisResult{Folder: folder, LocalFiles: len(local)}
+	seenMissing := make(map[string]bool)
+	var downloads []spotifyTrackList
+
+	for _, spotifyURL := range urls {
+		spotifyURL = strings.TrimSpace(spotifyURL)
+		if spotifyURL == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		source := GapSource{URL: spotifyURL}
+		list, err := fetchSpotifyTrackList(ctx, spotifyURL, settings.Separator)
+		if err != nil {
+			source.Error = err.Error()
+			result.Sources = append(result.Sources, source)
+			continue
+		}
+		source.Name = list.Name
+		source.Type = list.Type
+		source.Total = len(list.Tracks)
+
+		missing := spotifyTrackList{URL: list.URL, Name: list.Name, Type: list.Type, PlaylistName: list.PlaylistName}
+		for i, track := range list.Tracks {
+			present, isrc := index.contains(track)
+			if present {
+				source.Present++
+				continue
+			}
+			source.Missing++
+
+			position := i + 1
+			if i < len(list.Positions) && list.Positions[i] > 0 {
+				position = list.Positions[i]
+			}
+			if seenMissing[track.SpotifyID] {
+				continue
+			}
+			seenMissing[track.SpotifyID] = true
+
+			result.Missing = append(result.Missing, GapTrack{
+				SpotifyID: track.SpotifyID,
+				Name:      track.Name,
+				Artists:   track.Artists,
+				AlbumName: track.AlbumName,
+				ISRC:      isrc,
+				Source:    list.Name,
+				Position:  position,
+			})
+			missing.Tracks = append(missing.Tracks, track)
+			missing.Positions = append(missing.Positions, position)
+		}
+
+		result.Sources = append(result.Sources, source)
+		if len(missing.Tracks) > 0 {
+			downloads = append(downloads, missing)
+		}
+	}
+
+	if download {
+		settings.DownloadPath = folder
+		for _, list := range downloads {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			fmt.Printf("Downloading %d missing track(s) from %s\n", len(list.Tracks), list.Name)
+			result.Downloads = append(result.Downloads, a.downloadSpotifyTracks(ctx, list, settings))
+		}
+	}
+
+	return result, nil
+}
+
+func (a *App) AnalyzeLibraryGaps(folder string, urls []string, download bool) (*GapAnalysisResult, error) {
+	if folder == "" {
+		return nil, fmt.Errorf("folder path is required")
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("at least one Spotify URL is required")
+	}
+
+	ctx, cancel := a.startLibraryScan()
+	defer cancel()
+
+	return a.analyzeLibraryGaps(ctx, folder, urls, download, func(progress backend.LibraryScanProgress) {
+		a.emitEvent("library-scan-progress", progress)
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func historyTrackList(ctx context.Context, ids []string, settings batchDownloadSettings) (spotifyTrackList, BatchDownloadResult) {
+	list := spotifyTrackList{Name: "History", Type: "track"}
+	result := BatchDownloadResult{Name: list.Name}
+
+	for _, id := range ids {
+		item, err := backend.GetHistoryItem(id, "SpotiFLAC")
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		if item.SpotifyID == "" {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: missing Spotify ID", item.Title, item.Artists))
+			continue
+		}
+
+		trackList, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", item.SpotifyID), settings.Separator)
+		if err != nil || len(trackList.Tracks) == 0 {
+			result.Failed++
+			result.FailedIDs = append(result.FailedIDs, item.SpotifyID)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: %v", item.Title, item.Artists, err))
+			continue
+		}
+		list.Tracks = append(list.Tracks, trackList.Tracks[0])
+	}
+
+	return list, result
+}
+
+func (a *App) redownloadHistoryItems(ctx context.Context, ids []string, settings batchDownloadSettings) BatchDownloadResult {
+	list, result := historyTrackList(ctx, ids, settings)
+	if len(list.Tracks) == 0 {
+		result.Total = result.Failed
+		return result
+	}
+
+	downloaded := a.downloadSpotifyTracks(ctx, list, settings)
+	downloaded.Total += result.Failed
+	downloaded.Failed += result.Failed
+	downloaded.Errors = append(result.Errors, downloaded.Errors...)
+	downloaded.FailedIDs = append(result.FailedIDs, downloaded.FailedIDs...)
+	return downloaded
+}
+
+func (a *App) SearchDownloadHistory(query backend.HistoryQuery) (*backend.HistoryPage, error) {
+	return backend.QueryHistoryItems(query, "SpotiFLAC")
+}
+
+func (a *App) RedownloadHistoryItems(ids []string) (BatchDownloadResult, error) {
+	if len(ids) == 0 {
+		return BatchDownloadResult{}, fmt.Errorf("no history items selected")
+	}
+	return a.redownloadHistoryItems(context.Background(), ids, loadBatchDownloadSettings()), nil
+}
+
+func revealHistoryItem(id string) (string, error) {
+	item, err := backend.GetHistoryItem(id, "SpotiFLAC")
+	if err != nil {
+		return "", err
+	}
+	if item.Path == "" {
+		return "", fmt.Errorf("history item has no file path")
+	}
+
+	dir := filepath.Dir(item.Path)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("folder no longer exists: %s", dir)
+	}
+	if err := backend.OpenFolderInExplorer(dir); err != nil {
+		return "", fmt.Errorf("failed to open folder: %v", err)
+	}
+	return dir, nil
+}
+
+func (a *App) RevealHistoryItem(id string) error {
+	_, err := revealHistoryItem(id)
+	return err
+}
+
+func (a *App) ExportHistory(path string) (int, error) {
+	if path == "" {
+		return 0, fmt.Errorf("file path is required")
+	}
+	return backend.ExportHistory(path, "SpotiFLAC")
+}
+
+func (a *App) ImportHistory(path string) (*backend.HistoryImportResult, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	return backend.ImportHistory(path, "SpotiFLAC")
+}
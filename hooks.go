@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func fireDownloadHook(req DownloadRequest, response DownloadResponse, err error) {
+	event := backend.HookEvent{
+		SpotifyID: req.SpotifyID,
+		Track:     req.TrackName,
+		Artist:    req.ArtistName,
+		Album:     req.AlbumName,
+		ISRC:      req.ISRC,
+		Service:   req.Service,
+	}
+
+	switch {
+	case err == nil && response.Success:
+		if response.AlreadyExists || response.Skipped {
+			return
+		}
+		event.Event = backend.HookEventTrackComplete
+		event.FilePath = response.File
+		event.Quality = response.Quality
+	default:
+		event.Event = backend.HookEventTrackFailed
+		event.Error = response.Error
+		if event.Error == "" && err != nil {
+			event.Error = err.Error()
+		}
+	}
+
+	backend.FireHook(event)
+}
+
+func fireBatchHook(name, listType string, files []string, downloaded, skipped, failed int) {
+	backend.FireHook(backend.HookEvent{
+		Event:      backend.HookEventAlbumComplete,
+		Name:       name,
+		Type:       listType,
+		Downloaded: downloaded,
+		Skipped:    skipped,
+		Failed:     failed,
+		Files:      files,
+	})
+}
+
+func (a *App) NotifyBatchComplete(name, listType string, files []string, downloaded, skipped, failed int) {
+	fireBatchHook(name, listType, files, downloaded, skipped, failed)
+}
+
+func (a *App) NotifyTrackOutcome(req DownloadRequest, response DownloadResponse) {
+	if req.Service == "" {
+		req.Service = response.Service
+	}
+	fireDownloadHook(req, response, nil)
+}
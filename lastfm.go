@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func lastfmTrackList(ctx context.Context, name string, tracks []backend.LastfmTrack, settings batchDownloadSettings) (spotifyTrackList, BatchDownloadResult) {
+	list := spotifyTrackList{Name: name, Type: "playlist", PlaylistName: name}
+	result := BatchDownloadResult{Name: name}
+
+	for _, track := range tracks {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+
+		id := findSpotifyTrackBySearch(ctx, track.Name, track.Artist)
+		if id == "" {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: no Spotify match", track.Artist, track.Name))
+			continue
+		}
+
+		trackList, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", id), settings.Separator)
+		if err != nil || len(trackList.Tracks) == 0 {
+			result.Failed++
+			result.FailedIDs = append(result.FailedIDs, id)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: %v", track.Artist, track.Name, err))
+			continue
+		}
+		list.Tracks = append(list.Tracks, trackList.Tracks[0])
+	}
+
+	return list, result
+}
+
+func (a *App) downloadLastfmTracks(ctx context.Context, name string, tracks []backend.LastfmTrack, settings batchDownloadSettings) BatchDownloadResult {
+	list, result := lastfmTrackList(ctx, name, tracks, settings)
+	if len(list.Tracks) == 0 {
+		result.Total = result.Failed
+		return result
+	}
+
+	downloaded := a.downloadSpotifyTracks(ctx, list, settings)
+	downloaded.Total += result.Failed
+	downloaded.Failed += result.Failed
+	downloaded.Errors = append(result.Errors, downloaded.Errors...)
+	downloaded.FailedIDs = append(result.FailedIDs, downloaded.FailedIDs...)
+	return downloaded
+}
+
+func lastfmTopTracksName(period string) string {
+	if period = backend.NormalizeLastfmPeriod(period); period == "" {
+		period = backend.LastfmPeriodOverall
+	}
+	return fmt.Sprintf("Last.fm Top Tracks (%s)", period)
+}
+
+func (a *App) LastfmLogin() (backend.LastfmAuthStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	return backend.LastfmLogin(ctx, func(authURL string) {
+		if a.ctx != nil {
+			runtime.BrowserOpenURL(a.ctx, authURL)
+		}
+	})
+}
+
+func (a *App) LastfmLogout() error {
+	return backend.LastfmLogout()
+}
+
+func (a *App) GetLastfmAuthStatus() backend.LastfmAuthStatus {
+	return backend.GetLastfmAuthStatus()
+}
+
+func (a *App) GetLastfmLovedTracks(user string, limit int) ([]backend.LastfmTrack, error) {
+	return backend.GetLastfmLovedTracks(user, limit)
+}
+
+func (a *App) GetLastfmTopTracks(user, period string, limit int) ([]backend.LastfmTrack, error) {
+	return backend.GetLastfmTopTracks(user, period, limit)
+}
+
+func (a *App) DownloadLastfmLovedTracks(user string, limit int) (BatchDownloadResult, error) {
+	tracks, err := backend.GetLastfmLovedTracks(user, limit)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+	return a.downloadLastfmTracks(context.Background(), "Last.fm Loved Tracks", tracks, loadBatchDownloadSettings()), nil
+}
+
+func (a *App) DownloadLastfmTopTracks(user, period string, limit int) (BatchDownloadResult, error) {
+	tracks, err := backend.GetLastfmTopTracks(user, period, limit)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+	return a.downloadLastfmTracks(context.Background(), lastfmTopTracksName(period), tracks, loadBatchDownloadSettings()), nil
+}
@@ -4,6 +4,7 @@ import (
 	"embed"
 	"encoding/json"
 	"log"
+	"os"
 
 	"github.com/afkarxyz/SpotiFLAC/backend"
 
@@ -31,6 +32,17 @@ func main() {
 		backend.AppVersion = config.Info.ProductVersion
 	}
 
+	if handled, err := runCLI(os.Args[1:]); handled {
+		if err != nil {
+			log.Fatal("Error:", err.Error())
+		}
+		return
+	}
+
+	if err := backend.StartConsoleCapture(); err != nil {
+		log.Printf("Failed to capture console output: %v", err)
+	}
+
 	app := NewApp()
 
 	err := wails.Run(&options.App{
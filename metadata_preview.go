@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+const pendingTagValue = "(fetched during download)"
+
+func (a *App) PreviewMetadata(req DownloadRequest, format string) (*backend.MetadataPreview, error) {
+	if req.TrackName == "" && req.SpotifyID == "" {
+		return nil, fmt.Errorf("track name or Spotify ID is required")
+	}
+
+	settings := loadBatchDownloadSettings()
+	separator := req.Separator
+	if separator == "" {
+		separator = settings.Separator
+	}
+
+	artist := req.ArtistName
+	albumArtist := req.AlbumArtist
+	if req.UseFirstArtistOnly {
+		artist = backend.GetFirstArtist(artist)
+		albumArtist = backend.GetFirstArtist(albumArtist)
+	}
+
+	trackNumber := req.SpotifyTrackNumber
+	if trackNumber == 0 {
+		trackNumber = 1
+	}
+
+	spotifyURL := ""
+	if req.SpotifyID != "" {
+		spotifyURL = fmt.Sprintf("https://open.spotify.com/track/%s", req.SpotifyID)
+	}
+
+	isrc := strings.TrimSpace(req.ISRC)
+	if isrc == "" && req.SpotifyID != "" {
+		isrc = backend.ResolveTrackISRC(req.SpotifyID)
+	}
+
+	metadata := backend.Metadata{
+		Title:       req.TrackName,
+		Artist:      artist,
+		Album:       req.AlbumName,
+		AlbumArtist: albumArtist,
+		Date:        req.ReleaseDate,
+		TrackNumber: trackNumber,
+		TotalTracks: req.SpotifyTotalTracks,
+		DiscNumber:  req.SpotifyDiscNumber,
+		TotalDiscs:  req.SpotifyTotalDiscs,
+		URL:         spotifyURL,
+		Comment:     spotifyURL,
+		Copyright:   req.Copyright,
+		Publisher:   req.Publisher,
+		Composer:    req.Composer,
+		Separator:   separator,
+		Description: "https://github.com/spotbye/SpotiFLAC",
+		ISRC:        isrc,
+	}
+	if req.EmbedGenre {
+		metadata.Genre = pendingTagValue
+	}
+	if req.EmbedLyrics {
+		metadata.Lyrics = pendingTagValue
+	}
+
+	playlistTagName := strings.TrimSpace(req.SourcePlaylist)
+	if playlistTagName == "" {
+		playlistTagName = strings.TrimSpace(req.PlaylistName)
+	}
+	playlistPosition := req.PlaylistPosition
+	if playlistPosition == 0 && playlistTagName != "" {
+		playlistPosition = req.Position
+	}
+	extraTags := make(map[string]string, len(req.ExtraTags)+2)
+	if playlistTagName != "" && backend.GetEmbedPlaylistTagsSetting() {
+		extraTags["PLAYLIST"] = playlistTagName
+		if playlistPosition > 0 {
+			extraTags["PLAYLISTPOSITION"] = strconv.Itoa(playlistPosition)
+		}
+	}
+	for key, value := range req.ExtraTags {
+		extraTags[key] = value
+	}
+	metadata.ExtraTags = extraTags
+
+	return backend.PreviewEmbeddedTags(metadata, format, req.CoverURL != "")
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func applyMultiDiscLayout(req *DownloadRequest) {
+	if req.SpotifyTotalDiscs <= 1 || req.SpotifyDiscNumber <= 0 {
+		return
+	}
+	if strings.Contains(loadBatchDownloadSettings().FolderTemplate, "{disc") {
+		return
+	}
+
+	if backend.GetDiscSubfoldersSetting() {
+		discFolder := backend.DiscSubfolderName(req.SpotifyDiscNumber)
+		if filepath.Base(req.OutputDir) != discFolder {
+			req.OutputDir = filepath.Join(req.OutputDir, discFolder)
+		}
+		return
+	}
+
+	if req.UseAlbumTrackNumber && (req.TrackNumber || backend.IsFilenameTemplate(req.FilenameFormat)) {
+		req.FilenameFormat = backend.ApplyMultiDiscFilenameFormat(req.FilenameFormat)
+	}
+}
+
+func albumFolderForTrack(req DownloadRequest, filename string) string {
+	dir := filepath.Dir(filename)
+	if req.SpotifyTotalDiscs > 1 && filepath.Base(dir) == backend.DiscSubfolderName(req.SpotifyDiscNumber) {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func importedPlaylistTrackList(ctx context.Context, playlist *backend.ImportedPlaylist, settings batchDownloadSettings) (spotifyTrackList, BatchDownloadResult) {
+	list := spotifyTrackList{Name: playlist.Name, Type: "playlist", PlaylistName: playlist.Name}
+	result := BatchDownloadResult{Name: playlist.Name}
+
+	for _, track := range playlist.Tracks {
+		if ctx.Err() != nil {
+			break
+		}
+		spotifyID := track.SpotifyID
+		if spotifyID == "" && track.ISRC != "" {
+			spotifyID = findSpotifyTrackByISRC(ctx, track.ISRC)
+		}
+		if spotifyID == "" {
+			id, err := backend.ResolveImportedTrack(ctx, track)
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("row %d: %s - %s: %v", track.Row, track.Artist, track.Title, err))
+				continue
+			}
+			spotifyID = id
+		}
+
+		trackList, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", spotifyID), settings.Separator)
+		if err != nil || len(trackList.Tracks) == 0 {
+			result.Failed++
+			result.FailedIDs = append(result.FailedIDs, spotifyID)
+			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %s - %s: %v", track.Row, track.Artist, track.Title, err))
+			continue
+		}
+		list.Tracks = append(list.Tracks, trackList.Tracks[0])
+	}
+
+	return list, result
+}
+
+func (a *App) downloadImportedPlaylist(ctx context.Context, playlist *backend.ImportedPlaylist, settings batchDownloadSettings) BatchDownloadResult {
+	list, result := importedPlaylistTrackList(ctx, playlist, settings)
+	if len(list.Tracks) == 0 {
+		result.Total = result.Failed
+		return result
+	}
+
+	downloaded := a.downloadSpotifyTracks(ctx, list, settings)
+	downloaded.Total += result.Failed
+	downloaded.Failed += result.Failed
+	downloaded.Errors = append(result.Errors, downloaded.Errors...)
+	downloaded.FailedIDs = append(result.FailedIDs, downloaded.FailedIDs...)
+	return downloaded
+}
+
+func (a *App) ParsePlaylistFile(path string) (*backend.ImportedPlaylist, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	return backend.ParseCSVPlaylist(path)
+}
+
+func (a *App) DownloadPlaylistFile(path string) (BatchDownloadResult, error) {
+	if path == "" {
+		return BatchDownloadResult{}, fmt.Errorf("file path is required")
+	}
+	playlist, err := backend.ParseCSVPlaylist(path)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+	return a.downloadImportedPlaylist(context.Background(), playlist, loadBatchDownloadSettings()), nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func fetchPodcastEpisode(ctx context.Context, spotifyURL string) (backend.EpisodeMetadata, error) {
+	data, err := backend.GetFilteredSpotifyData(ctx, spotifyURL, false, 0, "", nil)
+	if err != nil {
+		return backend.EpisodeMetadata{}, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+
+	payload, ok := data.(backend.EpisodeResponse)
+	if !ok || payload.Type != "episode" || len(payload.Episodes) != 1 {
+		return backend.EpisodeMetadata{}, fmt.Errorf("%s is not a podcast episode URL", spotifyURL)
+	}
+	return payload.Episodes[0], nil
+}
+
+func (a *App) DownloadPodcastEpisode(spotifyURL string) (string, error) {
+	if strings.TrimSpace(spotifyURL) == "" {
+		return "", fmt.Errorf("episode URL is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	episode, err := fetchPodcastEpisode(ctx, spotifyURL)
+	if err != nil {
+		return "", err
+	}
+	return backend.DownloadPodcastEpisode(ctx, episode, loadBatchDownloadSettings().DownloadPath)
+}
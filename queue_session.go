@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func (a *App) PauseAll() error {
+	if !backend.PauseDownloads() {
+		return nil
+	}
+	if _, err := backend.SaveQueueSession(); err != nil {
+		fmt.Printf("Warning: failed to save queue session: %v\n", err)
+	}
+	a.emitEvent("downloads-paused", true)
+	return nil
+}
+
+func (a *App) ResumeAll() {
+	if backend.ResumeDownloads() {
+		if err := backend.DiscardQueueSession(); err != nil {
+			fmt.Printf("Warning: failed to discard queue session: %v\n", err)
+		}
+		a.emitEvent("downloads-paused", false)
+	}
+}
+
+// discardFinishedQueueSession drops the snapshot written by PauseAll once the
+// queue has drained, so the next launch doesn't offer to resume finished tracks.
+func discardFinishedQueueSession() {
+	if backend.IsDownloadsPaused() || backend.HasPendingDownloads() {
+		return
+	}
+	if err := backend.DiscardQueueSession(); err != nil {
+		fmt.Printf("Warning: failed to discard queue session: %v\n", err)
+	}
+}
+
+func (a *App) IsDownloadsPaused() bool {
+	return backend.IsPausedFor(backend.PauseReasonUser)
+}
+
+func (a *App) GetDownloadScheduleStatus() backend.DownloadScheduleStatus {
+	status, changed := backend.ApplyDownloadSchedule(time.Now())
+	if changed {
+		a.emitEvent("download-schedule", status)
+	}
+	return status
+}
+
+func (a *App) GetPreviousQueueSession() (*backend.QueueSession, error) {
+	return backend.LoadQueueSession()
+}
+
+func (a *App) DiscardPreviousQueueSession() error {
+	return backend.DiscardQueueSession()
+}
+
+func (a *App) ResumePreviousQueueSession() (int, error) {
+	session, err := backend.LoadQueueSession()
+	if err != nil {
+		return 0, err
+	}
+	if session == nil {
+		return 0, nil
+	}
+
+	items := backend.RestoreQueueSession(session)
+	if len(items) == 0 {
+		return 0, fmt.Errorf("saved session has no resumable tracks")
+	}
+	if err := backend.DiscardQueueSession(); err != nil {
+		fmt.Printf("Warning: failed to discard queue session: %v\n", err)
+	}
+
+	settings := loadBatchDownloadSettings()
+	go func() {
+		ctx := context.Background()
+		result := BatchDownloadResult{Name: "Previous session", Total: len(items)}
+		fmt.Printf("Resuming %d track(s) from the previous session\n", len(items))
+		for _, item := range items {
+			trackList, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", item.SpotifyID), settings.Separator)
+			if err == nil && len(trackList.Tracks) == 0 {
+				err = fmt.Errorf("track not found")
+			}
+			if err != nil {
+				fmt.Printf("Failed to resume %s: %v\n", item.SpotifyID, err)
+				backend.FailDownloadItem(item.ID, err.Error())
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: %v", item.TrackName, item.ArtistName, err))
+				continue
+			}
+
+			response, err := a.downloadSpotifyTrack(ctx, trackList.Tracks[0], item.PlaylistName, item.Position, item.ID, item.OutputDir, settings)
+			switch {
+			case err != nil || !response.Success:
+				result.Failed++
+				errMsg := response.Error
+				if errMsg == "" && err != nil {
+					errMsg = err.Error()
+				}
+				result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: %s", item.TrackName, item.ArtistName, errMsg))
+			case response.AlreadyExists || response.Skipped:
+				result.Skipped++
+			default:
+				result.Downloaded++
+				result.Files = append(result.Files, response.File)
+			}
+		}
+		a.emitEvent("queue-session-resumed", result)
+	}()
+	return len(items), nil
+}
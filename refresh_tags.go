@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+type RefreshTagsResult struct {
+	FilePath  string `json:"file_path"`
+	Source    string `json:"source,omitempty"`
+	SpotifyID string `json:"spotify_id,omitempty"`
+	ISRC      string `json:"isrc,omitempty"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+func collectRefreshTagFiles(paths []string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if !info.IsDir() {
+			if !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+			continue
+		}
+
+		audioFiles, err := backend.ListAudioFiles(path)
+		if err != nil {
+			continue
+		}
+		for _, file := range audioFiles {
+			if !seen[file.Path] {
+				seen[file.Path] = true
+				files = append(files, file.Path)
+			}
+		}
+	}
+	return files
+}
+
+func spotifyTrackToMetadata(track backend.AlbumTrackMetadata, settings batchDownloadSettings) backend.Metadata {
+	artist := track.Artists
+	albumArtist := track.AlbumArtist
+	if settings.UseFirstArtistOnly {
+		artist = backend.GetFirstArtist(artist)
+		albumArtist = backend.GetFirstArtist(albumArtist)
+	}
+
+	spotifyURL := fmt.Sprintf("https://open.spotify.com/track/%s", track.SpotifyID)
+	return backend.Metadata{
+		Title:       track.Name,
+		Artist:      artist,
+		Album:       track.AlbumName,
+		AlbumArtist: albumArtist,
+		Date:        track.ReleaseDate,
+		TrackNumber: track.TrackNumber,
+		TotalTracks: track.TotalTracks,
+		DiscNumber:  track.DiscNumber,
+		TotalDiscs:  track.TotalDiscs,
+		URL:         spotifyURL,
+		Comment:     spotifyURL,
+		UPC:         track.UPC,
+		Separator:   settings.Separator,
+	}
+}
+
+func (a *App) refreshFileTags(ctx context.Context, filePath string, settings batchDownloadSettings) RefreshTagsResult {
+	result := RefreshTagsResult{FilePath: filePath}
+
+	current, err := backend.ExtractFullMetadataFromFile(filePath)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to read tags: %v", err)
+		return result
+	}
+
+	result.SpotifyID = backend.EmbeddedSpotifyTrackID(current)
+	result.ISRC = strings.ToUpper(strings.TrimSpace(current.ISRC))
+
+	var fresh backend.Metadata
+	coverURL := ""
+	switch {
+	case result.SpotifyID != "":
+		list, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", result.SpotifyID), settings.Separator)
+		if err != nil || len(list.Tracks) == 0 {
+			result.Error = fmt.Sprintf("failed to fetch Spotify metadata: %v", err)
+			return result
+		}
+		track := list.Tracks[0]
+		fresh = spotifyTrackToMetadata(track, settings)
+		coverURL = track.Images
+		result.Source = "spotify"
+
+		if result.ISRC == "" {
+			result.ISRC = backend.ResolveTrackISRC(track.SpotifyID)
+		}
+		fresh.ISRC = result.ISRC
+		if result.ISRC != "" {
+			if originalDate, err := backend.GetQobuzOriginalReleaseDate(result.ISRC); err == nil {
+				fresh.OriginalDate = originalDate
+			}
+		}
+	case result.ISRC != "":
+		fresh, coverURL, err = backend.FetchQobuzTrackMetadata(result.ISRC)
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to fetch Qobuz metadata: %v", err)
+			return result
+		}
+		fresh.Separator = settings.Separator
+		result.Source = "qobuz"
+	default:
+		result.Error = "no embedded Spotify ID or ISRC"
+		return result
+	}
+
+	if current.TrackNumber > 0 {
+		fresh.TrackNumber = current.TrackNumber
+	}
+	if current.DiscNumber > 0 {
+		fresh.DiscNumber = current.DiscNumber
+	}
+
+	coverPath := ""
+	if coverURL != "" {
+		coverPath = filepath.Join(backend.StagingDir(), fmt.Sprintf("spotiflac_refresh_%d.jpg", time.Now().UnixNano()))
+		if err := backend.NewCoverClient().DownloadCoverToPath(coverURL, coverPath, settings.EmbedMaxQualityCover); err != nil {
+			fmt.Printf("Warning: failed to download cover for %s: %v\n", filePath, err)
+			coverPath = ""
+		} else {
+			defer os.Remove(coverPath)
+			coverPath = backend.PrepareCoverForEmbedding(coverPath)
+		}
+	}
+
+	if err := backend.RefreshFileTags(filePath, current, fresh, coverPath); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Success = true
+	return result
+}
+
+func (a *App) RefreshLibraryTags(paths []string) ([]RefreshTagsResult, error) {
+	files := collectRefreshTagFiles(paths)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no audio files found")
+	}
+
+	settings := loadBatchDownloadSettings()
+	ctx := context.Background()
+	results := make([]RefreshTagsResult, 0, len(files))
+	for i, filePath := range files {
+		fmt.Printf("Refreshing tags [%d/%d]: %s\n", i+1, len(files), filePath)
+		result := a.refreshFileTags(ctx, filePath, settings)
+		if !result.Success {
+			fmt.Printf("Warning: failed to refresh tags for %s: %s\n", filePath, result.Error)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+type RetagFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type RetagPreview struct {
+	FilePath  string             `json:"file_path"`
+	SpotifyID string             `json:"spotify_id,omitempty"`
+	MatchedBy string             `json:"matched_by,omitempty"`
+	Changes   []RetagFieldChange `json:"changes"`
+	Error     string             `json:"error,omitempty"`
+}
+
+type RetagResult struct {
+	FilePath  string             `json:"file_path"`
+	SpotifyID string             `json:"spotify_id,omitempty"`
+	MatchedBy string             `json:"matched_by,omitempty"`
+	Changes   []RetagFieldChange `json:"changes"`
+	Success   bool               `json:"success"`
+	Error     string             `json:"error,omitempty"`
+}
+
+type retagMatch struct {
+	current   backend.Metadata
+	fresh     backend.Metadata
+	spotifyID string
+	matchedBy string
+	coverURL  string
+}
+
+func formatRetagNumber(value int) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+func diffRetagMetadata(current, fresh backend.Metadata) []RetagFieldChange {
+	fields := []RetagFieldChange{
+		{Field: "title", Old: current.Title, New: fresh.Title},
+		{Field: "artist", Old: current.Artist, New: fresh.Artist},
+		{Field: "album", Old: current.Album, New: fresh.Album},
+		{Field: "album_artist", Old: current.AlbumArtist, New: fresh.AlbumArtist},
+		{Field: "date", Old: current.Date, New: fresh.Date},
+		{Field: "track_number", Old: formatRetagNumber(current.TrackNumber), New: formatRetagNumber(fresh.TrackNumber)},
+		{Field: "disc_number", Old: formatRetagNumber(current.DiscNumber), New: formatRetagNumber(fresh.DiscNumber)},
+		{Field: "isrc", Old: current.ISRC, New: fresh.ISRC},
+		{Field: "upc", Old: current.UPC, New: fresh.UPC},
+	}
+
+	changes := make([]RetagFieldChange, 0, len(fields))
+	for _, field := range fields {
+		field.Old = strings.TrimSpace(field.Old)
+		field.New = strings.TrimSpace(field.New)
+		if field.New == "" || field.Old == field.New {
+			continue
+		}
+		changes = append(changes, field)
+	}
+	return changes
+}
+
+func findSpotifyTrackByISRC(ctx context.Context, isrc string) string {
+	results, err := backend.SearchSpotifyByType(ctx, "isrc:"+isrc, "track", 5, 0)
+	if err != nil {
+		return ""
+	}
+	for _, result := range results {
+		if backend.ResolveTrackISRC(result.ID) == isrc {
+			return result.ID
+		}
+	}
+	return ""
+}
+
+func findSpotifyTrackBySearch(ctx context.Context, title, artist string) string {
+	if strings.TrimSpace(title) == "" || strings.TrimSpace(artist) == "" {
+		return ""
+	}
+	for _, query := range backend.BuildSearchQueries(title, artist) {
+		results, err := backend.SearchSpotifyByType(ctx, query, "track", 5, 0)
+		if err != nil {
+			continue
+		}
+		for _, result := range results {
+			if backend.SearchResultMatches(title, artist, result.Name, result.Artists) {
+				return result.ID
+			}
+		}
+	}
+	return ""
+}
+
+func matchRetagFile(ctx context.Context, filePath string, settings batchDownloadSettings) (*retagMatch, error) {
+	current, err := backend.ExtractFullMetadataFromFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags: %v", err)
+	}
+
+	match := &retagMatch{current: current}
+	isrc := strings.ToUpper(strings.TrimSpace(current.ISRC))
+
+	if id := backend.EmbeddedSpotifyTrackID(current); id != "" {
+		match.spotifyID, match.matchedBy = id, "spotify_id"
+	} else if isrc != "" {
+		if id := findSpotifyTrackByISRC(ctx, isrc); id != "" {
+			match.spotifyID, match.matchedBy = id, "isrc"
+		}
+	}
+	if match.spotifyID == "" {
+		if id := findSpotifyTrackBySearch(ctx, current.Title, current.Artist); id != "" {
+			match.spotifyID, match.matchedBy = id, "search"
+		}
+	}
+	if match.spotifyID == "" {
+		return nil, fmt.Errorf("no matching Spotify track")
+	}
+
+	list, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", match.spotifyID), settings.Separator)
+	if err != nil || len(list.Tracks) == 0 {
+		return nil, fmt.Errorf("failed to fetch Spotify metadata: %v", err)
+	}
+	track := list.Tracks[0]
+
+	match.fresh = spotifyTrackToMetadata(track, settings)
+	match.coverURL = track.Images
+	if match.matchedBy == "search" || isrc == "" {
+		if resolved := backend.ResolveTrackISRC(track.SpotifyID); resolved != "" {
+			isrc = resolved
+		}
+	}
+	match.fresh.ISRC = isrc
+
+	return match, nil
+}
+
+func downloadRetagCover(coverURL string, embedMaxQualityCover bool) string {
+	if coverURL == "" {
+		return ""
+	}
+	coverPath := filepath.Join(backend.StagingDir(), fmt.Sprintf("spotiflac_retag_%d.jpg", time.Now().UnixNano()))
+	if err := backend.NewCoverClient().DownloadCoverToPath(coverURL, coverPath, embedMaxQualityCover); err != nil {
+		fmt.Printf("Warning: failed to download cover %s: %v\n", coverURL, err)
+		os.Remove(coverPath)
+		return ""
+	}
+	return coverPath
+}
+
+func previewRetagFiles(ctx context.Context, files []string, settings batchDownloadSettings) []RetagPreview {
+	previews := make([]RetagPreview, 0, len(files))
+	for i, filePath := range files {
+		fmt.Printf("Matching [%d/%d]: %s\n", i+1, len(files), filePath)
+		preview := RetagPreview{FilePath: filePath}
+
+		match, err := matchRetagFile(ctx, filePath, settings)
+		if err != nil {
+			preview.Error = err.Error()
+			previews = append(previews, preview)
+			continue
+		}
+
+		preview.SpotifyID = match.spotifyID
+		preview.MatchedBy = match.matchedBy
+		preview.Changes = diffRetagMetadata(match.current, match.fresh)
+		previews = append(previews, preview)
+	}
+	return previews
+}
+
+func retagFiles(ctx context.Context, files []string, settings batchDownloadSettings, withCovers bool) []RetagResult {
+	results := make([]RetagResult, 0, len(files))
+	for i, filePath := range files {
+		fmt.Printf("Retagging [%d/%d]: %s\n", i+1, len(files), filePath)
+		result := RetagResult{FilePath: filePath}
+
+		match, err := matchRetagFile(ctx, filePath, settings)
+		if err != nil {
+			result.Error = err.Error()
+			fmt.Printf("Warning: failed to retag %s: %s\n", filePath, result.Error)
+			results = append(results, result)
+			continue
+		}
+
+		result.SpotifyID = match.spotifyID
+		result.MatchedBy = match.matchedBy
+		result.Changes = diffRetagMetadata(match.current, match.fresh)
+
+		coverPath := ""
+		if withCovers {
+			coverPath = downloadRetagCover(match.coverURL, settings.EmbedMaxQualityCover)
+		}
+		if len(result.Changes) == 0 && coverPath == "" {
+			result.Success = true
+			results = append(results, result)
+			continue
+		}
+
+		err = backend.RefreshFileTags(filePath, match.current, match.fresh, backend.PrepareCoverForEmbedding(coverPath))
+		if coverPath != "" {
+			os.Remove(coverPath)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			fmt.Printf("Warning: failed to retag %s: %s\n", filePath, result.Error)
+			results = append(results, result)
+			continue
+		}
+
+		result.Success = true
+		results = append(results, result)
+	}
+	return results
+}
+
+func (a *App) PreviewRetag(paths []string) ([]RetagPreview, error) {
+	files := collectRefreshTagFiles(paths)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no audio files found")
+	}
+
+	return previewRetagFiles(context.Background(), files, loadBatchDownloadSettings()), nil
+}
+
+func (a *App) RetagFiles(paths []string, withCovers bool) ([]RetagResult, error) {
+	files := collectRefreshTagFiles(paths)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no audio files found")
+	}
+
+	return retagFiles(context.Background(), files, loadBatchDownloadSettings(), withCovers), nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func (a *App) RetryFailedDownloads(manifestPath string, service string) (BatchDownloadResult, error) {
+	if strings.TrimSpace(manifestPath) == "" {
+		return BatchDownloadResult{}, fmt.Errorf("manifest path is required")
+	}
+
+	manifest, err := backend.LoadPlaylistManifest(manifestPath)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+
+	settings := loadBatchDownloadSettings()
+	settings.ManifestFormat = ""
+	settings.CreateM3U8File = false
+
+	service, err = parseDownloaderChoice(service)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+	if service != "" {
+		settings.Downloader = service
+	}
+
+	list := spotifyTrackList{
+		URL:          manifest.URL,
+		Name:         manifest.Name,
+		Type:         "playlist",
+		PlaylistName: manifest.Playlist,
+	}
+
+	ctx := context.Background()
+	result := BatchDownloadResult{URL: manifest.URL, Name: manifest.Name}
+	retryIndex := make(map[string]int)
+	for idx, entry := range manifest.Entries {
+		if entry.Status != backend.ManifestStatusFailed {
+			continue
+		}
+		if entry.SpotifyID == "" {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: missing Spotify ID", entry.Track, entry.Artist))
+			continue
+		}
+
+		trackList, err := fetchSpotifyTrackList(ctx, fmt.Sprintf("https://open.spotify.com/track/%s", entry.SpotifyID), settings.Separator)
+		if err != nil || len(trackList.Tracks) == 0 {
+			result.Failed++
+			result.FailedIDs = append(result.FailedIDs, entry.SpotifyID)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s - %s: %v", entry.Track, entry.Artist, err))
+			continue
+		}
+
+		retryIndex[entry.SpotifyID] = idx
+		list.Tracks = append(list.Tracks, trackList.Tracks[0])
+		list.Positions = append(list.Positions, entry.Position)
+	}
+
+	if len(list.Tracks) == 0 && result.Failed == 0 {
+		return result, nil
+	}
+
+	fmt.Printf("Retrying %d failed track(s) from %s\n", len(list.Tracks), manifestPath)
+	retried := a.downloadSpotifyTracks(ctx, list, settings)
+
+	result.Total = retried.Total + result.Failed
+	result.Downloaded = retried.Downloaded
+	result.Skipped = retried.Skipped
+	result.Failed += retried.Failed
+	result.Files = retried.Files
+	result.Errors = append(result.Errors, retried.Errors...)
+	result.FailedIDs = append(result.FailedIDs, retried.FailedIDs...)
+	result.Entries = retried.Entries
+
+	for _, entry := range retried.Entries {
+		if idx, ok := retryIndex[entry.SpotifyID]; ok {
+			manifest.Entries[idx] = entry
+		}
+	}
+	if err := backend.WritePlaylistManifest(manifestPath, *manifest); err != nil {
+		fmt.Printf("Warning: failed to update manifest: %v\n", err)
+	} else {
+		result.Manifest = manifestPath
+	}
+
+	return result, nil
+}
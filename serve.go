@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func runHeadless(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := fs.String("listen", fmt.Sprintf("127.0.0.1:%d", backend.GetAPIServerPortSetting()), "address for the REST API")
+	pidFile := fs.String("pid-file", "", "write the process ID to this file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !isLoopbackListenAddr(*listen) && !backend.HasAPIServerToken() {
+		return fmt.Errorf("refusing to listen on %s without an API token; run \"SpotiFLAC config set apiServerToken <secret>\" first", *listen)
+	}
+
+	if err := backend.StartConsoleCapture(); err != nil {
+		fmt.Printf("Warning: failed to capture console output: %v\n", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := NewApp()
+	app.headless = true
+	app.ctx = ctx
+	app.startServices(ctx)
+	defer app.shutdown(context.Background())
+
+	if err := app.startAPI(ctx, *listen); err != nil {
+		return err
+	}
+
+	if *pidFile != "" {
+		if err := os.WriteFile(*pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write PID file: %w", err)
+		}
+		defer os.Remove(*pidFile)
+	}
+
+	fmt.Printf("SpotiFLAC %s running headless (pid %d)\n", backend.AppVersion, os.Getpid())
+	<-ctx.Done()
+
+	fmt.Println("Shutting down...")
+	return nil
+}
+
+func isLoopbackListenAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
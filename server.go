@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+const apiDownloadQueueSize = 256
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type apiHealth struct {
+	Status        string `json:"status"`
+	PID           int    `json:"pid"`
+	Version       string `json:"version"`
+	Headless      bool   `json:"headless"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+}
+
+type apiDownloadAccepted struct {
+	ItemID string `json:"item_id"`
+	Status string `json:"status"`
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, apiError{Error: message})
+}
+
+func (a *App) startAPIServer(ctx context.Context, addr string) error {
+	token, generated, err := backend.EnsureAPIServerToken()
+	if err != nil {
+		return fmt.Errorf("failed to set up API token: %w", err)
+	}
+	a.apiToken = token
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start API server: %w", err)
+	}
+
+	a.apiJobs = make(chan DownloadRequest, apiDownloadQueueSize)
+	go a.runAPIDownloadWorker(ctx)
+
+	server := &http.Server{
+		Handler:           a.apiHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	done := make(chan struct{})
+	a.apiDone = done
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("[API] Server stopped: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("[API] Listening on http://%s\n", listener.Addr().String())
+	if generated {
+		if tokenPath, err := backend.GetAPIServerTokenPath(); err == nil {
+			fmt.Printf("[API] Generated an API token in %s (send it as X-API-Token; set apiServerToken to choose your own)\n", tokenPath)
+		} else {
+			fmt.Println("[API] Generated an API token (read it with GetAPIServerToken; send it as X-API-Token)")
+		}
+	}
+	return nil
+}
+
+func (a *App) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/health", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIJSON(w, http.StatusOK, apiHealth{
+			Status:        "ok",
+			PID:           os.Getpid(),
+			Version:       backend.AppVersion,
+			Headless:      a.headless,
+			UptimeSeconds: int64(time.Since(a.startedAt).Seconds()),
+		})
+	})
+	mux.HandleFunc("POST /api/download", a.handleAPIDownload)
+	mux.HandleFunc("GET /api/queue", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIJSON(w, http.StatusOK, backend.GetDownloadQueue())
+	})
+	mux.HandleFunc("DELETE /api/queue/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := backend.GetDownloadItem(id); !ok {
+			writeAPIError(w, http.StatusNotFound, "download not found")
+			return
+		}
+		if err := backend.RemoveDownloadItem(id); err != nil {
+			writeAPIError(w, http.StatusConflict, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/queue/{id}/priority", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Priority string `json:"priority"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&body); err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+		priority, err := backend.ParseDownloadPriority(body.Priority)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := backend.SetDownloadItemPriority(r.PathValue("id"), priority); err != nil {
+			writeAPIError(w, http.StatusConflict, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/queue/{id}/move-to-front", func(w http.ResponseWriter, r *http.Request) {
+		if err := backend.MoveDownloadItemToFront(r.PathValue("id")); err != nil {
+			writeAPIError(w, http.StatusConflict, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/queue/albums/move-to-front", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			AlbumName string `json:"album_name"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&body); err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+		moved, err := backend.MoveAlbumToFront(body.AlbumName)
+		if err != nil {
+			writeAPIError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, map[string]int{"moved": moved})
+	})
+	mux.HandleFunc("GET /api/progress", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIJSON(w, http.StatusOK, backend.GetDownloadProgress())
+	})
+	mux.HandleFunc("GET /api/history", func(w http.ResponseWriter, r *http.Request) {
+		items, err := backend.GetHistoryItems("SpotiFLAC")
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if items == nil {
+			items = []backend.HistoryItem{}
+		}
+		writeAPIJSON(w, http.StatusOK, items)
+	})
+	mux.HandleFunc("GET /api/stats", func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit <= 0 {
+			limit = 10
+		}
+		stats, err := backend.GetDownloadStatistics("SpotiFLAC", limit)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, stats)
+	})
+	mux.HandleFunc("GET /api/usage", func(w http.ResponseWriter, r *http.Request) {
+		usage, err := backend.GetDataUsage()
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, usage)
+	})
+
+	return a.apiMiddleware(mux)
+}
+
+func isAllowedAPIOrigin(origin string) bool {
+	origin = strings.TrimRight(origin, "/")
+	for _, allowed := range backend.GetAPIServerAllowedOriginsSetting() {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) apiMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if !isAllowedAPIOrigin(origin) {
+				writeAPIError(w, http.StatusForbidden, "origin not allowed")
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-API-Token")
+			w.Header().Add("Vary", "Origin")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if r.URL.Path != "/api/health" {
+			provided := strings.TrimSpace(r.Header.Get("X-API-Token"))
+			if provided == "" {
+				provided = strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			}
+			if a.apiToken == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(a.apiToken)) != 1 {
+				writeAPIError(w, http.StatusUnauthorized, "invalid API token")
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isPathWithin(base, target string) bool {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return false
+	}
+	target, err = filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+var apiCoverHostSuffixes = []string{".scdn.co", ".spotifycdn.com"}
+
+// isAllowedAPICoverURL keeps API clients from making the server fetch
+// arbitrary URLs through the cover_url override.
+func isAllowedAPICoverURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.User != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, suffix := range apiCoverHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) GetAPIServerToken() (string, error) {
+	token, _, err := backend.EnsureAPIServerToken()
+	return token, err
+}
+
+func (a *App) handleAPIDownload(w http.ResponseWriter, r *http.Request) {
+	var req DownloadRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if req.SpotifyID == "" && req.ServiceURL == "" {
+		writeAPIError(w, http.StatusBadRequest, "spotify_id or service_url is required")
+		return
+	}
+
+	settings := loadBatchDownloadSettings()
+	if req.OutputDir == "" {
+		req.OutputDir = settings.DownloadPath
+	} else if !isPathWithin(settings.DownloadPath, req.OutputDir) {
+		writeAPIError(w, http.StatusBadRequest, "output_dir must be inside the configured download path")
+		return
+	}
+	if req.FilenameFormat == "" {
+		req.FilenameFormat = settings.FilenameTemplate
+	} else if strings.ContainsAny(req.FilenameFormat, `/\`) || strings.Contains(req.FilenameFormat, "..") {
+		writeAPIError(w, http.StatusBadRequest, "filename_format must not contain path separators or \"..\"")
+		return
+	}
+	req.TidalAPIURL = settings.CustomTidalAPI
+	if req.CoverURL != "" && !isAllowedAPICoverURL(req.CoverURL) {
+		writeAPIError(w, http.StatusBadRequest, "cover_url must point to the Spotify image CDN")
+		return
+	}
+
+	if r.URL.Query().Get("wait") == "true" {
+		response, err := a.downloadTrack(r.Context(), req)
+		fireDownloadHook(req, response, err)
+		if err != nil {
+			writeAPIJSON(w, http.StatusBadGateway, response)
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, response)
+		return
+	}
+
+	if req.ItemID == "" {
+		req.ItemID = fmt.Sprintf("%s-%d", req.SpotifyID, time.Now().UnixNano())
+		backend.AddToQueue(req.ItemID, req.TrackName, req.ArtistName, req.AlbumName, req.SpotifyID)
+	}
+
+	select {
+	case a.apiJobs <- req:
+		writeAPIJSON(w, http.StatusAccepted, apiDownloadAccepted{ItemID: req.ItemID, Status: string(backend.StatusQueued)})
+	default:
+		backend.FailDownloadItem(req.ItemID, "API download queue is full")
+		writeAPIError(w, http.StatusServiceUnavailable, "download queue is full")
+	}
+}
+
+func (a *App) runAPIDownloadWorker(ctx context.Context) {
+	pending := make(map[string]DownloadRequest)
+	for {
+		if len(pending) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case req := <-a.apiJobs:
+				pending[req.ItemID] = req
+			}
+		}
+		for drained := false; !drained; {
+			select {
+			case req := <-a.apiJobs:
+				pending[req.ItemID] = req
+			default:
+				drained = true
+			}
+		}
+
+		itemID, ok := backend.NextQueuedItemID(func(id string) bool {
+			_, ok := pending[id]
+			return ok
+		})
+		if !ok {
+			clear(pending)
+			continue
+		}
+		req := pending[itemID]
+		delete(pending, itemID)
+
+		response, err := a.downloadTrack(ctx, req)
+		fireDownloadHook(req, response, err)
+		if err != nil {
+			fmt.Printf("[API] Download failed for %s: %v\n", req.ItemID, err)
+		}
+		a.emitEvent("api-download-finished", response)
+	}
+}
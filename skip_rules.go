@@ -0,0 +1,19 @@
+package main
+
+import "github.com/afkarxyz/SpotiFLAC/backend"
+
+func (a *App) CheckSkipRules(tracks []backend.AlbumTrackMetadata) map[string]string {
+	excluded := make(map[string]string)
+	rules := backend.GetSkipRulesSetting()
+	if !rules.Enabled() {
+		return excluded
+	}
+
+	skip := backend.NewSkipMatcher(rules)
+	for _, track := range tracks {
+		if reason := skip.Match(track); reason != "" && track.SpotifyID != "" {
+			excluded[track.SpotifyID] = reason
+		}
+	}
+	return excluded
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func (a *App) SpotifyLogin() (backend.SpotifyAuthStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	return backend.SpotifyLogin(ctx, func(authURL string) {
+		if a.ctx != nil {
+			runtime.BrowserOpenURL(a.ctx, authURL)
+		}
+	})
+}
+
+func (a *App) SpotifyLogout() error {
+	return backend.SpotifyLogout()
+}
+
+func (a *App) GetSpotifyAuthStatus() backend.SpotifyAuthStatus {
+	return backend.GetSpotifyAuthStatus()
+}
+
+func (a *App) GetSpotifyLikedSongs() (*backend.PlaylistResponsePayload, error) {
+	return backend.GetSpotifyLikedSongs(loadBatchDownloadSettings().Separator)
+}
+
+func (a *App) GetSpotifyMyPlaylists() ([]backend.SpotifyUserPlaylist, error) {
+	return backend.GetSpotifyUserPlaylists()
+}
+
+func (a *App) GetSpotifyMyPlaylistTracks(playlistID string) (*backend.PlaylistResponsePayload, error) {
+	if strings.TrimSpace(playlistID) == "" {
+		return nil, fmt.Errorf("playlist ID is required")
+	}
+	return backend.GetSpotifyUserPlaylistTracks(playlistID, loadBatchDownloadSettings().Separator)
+}
+
+func (a *App) GetSpotifyFollowedArtists() ([]backend.SearchResult, error) {
+	return backend.GetSpotifyFollowedArtists()
+}
+
+func (a *App) DownloadSpotifyLikedSongs() (BatchDownloadResult, error) {
+	settings := loadBatchDownloadSettings()
+
+	payload, err := backend.GetSpotifyLikedSongs(settings.Separator)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+
+	return a.downloadSpotifyTracks(context.Background(), spotifyTrackList{
+		URL:          "spotify:user:collection",
+		Name:         "Liked Songs",
+		Type:         "playlist",
+		PlaylistName: "Liked Songs",
+		Tracks:       payload.TrackList,
+	}, settings), nil
+}
+
+func (a *App) DownloadSpotifyMyPlaylist(playlistID string) (BatchDownloadResult, error) {
+	settings := loadBatchDownloadSettings()
+
+	payload, err := backend.GetSpotifyUserPlaylistTracks(playlistID, settings.Separator)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+
+	name := strings.TrimSpace(payload.PlaylistInfo.Owner.Name)
+	return a.downloadSpotifyTracks(context.Background(), spotifyTrackList{
+		URL:          fmt.Sprintf("https://open.spotify.com/playlist/%s", playlistID),
+		Name:         name,
+		Type:         "playlist",
+		PlaylistName: name,
+		Tracks:       payload.TrackList,
+	}, settings), nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func (a *App) TestSubsonicConnection(config backend.SubsonicConfig) error {
+	if !config.Configured() {
+		return fmt.Errorf("server URL and username are required")
+	}
+	return backend.NewSubsonicClient(config).Ping()
+}
+
+func (a *App) FindSubsonicTrack(title, artist string, durationSec int) (*backend.SubsonicSong, error) {
+	config := backend.GetSubsonicSetting()
+	if !config.Configured() {
+		return nil, fmt.Errorf("subsonic server is not configured")
+	}
+	return backend.NewSubsonicClient(config).FindTrack(title, artist, durationSec)
+}
+
+func (a *App) StartSubsonicScan() error {
+	config := backend.GetSubsonicSetting()
+	if !config.Configured() {
+		return fmt.Errorf("subsonic server is not configured")
+	}
+	return backend.NewSubsonicClient(config).StartScan()
+}
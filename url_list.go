@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func expandURLList(ctx context.Context, urls []string, settings batchDownloadSettings) ([]spotifyTrackList, BatchDownloadResult) {
+	var lists []spotifyTrackList
+	result := BatchDownloadResult{Name: "URL list"}
+	seen := make(map[string]bool)
+
+	for _, url := range urls {
+		if ctx.Err() != nil {
+			break
+		}
+
+		list, err := fetchSpotifyTrackList(ctx, url, settings.Separator)
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", url, err))
+			continue
+		}
+
+		filtered := list
+		filtered.Tracks = nil
+		filtered.Positions = nil
+		for i, track := range list.Tracks {
+			if track.SpotifyID != "" {
+				if seen[track.SpotifyID] {
+					continue
+				}
+				seen[track.SpotifyID] = true
+			}
+			filtered.Tracks = append(filtered.Tracks, track)
+			filtered.Positions = append(filtered.Positions, i+1)
+		}
+		if len(filtered.Tracks) > 0 {
+			lists = append(lists, filtered)
+		}
+	}
+
+	return lists, result
+}
+
+func (a *App) downloadURLList(ctx context.Context, urls []string, settings batchDownloadSettings) BatchDownloadResult {
+	lists, result := expandURLList(ctx, urls, settings)
+	result.Total = result.Failed
+
+	for _, list := range lists {
+		if ctx.Err() != nil {
+			result.Errors = append(result.Errors, ctx.Err().Error())
+			break
+		}
+
+		downloaded := a.downloadSpotifyTracks(ctx, list, settings)
+		result.Total += downloaded.Total
+		result.Downloaded += downloaded.Downloaded
+		result.Skipped += downloaded.Skipped
+		result.Failed += downloaded.Failed
+		result.Files = append(result.Files, downloaded.Files...)
+		result.Errors = append(result.Errors, downloaded.Errors...)
+		result.FailedIDs = append(result.FailedIDs, downloaded.FailedIDs...)
+	}
+	return result
+}
+
+func (a *App) ParseURLListFile(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	return backend.ParseURLList(path)
+}
+
+func (a *App) DownloadURLListFile(path string) (BatchDownloadResult, error) {
+	if path == "" {
+		return BatchDownloadResult{}, fmt.Errorf("file path is required")
+	}
+	urls, err := backend.ParseURLList(path)
+	if err != nil {
+		return BatchDownloadResult{}, err
+	}
+	return a.downloadURLList(context.Background(), urls, loadBatchDownloadSettings()), nil
+}
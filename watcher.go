@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/afkarxyz/SpotiFLAC/backend"
+)
+
+func (a *App) startWatchScheduler(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backend.GetWatchIntervalSetting()):
+			}
+
+			if !backend.GetWatchEnabledSetting() {
+				continue
+			}
+
+			if _, err := a.runWatchCycle(ctx); err != nil {
+				fmt.Printf("[Watch] Scheduled check failed: %v\n", err)
+			}
+		}
+	}()
+}
+
+func (a *App) runWatchCycle(ctx context.Context) ([]BatchDownloadResult, error) {
+	if !a.watchMu.TryLock() {
+		return nil, fmt.Errorf("a watchlist check is already running")
+	}
+	defer a.watchMu.Unlock()
+
+	entries, err := backend.GetWatchlistEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watchlist: %w", err)
+	}
+
+	settings := loadBatchDownloadSettings()
+	results := make([]BatchDownloadResult, 0, len(entries))
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		result, err := a.syncWatchlistEntry(ctx, entry, settings)
+		if err != nil {
+			fmt.Printf("[Watch] %s: %v\n", entry.URL, err)
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (a *App) syncWatchlistEntry(ctx context.Context, entry backend.WatchlistEntry, settings batchDownloadSettings) (BatchDownloadResult, error) {
+	fmt.Printf("[Watch] Checking %s (%s)\n", entry.Name, entry.URL)
+
+	list, err := fetchSpotifyTrackList(ctx, entry.URL, settings.Separator)
+	entry.LastChecked = time.Now().Unix()
+	if err != nil {
+		entry.LastError = err.Error()
+		_ = backend.SaveWatchlistEntry(entry)
+		return BatchDownloadResult{}, err
+	}
+	entry.LastError = ""
+	if list.Name != "" {
+		entry.Name = list.Name
+	}
+
+	var freshTracks []backend.AlbumTrackMetadata
+	var freshAlbums []backend.DiscographyAlbumMetadata
+	if entry.Type == "artist" {
+		freshTracks, freshAlbums = selectNewArtistReleases(&entry, list)
+	} else {
+		freshTracks = selectNewWatchlistTracks(&entry, list)
+	}
+	list.Tracks = freshTracks
+
+	result := BatchDownloadResult{URL: entry.URL, Name: entry.Name}
+	if len(freshTracks) > 0 {
+		fmt.Printf("[Watch] %d new track(s) found in %s\n", len(freshTracks), entry.Name)
+		result = a.downloadSpotifyTracks(ctx, list, settings)
+
+		failed := make(map[string]struct{}, len(result.FailedIDs))
+		for _, id := range result.FailedIDs {
+			failed[id] = struct{}{}
+		}
+
+		completed := make([]string, 0, len(freshTracks))
+		for _, track := range freshTracks {
+			if _, ok := failed[track.SpotifyID]; !ok {
+				completed = append(completed, track.SpotifyID)
+			}
+		}
+		entry.MarkTracksKnown(completed)
+		markCompletedArtistReleases(&entry, freshAlbums, freshTracks, failed)
+
+		a.emitEvent("watchlist-new-tracks", result)
+	}
+
+	entry.LastNewTracks = len(freshTracks)
+	entry.LastNewAlbums = nil
+	for _, album := range freshAlbums {
+		entry.LastNewAlbums = append(entry.LastNewAlbums, album.Name)
+	}
+	if err := backend.SaveWatchlistEntry(entry); err != nil {
+		return result, fmt.Errorf("failed to save watchlist entry: %w", err)
+	}
+
+	return result, nil
+}
+
+func selectNewWatchlistTracks(entry *backend.WatchlistEntry, list spotifyTrackList) []backend.AlbumTrackMetadata {
+	trackIDs := make([]string, 0, len(list.Tracks))
+	for _, track := range list.Tracks {
+		trackIDs = append(trackIDs, track.SpotifyID)
+	}
+
+	freshIDs := make(map[string]struct{})
+	for _, id := range entry.NewTrackIDs(trackIDs) {
+		freshIDs[id] = struct{}{}
+	}
+
+	freshTracks := make([]backend.AlbumTrackMetadata, 0, len(freshIDs))
+	for _, track := range list.Tracks {
+		if _, ok := freshIDs[track.SpotifyID]; ok {
+			freshTracks = append(freshTracks, track)
+		}
+	}
+
+	return freshTracks
+}
+
+func selectNewArtistReleases(entry *backend.WatchlistEntry, list spotifyTrackList) ([]backend.AlbumTrackMetadata, []backend.DiscographyAlbumMetadata) {
+	albumIDs := make([]string, 0, len(list.Albums))
+	for _, album := range list.Albums {
+		albumIDs = append(albumIDs, album.ID)
+	}
+
+	if len(entry.KnownAlbumIDs) == 0 && len(entry.KnownTrackIDs) > 0 {
+		fmt.Printf("[Watch] Recording current releases of %s as known\n", entry.Name)
+		entry.MarkAlbumsKnown(albumIDs)
+		return nil, nil
+	}
+
+	freshIDs := make(map[string]struct{})
+	for _, id := range entry.NewAlbumIDs(albumIDs) {
+		freshIDs[id] = struct{}{}
+	}
+	if len(freshIDs) == 0 {
+		return nil, nil
+	}
+
+	var freshAlbums []backend.DiscographyAlbumMetadata
+	for _, album := range list.Albums {
+		if _, ok := freshIDs[album.ID]; ok {
+			freshAlbums = append(freshAlbums, album)
+			fmt.Printf("[Watch] New release from %s: %s (%s, %s)\n", entry.Name, album.Name, album.AlbumType, album.ReleaseDate)
+		}
+	}
+
+	var freshTracks []backend.AlbumTrackMetadata
+	for _, track := range list.Tracks {
+		if _, ok := freshIDs[track.AlbumID]; ok {
+			freshTracks = append(freshTracks, track)
+		}
+	}
+
+	return freshTracks, freshAlbums
+}
+
+func markCompletedArtistReleases(entry *backend.WatchlistEntry, albums []backend.DiscographyAlbumMetadata, tracks []backend.AlbumTrackMetadata, failed map[string]struct{}) {
+	incomplete := make(map[string]struct{})
+	for _, track := range tracks {
+		if _, ok := failed[track.SpotifyID]; ok {
+			incomplete[track.AlbumID] = struct{}{}
+		}
+	}
+
+	completed := make([]string, 0, len(albums))
+	for _, album := range albums {
+		if _, ok := incomplete[album.ID]; !ok {
+			completed = append(completed, album.ID)
+		}
+	}
+	entry.MarkAlbumsKnown(completed)
+}
+
+func (a *App) AddToWatchlist(spotifyURL string) (*backend.WatchlistEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	spotifyURL, err := backend.ResolveSpotifyURL(ctx, spotifyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	entryType, id, err := backend.WatchlistEntryTypeFromURL(spotifyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	entryID := entryType + ":" + id
+	if existing, err := backend.GetWatchlistEntry(entryID); err == nil && existing != nil {
+		return existing, nil
+	}
+
+	list, err := fetchSpotifyTrackList(ctx, spotifyURL, loadBatchDownloadSettings().Separator)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := backend.WatchlistEntry{
+		ID:          entryID,
+		URL:         spotifyURL,
+		Type:        entryType,
+		Name:        list.Name,
+		AddedAt:     time.Now().Unix(),
+		LastChecked: time.Now().Unix(),
+	}
+
+	trackIDs := make([]string, 0, len(list.Tracks))
+	for _, track := range list.Tracks {
+		trackIDs = append(trackIDs, track.SpotifyID)
+	}
+	entry.MarkTracksKnown(trackIDs)
+
+	albumIDs := make([]string, 0, len(list.Albums))
+	for _, album := range list.Albums {
+		albumIDs = append(albumIDs, album.ID)
+	}
+	entry.MarkAlbumsKnown(albumIDs)
+
+	if err := backend.SaveWatchlistEntry(entry); err != nil {
+		return nil, fmt.Errorf("failed to save watchlist entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (a *App) GetWatchlist() ([]backend.WatchlistEntry, error) {
+	return backend.GetWatchlistEntries()
+}
+
+func (a *App) RemoveFromWatchlist(id string) error {
+	return backend.DeleteWatchlistEntry(id)
+}
+
+func (a *App) CheckWatchlistNow() ([]BatchDownloadResult, error) {
+	return a.runWatchCycle(context.Background())
+}